@@ -0,0 +1,252 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// actionKind returns the kernel name of the action, which differs from
+// its Type for the gact GenericAction.
+func actionKind(action Action) string {
+	if _, ok := action.(*GenericAction); ok {
+		return "gact"
+	}
+	return action.Type()
+}
+
+// actionSelector builds the attribute selecting actions of a kind, and of
+// a given index unless it is zero.
+func actionSelector(kind string, index int) *nl.RtAttr {
+	tab := nl.NewRtAttr(nl.TCA_ROOT_TAB, nil)
+	table := nl.NewRtAttrChild(tab, nl.TCA_ACT_TAB, nil)
+	nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated(kind))
+	if index != 0 {
+		nl.NewRtAttrChild(table, nl.TCA_ACT_INDEX, nl.Uint32Attr(uint32(index)))
+	}
+	return tab
+}
+
+// ActionAdd will add a shared action to the system. Filters reference it
+// by setting the same kind of action with its Index. If the Index is zero
+// the kernel picks one, use ActionList to find it.
+// Equivalent to: `tc actions add action $action`
+func ActionAdd(action Action) error {
+	return pkgHandle.ActionAdd(action)
+}
+
+// ActionAdd will add a shared action to the system. Filters reference it
+// by setting the same kind of action with its Index. If the Index is zero
+// the kernel picks one, use ActionList to find it.
+// Equivalent to: `tc actions add action $action`
+func (h *Handle) ActionAdd(action Action) error {
+	return h.actionModify(unix.RTM_NEWACTION, unix.NLM_F_CREATE|unix.NLM_F_EXCL, action)
+}
+
+// ActionAddContext is like ActionAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ActionAddContext(ctx context.Context, action Action) error {
+	return h.withContext(ctx).ActionAdd(action)
+}
+
+// ActionReplace will replace a shared action, or add it if it does not
+// exist.
+// Equivalent to: `tc actions replace action $action`
+func ActionReplace(action Action) error {
+	return pkgHandle.ActionReplace(action)
+}
+
+// ActionReplace will replace a shared action, or add it if it does not
+// exist.
+// Equivalent to: `tc actions replace action $action`
+func (h *Handle) ActionReplace(action Action) error {
+	return h.actionModify(unix.RTM_NEWACTION, unix.NLM_F_CREATE|unix.NLM_F_REPLACE, action)
+}
+
+// ActionReplaceContext is like ActionReplace but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) ActionReplaceContext(ctx context.Context, action Action) error {
+	return h.withContext(ctx).ActionReplace(action)
+}
+
+func (h *Handle) actionModify(cmd, flags int, action Action) error {
+	req := h.newNetlinkRequest(cmd, flags|unix.NLM_F_ACK)
+	req.AddData(&nl.TcActionMsg{
+		Family: nl.FAMILY_ALL,
+	})
+	tab := nl.NewRtAttr(nl.TCA_ROOT_TAB, nil)
+	if err := EncodeActions(tab, []Action{action}); err != nil {
+		return err
+	}
+	req.AddData(tab)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// ActionDel will delete a shared action, selected by its kind and Index,
+// from the system. Actions still bound to filters can not be deleted.
+// Equivalent to: `tc actions del action $kind index $index`
+func ActionDel(action Action) error {
+	return pkgHandle.ActionDel(action)
+}
+
+// ActionDel will delete a shared action, selected by its kind and Index,
+// from the system. Actions still bound to filters can not be deleted.
+// Equivalent to: `tc actions del action $kind index $index`
+func (h *Handle) ActionDel(action Action) error {
+	if action.Attrs().Index == 0 {
+		return fmt.Errorf("action index is required")
+	}
+	req := h.newNetlinkRequest(unix.RTM_DELACTION, unix.NLM_F_ACK)
+	req.AddData(&nl.TcActionMsg{
+		Family: nl.FAMILY_ALL,
+	})
+	req.AddData(actionSelector(actionKind(action), action.Attrs().Index))
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// ActionDelContext is like ActionDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ActionDelContext(ctx context.Context, action Action) error {
+	return h.withContext(ctx).ActionDel(action)
+}
+
+// ActionFlush will delete all the shared actions of a kind which are not
+// bound to filters.
+// Equivalent to: `tc actions flush action $kind`
+func ActionFlush(kind string) error {
+	return pkgHandle.ActionFlush(kind)
+}
+
+// ActionFlush will delete all the shared actions of a kind which are not
+// bound to filters.
+// Equivalent to: `tc actions flush action $kind`
+func (h *Handle) ActionFlush(kind string) error {
+	req := h.newNetlinkRequest(unix.RTM_DELACTION, unix.NLM_F_ROOT|unix.NLM_F_ACK)
+	req.AddData(&nl.TcActionMsg{
+		Family: nl.FAMILY_ALL,
+	})
+	req.AddData(actionSelector(kind, 0))
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// ActionFlushContext is like ActionFlush but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) ActionFlushContext(ctx context.Context, kind string) error {
+	return h.withContext(ctx).ActionFlush(kind)
+}
+
+// ActionGet gets a single action of the given kind, e.g. "police" or
+// "gact", by index together with its statistics.
+// Equivalent to: `tc -s actions get action $kind index $index`
+func ActionGet(kind string, index int) (Action, error) {
+	return pkgHandle.ActionGet(kind, index)
+}
+
+// ActionGet gets a single action of the given kind, e.g. "police" or
+// "gact", by index together with its statistics.
+// Equivalent to: `tc -s actions get action $kind index $index`
+func (h *Handle) ActionGet(kind string, index int) (Action, error) {
+	if index == 0 {
+		return nil, fmt.Errorf("action index is required")
+	}
+	req := h.newNetlinkRequest(unix.RTM_GETACTION, unix.NLM_F_ACK)
+	req.AddData(&nl.TcActionMsg{
+		Family: nl.FAMILY_ALL,
+	})
+	req.AddData(actionSelector(kind, index))
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_GETACTION)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := parseActionMsgs(msgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) != 1 {
+		return nil, fmt.Errorf("action %s with index %d not found or not supported", kind, index)
+	}
+	return actions[0], nil
+}
+
+// ActionGetContext is like ActionGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ActionGetContext(ctx context.Context, kind string, index int) (Action, error) {
+	return h.withContext(ctx).ActionGet(kind, index)
+}
+
+// ActionList gets the table of shared actions of the given kind, e.g.
+// "police" or "gact", together with their statistics. Kinds which this
+// library does not understand return no actions.
+// Equivalent to: `tc -s actions list action $kind`
+func ActionList(kind string) ([]Action, error) {
+	return pkgHandle.ActionList(kind)
+}
+
+// ActionList gets the table of shared actions of the given kind, e.g.
+// "police" or "gact", together with their statistics. Kinds which this
+// library does not understand return no actions.
+// Equivalent to: `tc -s actions list action $kind`
+func (h *Handle) ActionList(kind string) ([]Action, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETACTION, unix.NLM_F_DUMP)
+	req.AddData(&nl.TcActionMsg{
+		Family: nl.FAMILY_ALL,
+	})
+	req.AddData(actionSelector(kind, 0))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_GETACTION)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	actions, err := parseActionMsgs(msgs)
+	if err != nil {
+		return nil, err
+	}
+	return actions, executeErr
+}
+
+// ActionListContext is like ActionList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ActionListContext(ctx context.Context, kind string) ([]Action, error) {
+	return h.withContext(ctx).ActionList(kind)
+}
+
+func parseActionMsgs(msgs [][]byte) ([]Action, error) {
+	var res []Action
+	for _, m := range msgs {
+		msg := nl.DeserializeTcActionMsg(m)
+		attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type != nl.TCA_ROOT_TAB {
+				continue
+			}
+			tables, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			actions, err := parseActions(tables)
+			if err != nil {
+				return nil, err
+			}
+			for _, action := range actions {
+				// parseActions leaves unknown kinds nil
+				if action != nil {
+					res = append(res, action)
+				}
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,92 @@
+// +build linux
+
+package netlink
+
+import (
+	"testing"
+)
+
+func TestActionAddGetDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	police := NewPoliceAction()
+	police.Index = 42
+	police.Rate = 125000
+	police.Burst = 10000
+	if err := ActionAdd(police); err != nil {
+		t.Fatal(err)
+	}
+	if err := ActionAdd(police); err == nil {
+		t.Fatal("Expected an error adding an existing action")
+	}
+
+	action, err := ActionGet("police", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := action.(*PoliceAction)
+	if !ok {
+		t.Fatalf("Action is the wrong type: %v", action)
+	}
+	if got.Index != 42 || got.Rate != police.Rate || got.Burst != police.Burst {
+		t.Fatalf("Action not added properly: %+v", got)
+	}
+	if got.Statistics == nil || got.Timestamp == nil {
+		t.Fatal("Action statistics not returned")
+	}
+
+	police.Rate = 250000
+	if err := ActionReplace(police); err != nil {
+		t.Fatal(err)
+	}
+	actions, err := ActionList("police")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].(*PoliceAction).Rate != 250000 {
+		t.Fatalf("Action not replaced properly: %v", actions)
+	}
+
+	if err := ActionDel(police); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ActionGet("police", 42); err == nil {
+		t.Fatal("Expected an error getting a deleted action")
+	}
+}
+
+func TestActionFlush(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	for i := 1; i <= 3; i++ {
+		gact := &GenericAction{ActionAttrs: ActionAttrs{Index: i, Action: TC_ACT_SHOT}}
+		if err := ActionAdd(gact); err != nil {
+			t.Fatal(err)
+		}
+	}
+	actions, err := ActionList("gact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %d", len(actions))
+	}
+	for _, action := range actions {
+		if action.Attrs().Action != TC_ACT_SHOT {
+			t.Fatalf("Action not added properly: %+v", action.Attrs())
+		}
+	}
+
+	if err := ActionFlush("gact"); err != nil {
+		t.Fatal(err)
+	}
+	actions, err = ActionList("gact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 0 {
+		t.Fatalf("Actions not flushed: %v", actions)
+	}
+}
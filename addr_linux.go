@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -27,6 +29,12 @@ func (h *Handle) AddrAdd(link Link, addr *Addr) error {
 	return h.addrHandle(link, addr, req)
 }
 
+// AddrAddContext is like AddrAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) AddrAddContext(ctx context.Context, link Link, addr *Addr) error {
+	return h.withContext(ctx).AddrAdd(link, addr)
+}
+
 // AddrReplace will replace (or, if not present, add) an IP address on a link device.
 // Equivalent to: `ip addr replace $addr dev $link`
 func AddrReplace(link Link, addr *Addr) error {
@@ -40,6 +48,12 @@ func (h *Handle) AddrReplace(link Link, addr *Addr) error {
 	return h.addrHandle(link, addr, req)
 }
 
+// AddrReplaceContext is like AddrReplace but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) AddrReplaceContext(ctx context.Context, link Link, addr *Addr) error {
+	return h.withContext(ctx).AddrReplace(link, addr)
+}
+
 // AddrDel will delete an IP address from a link device.
 // Equivalent to: `ip addr del $addr dev $link`
 func AddrDel(link Link, addr *Addr) error {
@@ -53,6 +67,12 @@ func (h *Handle) AddrDel(link Link, addr *Addr) error {
 	return h.addrHandle(link, addr, req)
 }
 
+// AddrDelContext is like AddrDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) AddrDelContext(ctx context.Context, link Link, addr *Addr) error {
+	return h.withContext(ctx).AddrDel(link, addr)
+}
+
 func (h *Handle) addrHandle(link Link, addr *Addr, req *nl.NetlinkRequest) error {
 	base := link.Attrs()
 	if addr.Label != "" && !strings.HasPrefix(addr.Label, base.Name) {
@@ -153,9 +173,9 @@ func (h *Handle) AddrList(link Link, family int) ([]Addr, error) {
 	msg := nl.NewIfInfomsg(family)
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWADDR)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWADDR)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	indexFilter := 0
@@ -184,7 +204,13 @@ func (h *Handle) AddrList(link Link, family int) ([]Addr, error) {
 		res = append(res, addr)
 	}
 
-	return res, nil
+	return res, executeErr
+}
+
+// AddrListContext is like AddrList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) AddrListContext(ctx context.Context, link Link, family int) ([]Addr, error) {
+	return h.withContext(ctx).AddrList(link, family)
 }
 
 func parseAddr(m []byte) (addr Addr, family, index int, err error) {
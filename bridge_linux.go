@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vishvananda/netlink/nl"
@@ -21,9 +23,9 @@ func (h *Handle) BridgeVlanList() (map[int32][]*nl.BridgeVlanInfo, error) {
 	req.AddData(msg)
 	req.AddData(nl.NewRtAttr(unix.IFLA_EXT_MASK, nl.Uint32Attr(uint32(nl.RTEXT_FILTER_BRVLAN))))
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 	ret := make(map[int32][]*nl.BridgeVlanInfo)
 	for _, m := range msgs {
@@ -51,7 +53,13 @@ func (h *Handle) BridgeVlanList() (map[int32][]*nl.BridgeVlanInfo, error) {
 			}
 		}
 	}
-	return ret, nil
+	return ret, executeErr
+}
+
+// BridgeVlanListContext is like BridgeVlanList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) BridgeVlanListContext(ctx context.Context) (map[int32][]*nl.BridgeVlanInfo, error) {
+	return h.withContext(ctx).BridgeVlanList()
 }
 
 // BridgeVlanAdd adds a new vlan filter entry
@@ -66,6 +74,12 @@ func (h *Handle) BridgeVlanAdd(link Link, vid uint16, pvid, untagged, self, mast
 	return h.bridgeVlanModify(unix.RTM_SETLINK, link, vid, pvid, untagged, self, master)
 }
 
+// BridgeVlanAddContext is like BridgeVlanAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) BridgeVlanAddContext(ctx context.Context, link Link, vid uint16, pvid, untagged, self, master bool) error {
+	return h.withContext(ctx).BridgeVlanAdd(link, vid, pvid, untagged, self, master)
+}
+
 // BridgeVlanDel adds a new vlan filter entry
 // Equivalent to: `bridge vlan del dev DEV vid VID [ pvid ] [ untagged ] [ self ] [ master ]`
 func BridgeVlanDel(link Link, vid uint16, pvid, untagged, self, master bool) error {
@@ -78,6 +92,12 @@ func (h *Handle) BridgeVlanDel(link Link, vid uint16, pvid, untagged, self, mast
 	return h.bridgeVlanModify(unix.RTM_DELLINK, link, vid, pvid, untagged, self, master)
 }
 
+// BridgeVlanDelContext is like BridgeVlanDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) BridgeVlanDelContext(ctx context.Context, link Link, vid uint16, pvid, untagged, self, master bool) error {
+	return h.withContext(ctx).BridgeVlanDel(link, vid, pvid, untagged, self, master)
+}
+
 func (h *Handle) bridgeVlanModify(cmd int, link Link, vid uint16, pvid, untagged, self, master bool) error {
 	base := link.Attrs()
 	h.ensureIndex(base)
@@ -0,0 +1,182 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// ChainAdd will add a filter chain to the system.
+// Equivalent to: `tc chain add $chain`
+func ChainAdd(chain Chain) error {
+	return pkgHandle.ChainAdd(chain)
+}
+
+// ChainAdd will add a filter chain to the system.
+// Equivalent to: `tc chain add $chain`
+func (h *Handle) ChainAdd(chain Chain) error {
+	return h.chainModify(nl.RTM_NEWCHAIN, unix.NLM_F_CREATE|unix.NLM_F_EXCL, chain)
+}
+
+// ChainAddContext is like ChainAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ChainAddContext(ctx context.Context, chain Chain) error {
+	return h.withContext(ctx).ChainAdd(chain)
+}
+
+// ChainDel will delete a filter chain, and all of its filters, from the
+// system.
+// Equivalent to: `tc chain del $chain`
+func ChainDel(chain Chain) error {
+	return pkgHandle.ChainDel(chain)
+}
+
+// ChainDel will delete a filter chain, and all of its filters, from the
+// system.
+// Equivalent to: `tc chain del $chain`
+func (h *Handle) ChainDel(chain Chain) error {
+	return h.chainModify(nl.RTM_DELCHAIN, 0, chain)
+}
+
+// ChainDelContext is like ChainDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ChainDelContext(ctx context.Context, chain Chain) error {
+	return h.withContext(ctx).ChainDel(chain)
+}
+
+func (h *Handle) chainModify(cmd, flags int, chain Chain) error {
+	req := h.newNetlinkRequest(cmd, flags|unix.NLM_F_ACK)
+	msg := &nl.TcMsg{
+		Family:  nl.FAMILY_ALL,
+		Ifindex: int32(chain.LinkIndex),
+		Parent:  chain.Parent,
+	}
+	if chain.Block != 0 {
+		setTcMsgBlock(msg, chain.Block)
+	}
+	if cmd == nl.RTM_NEWCHAIN && chain.Template != nil {
+		msg.Info = MakeHandle(0, nl.Swap16(chain.Template.Protocol))
+	}
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.TCA_CHAIN, nl.Uint32Attr(chain.Chain)))
+
+	if cmd == nl.RTM_NEWCHAIN && chain.Template != nil {
+		req.AddData(nl.NewRtAttr(nl.TCA_KIND, nl.ZeroTerminated(chain.Template.Type())))
+		options := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
+		if err := encodeFlowerData(options, chain.Template); err != nil {
+			return err
+		}
+		req.AddData(options)
+	}
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// ChainList gets a list of the filter chains of a link.
+// Equivalent to: `tc chain show dev $link parent $parent`.
+func ChainList(link Link, parent uint32) ([]Chain, error) {
+	return pkgHandle.ChainList(link, parent)
+}
+
+// ChainList gets a list of the filter chains of a link.
+// Equivalent to: `tc chain show dev $link parent $parent`.
+func (h *Handle) ChainList(link Link, parent uint32) ([]Chain, error) {
+	msg := &nl.TcMsg{
+		Family: nl.FAMILY_ALL,
+		Parent: parent,
+	}
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		msg.Ifindex = int32(base.Index)
+	}
+	return h.chainList(msg)
+}
+
+// ChainListContext is like ChainList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ChainListContext(ctx context.Context, link Link, parent uint32) ([]Chain, error) {
+	return h.withContext(ctx).ChainList(link, parent)
+}
+
+// ChainListBlock gets a list of the filter chains of a shared block.
+// Equivalent to: `tc chain show block $block`.
+func ChainListBlock(block uint32) ([]Chain, error) {
+	return pkgHandle.ChainListBlock(block)
+}
+
+// ChainListBlock gets a list of the filter chains of a shared block.
+// Equivalent to: `tc chain show block $block`.
+func (h *Handle) ChainListBlock(block uint32) ([]Chain, error) {
+	msg := &nl.TcMsg{
+		Family: nl.FAMILY_ALL,
+	}
+	setTcMsgBlock(msg, block)
+	return h.chainList(msg)
+}
+
+// ChainListBlockContext is like ChainListBlock but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) ChainListBlockContext(ctx context.Context, block uint32) ([]Chain, error) {
+	return h.withContext(ctx).ChainListBlock(block)
+}
+
+func (h *Handle) chainList(msg *nl.TcMsg) ([]Chain, error) {
+	req := h.newNetlinkRequest(nl.RTM_GETCHAIN, unix.NLM_F_DUMP)
+	req.AddData(msg)
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, nl.RTM_NEWCHAIN)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	var res []Chain
+	for _, m := range msgs {
+		reply := nl.DeserializeTcMsg(m)
+
+		attrs, err := nl.ParseRouteAttr(m[reply.Len():])
+		if err != nil {
+			return nil, err
+		}
+
+		// the kernel reports the qdisc handle rather than the
+		// block parent, so keep the parent that was asked for
+		chain := Chain{
+			LinkIndex: int(reply.Ifindex),
+			Parent:    msg.Parent,
+		}
+		if uint32(reply.Ifindex) == nl.TCM_IFINDEX_MAGIC_BLOCK {
+			chain.LinkIndex = 0
+			chain.Block = reply.Parent
+			chain.Parent = 0
+		}
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case nl.TCA_CHAIN:
+				chain.Chain = native.Uint32(attr.Value)
+			case nl.TCA_KIND:
+				// flower is the only classifier supporting templates
+				if string(attr.Value[:len(attr.Value)-1]) == "flower" {
+					chain.Template = &Flower{}
+				}
+			case nl.TCA_OPTIONS:
+				if chain.Template == nil {
+					continue
+				}
+				data, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := parseFlowerData(chain.Template, data); err != nil {
+					return nil, err
+				}
+			}
+		}
+		res = append(res, chain)
+	}
+
+	return res, executeErr
+}
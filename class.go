@@ -99,6 +99,17 @@ type HtbClass struct {
 	Quantum uint32
 	Level   uint32
 	Prio    uint32
+	XStats  *HtbXStats // read only
+}
+
+// HtbXStats are the htb specific statistics of a class.
+// Ref: struct tc_htb_xstats { ... }
+type HtbXStats struct {
+	Lends   uint32
+	Borrows uint32
+	Giants  uint32 // unused since 'Make HTB scheduler work with TSO.'
+	Tokens  int32
+	Ctokens int32
 }
 
 func (q HtbClass) String() string {
@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -74,6 +75,12 @@ func (h *Handle) ClassDel(class Class) error {
 	return h.classModify(unix.RTM_DELTCLASS, 0, class)
 }
 
+// ClassDelContext is like ClassDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ClassDelContext(ctx context.Context, class Class) error {
+	return h.withContext(ctx).ClassDel(class)
+}
+
 // ClassChange will change a class in place
 // Equivalent to: `tc class change $class`
 // The parent and handle MUST NOT be changed.
@@ -88,6 +95,12 @@ func (h *Handle) ClassChange(class Class) error {
 	return h.classModify(unix.RTM_NEWTCLASS, 0, class)
 }
 
+// ClassChangeContext is like ClassChange but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ClassChangeContext(ctx context.Context, class Class) error {
+	return h.withContext(ctx).ClassChange(class)
+}
+
 // ClassReplace will replace a class to the system.
 // quivalent to: `tc class replace $class`
 // The handle MAY be changed.
@@ -106,6 +119,12 @@ func (h *Handle) ClassReplace(class Class) error {
 	return h.classModify(unix.RTM_NEWTCLASS, unix.NLM_F_CREATE, class)
 }
 
+// ClassReplaceContext is like ClassReplace but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ClassReplaceContext(ctx context.Context, class Class) error {
+	return h.withContext(ctx).ClassReplace(class)
+}
+
 // ClassAdd will add a class to the system.
 // Equivalent to: `tc class add $class`
 func ClassAdd(class Class) error {
@@ -122,6 +141,12 @@ func (h *Handle) ClassAdd(class Class) error {
 	)
 }
 
+// ClassAddContext is like ClassAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ClassAddContext(ctx context.Context, class Class) error {
+	return h.withContext(ctx).ClassAdd(class)
+}
+
 func (h *Handle) classModify(cmd, flags int, class Class) error {
 	req := h.newNetlinkRequest(cmd, flags|unix.NLM_F_ACK)
 	base := class.Attrs()
@@ -213,9 +238,9 @@ func (h *Handle) ClassList(link Link, parent uint32) ([]Class, error) {
 	}
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWTCLASS)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWTCLASS)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Class
@@ -280,13 +305,26 @@ func (h *Handle) ClassList(link Link, parent uint32) ([]Class, error) {
 				if err != nil {
 					return nil, err
 				}
+			case nl.TCA_XSTATS:
+				if htb, ok := class.(*HtbClass); ok {
+					htb.XStats = &HtbXStats{}
+					if err := parseXStats(attr.Value, htb.XStats); err != nil {
+						return nil, err
+					}
+				}
 			}
 		}
 		*class.Attrs() = base
 		res = append(res, class)
 	}
 
-	return res, nil
+	return res, executeErr
+}
+
+// ClassListContext is like ClassList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ClassListContext(ctx context.Context, link Link, parent uint32) ([]Class, error) {
+	return h.withContext(ctx).ClassList(link, parent)
 }
 
 func parseHtbClassData(class Class, data []syscall.NetlinkRouteAttr) (bool, error) {
@@ -354,6 +392,15 @@ func parseGnetStats(data []byte, gnetStats interface{}) error {
 	return binary.Read(buf, native, gnetStats)
 }
 
+// parseXStats decodes the kind specific statistics in data into xstats.
+// Shorter structs from older kernels leave the missing fields zeroed and
+// the fields added by newer kernels are ignored.
+func parseXStats(data []byte, xstats interface{}) error {
+	buf := make([]byte, binary.Size(xstats))
+	copy(buf, data)
+	return parseGnetStats(buf, xstats)
+}
+
 func parseTcStats2(data []byte) (*ClassStatistics, error) {
 	rtAttrs, err := nl.ParseRouteAttr(data)
 	if err != nil {
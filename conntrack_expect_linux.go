@@ -0,0 +1,267 @@
+package netlink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// ConntrackExpect is an expectation of the conntrack expect table, created by
+// a helper or from userspace to let a related connection of a master flow in.
+type ConntrackExpect struct {
+	FamilyType uint8
+	// Master is the original tuple of the master flow
+	Master IPTuple
+	// Tuple and Mask select the expected connections
+	Tuple IPTuple
+	Mask  IPTuple
+	// TimeOut is the remaining lifetime of the expectation in seconds
+	TimeOut uint32
+	ID      uint32
+	Zone    uint16
+	// Flags is a mask of the nl.NF_CT_EXPECT_* flags
+	Flags    uint32
+	Class    uint32
+	Helper   string
+	Function string
+}
+
+func (e *ConntrackExpect) String() string {
+	// conntrack cmd output:
+	// 297 proto=6 src=10.0.0.2 dst=10.0.0.1 sport=0 dport=41739 mask-src=255.255.255.255 mask-dst=255.255.255.255 sport=0 dport=65535 master-src=10.0.0.2 master-dst=10.0.0.1 sport=36390 dport=21 class=0 helper=ftp
+	res := fmt.Sprintf("%d proto=%d src=%s dst=%s sport=%d dport=%d mask-src=%s mask-dst=%s sport=%d dport=%d master-src=%s master-dst=%s sport=%d dport=%d",
+		e.TimeOut, e.Tuple.Protocol,
+		e.Tuple.SrcIP, e.Tuple.DstIP, e.Tuple.SrcPort, e.Tuple.DstPort,
+		e.Mask.SrcIP, e.Mask.DstIP, e.Mask.SrcPort, e.Mask.DstPort,
+		e.Master.SrcIP, e.Master.DstIP, e.Master.SrcPort, e.Master.DstPort)
+	if e.Flags&nl.NF_CT_EXPECT_PERMANENT != 0 {
+		res += " PERMANENT"
+	}
+	if e.Flags&nl.NF_CT_EXPECT_INACTIVE != 0 {
+		res += " INACTIVE"
+	}
+	if e.Flags&nl.NF_CT_EXPECT_USERSPACE != 0 {
+		res += " USERSPACE"
+	}
+	if e.Zone != 0 {
+		res += fmt.Sprintf(" zone=%d", e.Zone)
+	}
+	res += fmt.Sprintf(" class=%d", e.Class)
+	if e.Helper != "" {
+		res += " helper=" + e.Helper
+	}
+	if e.Function != "" {
+		res += " fn=" + e.Function
+	}
+	return res
+}
+
+// ConntrackExpectEvent is sent when an expectation is created or destroyed
+type ConntrackExpectEvent struct {
+	Type   ConntrackEventType
+	Expect *ConntrackExpect
+}
+
+// ConntrackExpectList returns the expectations of a specific family
+// conntrack -L expect [options]          List expectation table
+func ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return pkgHandle.ConntrackExpectList(family)
+}
+
+// ConntrackExpectAdd creates a new expectation
+// conntrack -I expect parameters          Create an expectation
+func ConntrackExpectAdd(family InetFamily, expect *ConntrackExpect) error {
+	return pkgHandle.ConntrackExpectAdd(family, expect)
+}
+
+// ConntrackExpectDel deletes the expectation matching the tuple of expect
+// conntrack -D expect parameters          Delete an expectation
+func ConntrackExpectDel(family InetFamily, expect *ConntrackExpect) error {
+	return pkgHandle.ConntrackExpectDel(family, expect)
+}
+
+// ConntrackExpectFlush deletes all the expectations
+// conntrack -F expect          Flush expectation table
+func ConntrackExpectFlush() error {
+	return pkgHandle.ConntrackExpectFlush()
+}
+
+// ConntrackExpectList returns the expectations of a specific family using the netlink handle passed
+// conntrack -L expect [options]          List expectation table
+func (h *Handle) ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_GET, unix.NLM_F_DUMP)
+	res, executeErr := req.Execute(unix.NETLINK_NETFILTER, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	var result []*ConntrackExpect
+	for _, dataRaw := range res {
+		result = append(result, parseConntrackExpect(dataRaw))
+	}
+	return result, executeErr
+}
+
+// ConntrackExpectListContext is like ConntrackExpectList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackExpectListContext(ctx context.Context, family InetFamily) ([]*ConntrackExpect, error) {
+	return h.withContext(ctx).ConntrackExpectList(family)
+}
+
+// ConntrackExpectAdd creates a new expectation using the netlink handle passed
+// conntrack -I expect parameters          Create an expectation
+// The master flow must exist and have a helper, Tuple, Mask and Master are
+// required. The timeout of the helper policy overrides TimeOut.
+func (h *Handle) ConntrackExpectAdd(family InetFamily, expect *ConntrackExpect) error {
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_NEW, unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL)
+	attrs, err := expect.toNlData(family)
+	if err != nil {
+		return err
+	}
+	for _, a := range attrs {
+		req.AddData(a)
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackExpectAddContext is like ConntrackExpectAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackExpectAddContext(ctx context.Context, family InetFamily, expect *ConntrackExpect) error {
+	return h.withContext(ctx).ConntrackExpectAdd(family, expect)
+}
+
+// ConntrackExpectDel deletes the expectation matching the tuple of expect using the netlink handle passed
+// conntrack -D expect parameters          Delete an expectation
+// The ID and zone of expect are also matched when set.
+func (h *Handle) ConntrackExpectDel(family InetFamily, expect *ConntrackExpect) error {
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_DELETE, unix.NLM_F_ACK)
+	tuple, err := expect.Tuple.toNlData(nl.CTA_EXPECT_TUPLE, family)
+	if err != nil {
+		return err
+	}
+	req.AddData(tuple)
+	if expect.ID != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_EXPECT_ID, htonl(expect.ID)))
+	}
+	if expect.Zone != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_EXPECT_ZONE, htons(expect.Zone)))
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackExpectDelContext is like ConntrackExpectDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackExpectDelContext(ctx context.Context, family InetFamily, expect *ConntrackExpect) error {
+	return h.withContext(ctx).ConntrackExpectDel(family, expect)
+}
+
+// ConntrackExpectFlush deletes all the expectations using the netlink handle passed
+// conntrack -F expect          Flush expectation table
+func (h *Handle) ConntrackExpectFlush() error {
+	req := h.newConntrackRequest(ConntrackExpectTable, unix.AF_UNSPEC, nl.IPCTNL_MSG_EXP_DELETE, unix.NLM_F_ACK)
+	_, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackExpectFlushContext is like ConntrackExpectFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackExpectFlushContext(ctx context.Context) error {
+	return h.withContext(ctx).ConntrackExpectFlush()
+}
+
+// ConntrackExpectSubscribe takes a chan down which expectation events will be
+// sent when expectations are created or destroyed. Close the 'done' chan to
+// stop subscription.
+// conntrack -E expect [options]          Show events
+func ConntrackExpectSubscribe(ch chan<- ConntrackExpectEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return conntrackSubscribeAt(*options.Namespace, netns.None(), ConntrackExpectTable, done, options,
+		func(tp ConntrackEventType, data []byte) {
+			ch <- ConntrackExpectEvent{Type: tp, Expect: parseConntrackExpect(data)}
+		}, func() { close(ch) })
+}
+
+// toNlData builds the attributes of a create request
+func (e *ConntrackExpect) toNlData(family InetFamily) ([]*nl.RtAttr, error) {
+	master, err := e.Master.toNlData(nl.CTA_EXPECT_MASTER, family)
+	if err != nil {
+		return nil, err
+	}
+	tuple, err := e.Tuple.toNlData(nl.CTA_EXPECT_TUPLE, family)
+	if err != nil {
+		return nil, err
+	}
+	// The kernel only reads the port masks of the protocol of the mask
+	mask := e.Mask
+	if mask.Protocol == 0 {
+		mask.Protocol = e.Tuple.Protocol
+	}
+	maskAttr, err := mask.toNlData(nl.CTA_EXPECT_MASK, family)
+	if err != nil {
+		return nil, err
+	}
+	attrs := []*nl.RtAttr{master, tuple, maskAttr}
+	if e.TimeOut != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_TIMEOUT, htonl(e.TimeOut)))
+	}
+	if e.Zone != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_ZONE, htons(e.Zone)))
+	}
+	if e.Flags != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_FLAGS, htonl(e.Flags)))
+	}
+	if e.Class != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_CLASS, htonl(e.Class)))
+	}
+	if e.Helper != "" {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_HELP_NAME, nl.ZeroTerminated(e.Helper)))
+	}
+	return attrs, nil
+}
+
+func parseConntrackExpect(data []byte) *ConntrackExpect {
+	e := &ConntrackExpect{}
+	// First there is the Nfgenmsg header
+	// consume only the family field
+	e.FamilyType = data[0]
+
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return e
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_EXPECT_MASTER:
+			parseIpTuple(attr.Value, &e.Master)
+		case nl.CTA_EXPECT_TUPLE:
+			parseIpTuple(attr.Value, &e.Tuple)
+		case nl.CTA_EXPECT_MASK:
+			parseIpTuple(attr.Value, &e.Mask)
+		case nl.CTA_EXPECT_TIMEOUT:
+			e.TimeOut = ntohl(attr.Value)
+		case nl.CTA_EXPECT_ID:
+			e.ID = ntohl(attr.Value)
+		case nl.CTA_EXPECT_ZONE:
+			e.Zone = ntohs(attr.Value)
+		case nl.CTA_EXPECT_FLAGS:
+			e.Flags = ntohl(attr.Value)
+		case nl.CTA_EXPECT_CLASS:
+			e.Class = ntohl(attr.Value)
+		case nl.CTA_EXPECT_HELP_NAME:
+			e.Helper = string(bytes.TrimRight(attr.Value, "\x00"))
+		case nl.CTA_EXPECT_FN:
+			e.Function = string(bytes.TrimRight(attr.Value, "\x00"))
+		}
+	}
+	return e
+}
@@ -0,0 +1,179 @@
+// +build linux
+
+package netlink
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+func expectConntrackExpectEvent(ch <-chan ConntrackExpectEvent, tp ConntrackEventType, dstPort uint16) bool {
+	for {
+		timeout := time.After(time.Minute)
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return false
+			}
+			if event.Expect.Tuple.DstPort == dstPort {
+				return event.Type == tp
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+// TestConntrackExpectAddDel creates an expectation from userspace for a
+// master flow with the ftp helper, then lists and deletes it
+func TestConntrackExpectAddDel(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	ch := make(chan ConntrackExpectEvent)
+	done := make(chan struct{})
+	defer close(done)
+	if err := ConntrackExpectSubscribe(ch, done, ConntrackSubscribeOptions{Namespace: ns}); err != nil {
+		t.Fatal(err)
+	}
+
+	master := &ConntrackFlow{
+		Forward: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			Protocol: TCP_PROTO,
+			SrcPort:  40000,
+			DstPort:  21,
+		},
+		Reverse: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			Protocol: TCP_PROTO,
+			SrcPort:  21,
+			DstPort:  40000,
+		},
+		TimeOut: 100,
+		// The kernel only accepts expectations of flows with a helper
+		Helper: "ftp",
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, master))
+
+	expect := &ConntrackExpect{
+		Master: master.Forward,
+		Tuple: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			Protocol: TCP_PROTO,
+			DstPort:  41000,
+		},
+		Mask: IPTuple{
+			SrcIP:   net.ParseIP("255.255.255.255"),
+			DstIP:   net.ParseIP("255.255.255.255"),
+			DstPort: 0xffff,
+		},
+	}
+	CheckErrorFail(t, h.ConntrackExpectAdd(unix.AF_INET, expect))
+	if !expectConntrackExpectEvent(ch, ConntrackEventNew, 41000) {
+		t.Fatal("New expectation event not received as expected")
+	}
+
+	expects, err := h.ConntrackExpectList(unix.AF_INET)
+	CheckErrorFail(t, err)
+	if len(expects) != 1 {
+		t.Fatalf("Found %d expectations instead of 1", len(expects))
+	}
+	res := expects[0]
+	if !res.Master.SrcIP.Equal(master.Forward.SrcIP) || res.Master.DstPort != 21 {
+		t.Fatalf("Wrong master tuple: %+v", res.Master)
+	}
+	if !res.Tuple.SrcIP.Equal(expect.Tuple.SrcIP) || res.Tuple.DstPort != 41000 || res.Tuple.Protocol != TCP_PROTO {
+		t.Fatalf("Wrong expected tuple: %+v", res.Tuple)
+	}
+	if res.Mask.DstPort != 0xffff || res.Mask.SrcPort != 0 {
+		t.Fatalf("Wrong mask: %+v", res.Mask)
+	}
+	if res.TimeOut == 0 {
+		t.Fatal("Missing expectation timeout")
+	}
+	if res.Helper != "ftp" {
+		t.Fatalf("Wrong helper %q, expected ftp", res.Helper)
+	}
+
+	CheckErrorFail(t, h.ConntrackExpectDel(unix.AF_INET, expect))
+	if !expectConntrackExpectEvent(ch, ConntrackEventDestroy, 41000) {
+		t.Fatal("Destroy expectation event not received as expected")
+	}
+	if err := h.ConntrackExpectDel(unix.AF_INET, expect); !errors.Is(err, unix.ENOENT) {
+		t.Fatalf("Deleting a deleted expectation should return ENOENT, got %v", err)
+	}
+
+	CheckErrorFail(t, h.ConntrackExpectAdd(unix.AF_INET, expect))
+	CheckErrorFail(t, h.ConntrackExpectFlush())
+	expects, err = h.ConntrackExpectList(unix.AF_INET)
+	CheckErrorFail(t, err)
+	if len(expects) != 0 {
+		t.Fatalf("Found %d expectations after the flush", len(expects))
+	}
+
+	// Switch back to the original namespace
+	netns.Set(*origns)
+}
+
+func TestConntrackExpectSerialize(t *testing.T) {
+	expect := ConntrackExpect{
+		FamilyType: unix.AF_INET6,
+		Master: IPTuple{
+			SrcIP:    net.ParseIP("2001:db8::1"),
+			DstIP:    net.ParseIP("2001:db8::2"),
+			Protocol: UDP_PROTO,
+			SrcPort:  5060,
+			DstPort:  5060,
+		},
+		Tuple: IPTuple{
+			SrcIP:    net.ParseIP("2001:db8::2"),
+			DstIP:    net.ParseIP("2001:db8::1"),
+			Protocol: UDP_PROTO,
+			DstPort:  16384,
+		},
+		Mask: IPTuple{
+			SrcIP:    net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+			DstIP:    net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+			Protocol: UDP_PROTO,
+			DstPort:  0xffff,
+		},
+		TimeOut: 30,
+		Zone:    2,
+		Flags:   nl.NF_CT_EXPECT_PERMANENT,
+		Class:   1,
+		Helper:  "sip",
+	}
+	attrs, err := expect.toNlData(unix.AF_INET6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte{unix.AF_INET6, 0, 0, 0}
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	res := parseConntrackExpect(data)
+	if !reflect.DeepEqual(*res, expect) {
+		t.Fatalf("Parsed expectation\n%+v\ndoes not match\n%+v", *res, expect)
+	}
+}
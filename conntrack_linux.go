@@ -2,12 +2,15 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
+	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
 
@@ -51,6 +54,13 @@ func ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*Conntra
 	return pkgHandle.ConntrackTableList(table, family)
 }
 
+// ConntrackTableListFilter returns the flows of a table of a specific family
+// matching the filter, the filtering is done by the kernel when supported
+// conntrack -L [table] [options]          List conntrack or expectation table
+func ConntrackTableListFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([]*ConntrackFlow, error) {
+	return pkgHandle.ConntrackTableListFilter(table, family, filter)
+}
+
 // ConntrackTableFlush flushes all the flows of a specified table
 // conntrack -F [table]            Flush table
 // The flush operation applies to all the family types
@@ -67,9 +77,9 @@ func ConntrackDeleteFilter(table ConntrackTableType, family InetFamily, filter C
 // ConntrackTableList returns the flow list of a table of a specific family using the netlink handle passed
 // conntrack -L [table] [options]          List conntrack or expectation table
 func (h *Handle) ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*ConntrackFlow, error) {
-	res, err := h.dumpConntrackTable(table, family)
-	if err != nil {
-		return nil, err
+	res, executeErr := h.dumpConntrackTable(table, family, nil)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	// Deserialize all the flows
@@ -78,7 +88,41 @@ func (h *Handle) ConntrackTableList(table ConntrackTableType, family InetFamily)
 		result = append(result, parseRawData(dataRaw))
 	}
 
-	return result, nil
+	return result, executeErr
+}
+
+// ConntrackTableListContext is like ConntrackTableList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackTableListContext(ctx context.Context, table ConntrackTableType, family InetFamily) ([]*ConntrackFlow, error) {
+	return h.withContext(ctx).ConntrackTableList(table, family)
+}
+
+// ConntrackTableListFilter returns the flows of a table of a specific family
+// matching the filter using the netlink handle passed. The mark, status, zone,
+// addresses, protocol and ports of the filter are also sent to the kernel so
+// that only the matching flows are dumped.
+// conntrack -L [table] [options]          List conntrack or expectation table
+func (h *Handle) ConntrackTableListFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([]*ConntrackFlow, error) {
+	res, executeErr := h.dumpConntrackTable(table, family, filter)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	var result []*ConntrackFlow
+	for _, dataRaw := range res {
+		flow := parseRawData(dataRaw)
+		if filter != nil && !filter.empty() && !filter.MatchConntrackFlow(flow) {
+			continue
+		}
+		result = append(result, flow)
+	}
+	return result, executeErr
+}
+
+// ConntrackTableListFilterContext is like ConntrackTableListFilter but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackTableListFilterContext(ctx context.Context, table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([]*ConntrackFlow, error) {
+	return h.withContext(ctx).ConntrackTableListFilter(table, family, filter)
 }
 
 // ConntrackTableFlush flushes all the flows of a specified table using the netlink handle passed
@@ -90,12 +134,20 @@ func (h *Handle) ConntrackTableFlush(table ConntrackTableType) error {
 	return err
 }
 
+// ConntrackTableFlushContext is like ConntrackTableFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackTableFlushContext(ctx context.Context, table ConntrackTableType) error {
+	return h.withContext(ctx).ConntrackTableFlush(table)
+}
+
 // ConntrackDeleteFilter deletes entries on the specified table on the base of the filter using the netlink handle passed
 // conntrack -D [table] parameters         Delete conntrack or expectation
 func (h *Handle) ConntrackDeleteFilter(table ConntrackTableType, family InetFamily, filter CustomConntrackFilter) (uint, error) {
-	res, err := h.dumpConntrackTable(table, family)
-	if err != nil {
-		return 0, err
+	// Let the kernel skip the flows that can't match the built-in filter
+	dumpFilter, _ := filter.(*ConntrackFilter)
+	res, executeErr := h.dumpConntrackTable(table, family, dumpFilter)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return 0, executeErr
 	}
 
 	var matched uint
@@ -110,7 +162,374 @@ func (h *Handle) ConntrackDeleteFilter(table ConntrackTableType, family InetFami
 		}
 	}
 
-	return matched, nil
+	return matched, executeErr
+}
+
+// ConntrackDeleteFilterContext is like ConntrackDeleteFilter but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackDeleteFilterContext(ctx context.Context, table ConntrackTableType, family InetFamily, filter CustomConntrackFilter) (uint, error) {
+	return h.withContext(ctx).ConntrackDeleteFilter(table, family, filter)
+}
+
+// ConntrackCreate creates a new conntrack flow in the desired table
+// conntrack -I [table]		Create a conntrack or expectation
+func ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return pkgHandle.ConntrackCreate(table, family, flow)
+}
+
+// ConntrackUpdate updates an existing conntrack flow in the desired table
+// conntrack -U [table]		Update a conntrack
+func ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return pkgHandle.ConntrackUpdate(table, family, flow)
+}
+
+// ConntrackGet fetches the conntrack flow matching the original tuple and zone of flow
+// conntrack -G [table] parameters		Get conntrack or expectation
+func ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return pkgHandle.ConntrackGet(table, family, flow)
+}
+
+// ConntrackCreate creates a new conntrack flow in the desired table using the netlink handle passed
+// conntrack -I [table]		Create a conntrack or expectation
+// Both tuples and a timeout are required.
+func (h *Handle) ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_NEW, unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL)
+	attrs, err := flow.toNlData(family)
+	if err != nil {
+		return err
+	}
+	for _, a := range attrs {
+		req.AddData(a)
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackCreateContext is like ConntrackCreate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackCreateContext(ctx context.Context, table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return h.withContext(ctx).ConntrackCreate(table, family, flow)
+}
+
+// ConntrackUpdate updates an existing conntrack flow in the desired table using the netlink handle passed
+// conntrack -U [table]		Update a conntrack
+// The flow is looked up by its original tuple, the fields left to their zero
+// value are not changed. SendMark and SendTimeOut set the mark and the timeout
+// to zero.
+func (h *Handle) ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_NEW, unix.NLM_F_ACK)
+	attrs, err := flow.toNlData(family)
+	if err != nil {
+		return err
+	}
+	for _, a := range attrs {
+		req.AddData(a)
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackUpdateContext is like ConntrackUpdate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackUpdateContext(ctx context.Context, table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return h.withContext(ctx).ConntrackUpdate(table, family, flow)
+}
+
+// ConntrackGet fetches the conntrack flow matching the original tuple and zone of flow using the netlink handle passed
+// conntrack -G [table] parameters		Get conntrack or expectation
+func (h *Handle) ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_GET, unix.NLM_F_ACK)
+	tuple, err := flow.Forward.toNlData(nl.CTA_TUPLE_ORIG, family)
+	if err != nil {
+		return nil, err
+	}
+	req.AddData(tuple)
+	if flow.Zone != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_ZONE, htons(flow.Zone)))
+	}
+	res, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no conntrack flow returned")
+	}
+	return parseRawData(res[0]), nil
+}
+
+// ConntrackGetContext is like ConntrackGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackGetContext(ctx context.Context, table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return h.withContext(ctx).ConntrackGet(table, family, flow)
+}
+
+// ConntrackCPUStatistics holds the conntrack statistics of one CPU, as found in
+// /proc/net/stat/nf_conntrack
+type ConntrackCPUStatistics struct {
+	CPU           uint16
+	Found         uint32
+	Invalid       uint32
+	Insert        uint32
+	InsertFailed  uint32
+	Drop          uint32
+	EarlyDrop     uint32
+	Error         uint32
+	SearchRestart uint32
+	ClashResolve  uint32
+	ChainTooLong  uint32
+}
+
+// ConntrackStatistics holds the size of the conntrack table and its per CPU
+// statistics
+type ConntrackStatistics struct {
+	Entries    uint32
+	MaxEntries uint32
+	CPUs       []ConntrackCPUStatistics
+}
+
+// ConntrackStats returns the number of flows, the maximum size of the table
+// and the per CPU statistics of conntrack
+// conntrack -S          Show statistics
+func ConntrackStats() (*ConntrackStatistics, error) {
+	return pkgHandle.ConntrackStats()
+}
+
+// ConntrackStats returns the number of flows, the maximum size of the table
+// and the per CPU statistics of conntrack using the netlink handle passed
+// conntrack -S          Show statistics
+func (h *Handle) ConntrackStats() (*ConntrackStatistics, error) {
+	req := h.newConntrackRequest(ConntrackTable, unix.AF_UNSPEC, nl.IPCTNL_MSG_CT_GET_STATS, unix.NLM_F_ACK)
+	msgs, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	if err != nil {
+		return nil, err
+	}
+	stats := &ConntrackStatistics{}
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofNfgenmsg:])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case nl.CTA_STATS_GLOBAL_ENTRIES:
+				stats.Entries = ntohl(attr.Value)
+			case nl.CTA_STATS_GLOBAL_MAX_ENTRIES:
+				stats.MaxEntries = ntohl(attr.Value)
+			}
+		}
+	}
+
+	req = h.newConntrackRequest(ConntrackTable, unix.AF_UNSPEC, nl.IPCTNL_MSG_CT_GET_STATS_CPU, unix.NLM_F_DUMP)
+	msgs, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		cpu, err := parseConntrackCPUStats(m)
+		if err != nil {
+			return nil, err
+		}
+		stats.CPUs = append(stats.CPUs, *cpu)
+	}
+	return stats, nil
+}
+
+// ConntrackStatsContext is like ConntrackStats but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) ConntrackStatsContext(ctx context.Context) (*ConntrackStatistics, error) {
+	return h.withContext(ctx).ConntrackStats()
+}
+
+func parseConntrackCPUStats(data []byte) (*ConntrackCPUStatistics, error) {
+	// The CPU is the resource id of the Nfgenmsg header
+	stats := &ConntrackCPUStatistics{CPU: ntohs(data[2:4])}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		value := ntohl(attr.Value)
+		switch attr.Attr.Type {
+		case nl.CTA_STATS_FOUND:
+			stats.Found = value
+		case nl.CTA_STATS_INVALID:
+			stats.Invalid = value
+		case nl.CTA_STATS_INSERT:
+			stats.Insert = value
+		case nl.CTA_STATS_INSERT_FAILED:
+			stats.InsertFailed = value
+		case nl.CTA_STATS_DROP:
+			stats.Drop = value
+		case nl.CTA_STATS_EARLY_DROP:
+			stats.EarlyDrop = value
+		case nl.CTA_STATS_ERROR:
+			stats.Error = value
+		case nl.CTA_STATS_SEARCH_RESTART:
+			stats.SearchRestart = value
+		case nl.CTA_STATS_CLASH_RESOLVE:
+			stats.ClashResolve = value
+		case nl.CTA_STATS_CHAIN_TOOLONG:
+			stats.ChainTooLong = value
+		}
+	}
+	return stats, nil
+}
+
+// ConntrackEventType is the type of a conntrack event, its value is the
+// NFNLGRP_CONNTRACK_* multicast group the event is delivered to
+type ConntrackEventType uint8
+
+const (
+	ConntrackEventNew     ConntrackEventType = nl.NFNLGRP_CONNTRACK_NEW
+	ConntrackEventUpdate  ConntrackEventType = nl.NFNLGRP_CONNTRACK_UPDATE
+	ConntrackEventDestroy ConntrackEventType = nl.NFNLGRP_CONNTRACK_DESTROY
+)
+
+func (t ConntrackEventType) String() string {
+	switch t {
+	case ConntrackEventNew:
+		return "NEW"
+	case ConntrackEventUpdate:
+		return "UPDATE"
+	case ConntrackEventDestroy:
+		return "DESTROY"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", uint8(t))
+	}
+}
+
+// ConntrackEvent is sent when a conntrack flow is created, updated or destroyed
+type ConntrackEvent struct {
+	Type ConntrackEventType
+	Flow *ConntrackFlow
+}
+
+// ConntrackSubscribeOptions contains a set of options to use with
+// ConntrackSubscribe.
+type ConntrackSubscribeOptions struct {
+	Namespace *netns.NsHandle
+	// ErrorCallback is called with the error that stops the subscription.
+	// It is also called with unix.ENOBUFS, without stopping the
+	// subscription, when the socket overran and events were lost.
+	ErrorCallback func(error)
+	// Events selects the types of event to receive, all of them when empty
+	Events []ConntrackEventType
+	// ReceiveBufferSize sets the socket receive buffer size, a larger buffer
+	// makes overruns less likely on busy hosts. ReceiveBufferForceSize uses
+	// SO_RCVBUFFORCE to go over net.core.rmem_max, which needs CAP_NET_ADMIN.
+	ReceiveBufferSize      int
+	ReceiveBufferForceSize bool
+}
+
+// ConntrackSubscribe takes a chan down which conntrack events will be sent
+// when flows are created, updated or destroyed. Close the 'done' chan to stop
+// subscription.
+// conntrack -E [table] [options]          Show events
+func ConntrackSubscribe(ch chan<- ConntrackEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return conntrackSubscribeAt(*options.Namespace, netns.None(), ConntrackTable, done, options,
+		func(tp ConntrackEventType, data []byte) {
+			ch <- ConntrackEvent{Type: tp, Flow: parseRawData(data)}
+		}, func() { close(ch) })
+}
+
+// conntrackSubscribeAt subscribes to the events of the conntrack or expect
+// table and passes the type and raw data of each event to deliver. closeCh
+// is called once no more events will be delivered.
+func conntrackSubscribeAt(newNs, curNs netns.NsHandle, table ConntrackTableType, done <-chan struct{}, options ConntrackSubscribeOptions,
+	deliver func(ConntrackEventType, []byte), closeCh func()) error {
+	events := options.Events
+	if len(events) == 0 {
+		events = []ConntrackEventType{ConntrackEventNew, ConntrackEventUpdate, ConntrackEventDestroy}
+	}
+	// The expectation groups follow the conntrack ones in the same order
+	var offset uint
+	if table == ConntrackExpectTable {
+		offset = nl.NFNLGRP_CONNTRACK_EXP_NEW - nl.NFNLGRP_CONNTRACK_NEW
+	}
+	groups := make([]uint, 0, len(events))
+	for _, e := range events {
+		if e < ConntrackEventNew || e > ConntrackEventDestroy {
+			return fmt.Errorf("invalid conntrack event type %d", e)
+		}
+		groups = append(groups, uint(e)+offset)
+	}
+
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_NETFILTER, groups...)
+	if err != nil {
+		return err
+	}
+	if options.ReceiveBufferSize > 0 {
+		opt := unix.SO_RCVBUF
+		if options.ReceiveBufferForceSize {
+			opt = unix.SO_RCVBUFFORCE
+		}
+		if err := unix.SetsockoptInt(s.GetFd(), unix.SOL_SOCKET, opt, options.ReceiveBufferSize); err != nil {
+			s.Close()
+			return err
+		}
+	}
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	cberr := options.ErrorCallback
+	go func() {
+		defer closeCh()
+		for {
+			msgs, err := s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				if err == unix.ENOBUFS {
+					continue
+				}
+				return
+			}
+			for _, m := range msgs {
+				if m.Header.Type == unix.NLMSG_DONE {
+					continue
+				}
+				if m.Header.Type == unix.NLMSG_ERROR {
+					native := nl.NativeEndian()
+					error := int32(native.Uint32(m.Data[0:4]))
+					if error == 0 {
+						continue
+					}
+					if cberr != nil {
+						cberr(syscall.Errno(-error))
+					}
+					return
+				}
+				if len(m.Data) < nl.SizeofNfgenmsg {
+					continue
+				}
+				// IPCTNL_MSG_EXP_NEW and IPCTNL_MSG_EXP_DELETE share the
+				// values of their conntrack counterparts
+				var tp ConntrackEventType
+				switch m.Header.Type & 0xff {
+				case nl.IPCTNL_MSG_CT_NEW:
+					tp = ConntrackEventUpdate
+					if m.Header.Flags&(unix.NLM_F_CREATE|unix.NLM_F_EXCL) != 0 {
+						tp = ConntrackEventNew
+					}
+				case nl.IPCTNL_MSG_CT_DELETE:
+					tp = ConntrackEventDestroy
+				default:
+					continue
+				}
+				deliver(tp, m.Data)
+			}
+		}
+	}()
+
+	return nil
 }
 
 func (h *Handle) newConntrackRequest(table ConntrackTableType, family InetFamily, operation, flags int) *nl.NetlinkRequest {
@@ -126,15 +545,20 @@ func (h *Handle) newConntrackRequest(table ConntrackTableType, family InetFamily
 	return req
 }
 
-func (h *Handle) dumpConntrackTable(table ConntrackTableType, family InetFamily) ([][]byte, error) {
+func (h *Handle) dumpConntrackTable(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([][]byte, error) {
 	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_GET, unix.NLM_F_DUMP)
+	if filter != nil && table == ConntrackTable {
+		for _, attr := range filter.toNlData(family) {
+			req.AddData(attr)
+		}
+	}
 	return req.Execute(unix.NETLINK_NETFILTER, 0)
 }
 
 // The full conntrack flow structure is very complicated and can be found in the file:
 // http://git.netfilter.org/libnetfilter_conntrack/tree/include/internal/object.h
 // For the time being, the structure below allows to parse and extract the base information of a flow
-type ipTuple struct {
+type IPTuple struct {
 	Bytes    uint64
 	DstIP    net.IP
 	DstPort  uint16
@@ -142,62 +566,283 @@ type ipTuple struct {
 	Protocol uint8
 	SrcIP    net.IP
 	SrcPort  uint16
+	// ICMPID, ICMPType and ICMPCode replace the ports of the ICMP and
+	// ICMPv6 flows
+	ICMPID   uint16
+	ICMPType uint8
+	ICMPCode uint8
+}
+
+// toNlData builds the nested tuple attribute of the given type
+// (CTA_TUPLE_ORIG or CTA_TUPLE_REPLY)
+func (t *IPTuple) toNlData(tupleType int, family InetFamily) (*nl.RtAttr, error) {
+	// The message structure is the following:
+	// <len, NLA_F_NESTED|tupleType>
+	// 	<len, NLA_F_NESTED|CTA_TUPLE_IP>
+	// 		<len, [CTA_IP_V4_SRC|CTA_IP_V6_SRC], the source IP>
+	// 		<len, [CTA_IP_V4_DST|CTA_IP_V6_DST], the destination IP>
+	// 	<len, NLA_F_NESTED|CTA_TUPLE_PROTO>
+	// 		<len, CTA_PROTO_NUM, 1 byte for the protocol>
+	// 		<len, CTA_PROTO_SRC_PORT, 2 bytes for the source port>
+	// 		<len, CTA_PROTO_DST_PORT, 2 bytes for the destination port>
+	// The ports are replaced by CTA_PROTO_ICMP_{ID,TYPE,CODE} for ICMP and
+	// CTA_PROTO_ICMPV6_{ID,TYPE,CODE} for ICMPv6, the other protocols have
+	// neither.
+	var srcType, dstType int
+	var src, dst net.IP
+	switch family {
+	case unix.AF_INET:
+		srcType, dstType = nl.CTA_IP_V4_SRC, nl.CTA_IP_V4_DST
+		src, dst = t.SrcIP.To4(), t.DstIP.To4()
+	case unix.AF_INET6:
+		srcType, dstType = nl.CTA_IP_V6_SRC, nl.CTA_IP_V6_DST
+		src, dst = t.SrcIP.To16(), t.DstIP.To16()
+	default:
+		return nil, fmt.Errorf("unsupported conntrack family %d", family)
+	}
+	if src == nil || dst == nil {
+		return nil, fmt.Errorf("invalid tuple addresses %s -> %s for family %d", t.SrcIP, t.DstIP, family)
+	}
+
+	tuple := nl.NewRtAttr(tupleType|nl.NLA_F_NESTED, nil)
+	ip := nl.NewRtAttrChild(tuple, nl.CTA_TUPLE_IP|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(ip, srcType, src)
+	nl.NewRtAttrChild(ip, dstType, dst)
+
+	proto := nl.NewRtAttrChild(tuple, nl.CTA_TUPLE_PROTO|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(proto, nl.CTA_PROTO_NUM, nl.Uint8Attr(t.Protocol))
+	switch t.Protocol {
+	case unix.IPPROTO_TCP, unix.IPPROTO_UDP, unix.IPPROTO_UDPLITE, unix.IPPROTO_SCTP, unix.IPPROTO_DCCP, unix.IPPROTO_GRE:
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_SRC_PORT, htons(t.SrcPort))
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_DST_PORT, htons(t.DstPort))
+	case unix.IPPROTO_ICMP:
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMP_ID, htons(t.ICMPID))
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMP_TYPE, nl.Uint8Attr(t.ICMPType))
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMP_CODE, nl.Uint8Attr(t.ICMPCode))
+	case unix.IPPROTO_ICMPV6:
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMPV6_ID, htons(t.ICMPID))
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMPV6_TYPE, nl.Uint8Attr(t.ICMPType))
+		nl.NewRtAttrChild(proto, nl.CTA_PROTO_ICMPV6_CODE, nl.Uint8Attr(t.ICMPCode))
+	}
+	return tuple, nil
+}
+
+// ProtoInfo is the layer 4 protocol specific information of a flow
+type ProtoInfo interface {
+	Protocol() string
+}
+
+// ProtoInfoTCP is the CTA_PROTOINFO_TCP information of a TCP flow
+type ProtoInfoTCP struct {
+	// State is one of the nl.TCP_CONNTRACK_* states
+	State       uint8
+	WScaleOrig  uint8
+	WScaleReply uint8
+}
+
+// Protocol returns "tcp"
+func (p *ProtoInfoTCP) Protocol() string { return "tcp" }
+
+var tcpConntrackStates = map[uint8]string{
+	nl.TCP_CONNTRACK_NONE:        "NONE",
+	nl.TCP_CONNTRACK_SYN_SENT:    "SYN_SENT",
+	nl.TCP_CONNTRACK_SYN_RECV:    "SYN_RECV",
+	nl.TCP_CONNTRACK_ESTABLISHED: "ESTABLISHED",
+	nl.TCP_CONNTRACK_FIN_WAIT:    "FIN_WAIT",
+	nl.TCP_CONNTRACK_CLOSE_WAIT:  "CLOSE_WAIT",
+	nl.TCP_CONNTRACK_LAST_ACK:    "LAST_ACK",
+	nl.TCP_CONNTRACK_TIME_WAIT:   "TIME_WAIT",
+	nl.TCP_CONNTRACK_CLOSE:       "CLOSE",
+	nl.TCP_CONNTRACK_SYN_SENT2:   "SYN_SENT2",
+}
+
+// StateString returns the state name as printed by the conntrack tool
+func (p *ProtoInfoTCP) StateString() string {
+	if name, ok := tcpConntrackStates[p.State]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", p.State)
+}
+
+// ConntrackSeqAdj is the TCP sequence adjustment of one direction of a
+// NATed flow whose payload was mangled by a helper
+type ConntrackSeqAdj struct {
+	CorrectionPos uint32
+	OffsetBefore  uint32
+	OffsetAfter   uint32
+}
+
+func (a *ConntrackSeqAdj) String() string {
+	return fmt.Sprintf("pos=%d before=%d after=%d", a.CorrectionPos, a.OffsetBefore, a.OffsetAfter)
 }
 
 type ConntrackFlow struct {
 	FamilyType uint8
-	Forward    ipTuple
-	Reverse    ipTuple
+	Forward    IPTuple
+	Reverse    IPTuple
 	Mark       uint32
+	Zone       uint16
+	// Status is a mask of the nl.IPS_* status bits
+	Status uint32
+	// TimeOut is the remaining lifetime of the flow in seconds
+	TimeOut uint32
+	ID      uint32
+	Use     uint32
+	// Mark and TimeOut are only sent by ConntrackCreate and ConntrackUpdate
+	// when non-zero, or when SendMark or SendTimeOut is set, e.g. to clear
+	// the mark of a flow
+	SendMark    bool
+	SendTimeOut bool
+	// Labels is a 128 bit bitmap of conntrack labels, LabelsMask
+	// selects the labels changed by ConntrackUpdate
+	Labels     []byte
+	LabelsMask []byte
+	ProtoInfo  ProtoInfo
+	// TimeStart and TimeStop are in nanoseconds since the epoch, they are
+	// only reported with net.netfilter.nf_conntrack_timestamp enabled
+	TimeStart   uint64
+	TimeStop    uint64
+	SeqAdjOrig  *ConntrackSeqAdj
+	SeqAdjReply *ConntrackSeqAdj
+	Helper      string
 }
 
 func (s *ConntrackFlow) String() string {
 	// conntrack cmd output:
-	// udp      17 src=127.0.0.1 dst=127.0.0.1 sport=4001 dport=1234 packets=5 bytes=532 [UNREPLIED] src=127.0.0.1 dst=127.0.0.1 sport=1234 dport=4001 packets=10 bytes=1078 mark=0
-	return fmt.Sprintf("%s\t%d src=%s dst=%s sport=%d dport=%d packets=%d bytes=%d\tsrc=%s dst=%s sport=%d dport=%d packets=%d bytes=%d mark=%d",
-		nl.L4ProtoMap[s.Forward.Protocol], s.Forward.Protocol,
-		s.Forward.SrcIP.String(), s.Forward.DstIP.String(), s.Forward.SrcPort, s.Forward.DstPort, s.Forward.Packets, s.Forward.Bytes,
-		s.Reverse.SrcIP.String(), s.Reverse.DstIP.String(), s.Reverse.SrcPort, s.Reverse.DstPort, s.Reverse.Packets, s.Reverse.Bytes,
-		s.Mark)
-}
-
-// This method parse the ip tuple structure
-// The message structure is the following:
-// <len, [CTA_IP_V4_SRC|CTA_IP_V6_SRC], 16 bytes for the IP>
-// <len, [CTA_IP_V4_DST|CTA_IP_V6_DST], 16 bytes for the IP>
-// <len, NLA_F_NESTED|nl.CTA_TUPLE_PROTO, 1 byte for the protocol, 3 bytes of padding>
-// <len, CTA_PROTO_SRC_PORT, 2 bytes for the source port, 2 bytes of padding>
-// <len, CTA_PROTO_DST_PORT, 2 bytes for the source port, 2 bytes of padding>
-func parseIpTuple(reader *bytes.Reader, tpl *ipTuple) uint8 {
-	for i := 0; i < 2; i++ {
-		_, t, _, v := parseNfAttrTLV(reader)
-		switch t {
-		case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
-			tpl.SrcIP = v
-		case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
-			tpl.DstIP = v
+	// tcp      6 431999 ESTABLISHED src=127.0.0.1 dst=127.0.0.1 sport=4001 dport=1234 packets=5 bytes=532 src=127.0.0.1 dst=127.0.0.1 sport=1234 dport=4001 packets=10 bytes=1078 [ASSURED] mark=0 zone=0 use=1
+	res := fmt.Sprintf("%s\t%d %d", nl.L4ProtoMap[s.Forward.Protocol], s.Forward.Protocol, s.TimeOut)
+	if tcp, ok := s.ProtoInfo.(*ProtoInfoTCP); ok {
+		res += " " + tcp.StateString()
+	}
+	res += fmt.Sprintf(" src=%s dst=%s sport=%d dport=%d packets=%d bytes=%d",
+		s.Forward.SrcIP, s.Forward.DstIP, s.Forward.SrcPort, s.Forward.DstPort, s.Forward.Packets, s.Forward.Bytes)
+	if s.Status != 0 && s.Status&nl.IPS_SEEN_REPLY == 0 {
+		res += " [UNREPLIED]"
+	}
+	res += fmt.Sprintf("\tsrc=%s dst=%s sport=%d dport=%d packets=%d bytes=%d",
+		s.Reverse.SrcIP, s.Reverse.DstIP, s.Reverse.SrcPort, s.Reverse.DstPort, s.Reverse.Packets, s.Reverse.Bytes)
+	if s.Status&nl.IPS_OFFLOAD != 0 {
+		res += " [OFFLOAD]"
+	} else if s.Status&nl.IPS_ASSURED != 0 {
+		res += " [ASSURED]"
+	}
+	res += fmt.Sprintf(" mark=%d zone=%d use=%d id=%d", s.Mark, s.Zone, s.Use, s.ID)
+	if len(s.Labels) > 0 {
+		res += fmt.Sprintf(" labels=%x", s.Labels)
+	}
+	if s.Helper != "" {
+		res += " helper=" + s.Helper
+	}
+	if s.SeqAdjOrig != nil {
+		res += fmt.Sprintf(" seqadj_orig=(%s)", s.SeqAdjOrig)
+	}
+	if s.SeqAdjReply != nil {
+		res += fmt.Sprintf(" seqadj_reply=(%s)", s.SeqAdjReply)
+	}
+	if s.TimeStart != 0 {
+		res += fmt.Sprintf(" start=%d", s.TimeStart)
+	}
+	if s.TimeStop != 0 {
+		res += fmt.Sprintf(" stop=%d", s.TimeStop)
+	}
+	return res
+}
+
+// toNlData builds the attributes of a create or update request. The tuples
+// are skipped when their addresses are unset, so that an update can address
+// the flow by its original tuple only.
+func (s *ConntrackFlow) toNlData(family InetFamily) ([]*nl.RtAttr, error) {
+	var attrs []*nl.RtAttr
+	forward, err := s.Forward.toNlData(nl.CTA_TUPLE_ORIG, family)
+	if err != nil {
+		return nil, err
+	}
+	attrs = append(attrs, forward)
+	if s.Reverse.SrcIP != nil || s.Reverse.DstIP != nil {
+		reverse, err := s.Reverse.toNlData(nl.CTA_TUPLE_REPLY, family)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, reverse)
+	}
+	if s.Status != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_STATUS, htonl(s.Status)))
+	}
+	if s.TimeOut != 0 || s.SendTimeOut {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_TIMEOUT, htonl(s.TimeOut)))
+	}
+	if s.Mark != 0 || s.SendMark {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_MARK, htonl(s.Mark)))
+	}
+	if s.Zone != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_ZONE, htons(s.Zone)))
+	}
+	if s.Helper != "" {
+		help := nl.NewRtAttr(nl.CTA_HELP|nl.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(help, nl.CTA_HELP_NAME, nl.ZeroTerminated(s.Helper))
+		attrs = append(attrs, help)
+	}
+	if tcp, ok := s.ProtoInfo.(*ProtoInfoTCP); ok && tcp.State != nl.TCP_CONNTRACK_NONE {
+		info := nl.NewRtAttr(nl.CTA_PROTOINFO|nl.NLA_F_NESTED, nil)
+		tcpInfo := nl.NewRtAttrChild(info, nl.CTA_PROTOINFO_TCP|nl.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(tcpInfo, nl.CTA_PROTOINFO_TCP_STATE, nl.Uint8Attr(tcp.State))
+		attrs = append(attrs, info)
+	}
+	if len(s.Labels) > 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_LABELS, s.Labels))
+		if len(s.LabelsMask) > 0 {
+			attrs = append(attrs, nl.NewRtAttr(nl.CTA_LABELS_MASK, s.LabelsMask))
 		}
 	}
-	// Skip the next 4 bytes  nl.NLA_F_NESTED|nl.CTA_TUPLE_PROTO
-	reader.Seek(4, seekCurrent)
-	_, t, _, v := parseNfAttrTLV(reader)
-	if t == nl.CTA_PROTO_NUM {
-		tpl.Protocol = uint8(v[0])
-	}
-	// Skip some padding 3 bytes
-	reader.Seek(3, seekCurrent)
-	for i := 0; i < 2; i++ {
-		_, t, _ := parseNfAttrTL(reader)
-		switch t {
-		case nl.CTA_PROTO_SRC_PORT:
-			parseBERaw16(reader, &tpl.SrcPort)
-		case nl.CTA_PROTO_DST_PORT:
-			parseBERaw16(reader, &tpl.DstPort)
+	return attrs, nil
+}
+
+// This method parse the ip tuple structure, see IPTuple.toNlData for the
+// layout of the message
+func parseIpTuple(data []byte, tpl *IPTuple) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_IP:
+			ips, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, ip := range ips {
+				switch ip.Attr.Type {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					tpl.SrcIP = net.IP(ip.Value)
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					tpl.DstIP = net.IP(ip.Value)
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			protos, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, proto := range protos {
+				switch proto.Attr.Type {
+				case nl.CTA_PROTO_NUM:
+					tpl.Protocol = proto.Value[0]
+				case nl.CTA_PROTO_SRC_PORT:
+					tpl.SrcPort = ntohs(proto.Value)
+				case nl.CTA_PROTO_DST_PORT:
+					tpl.DstPort = ntohs(proto.Value)
+				case nl.CTA_PROTO_ICMP_ID, nl.CTA_PROTO_ICMPV6_ID:
+					tpl.ICMPID = ntohs(proto.Value)
+				case nl.CTA_PROTO_ICMP_TYPE, nl.CTA_PROTO_ICMPV6_TYPE:
+					tpl.ICMPType = proto.Value[0]
+				case nl.CTA_PROTO_ICMP_CODE, nl.CTA_PROTO_ICMPV6_CODE:
+					tpl.ICMPCode = proto.Value[0]
+				}
+			}
 		}
-		// Skip some padding 2 byte
-		reader.Seek(2, seekCurrent)
 	}
-	return tpl.Protocol
+	return nil
 }
 
 func parseNfAttrTLV(r *bytes.Reader) (isNested bool, attrType, len uint16, value []byte) {
@@ -219,23 +864,17 @@ func parseNfAttrTL(r *bytes.Reader) (isNested bool, attrType, len uint16) {
 	return isNested, attrType, len
 }
 
-func parseBERaw16(r *bytes.Reader, v *uint16) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseBERaw64(r *bytes.Reader, v *uint64) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseByteAndPacketCounters(r *bytes.Reader) (bytes, packets uint64) {
-	for i := 0; i < 2; i++ {
-		switch _, t, _ := parseNfAttrTL(r); t {
+func parseByteAndPacketCounters(data []byte) (bytes, packets uint64) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
 		case nl.CTA_COUNTERS_BYTES:
-			parseBERaw64(r, &bytes)
+			bytes = binary.BigEndian.Uint64(attr.Value)
 		case nl.CTA_COUNTERS_PACKETS:
-			parseBERaw64(r, &packets)
-		default:
-			return
+			packets = binary.BigEndian.Uint64(attr.Value)
 		}
 	}
 	return
@@ -243,56 +882,132 @@ func parseByteAndPacketCounters(r *bytes.Reader) (bytes, packets uint64) {
 
 func parseRawData(data []byte) *ConntrackFlow {
 	s := &ConntrackFlow{}
-	var proto uint8
 	// First there is the Nfgenmsg header
 	// consume only the family field
-	reader := bytes.NewReader(data)
-	binary.Read(reader, nl.NativeEndian(), &s.FamilyType)
+	s.FamilyType = data[0]
 
-	// skip rest of the Netfilter header
-	reader.Seek(3, seekCurrent)
 	// The message structure is the following:
-	// <len, NLA_F_NESTED|CTA_TUPLE_ORIG> 4 bytes
-	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
-	// flow information of the forward flow
-	// <len, NLA_F_NESTED|CTA_TUPLE_REPLY> 4 bytes
-	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
-	// flow information of the reverse flow
-	for reader.Len() > 0 {
-		if nested, t, l := parseNfAttrTL(reader); nested {
-			switch t {
-			case nl.CTA_TUPLE_ORIG:
-				if nested, t, _ = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					proto = parseIpTuple(reader, &s.Forward)
-				}
-			case nl.CTA_TUPLE_REPLY:
-				if nested, t, _ = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					parseIpTuple(reader, &s.Reverse)
-				} else {
-					// Header not recognized skip it
-					reader.Seek(int64(l), seekCurrent)
-				}
-			case nl.CTA_COUNTERS_ORIG:
-				s.Forward.Bytes, s.Forward.Packets = parseByteAndPacketCounters(reader)
-			case nl.CTA_COUNTERS_REPLY:
-				s.Reverse.Bytes, s.Reverse.Packets = parseByteAndPacketCounters(reader)
+	// <len, NLA_F_NESTED|CTA_TUPLE_ORIG> flow information of the forward flow
+	// <len, NLA_F_NESTED|CTA_TUPLE_REPLY> flow information of the reverse flow
+	// followed by the other CTA_* attributes of the flow
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return s
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_ORIG:
+			parseIpTuple(attr.Value, &s.Forward)
+		case nl.CTA_TUPLE_REPLY:
+			parseIpTuple(attr.Value, &s.Reverse)
+		case nl.CTA_COUNTERS_ORIG:
+			s.Forward.Bytes, s.Forward.Packets = parseByteAndPacketCounters(attr.Value)
+		case nl.CTA_COUNTERS_REPLY:
+			s.Reverse.Bytes, s.Reverse.Packets = parseByteAndPacketCounters(attr.Value)
+		case nl.CTA_STATUS:
+			s.Status = ntohl(attr.Value)
+		case nl.CTA_TIMEOUT:
+			s.TimeOut = ntohl(attr.Value)
+		case nl.CTA_MARK:
+			s.Mark = ntohl(attr.Value)
+		case nl.CTA_LABELS:
+			s.Labels = attr.Value
+		case nl.CTA_ZONE:
+			s.Zone = ntohs(attr.Value)
+		case nl.CTA_ID:
+			s.ID = ntohl(attr.Value)
+		case nl.CTA_USE:
+			s.Use = ntohl(attr.Value)
+		case nl.CTA_PROTOINFO:
+			s.ProtoInfo = parseProtoInfo(attr.Value)
+		case nl.CTA_TIMESTAMP:
+			s.TimeStart, s.TimeStop = parseTimestamp(attr.Value)
+		case nl.CTA_SEQ_ADJ_ORIG:
+			s.SeqAdjOrig = parseSeqAdj(attr.Value)
+		case nl.CTA_SEQ_ADJ_REPLY:
+			s.SeqAdjReply = parseSeqAdj(attr.Value)
+		case nl.CTA_HELP:
+			s.Helper = parseHelperName(attr.Value)
+		}
+	}
+	return s
+}
+
+func parseProtoInfo(data []byte) ProtoInfo {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.CTA_PROTOINFO_TCP {
+			continue
+		}
+		tcpAttrs, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil
+		}
+		tcp := &ProtoInfoTCP{}
+		for _, a := range tcpAttrs {
+			switch a.Attr.Type {
+			case nl.CTA_PROTOINFO_TCP_STATE:
+				tcp.State = a.Value[0]
+			case nl.CTA_PROTOINFO_TCP_WSCALE_ORIGINAL:
+				tcp.WScaleOrig = a.Value[0]
+			case nl.CTA_PROTOINFO_TCP_WSCALE_REPLY:
+				tcp.WScaleReply = a.Value[0]
 			}
 		}
+		return tcp
+	}
+	return nil
+}
+
+func parseTimestamp(data []byte) (start, stop uint64) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.CTA_TIMESTAMP_START:
+			start = binary.BigEndian.Uint64(attr.Value)
+		case nl.CTA_TIMESTAMP_STOP:
+			stop = binary.BigEndian.Uint64(attr.Value)
+		}
+	}
+	return
+}
+
+func parseSeqAdj(data []byte) *ConntrackSeqAdj {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil
 	}
-	if proto == TCP_PROTO {
-		reader.Seek(64, seekCurrent)
-		_, t, _, v := parseNfAttrTLV(reader)
-		if t == nl.CTA_MARK {
-			s.Mark = uint32(v[3])
+	adj := &ConntrackSeqAdj{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.CTA_SEQADJ_CORRECTION_POS:
+			adj.CorrectionPos = ntohl(attr.Value)
+		case nl.CTA_SEQADJ_OFFSET_BEFORE:
+			adj.OffsetBefore = ntohl(attr.Value)
+		case nl.CTA_SEQADJ_OFFSET_AFTER:
+			adj.OffsetAfter = ntohl(attr.Value)
 		}
-	} else if proto == UDP_PROTO {
-		reader.Seek(16, seekCurrent)
-		_, t, _, v := parseNfAttrTLV(reader)
-		if t == nl.CTA_MARK {
-			s.Mark = uint32(v[3])
+	}
+	return adj
+}
+
+func parseHelperName(data []byte) string {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return ""
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.CTA_HELP_NAME {
+			return string(bytes.TrimRight(attr.Value, "\x00"))
 		}
 	}
-	return s
+	return ""
 }
 
 // Conntrack parameters and options:
@@ -326,11 +1041,20 @@ func parseRawData(data []byte) *ConntrackFlow {
 type ConntrackFilterType uint8
 
 const (
-	ConntrackOrigSrcIP = iota // -orig-src ip   Source address from original direction
-	ConntrackOrigDstIP        // -orig-dst ip   Destination address from original direction
-	ConntrackNatSrcIP         // -src-nat ip    Source NAT ip
-	ConntrackNatDstIP         // -dst-nat ip    Destination NAT ip
-	ConntrackNatAnyIP         // -any-nat ip    Source or destination NAT ip
+	ConntrackOrigSrcIP     = iota // -orig-src ip    Source address from original direction
+	ConntrackOrigDstIP            // -orig-dst ip    Destination address from original direction
+	ConntrackNatSrcIP             // -src-nat ip     Source NAT ip
+	ConntrackNatDstIP             // -dst-nat ip     Destination NAT ip
+	ConntrackNatAnyIP             // -any-nat ip     Source or destination NAT ip
+	ConntrackOrigSrcPort          // --sport port    Source port from original direction
+	ConntrackOrigDstPort          // --dport port    Destination port from original direction
+	ConntrackReplySrcPort         // --reply-port-src port  Source port from reply direction
+	ConntrackReplyDstPort         // --reply-port-dst port  Destination port from reply direction
+	ConntrackMatchLabels          // --label label   Flows having all the labels
+	ConntrackUnmatchLabels        // Flows having none of the labels
+
+	ConntrackReplySrcIP = ConntrackNatSrcIP // -reply-src ip  Source address from reply direction
+	ConntrackReplyDstIP = ConntrackNatDstIP // -reply-dst ip  Destination address from reply direction
 )
 
 type CustomConntrackFilter interface {
@@ -339,26 +1063,130 @@ type CustomConntrackFilter interface {
 	MatchConntrackFlow(flow *ConntrackFlow) bool
 }
 
+type conntrackMaskFilter struct {
+	value uint32
+	mask  uint32
+}
+
 type ConntrackFilter struct {
-	ipFilter map[ConntrackFilterType]net.IP
+	ipFilter     map[ConntrackFilterType]net.IP
+	portFilter   map[ConntrackFilterType]uint16
+	labelFilter  map[ConntrackFilterType][]byte
+	protoFilter  uint8
+	zoneFilter   *uint16
+	markFilter   *conntrackMaskFilter
+	statusFilter *conntrackMaskFilter
 }
 
+var errFilterPresent = errors.New("Filter attribute already present")
+
 // AddIP adds an IP to the conntrack filter
 func (f *ConntrackFilter) AddIP(tp ConntrackFilterType, ip net.IP) error {
+	switch tp {
+	case ConntrackOrigSrcIP, ConntrackOrigDstIP, ConntrackNatSrcIP, ConntrackNatDstIP, ConntrackNatAnyIP:
+	default:
+		return fmt.Errorf("invalid IP filter type %d", tp)
+	}
 	if f.ipFilter == nil {
 		f.ipFilter = make(map[ConntrackFilterType]net.IP)
 	}
 	if _, ok := f.ipFilter[tp]; ok {
-		return errors.New("Filter attribute already present")
+		return errFilterPresent
 	}
 	f.ipFilter[tp] = ip
 	return nil
 }
 
+// AddProtocol adds the layer 4 protocol number to the conntrack filter
+func (f *ConntrackFilter) AddProtocol(proto uint8) error {
+	if f.protoFilter != 0 {
+		return errFilterPresent
+	}
+	if proto == 0 {
+		return errors.New("invalid protocol 0")
+	}
+	f.protoFilter = proto
+	return nil
+}
+
+// AddPort adds a port to the conntrack filter, the protocol must be added
+// first since ports are only meaningful for a given protocol
+func (f *ConntrackFilter) AddPort(tp ConntrackFilterType, port uint16) error {
+	switch tp {
+	case ConntrackOrigSrcPort, ConntrackOrigDstPort, ConntrackReplySrcPort, ConntrackReplyDstPort:
+	default:
+		return fmt.Errorf("invalid port filter type %d", tp)
+	}
+	if f.protoFilter == 0 {
+		return errors.New("a protocol must be added to the filter before a port")
+	}
+	if f.portFilter == nil {
+		f.portFilter = make(map[ConntrackFilterType]uint16)
+	}
+	if _, ok := f.portFilter[tp]; ok {
+		return errFilterPresent
+	}
+	f.portFilter[tp] = port
+	return nil
+}
+
+// AddZone adds the conntrack zone to the conntrack filter
+func (f *ConntrackFilter) AddZone(zone uint16) error {
+	if f.zoneFilter != nil {
+		return errFilterPresent
+	}
+	f.zoneFilter = &zone
+	return nil
+}
+
+// AddMark adds a mark to the conntrack filter, the flows match when
+// flow.Mark & mask == mark
+func (f *ConntrackFilter) AddMark(mark, mask uint32) error {
+	if f.markFilter != nil {
+		return errFilterPresent
+	}
+	f.markFilter = &conntrackMaskFilter{value: mark & mask, mask: mask}
+	return nil
+}
+
+// AddStatus adds status bits to the conntrack filter, the flows match when
+// flow.Status & mask == status
+func (f *ConntrackFilter) AddStatus(status, mask uint32) error {
+	if f.statusFilter != nil {
+		return errFilterPresent
+	}
+	f.statusFilter = &conntrackMaskFilter{value: status & mask, mask: mask}
+	return nil
+}
+
+// AddLabels adds a label bitmap to the conntrack filter. With
+// ConntrackMatchLabels the flows match when they have all the labels set in
+// labels, with ConntrackUnmatchLabels when they have none of them.
+func (f *ConntrackFilter) AddLabels(tp ConntrackFilterType, labels []byte) error {
+	switch tp {
+	case ConntrackMatchLabels, ConntrackUnmatchLabels:
+	default:
+		return fmt.Errorf("invalid label filter type %d", tp)
+	}
+	if f.labelFilter == nil {
+		f.labelFilter = make(map[ConntrackFilterType][]byte)
+	}
+	if _, ok := f.labelFilter[tp]; ok {
+		return errFilterPresent
+	}
+	f.labelFilter[tp] = labels
+	return nil
+}
+
+func (f *ConntrackFilter) empty() bool {
+	return len(f.ipFilter) == 0 && len(f.portFilter) == 0 && len(f.labelFilter) == 0 &&
+		f.protoFilter == 0 && f.zoneFilter == nil && f.markFilter == nil && f.statusFilter == nil
+}
+
 // MatchConntrackFlow applies the filter to the flow and returns true if the flow matches the filter
 // false otherwise
 func (f *ConntrackFilter) MatchConntrackFlow(flow *ConntrackFlow) bool {
-	if len(f.ipFilter) == 0 {
+	if f.empty() {
 		// empty filter always not match
 		return false
 	}
@@ -389,7 +1217,164 @@ func (f *ConntrackFilter) MatchConntrackFlow(flow *ConntrackFlow) bool {
 		match = match && (elem.Equal(flow.Reverse.SrcIP) || elem.Equal(flow.Reverse.DstIP))
 	}
 
+	// -p, --protonum proto  Layer 4 Protocol
+	if f.protoFilter != 0 {
+		match = match && f.protoFilter == flow.Forward.Protocol
+	}
+
+	// --sport, --dport, --reply-port-src, --reply-port-dst port
+	if elem, found := f.portFilter[ConntrackOrigSrcPort]; match && found {
+		match = match && elem == flow.Forward.SrcPort
+	}
+	if elem, found := f.portFilter[ConntrackOrigDstPort]; match && found {
+		match = match && elem == flow.Forward.DstPort
+	}
+	if elem, found := f.portFilter[ConntrackReplySrcPort]; match && found {
+		match = match && elem == flow.Reverse.SrcPort
+	}
+	if elem, found := f.portFilter[ConntrackReplyDstPort]; match && found {
+		match = match && elem == flow.Reverse.DstPort
+	}
+
+	// -w, --zone value
+	if f.zoneFilter != nil {
+		match = match && *f.zoneFilter == flow.Zone
+	}
+
+	// -m, --mark mark[/mask]
+	if f.markFilter != nil {
+		match = match && flow.Mark&f.markFilter.mask == f.markFilter.value
+	}
+
+	// -u, --status status
+	if f.statusFilter != nil {
+		match = match && flow.Status&f.statusFilter.mask == f.statusFilter.value
+	}
+
+	// -l, --label label
+	if elem, found := f.labelFilter[ConntrackMatchLabels]; match && found {
+		match = match && labelsMatch(flow.Labels, elem, true)
+	}
+	if elem, found := f.labelFilter[ConntrackUnmatchLabels]; match && found {
+		match = match && labelsMatch(flow.Labels, elem, false)
+	}
+
 	return match
 }
 
+// labelsMatch returns whether all the bits of labels are set in the flow
+// labels when all is true, or whether none of them is set otherwise
+func labelsMatch(flowLabels, labels []byte, all bool) bool {
+	for i, l := range labels {
+		var fl byte
+		if i < len(flowLabels) {
+			fl = flowLabels[i]
+		}
+		if all && fl&l != l {
+			return false
+		}
+		if !all && fl&l != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// toNlData builds the attributes of a dump request filtered by the kernel.
+// Kernels without support for some of them dump the whole table, so the
+// result still has to go through MatchConntrackFlow.
+func (f *ConntrackFilter) toNlData(family InetFamily) []*nl.RtAttr {
+	var attrs []*nl.RtAttr
+	if f.markFilter != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_MARK, htonl(f.markFilter.value)))
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_MARK_MASK, htonl(f.markFilter.mask)))
+	}
+	if f.statusFilter != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_STATUS, htonl(f.statusFilter.value)))
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_STATUS_MASK, htonl(f.statusFilter.mask)))
+	}
+	if f.zoneFilter != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_ZONE, htons(*f.zoneFilter)))
+	}
+
+	// CTA_FILTER selects the fields of CTA_TUPLE_ORIG and CTA_TUPLE_REPLY
+	// that the flows have to match, it needs a layer 3 protocol
+	if family != unix.AF_INET && family != unix.AF_INET6 {
+		return attrs
+	}
+	orig, origFlags, ok := f.tupleFilter(nl.CTA_TUPLE_ORIG, family, ConntrackOrigSrcIP, ConntrackOrigDstIP, ConntrackOrigSrcPort, ConntrackOrigDstPort)
+	if !ok {
+		return attrs
+	}
+	reply, replyFlags, ok := f.tupleFilter(nl.CTA_TUPLE_REPLY, family, ConntrackReplySrcIP, ConntrackReplyDstIP, ConntrackReplySrcPort, ConntrackReplyDstPort)
+	if !ok {
+		return attrs
+	}
+	if origFlags == 0 && replyFlags == 0 {
+		return attrs
+	}
+	filter := nl.NewRtAttr(nl.CTA_FILTER|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(filter, nl.CTA_FILTER_ORIG_FLAGS, nl.Uint32Attr(origFlags))
+	nl.NewRtAttrChild(filter, nl.CTA_FILTER_REPLY_FLAGS, nl.Uint32Attr(replyFlags))
+	attrs = append(attrs, filter)
+	if origFlags != 0 {
+		attrs = append(attrs, orig)
+	}
+	if replyFlags != 0 {
+		attrs = append(attrs, reply)
+	}
+	return attrs
+}
+
+// tupleFilter builds the tuple attribute and the CTA_FILTER flags for one
+// direction, ok is false when the filter can't be expressed for the family
+func (f *ConntrackFilter) tupleFilter(tupleType int, family InetFamily, srcIP, dstIP, srcPort, dstPort ConntrackFilterType) (tuple *nl.RtAttr, flags uint32, ok bool) {
+	srcType, dstType := nl.CTA_IP_V4_SRC, nl.CTA_IP_V4_DST
+	toFamily := net.IP.To4
+	if family == unix.AF_INET6 {
+		srcType, dstType = nl.CTA_IP_V6_SRC, nl.CTA_IP_V6_DST
+		toFamily = net.IP.To16
+	}
+
+	tuple = nl.NewRtAttr(tupleType|nl.NLA_F_NESTED, nil)
+	ipAttr := nl.NewRtAttr(nl.CTA_TUPLE_IP|nl.NLA_F_NESTED, nil)
+	if ip, found := f.ipFilter[srcIP]; found {
+		if toFamily(ip) == nil {
+			return nil, 0, false
+		}
+		nl.NewRtAttrChild(ipAttr, srcType, toFamily(ip))
+		flags |= nl.CTA_FILTER_F_CTA_IP_SRC
+	}
+	if ip, found := f.ipFilter[dstIP]; found {
+		if toFamily(ip) == nil {
+			return nil, 0, false
+		}
+		nl.NewRtAttrChild(ipAttr, dstType, toFamily(ip))
+		flags |= nl.CTA_FILTER_F_CTA_IP_DST
+	}
+	if flags != 0 {
+		tuple.AddChild(ipAttr)
+	}
+
+	protoAttr := nl.NewRtAttr(nl.CTA_TUPLE_PROTO|nl.NLA_F_NESTED, nil)
+	if port, found := f.portFilter[srcPort]; found {
+		nl.NewRtAttrChild(protoAttr, nl.CTA_PROTO_SRC_PORT, htons(port))
+		flags |= nl.CTA_FILTER_F_CTA_PROTO_SRC_PORT
+	}
+	if port, found := f.portFilter[dstPort]; found {
+		nl.NewRtAttrChild(protoAttr, nl.CTA_PROTO_DST_PORT, htons(port))
+		flags |= nl.CTA_FILTER_F_CTA_PROTO_DST_PORT
+	}
+	// The protocol is required by the kernel to match the ports, and the
+	// original direction carries it otherwise
+	if f.protoFilter != 0 && (tupleType == nl.CTA_TUPLE_ORIG || flags&(nl.CTA_FILTER_F_CTA_PROTO_SRC_PORT|nl.CTA_FILTER_F_CTA_PROTO_DST_PORT) != 0) {
+		nl.NewRtAttrChild(protoAttr, nl.CTA_PROTO_NUM, nl.Uint8Attr(f.protoFilter))
+		flags |= nl.CTA_FILTER_F_CTA_PROTO_NUM
+	}
+	if flags&nl.CTA_FILTER_F_CTA_PROTO_NUM != 0 {
+		tuple.AddChild(protoAttr)
+	}
+	return tuple, flags, true
+}
+
 var _ CustomConntrackFilter = (*ConntrackFilter)(nil)
@@ -3,11 +3,16 @@
 package netlink
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/vishvananda/netlink/nl"
 	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
@@ -291,17 +296,671 @@ func TestConntrackTableDelete(t *testing.T) {
 	netns.Set(*origns)
 }
 
+// TestConntrackCreateUpdateGet creates a flow through netlink, then reads it
+// back, updates it and validates the result
+func TestConntrackCreateUpdateGet(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	flow := &ConntrackFlow{
+		Forward: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			Protocol: TCP_PROTO,
+			SrcPort:  1000,
+			DstPort:  2000,
+		},
+		Reverse: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			Protocol: TCP_PROTO,
+			SrcPort:  2000,
+			DstPort:  1000,
+		},
+		Mark:      5,
+		Zone:      3,
+		TimeOut:   100,
+		ProtoInfo: &ProtoInfoTCP{State: nl.TCP_CONNTRACK_ESTABLISHED},
+	}
+	if err := h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow); err != nil {
+		t.Fatalf("Failed to create flow: %v", err)
+	}
+	if err := h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow); !errors.Is(err, unix.EEXIST) {
+		t.Fatalf("Creating the flow twice should return EEXIST, got %v", err)
+	}
+
+	res, err := h.ConntrackGet(ConntrackTable, unix.AF_INET, flow)
+	CheckErrorFail(t, err)
+	if !res.Forward.SrcIP.Equal(flow.Forward.SrcIP) || !res.Forward.DstIP.Equal(flow.Forward.DstIP) ||
+		res.Forward.SrcPort != 1000 || res.Forward.DstPort != 2000 || res.Forward.Protocol != TCP_PROTO {
+		t.Fatalf("Wrong forward tuple: %+v", res.Forward)
+	}
+	if !res.Reverse.SrcIP.Equal(flow.Reverse.SrcIP) || res.Reverse.SrcPort != 2000 {
+		t.Fatalf("Wrong reverse tuple: %+v", res.Reverse)
+	}
+	if res.Mark != 5 {
+		t.Fatalf("Wrong mark %d, expected 5", res.Mark)
+	}
+	if res.TimeOut == 0 || res.TimeOut > 100 {
+		t.Fatalf("Wrong timeout %d, expected at most 100", res.TimeOut)
+	}
+	if res.Zone != 3 {
+		t.Fatalf("Wrong zone %d, expected 3", res.Zone)
+	}
+	if tcp, ok := res.ProtoInfo.(*ProtoInfoTCP); !ok || tcp.State != nl.TCP_CONNTRACK_ESTABLISHED {
+		t.Fatalf("Wrong protoinfo %+v, expected ESTABLISHED", res.ProtoInfo)
+	}
+	if res.ID == 0 || res.Use == 0 {
+		t.Fatalf("Missing id %d or use %d", res.ID, res.Use)
+	}
+
+	update := &ConntrackFlow{
+		Forward: flow.Forward,
+		Zone:    flow.Zone,
+		Mark:    10,
+		TimeOut: 200,
+	}
+	CheckErrorFail(t, h.ConntrackUpdate(ConntrackTable, unix.AF_INET, update))
+
+	res, err = h.ConntrackGet(ConntrackTable, unix.AF_INET, flow)
+	CheckErrorFail(t, err)
+	if res.Mark != 10 {
+		t.Fatalf("Wrong mark %d after update, expected 10", res.Mark)
+	}
+	if res.TimeOut <= 100 || res.TimeOut > 200 {
+		t.Fatalf("Wrong timeout %d after update, expected more than 100", res.TimeOut)
+	}
+
+	update = &ConntrackFlow{Forward: flow.Forward, Zone: flow.Zone, SendMark: true}
+	CheckErrorFail(t, h.ConntrackUpdate(ConntrackTable, unix.AF_INET, update))
+	res, err = h.ConntrackGet(ConntrackTable, unix.AF_INET, flow)
+	CheckErrorFail(t, err)
+	if res.Mark != 0 {
+		t.Fatalf("Wrong mark %d after clearing it", res.Mark)
+	}
+
+	ping := &ConntrackFlow{
+		Forward: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.3"),
+			Protocol: unix.IPPROTO_ICMP,
+			ICMPID:   42,
+			ICMPType: 8,
+		},
+		Reverse: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.3"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			Protocol: unix.IPPROTO_ICMP,
+			ICMPID:   42,
+		},
+		TimeOut: 30,
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, ping))
+	res, err = h.ConntrackGet(ConntrackTable, unix.AF_INET, ping)
+	CheckErrorFail(t, err)
+	if res.Forward.Protocol != unix.IPPROTO_ICMP || res.Forward.ICMPID != 42 || res.Forward.ICMPType != 8 {
+		t.Fatalf("Wrong ICMP forward tuple: %+v", res.Forward)
+	}
+
+	filter := &ConntrackFilter{}
+	filter.AddIP(ConntrackOrigSrcIP, flow.Forward.SrcIP)
+	deleted, err := h.ConntrackDeleteFilter(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if deleted != 1 {
+		t.Fatalf("Error deleted a wrong number of flows:%d instead of 1", deleted)
+	}
+	if _, err := h.ConntrackGet(ConntrackTable, unix.AF_INET, flow); !errors.Is(err, unix.ENOENT) {
+		t.Fatalf("Getting a deleted flow should return ENOENT, got %v", err)
+	}
+
+	// Switch back to the original namespace
+	netns.Set(*origns)
+}
+
+func expectConntrackEvent(ch <-chan ConntrackEvent, tp ConntrackEventType, src net.IP) (*ConntrackEvent, bool) {
+	for {
+		timeout := time.After(time.Minute)
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return nil, false
+			}
+			if event.Flow.Forward.SrcIP.Equal(src) {
+				return &event, event.Type == tp
+			}
+		case <-timeout:
+			return nil, false
+		}
+	}
+}
+
+// TestConntrackSubscribe checks that the selected events are received for the
+// flows of the subscribed namespace
+func TestConntrackSubscribe(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	ch := make(chan ConntrackEvent)
+	done := make(chan struct{})
+	defer close(done)
+	var lastError error
+	defer func() {
+		if lastError != nil {
+			t.Fatalf("Fatal error received during subscription: %v", lastError)
+		}
+	}()
+	if err := ConntrackSubscribe(ch, done, ConntrackSubscribeOptions{
+		Namespace: ns,
+		Events:    []ConntrackEventType{ConntrackEventNew, ConntrackEventDestroy},
+		ErrorCallback: func(err error) {
+			lastError = err
+		},
+		ReceiveBufferSize: 1 << 20,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	flow := &ConntrackFlow{
+		Forward: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			Protocol: UDP_PROTO,
+			SrcPort:  1000,
+			DstPort:  2000,
+		},
+		Reverse: IPTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			Protocol: UDP_PROTO,
+			SrcPort:  2000,
+			DstPort:  1000,
+		},
+		TimeOut: 100,
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow))
+	event, ok := expectConntrackEvent(ch, ConntrackEventNew, flow.Forward.SrcIP)
+	if !ok {
+		t.Fatalf("New event not received as expected: %+v", event)
+	}
+	if event.Flow.Forward.DstPort != 2000 || event.Flow.Reverse.SrcPort != 2000 {
+		t.Fatalf("Wrong flow in the new event: %s", event.Flow)
+	}
+
+	// Update events were not selected, the next event must be the destroy one
+	CheckErrorFail(t, h.ConntrackUpdate(ConntrackTable, unix.AF_INET, &ConntrackFlow{Forward: flow.Forward, Mark: 1}))
+	filter := &ConntrackFilter{}
+	filter.AddIP(ConntrackOrigSrcIP, flow.Forward.SrcIP)
+	_, err := h.ConntrackDeleteFilter(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if event, ok := expectConntrackEvent(ch, ConntrackEventDestroy, flow.Forward.SrcIP); !ok {
+		t.Fatalf("Destroy event not received as expected: %+v", event)
+	}
+}
+
+// TestConntrackTableListFilter creates flows through netlink and checks that
+// the filtered dumps and deletions only consider the matching ones
+func TestConntrackTableListFilter(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	newFlow := func(src string, proto uint8, dport uint16, zone uint16, mark uint32) *ConntrackFlow {
+		return &ConntrackFlow{
+			Forward: IPTuple{SrcIP: net.ParseIP(src), DstIP: net.ParseIP("10.0.1.1"), Protocol: proto, SrcPort: 4000, DstPort: dport},
+			Reverse: IPTuple{SrcIP: net.ParseIP("10.0.1.1"), DstIP: net.ParseIP(src), Protocol: proto, SrcPort: dport, DstPort: 4000},
+			Zone:    zone,
+			Mark:    mark,
+			TimeOut: 100,
+		}
+	}
+	flows := []*ConntrackFlow{
+		newFlow("10.0.0.1", UDP_PROTO, 53, 10, 0x11),
+		newFlow("10.0.0.2", UDP_PROTO, 53, 0, 0x12),
+		newFlow("10.0.0.3", TCP_PROTO, 53, 10, 0x23),
+		newFlow("10.0.0.4", UDP_PROTO, 54, 10, 0x24),
+	}
+	for _, flow := range flows {
+		CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow))
+	}
+
+	// The mark is filtered by the kernel
+	filter := &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddMark(0x10, 0xf0))
+	raw, err := h.dumpConntrackTable(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if len(raw) != 2 {
+		t.Fatalf("Kernel dumped %d flows instead of 2 for mark 0x10/0xf0", len(raw))
+	}
+	res, err := h.ConntrackTableListFilter(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if len(res) != 2 {
+		t.Fatalf("Found %d flows instead of 2 for mark 0x10/0xf0", len(res))
+	}
+
+	// All UDP flows to port 53 in zone 10
+	filter = &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddProtocol(UDP_PROTO))
+	CheckErrorFail(t, filter.AddPort(ConntrackOrigDstPort, 53))
+	CheckErrorFail(t, filter.AddZone(10))
+	res, err = h.ConntrackTableListFilter(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if len(res) != 1 || !res[0].Forward.SrcIP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("Wrong flows for udp dport 53 zone 10: %v", res)
+	}
+
+	deleted, err := h.ConntrackDeleteFilter(ConntrackTable, unix.AF_INET, filter)
+	CheckErrorFail(t, err)
+	if deleted != 1 {
+		t.Fatalf("Error deleted a wrong number of flows:%d instead of 1", deleted)
+	}
+	res, err = h.ConntrackTableListFilter(ConntrackTable, unix.AF_INET, nil)
+	CheckErrorFail(t, err)
+	if len(res) != 3 {
+		t.Fatalf("Found %d flows instead of 3 after the deletion", len(res))
+	}
+
+	// Switch back to the original namespace
+	netns.Set(*origns)
+}
+
+// TestConntrackStats checks the table count of a new namespace and that the
+// per CPU statistics are reported
+func TestConntrackStats(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	for i := uint16(0); i < 2; i++ {
+		flow := &ConntrackFlow{
+			Forward: IPTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), Protocol: UDP_PROTO, SrcPort: 1000 + i, DstPort: 53},
+			Reverse: IPTuple{SrcIP: net.ParseIP("10.0.0.2"), DstIP: net.ParseIP("10.0.0.1"), Protocol: UDP_PROTO, SrcPort: 53, DstPort: 1000 + i},
+			TimeOut: 100,
+		}
+		CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow))
+	}
+
+	stats, err := h.ConntrackStats()
+	CheckErrorFail(t, err)
+	if stats.Entries != 2 {
+		t.Fatalf("Found %d entries instead of 2", stats.Entries)
+	}
+	if stats.MaxEntries == 0 {
+		t.Fatal("Missing the maximum number of entries")
+	}
+	if len(stats.CPUs) == 0 {
+		t.Fatal("Missing the per CPU statistics")
+	}
+
+	// Switch back to the original namespace
+	netns.Set(*origns)
+}
+
+func TestConntrackCPUStatsParse(t *testing.T) {
+	// Nfgenmsg with the CPU as resource id
+	data := []byte{unix.AF_UNSPEC, nl.NFNETLINK_V0, 0, 3}
+	for _, a := range []struct {
+		tp    int
+		value uint32
+	}{
+		{nl.CTA_STATS_FOUND, 1},
+		{nl.CTA_STATS_INVALID, 2},
+		{nl.CTA_STATS_INSERT, 3},
+		{nl.CTA_STATS_INSERT_FAILED, 4},
+		{nl.CTA_STATS_DROP, 5},
+		{nl.CTA_STATS_EARLY_DROP, 6},
+		{nl.CTA_STATS_ERROR, 7},
+		{nl.CTA_STATS_SEARCH_RESTART, 8},
+		{nl.CTA_STATS_CLASH_RESOLVE, 9},
+		{nl.CTA_STATS_CHAIN_TOOLONG, 10},
+	} {
+		data = append(data, nl.NewRtAttr(a.tp, htonl(a.value)).Serialize()...)
+	}
+	stats, err := parseConntrackCPUStats(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ConntrackCPUStatistics{
+		CPU:           3,
+		Found:         1,
+		Invalid:       2,
+		Insert:        3,
+		InsertFailed:  4,
+		Drop:          5,
+		EarlyDrop:     6,
+		Error:         7,
+		SearchRestart: 8,
+		ClashResolve:  9,
+		ChainTooLong:  10,
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("Parsed statistics\n%+v\ndo not match\n%+v", stats, expected)
+	}
+}
+
+// TestConntrackFlowSerialize checks that a serialized flow is parsed back to
+// the same content
+func TestConntrackFlowSerialize(t *testing.T) {
+	flows := []struct {
+		family InetFamily
+		flow   ConntrackFlow
+	}{
+		{
+			family: unix.AF_INET,
+			flow: ConntrackFlow{
+				FamilyType: unix.AF_INET,
+				Forward: IPTuple{
+					SrcIP:    net.ParseIP("192.168.0.1").To4(),
+					DstIP:    net.ParseIP("192.168.0.2").To4(),
+					Protocol: TCP_PROTO,
+					SrcPort:  1234,
+					DstPort:  80,
+				},
+				Reverse: IPTuple{
+					SrcIP:    net.ParseIP("192.168.0.2").To4(),
+					DstIP:    net.ParseIP("192.168.0.1").To4(),
+					Protocol: TCP_PROTO,
+					SrcPort:  80,
+					DstPort:  1234,
+				},
+				Mark:      0xabcd,
+				Zone:      7,
+				Status:    0x8,
+				TimeOut:   120,
+				Labels:    []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80},
+				ProtoInfo: &ProtoInfoTCP{State: nl.TCP_CONNTRACK_ESTABLISHED},
+				Helper:    "ftp",
+			},
+		},
+		{
+			family: unix.AF_INET6,
+			flow: ConntrackFlow{
+				FamilyType: unix.AF_INET6,
+				Forward: IPTuple{
+					SrcIP:    net.ParseIP("2001:db8::1"),
+					DstIP:    net.ParseIP("2001:db8::2"),
+					Protocol: UDP_PROTO,
+					SrcPort:  5353,
+					DstPort:  53,
+				},
+				TimeOut: 30,
+			},
+		},
+		{
+			family: unix.AF_INET,
+			flow: ConntrackFlow{
+				FamilyType: unix.AF_INET,
+				Forward: IPTuple{
+					SrcIP:    net.ParseIP("192.168.0.1").To4(),
+					DstIP:    net.ParseIP("192.168.0.2").To4(),
+					Protocol: unix.IPPROTO_ICMP,
+					ICMPID:   7,
+					ICMPType: 8,
+				},
+				TimeOut: 30,
+			},
+		},
+		{
+			family: unix.AF_INET6,
+			flow: ConntrackFlow{
+				FamilyType: unix.AF_INET6,
+				Forward: IPTuple{
+					SrcIP:    net.ParseIP("2001:db8::1"),
+					DstIP:    net.ParseIP("2001:db8::2"),
+					Protocol: unix.IPPROTO_ICMPV6,
+					ICMPID:   7,
+					ICMPType: 128,
+				},
+				TimeOut: 30,
+			},
+		},
+	}
+
+	for _, tt := range flows {
+		attrs, err := tt.flow.toNlData(tt.family)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := []byte{uint8(tt.family), 0, 0, 0}
+		for _, attr := range attrs {
+			data = append(data, attr.Serialize()...)
+		}
+		res := parseRawData(data)
+		if !reflect.DeepEqual(*res, tt.flow) {
+			t.Fatalf("Parsed flow\n%+v\ndoes not match\n%+v", *res, tt.flow)
+		}
+	}
+
+	// The kernel rejects the ports of the ICMP tuples
+	ping := &IPTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), Protocol: unix.IPPROTO_ICMP, SrcPort: 1}
+	tuple, err := ping.toNlData(nl.CTA_TUPLE_ORIG, unix.AF_INET)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := nl.ParseRouteAttr(tuple.Serialize()[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.CTA_TUPLE_PROTO {
+			continue
+		}
+		protos, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, proto := range protos {
+			if proto.Attr.Type == nl.CTA_PROTO_SRC_PORT || proto.Attr.Type == nl.CTA_PROTO_DST_PORT {
+				t.Fatal("ICMP tuple should not hold ports")
+			}
+		}
+	}
+
+	bad := &ConntrackFlow{Forward: IPTuple{SrcIP: net.ParseIP("2001:db8::1"), DstIP: net.ParseIP("10.0.0.1")}}
+	if _, err := bad.toNlData(unix.AF_INET); err == nil {
+		t.Fatal("Expected an error serializing an IPv6 tuple as AF_INET")
+	}
+}
+
+// TestConntrackFlowParse checks the parsing of the attributes only reported
+// by the kernel
+func TestConntrackFlowParse(t *testing.T) {
+	data := []byte{unix.AF_INET, 0, 0, 0}
+	add := func(attr *nl.RtAttr) {
+		data = append(data, attr.Serialize()...)
+	}
+
+	add(nl.NewRtAttr(nl.CTA_ID, htonl(0x1234)))
+	add(nl.NewRtAttr(nl.CTA_USE, htonl(2)))
+
+	counters := nl.NewRtAttr(nl.CTA_COUNTERS_ORIG|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(counters, nl.CTA_COUNTERS_PACKETS, []byte{0, 0, 0, 0, 0, 0, 0, 3})
+	nl.NewRtAttrChild(counters, nl.CTA_COUNTERS_BYTES, []byte{0, 0, 0, 0, 0, 0, 1, 0})
+	add(counters)
+
+	info := nl.NewRtAttr(nl.CTA_PROTOINFO|nl.NLA_F_NESTED, nil)
+	tcpInfo := nl.NewRtAttrChild(info, nl.CTA_PROTOINFO_TCP|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(tcpInfo, nl.CTA_PROTOINFO_TCP_STATE, nl.Uint8Attr(nl.TCP_CONNTRACK_TIME_WAIT))
+	nl.NewRtAttrChild(tcpInfo, nl.CTA_PROTOINFO_TCP_WSCALE_ORIGINAL, nl.Uint8Attr(7))
+	nl.NewRtAttrChild(tcpInfo, nl.CTA_PROTOINFO_TCP_WSCALE_REPLY, nl.Uint8Attr(9))
+	nl.NewRtAttrChild(tcpInfo, nl.CTA_PROTOINFO_TCP_FLAGS_ORIGINAL, []byte{0x23, 0x23})
+	add(info)
+
+	tstamp := nl.NewRtAttr(nl.CTA_TIMESTAMP|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(tstamp, nl.CTA_TIMESTAMP_START, []byte{0, 0, 0, 0, 0, 0, 0x10, 0})
+	nl.NewRtAttrChild(tstamp, nl.CTA_TIMESTAMP_STOP, []byte{0, 0, 0, 0, 0, 0, 0x20, 0})
+	add(tstamp)
+
+	seqadj := nl.NewRtAttr(nl.CTA_SEQ_ADJ_REPLY|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(seqadj, nl.CTA_SEQADJ_CORRECTION_POS, htonl(100))
+	nl.NewRtAttrChild(seqadj, nl.CTA_SEQADJ_OFFSET_BEFORE, htonl(4))
+	nl.NewRtAttrChild(seqadj, nl.CTA_SEQADJ_OFFSET_AFTER, htonl(8))
+	add(seqadj)
+
+	help := nl.NewRtAttr(nl.CTA_HELP|nl.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(help, nl.CTA_HELP_NAME, nl.ZeroTerminated("ftp"))
+	add(help)
+
+	flow := parseRawData(data)
+	expected := &ConntrackFlow{
+		FamilyType: unix.AF_INET,
+		Forward:    IPTuple{Packets: 3, Bytes: 256},
+		ID:         0x1234,
+		Use:        2,
+		ProtoInfo:  &ProtoInfoTCP{State: nl.TCP_CONNTRACK_TIME_WAIT, WScaleOrig: 7, WScaleReply: 9},
+		TimeStart:  0x1000,
+		TimeStop:   0x2000,
+		SeqAdjReply: &ConntrackSeqAdj{
+			CorrectionPos: 100,
+			OffsetBefore:  4,
+			OffsetAfter:   8,
+		},
+		Helper: "ftp",
+	}
+	if !reflect.DeepEqual(flow, expected) {
+		t.Fatalf("Parsed flow\n%+v\ndoes not match\n%+v", flow, expected)
+	}
+	str := flow.String()
+	for _, want := range []string{"TIME_WAIT", "packets=3 bytes=256", "use=2", "helper=ftp", "seqadj_reply=(pos=100 before=4 after=8)", "start=4096 stop=8192"} {
+		if !strings.Contains(str, want) {
+			t.Fatalf("%q does not contain %q", str, want)
+		}
+	}
+}
+
+func TestConntrackFilterFields(t *testing.T) {
+	flows := []ConntrackFlow{
+		{
+			Forward: IPTuple{Protocol: UDP_PROTO, SrcPort: 4000, DstPort: 53},
+			Reverse: IPTuple{Protocol: UDP_PROTO, SrcPort: 53, DstPort: 4000},
+			Zone:    10,
+			Mark:    0x11,
+			Status:  nl.IPS_CONFIRMED | nl.IPS_SEEN_REPLY,
+			Labels:  []byte{0x3},
+		},
+		{
+			Forward: IPTuple{Protocol: UDP_PROTO, SrcPort: 4001, DstPort: 53},
+			Reverse: IPTuple{Protocol: UDP_PROTO, SrcPort: 53, DstPort: 4001},
+			Mark:    0x21,
+			Status:  nl.IPS_CONFIRMED,
+			Labels:  []byte{0x1},
+		},
+		{
+			Forward: IPTuple{Protocol: TCP_PROTO, SrcPort: 4002, DstPort: 53},
+			Reverse: IPTuple{Protocol: TCP_PROTO, SrcPort: 53, DstPort: 4002},
+			Zone:    10,
+			Status:  nl.IPS_CONFIRMED | nl.IPS_SEEN_REPLY | nl.IPS_ASSURED,
+		},
+	}
+	count := func(filter *ConntrackFilter) int {
+		var n int
+		for i := range flows {
+			if filter.MatchConntrackFlow(&flows[i]) {
+				n++
+			}
+		}
+		return n
+	}
+
+	filter := &ConntrackFilter{}
+	if err := filter.AddPort(ConntrackOrigDstPort, 53); err == nil {
+		t.Fatal("Adding a port without a protocol should fail")
+	}
+	if count(filter) != 0 {
+		t.Fatal("An empty filter should not match")
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(f *ConntrackFilter) error
+		expect int
+	}{
+		{"protocol", func(f *ConntrackFilter) error { return f.AddProtocol(UDP_PROTO) }, 2},
+		{"orig dport", func(f *ConntrackFilter) error {
+			f.AddProtocol(TCP_PROTO)
+			return f.AddPort(ConntrackOrigDstPort, 53)
+		}, 1},
+		{"reply dport", func(f *ConntrackFilter) error {
+			f.AddProtocol(UDP_PROTO)
+			return f.AddPort(ConntrackReplyDstPort, 4001)
+		}, 1},
+		{"zone", func(f *ConntrackFilter) error { return f.AddZone(10) }, 2},
+		{"zone 0", func(f *ConntrackFilter) error { return f.AddZone(0) }, 1},
+		{"mark", func(f *ConntrackFilter) error { return f.AddMark(0x1, 0xf) }, 2},
+		{"mark mask", func(f *ConntrackFilter) error { return f.AddMark(0x10, 0xf0) }, 1},
+		{"status", func(f *ConntrackFilter) error {
+			return f.AddStatus(nl.IPS_SEEN_REPLY, nl.IPS_SEEN_REPLY|nl.IPS_ASSURED)
+		}, 1},
+		{"labels", func(f *ConntrackFilter) error { return f.AddLabels(ConntrackMatchLabels, []byte{0x1}) }, 2},
+		{"all labels", func(f *ConntrackFilter) error { return f.AddLabels(ConntrackMatchLabels, []byte{0x3}) }, 1},
+		{"unmatch labels", func(f *ConntrackFilter) error { return f.AddLabels(ConntrackUnmatchLabels, []byte{0x2}) }, 2},
+		{"udp port 53 zone 10", func(f *ConntrackFilter) error {
+			f.AddProtocol(UDP_PROTO)
+			f.AddPort(ConntrackOrigDstPort, 53)
+			return f.AddZone(10)
+		}, 1},
+	}
+	for _, tt := range tests {
+		filter := &ConntrackFilter{}
+		if err := tt.setup(filter); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if n := count(filter); n != tt.expect {
+			t.Fatalf("%s: matched %d flows instead of %d", tt.name, n, tt.expect)
+		}
+	}
+
+	filter = &ConntrackFilter{}
+	filter.AddZone(1)
+	if err := filter.AddZone(2); err == nil {
+		t.Fatal("Adding the zone twice should fail")
+	}
+}
+
 func TestConntrackFilter(t *testing.T) {
 	var flowList []ConntrackFlow
 	flowList = append(flowList, ConntrackFlow{
 		FamilyType: unix.AF_INET,
-		Forward: ipTuple{
+		Forward: IPTuple{
 			SrcIP:   net.ParseIP("10.0.0.1"),
 			DstIP:   net.ParseIP("20.0.0.1"),
 			SrcPort: 1000,
 			DstPort: 2000,
 		},
-		Reverse: ipTuple{
+		Reverse: IPTuple{
 			SrcIP:   net.ParseIP("20.0.0.1"),
 			DstIP:   net.ParseIP("192.168.1.1"),
 			SrcPort: 2000,
@@ -310,13 +969,13 @@ func TestConntrackFilter(t *testing.T) {
 	},
 		ConntrackFlow{
 			FamilyType: unix.AF_INET,
-			Forward: ipTuple{
+			Forward: IPTuple{
 				SrcIP:   net.ParseIP("10.0.0.2"),
 				DstIP:   net.ParseIP("20.0.0.2"),
 				SrcPort: 5000,
 				DstPort: 6000,
 			},
-			Reverse: ipTuple{
+			Reverse: IPTuple{
 				SrcIP:   net.ParseIP("20.0.0.2"),
 				DstIP:   net.ParseIP("192.168.1.1"),
 				SrcPort: 6000,
@@ -325,13 +984,13 @@ func TestConntrackFilter(t *testing.T) {
 		},
 		ConntrackFlow{
 			FamilyType: unix.AF_INET6,
-			Forward: ipTuple{
+			Forward: IPTuple{
 				SrcIP:   net.ParseIP("eeee:eeee:eeee:eeee:eeee:eeee:eeee:eeee"),
 				DstIP:   net.ParseIP("dddd:dddd:dddd:dddd:dddd:dddd:dddd:dddd"),
 				SrcPort: 1000,
 				DstPort: 2000,
 			},
-			Reverse: ipTuple{
+			Reverse: IPTuple{
 				SrcIP:   net.ParseIP("dddd:dddd:dddd:dddd:dddd:dddd:dddd:dddd"),
 				DstIP:   net.ParseIP("eeee:eeee:eeee:eeee:eeee:eeee:eeee:eeee"),
 				SrcPort: 2000,
@@ -14,12 +14,25 @@ type ConntrackFlow struct{}
 // ConntrackFilter placeholder
 type ConntrackFilter struct{}
 
+// ConntrackExpect placeholder
+type ConntrackExpect struct{}
+
+// ConntrackStatistics placeholder
+type ConntrackStatistics struct{}
+
 // ConntrackTableList returns the flow list of a table of a specific family
 // conntrack -L [table] [options]          List conntrack or expectation table
 func ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*ConntrackFlow, error) {
 	return nil, ErrNotImplemented
 }
 
+// ConntrackTableListFilter returns the flows of a table of a specific family
+// matching the filter, the filtering is done by the kernel when supported
+// conntrack -L [table] [options]          List conntrack or expectation table
+func ConntrackTableListFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([]*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
 // ConntrackTableFlush flushes all the flows of a specified table
 // conntrack -F [table]            Flush table
 // The flush operation applies to all the family types
@@ -39,6 +52,13 @@ func (h *Handle) ConntrackTableList(table ConntrackTableType, family InetFamily)
 	return nil, ErrNotImplemented
 }
 
+// ConntrackTableListFilter returns the flows of a table of a specific family
+// matching the filter using the netlink handle passed
+// conntrack -L [table] [options]          List conntrack or expectation table
+func (h *Handle) ConntrackTableListFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) ([]*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
 // ConntrackTableFlush flushes all the flows of a specified table using the netlink handle passed
 // conntrack -F [table]            Flush table
 // The flush operation applies to all the family types
@@ -51,3 +71,101 @@ func (h *Handle) ConntrackTableFlush(table ConntrackTableType) error {
 func (h *Handle) ConntrackDeleteFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) (uint, error) {
 	return 0, ErrNotImplemented
 }
+
+// ConntrackCreate creates a new conntrack flow in the desired table
+// conntrack -I [table]		Create a conntrack or expectation
+func ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackUpdate updates an existing conntrack flow in the desired table
+// conntrack -U [table]		Update a conntrack
+func ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackGet fetches the conntrack flow matching the original tuple and zone of flow
+// conntrack -G [table] parameters		Get conntrack or expectation
+func ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackCreate creates a new conntrack flow in the desired table using the netlink handle passed
+// conntrack -I [table]		Create a conntrack or expectation
+func (h *Handle) ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackUpdate updates an existing conntrack flow in the desired table using the netlink handle passed
+// conntrack -U [table]		Update a conntrack
+func (h *Handle) ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackGet fetches the conntrack flow matching the original tuple and zone of flow using the netlink handle passed
+// conntrack -G [table] parameters		Get conntrack or expectation
+func (h *Handle) ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpectList returns the expectations of a specific family
+// conntrack -L expect [options]          List expectation table
+func ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpectAdd creates a new expectation
+// conntrack -I expect parameters          Create an expectation
+func ConntrackExpectAdd(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectDel deletes the expectation matching the tuple of expect
+// conntrack -D expect parameters          Delete an expectation
+func ConntrackExpectDel(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectFlush deletes all the expectations
+// conntrack -F expect          Flush expectation table
+func ConntrackExpectFlush() error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectList returns the expectations of a specific family using the netlink handle passed
+// conntrack -L expect [options]          List expectation table
+func (h *Handle) ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpectAdd creates a new expectation using the netlink handle passed
+// conntrack -I expect parameters          Create an expectation
+func (h *Handle) ConntrackExpectAdd(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectDel deletes the expectation matching the tuple of expect using the netlink handle passed
+// conntrack -D expect parameters          Delete an expectation
+func (h *Handle) ConntrackExpectDel(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectFlush deletes all the expectations using the netlink handle passed
+// conntrack -F expect          Flush expectation table
+func (h *Handle) ConntrackExpectFlush() error {
+	return ErrNotImplemented
+}
+
+// ConntrackStats returns the number of flows, the maximum size of the table
+// and the per CPU statistics of conntrack
+// conntrack -S          Show statistics
+func ConntrackStats() (*ConntrackStatistics, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackStats returns the number of flows, the maximum size of the table
+// and the per CPU statistics of conntrack using the netlink handle passed
+// conntrack -S          Show statistics
+func (h *Handle) ConntrackStats() (*ConntrackStatistics, error) {
+	return nil, ErrNotImplemented
+}
@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"fmt"
+	"net"
 )
 
 type Filter interface {
@@ -14,14 +15,40 @@ type Filter interface {
 // parent == HANDLE_ROOT.
 type FilterAttrs struct {
 	LinkIndex int
-	Handle    uint32
-	Parent    uint32
-	Priority  uint16 // lower is higher priority
-	Protocol  uint16 // unix.ETH_P_*
+	// Block attaches the filter to the shared block with this index
+	// instead of LinkIndex and Parent.
+	Block    uint32
+	Handle   uint32
+	Parent   uint32
+	Priority uint16 // lower is higher priority
+	Protocol uint16 // unix.ETH_P_*
+	// Chain is the filter chain, nil means chain 0.
+	Chain *uint32
 }
 
 func (q FilterAttrs) String() string {
-	return fmt.Sprintf("{LinkIndex: %d, Handle: %s, Parent: %s, Priority: %d, Protocol: %d}", q.LinkIndex, HandleStr(q.Handle), HandleStr(q.Parent), q.Priority, q.Protocol)
+	chain := "none"
+	if q.Chain != nil {
+		chain = fmt.Sprintf("%d", *q.Chain)
+	}
+	return fmt.Sprintf("{LinkIndex: %d, Block: %d, Handle: %s, Parent: %s, Priority: %d, Protocol: %d, Chain: %s}", q.LinkIndex, q.Block, HandleStr(q.Handle), HandleStr(q.Parent), q.Priority, q.Protocol, chain)
+}
+
+// Chain contains the attributes of a tc filter chain.
+type Chain struct {
+	LinkIndex int
+	// Block addresses the chain of the shared block with this index
+	// instead of LinkIndex and Parent.
+	Block  uint32
+	Parent uint32
+	Chain  uint32
+	// Template restricts the keys and masks that the filters of the
+	// chain may match on. Only flower supports chain templates.
+	Template *Flower
+}
+
+func (c Chain) String() string {
+	return fmt.Sprintf("{LinkIndex: %d, Block: %d, Parent: %s, Chain: %d, Template: %t}", c.LinkIndex, c.Block, HandleStr(c.Parent), c.Chain, c.Template != nil)
 }
 
 type TcAct int32
@@ -37,8 +64,21 @@ const (
 	TC_ACT_REPEAT     TcAct = 6
 	TC_ACT_REDIRECT   TcAct = 7
 	TC_ACT_JUMP       TcAct = 0x10000000
+	TC_ACT_GOTO_CHAIN TcAct = 0x20000000
 )
 
+// TC_ACT_JUMP and TC_ACT_GOTO_CHAIN carry their argument in the low bits.
+const (
+	TC_ACT_EXT_VAL_MASK TcAct = 0x0fffffff
+	tcActExtOpcodeMask  TcAct = ^TC_ACT_EXT_VAL_MASK
+)
+
+// TcActGotoChain returns the action that continues classification with the
+// filters of the given chain.
+func TcActGotoChain(chain uint32) TcAct {
+	return TC_ACT_GOTO_CHAIN | TcAct(chain)&TC_ACT_EXT_VAL_MASK
+}
+
 func (a TcAct) String() string {
 	switch a {
 	case TC_ACT_UNSPEC:
@@ -62,6 +102,12 @@ func (a TcAct) String() string {
 	case TC_ACT_JUMP:
 		return "jump"
 	}
+	switch a & tcActExtOpcodeMask {
+	case TC_ACT_JUMP:
+		return fmt.Sprintf("jump %d", int32(a&TC_ACT_EXT_VAL_MASK))
+	case TC_ACT_GOTO_CHAIN:
+		return fmt.Sprintf("goto chain %d", int32(a&TC_ACT_EXT_VAL_MASK))
+	}
 	return fmt.Sprintf("0x%x", int32(a))
 }
 
@@ -92,11 +138,25 @@ func (a TcPolAct) String() string {
 }
 
 type ActionAttrs struct {
-	Index   int
-	Capab   int
-	Action  TcAct
-	Refcnt  int
-	Bindcnt int
+	Index      int
+	Capab      int
+	Action     TcAct
+	Refcnt     int
+	Bindcnt    int
+	Statistics *ActionStatistic
+	Timestamp  *ActionTimestamp
+}
+
+// ActionStatistic holds the counters the kernel reports for an action.
+type ActionStatistic ClassStatistics
+
+// ActionTimestamp holds the times the kernel reports for an action, in
+// clock ticks (USER_HZ) relative to now.
+type ActionTimestamp struct {
+	Installed uint64
+	LastUsed  uint64
+	Expires   uint64
+	FirstUsed uint64
 }
 
 func (q ActionAttrs) String() string {
@@ -109,8 +169,28 @@ type Action interface {
 	Type() string
 }
 
+type GactRandomType uint16
+
+const (
+	GACT_RAND_NONE    GactRandomType = iota
+	GACT_RAND_NETRAND                // random
+	GACT_RAND_DETERM                 // deterministic
+)
+
+// GactRandom makes a gact action take Action instead of its own action
+// for a share of the packets: on average one packet in Val for
+// GACT_RAND_NETRAND, exactly every Val-th packet for GACT_RAND_DETERM.
+type GactRandom struct {
+	Type   GactRandomType
+	Val    uint16
+	Action TcAct
+}
+
+// GenericAction is the gact action, which applies its TcAct verdict.
+// Use TcActGotoChain to continue in another chain.
 type GenericAction struct {
 	ActionAttrs
+	Random *GactRandom
 }
 
 func (action *GenericAction) Type() string {
@@ -182,6 +262,259 @@ func NewMirredAction(redirIndex int) *MirredAction {
 	}
 }
 
+type TunnelKeyAct int8
+
+const (
+	TCA_TUNNEL_KEY_SET   TunnelKeyAct = 1 // set tunnel key
+	TCA_TUNNEL_KEY_UNSET TunnelKeyAct = 2 // unset tunnel key
+)
+
+// TunnelKeyAction sets the tunnel metadata used by collect_md tunnel
+// devices, or releases it.
+type TunnelKeyAction struct {
+	ActionAttrs
+	TunnelKeyAction TunnelKeyAct
+	SrcAddr         net.IP
+	DstAddr         net.IP
+	KeyID           uint32
+	DestPort        uint16
+	Tos             uint8
+	TTL             uint8
+	NoCsum          bool
+}
+
+func (action *TunnelKeyAction) Type() string {
+	return "tunnel_key"
+}
+
+func (action *TunnelKeyAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewTunnelKeyAction() *TunnelKeyAction {
+	return &TunnelKeyAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_PIPE,
+		},
+		TunnelKeyAction: TCA_TUNNEL_KEY_SET,
+	}
+}
+
+// SkbEditAction edits the packet metadata. Nil fields are left untouched.
+type SkbEditAction struct {
+	ActionAttrs
+	QueueMapping *uint16
+	PType        *uint16
+	Priority     *uint32
+	Mark         *uint32
+	Mask         *uint32 // applied to Mark
+}
+
+func (action *SkbEditAction) Type() string {
+	return "skbedit"
+}
+
+func (action *SkbEditAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewSkbEditAction() *SkbEditAction {
+	return &SkbEditAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_PIPE,
+		},
+	}
+}
+
+type VlanAct int32
+
+const (
+	TCA_VLAN_ACT_POP    VlanAct = 1
+	TCA_VLAN_ACT_PUSH   VlanAct = 2
+	TCA_VLAN_ACT_MODIFY VlanAct = 3
+)
+
+func (a VlanAct) String() string {
+	switch a {
+	case TCA_VLAN_ACT_POP:
+		return "pop"
+	case TCA_VLAN_ACT_PUSH:
+		return "push"
+	case TCA_VLAN_ACT_MODIFY:
+		return "modify"
+	}
+	return "unknown"
+}
+
+// VlanAction pushes, pops or rewrites a vlan tag.
+type VlanAction struct {
+	ActionAttrs
+	VlanAction VlanAct
+	VlanId     uint16
+	// VlanProtocol defaults to unix.ETH_P_8021Q.
+	VlanProtocol uint16
+	VlanPrio     uint8
+}
+
+func (action *VlanAction) Type() string {
+	return "vlan"
+}
+
+func (action *VlanAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewVlanAction(vlanAction VlanAct) *VlanAction {
+	return &VlanAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_PIPE,
+		},
+		VlanAction: vlanAction,
+	}
+}
+
+type PeditHeaderType uint16
+
+const (
+	PEDIT_HDR_TYPE_NETWORK PeditHeaderType = iota // offset from the network header
+	PEDIT_HDR_TYPE_ETH
+	PEDIT_HDR_TYPE_IP4
+	PEDIT_HDR_TYPE_IP6
+	PEDIT_HDR_TYPE_TCP
+	PEDIT_HDR_TYPE_UDP
+)
+
+type PeditCmd uint16
+
+const (
+	PEDIT_CMD_SET PeditCmd = iota
+	PEDIT_CMD_ADD
+)
+
+// PeditKey edits the 32 bit word at Off bytes into the header selected by
+// HeaderType. Val and Mask are the host order representation of the big
+// endian word: the bits set in Mask are kept, the others are replaced by
+// (or, for PEDIT_CMD_ADD, incremented by) Val.
+type PeditKey struct {
+	HeaderType PeditHeaderType
+	Cmd        PeditCmd
+	Off        uint32
+	Val        uint32
+	Mask       uint32
+	At         uint32
+	OffMask    uint32
+	Shift      uint32
+}
+
+// PeditAction rewrites arbitrary packet bytes.
+type PeditAction struct {
+	ActionAttrs
+	Keys []PeditKey
+}
+
+func (action *PeditAction) Type() string {
+	return "pedit"
+}
+
+func (action *PeditAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewPeditAction() *PeditAction {
+	return &PeditAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_PIPE,
+		},
+	}
+}
+
+type CsumUpdateFlags uint32
+
+const (
+	TCA_CSUM_UPDATE_FLAG_IPV4HDR CsumUpdateFlags = 1 << iota
+	TCA_CSUM_UPDATE_FLAG_ICMP
+	TCA_CSUM_UPDATE_FLAG_IGMP
+	TCA_CSUM_UPDATE_FLAG_TCP
+	TCA_CSUM_UPDATE_FLAG_UDP
+	TCA_CSUM_UPDATE_FLAG_UDPLITE
+	TCA_CSUM_UPDATE_FLAG_SCTP
+)
+
+// CsumAction recalculates the checksums selected by UpdateFlags, usually
+// after a PeditAction.
+type CsumAction struct {
+	ActionAttrs
+	UpdateFlags CsumUpdateFlags
+}
+
+func (action *CsumAction) Type() string {
+	return "csum"
+}
+
+func (action *CsumAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewCsumAction() *CsumAction {
+	return &CsumAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_OK,
+		},
+	}
+}
+
+// PoliceAction rate limits packets. Packets within the rate get
+// NotExceedAction, the others ExceedAction.
+type PoliceAction struct {
+	ActionAttrs
+	Rate            uint64 // in byte per second
+	Burst           uint32 // in byte
+	Mtu             uint32
+	PeakRate        uint64 // in byte per second
+	AvRate          uint32 // in byte per second
+	Overhead        uint16
+	LinkLayer       int
+	ExceedAction    TcPolAct
+	NotExceedAction TcPolAct
+}
+
+func (action *PoliceAction) Type() string {
+	return "police"
+}
+
+func (action *PoliceAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewPoliceAction() *PoliceAction {
+	return &PoliceAction{
+		ExceedAction:    TC_POLICE_SHOT,
+		NotExceedAction: TC_POLICE_OK,
+	}
+}
+
+// ConnmarkAction restores the packet mark from its conntrack entry.
+type ConnmarkAction struct {
+	ActionAttrs
+	Zone uint16
+}
+
+func (action *ConnmarkAction) Type() string {
+	return "connmark"
+}
+
+func (action *ConnmarkAction) Attrs() *ActionAttrs {
+	return &action.ActionAttrs
+}
+
+func NewConnmarkAction() *ConnmarkAction {
+	return &ConnmarkAction{
+		ActionAttrs: ActionAttrs{
+			Action: TC_ACT_PIPE,
+		},
+	}
+}
+
 // Sel of the U32 filters that contains multiple TcU32Key. This is the copy
 // and the frontend representation of nl.TcU32Sel. It is serialized into canonical
 // nl.TcU32Sel with the appropriate endianness.
@@ -242,6 +575,82 @@ func (filter *MatchAll) Type() string {
 	return "matchall"
 }
 
+// Flower filters match on packet header fields and tunnel metadata. Keys
+// left at their zero value are not matched unless their mask is set, so
+// e.g. CtStateMask alone matches untracked packets. A mask left empty while
+// its key is set defaults to an exact match.
+type Flower struct {
+	FilterAttrs
+	ClassId uint32
+	Indev   string
+	// EthType defaults to FilterAttrs.Protocol, as it does for `tc`, and
+	// must be IPv4 or IPv6 for the IP, IPProto and port keys to apply.
+	EthType uint16
+
+	DestMac     net.HardwareAddr
+	DestMacMask net.HardwareAddr
+	SrcMac      net.HardwareAddr
+	SrcMacMask  net.HardwareAddr
+
+	VlanId      uint16
+	VlanPrio    uint8
+	VlanEthType uint16
+
+	// IPProto must be TCP, UDP or SCTP for the port keys to apply.
+	IPProto    uint8
+	DestIP     net.IP
+	DestIPMask net.IPMask
+	SrcIP      net.IP
+	SrcIPMask  net.IPMask
+	IPTos      uint8
+	IPTosMask  uint8
+	IPTTL      uint8
+	IPTTLMask  uint8
+
+	DestPort     uint16
+	DestPortMask uint16
+	SrcPort      uint16
+	SrcPortMask  uint16
+
+	CtState     uint16 // nl.TCA_FLOWER_KEY_CT_FLAGS_*
+	CtStateMask uint16
+	CtZone      uint16
+	CtZoneMask  uint16
+	CtMark      uint32
+	CtMarkMask  uint32
+
+	EncKeyId        uint32
+	EncDestIP       net.IP
+	EncDestIPMask   net.IPMask
+	EncSrcIP        net.IP
+	EncSrcIPMask    net.IPMask
+	EncDestPort     uint16
+	EncDestPortMask uint16
+	EncSrcPort      uint16
+	EncSrcPortMask  uint16
+	EncTos          uint8
+	EncTosMask      uint8
+	EncTTL          uint8
+	EncTTLMask      uint8
+
+	SkipHw bool
+	SkipSw bool
+	// InHw and InHwCount are reported by the kernel and ignored when
+	// adding the filter.
+	InHw      bool
+	InHwCount uint32
+
+	Actions []Action
+}
+
+func (filter *Flower) Attrs() *FilterAttrs {
+	return &filter.FilterAttrs
+}
+
+func (filter *Flower) Type() string {
+	return "flower"
+}
+
 type FilterFwAttrs struct {
 	ClassId   uint32
 	InDev     string
@@ -2,9 +2,12 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"syscall"
 	"unsafe"
 
@@ -90,6 +93,32 @@ func (filter *Fw) Type() string {
 	return "fw"
 }
 
+// addFilterMsg adds the tcmsg addressing the filter, and its chain if
+// set, to req.
+func addFilterMsg(req *nl.NetlinkRequest, base *FilterAttrs) {
+	msg := &nl.TcMsg{
+		Family:  nl.FAMILY_ALL,
+		Ifindex: int32(base.LinkIndex),
+		Handle:  base.Handle,
+		Parent:  base.Parent,
+		Info:    MakeHandle(base.Priority, nl.Swap16(base.Protocol)),
+	}
+	if base.Block != 0 {
+		setTcMsgBlock(msg, base.Block)
+	}
+	req.AddData(msg)
+	if base.Chain != nil {
+		req.AddData(nl.NewRtAttr(nl.TCA_CHAIN, nl.Uint32Attr(*base.Chain)))
+	}
+}
+
+// setTcMsgBlock points msg at the shared block with the given index.
+func setTcMsgBlock(msg *nl.TcMsg, block uint32) {
+	magic := uint32(nl.TCM_IFINDEX_MAGIC_BLOCK)
+	msg.Ifindex = int32(magic)
+	msg.Parent = block
+}
+
 // FilterDel will delete a filter from the system.
 // Equivalent to: `tc filter del $filter`
 func FilterDel(filter Filter) error {
@@ -100,20 +129,18 @@ func FilterDel(filter Filter) error {
 // Equivalent to: `tc filter del $filter`
 func (h *Handle) FilterDel(filter Filter) error {
 	req := h.newNetlinkRequest(unix.RTM_DELTFILTER, unix.NLM_F_ACK)
-	base := filter.Attrs()
-	msg := &nl.TcMsg{
-		Family:  nl.FAMILY_ALL,
-		Ifindex: int32(base.LinkIndex),
-		Handle:  base.Handle,
-		Parent:  base.Parent,
-		Info:    MakeHandle(base.Priority, nl.Swap16(base.Protocol)),
-	}
-	req.AddData(msg)
+	addFilterMsg(req, filter.Attrs())
 
 	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	return err
 }
 
+// FilterDelContext is like FilterDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FilterDelContext(ctx context.Context, filter Filter) error {
+	return h.withContext(ctx).FilterDel(filter)
+}
+
 // FilterAdd will add a filter to the system.
 // Equivalent to: `tc filter add $filter`
 func FilterAdd(filter Filter) error {
@@ -125,15 +152,7 @@ func FilterAdd(filter Filter) error {
 func (h *Handle) FilterAdd(filter Filter) error {
 	native = nl.NativeEndian()
 	req := h.newNetlinkRequest(unix.RTM_NEWTFILTER, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
-	base := filter.Attrs()
-	msg := &nl.TcMsg{
-		Family:  nl.FAMILY_ALL,
-		Ifindex: int32(base.LinkIndex),
-		Handle:  base.Handle,
-		Parent:  base.Parent,
-		Info:    MakeHandle(base.Priority, nl.Swap16(base.Protocol)),
-	}
-	req.AddData(msg)
+	addFilterMsg(req, filter.Attrs())
 	req.AddData(nl.NewRtAttr(nl.TCA_KIND, nl.ZeroTerminated(filter.Type())))
 
 	options := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
@@ -239,6 +258,10 @@ func (h *Handle) FilterAdd(filter Filter) error {
 		if filter.ClassId != 0 {
 			nl.NewRtAttrChild(options, nl.TCA_MATCHALL_CLASSID, nl.Uint32Attr(filter.ClassId))
 		}
+	case *Flower:
+		if err := encodeFlowerData(options, filter); err != nil {
+			return err
+		}
 	}
 
 	req.AddData(options)
@@ -246,6 +269,12 @@ func (h *Handle) FilterAdd(filter Filter) error {
 	return err
 }
 
+// FilterAddContext is like FilterAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FilterAddContext(ctx context.Context, filter Filter) error {
+	return h.withContext(ctx).FilterAdd(filter)
+}
+
 // FilterList gets a list of filters in the system.
 // Equivalent to: `tc filter show`.
 // Generally returns nothing if link and parent are not specified.
@@ -257,7 +286,6 @@ func FilterList(link Link, parent uint32) ([]Filter, error) {
 // Equivalent to: `tc filter show`.
 // Generally returns nothing if link and parent are not specified.
 func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
-	req := h.newNetlinkRequest(unix.RTM_GETTFILTER, unix.NLM_F_DUMP)
 	msg := &nl.TcMsg{
 		Family: nl.FAMILY_ALL,
 		Parent: parent,
@@ -267,11 +295,44 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 		h.ensureIndex(base)
 		msg.Ifindex = int32(base.Index)
 	}
+	return h.filterList(msg)
+}
+
+// FilterListContext is like FilterList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FilterListContext(ctx context.Context, link Link, parent uint32) ([]Filter, error) {
+	return h.withContext(ctx).FilterList(link, parent)
+}
+
+// FilterListBlock gets a list of the filters of a shared block.
+// Equivalent to: `tc filter show block $block`.
+func FilterListBlock(block uint32) ([]Filter, error) {
+	return pkgHandle.FilterListBlock(block)
+}
+
+// FilterListBlock gets a list of the filters of a shared block.
+// Equivalent to: `tc filter show block $block`.
+func (h *Handle) FilterListBlock(block uint32) ([]Filter, error) {
+	msg := &nl.TcMsg{
+		Family: nl.FAMILY_ALL,
+	}
+	setTcMsgBlock(msg, block)
+	return h.filterList(msg)
+}
+
+// FilterListBlockContext is like FilterListBlock but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) FilterListBlockContext(ctx context.Context, block uint32) ([]Filter, error) {
+	return h.withContext(ctx).FilterListBlock(block)
+}
+
+func (h *Handle) filterList(msg *nl.TcMsg) ([]Filter, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETTFILTER, unix.NLM_F_DUMP)
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWTFILTER)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWTFILTER)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Filter
@@ -288,6 +349,11 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 			Handle:    msg.Handle,
 			Parent:    msg.Parent,
 		}
+		if uint32(msg.Ifindex) == nl.TCM_IFINDEX_MAGIC_BLOCK {
+			base.LinkIndex = 0
+			base.Block = msg.Parent
+			base.Parent = 0
+		}
 		base.Priority, base.Protocol = MajorMinor(msg.Info)
 		base.Protocol = nl.Swap16(base.Protocol)
 
@@ -307,6 +373,8 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 					filter = &BpfFilter{}
 				case "matchall":
 					filter = &MatchAll{}
+				case "flower":
+					filter = &Flower{}
 				default:
 					filter = &GenericFilter{FilterType: filterType}
 				}
@@ -336,9 +404,17 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 					if err != nil {
 						return nil, err
 					}
+				case "flower":
+					detailed, err = parseFlowerData(filter, data)
+					if err != nil {
+						return nil, err
+					}
 				default:
 					detailed = true
 				}
+			case nl.TCA_CHAIN:
+				chain := native.Uint32(attr.Value)
+				base.Chain = &chain
 			}
 		}
 		// only return the detailed version of the filter
@@ -348,7 +424,7 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 		}
 	}
 
-	return res, nil
+	return res, executeErr
 }
 
 func toTcGen(attrs *ActionAttrs, tcgen *nl.TcGen) {
@@ -367,6 +443,26 @@ func toAttrs(tcgen *nl.TcGen, attrs *ActionAttrs) {
 	attrs.Bindcnt = int(tcgen.Bindcnt)
 }
 
+func toTimeStamp(tcf *nl.Tcf) *ActionTimestamp {
+	return &ActionTimestamp{
+		Installed: tcf.Install,
+		LastUsed:  tcf.LastUse,
+		Expires:   tcf.Expires,
+		FirstUsed: tcf.FirstUse,
+	}
+}
+
+func addTunnelKeyAddr(aopts *nl.RtAttr, v4Type, v6Type int, ip net.IP) {
+	if ip == nil {
+		return
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		nl.NewRtAttrChild(aopts, v4Type, ip4)
+	} else {
+		nl.NewRtAttrChild(aopts, v6Type, ip.To16())
+	}
+}
+
 func EncodeActions(attr *nl.RtAttr, actions []Action) error {
 	tabIndex := int(nl.TCA_ACT_TAB)
 
@@ -385,6 +481,182 @@ func EncodeActions(attr *nl.RtAttr, actions []Action) error {
 			}
 			toTcGen(action.Attrs(), &mirred.TcGen)
 			nl.NewRtAttrChild(aopts, nl.TCA_MIRRED_PARMS, mirred.Serialize())
+		case *TunnelKeyAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("tunnel_key"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			tun := nl.TcTunnelKey{
+				Action: int32(action.TunnelKeyAction),
+			}
+			toTcGen(action.Attrs(), &tun.TcGen)
+			nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_PARMS, tun.Serialize())
+			if action.TunnelKeyAction == TCA_TUNNEL_KEY_SET {
+				addTunnelKeyAddr(aopts, nl.TCA_TUNNEL_KEY_ENC_IPV4_SRC, nl.TCA_TUNNEL_KEY_ENC_IPV6_SRC, action.SrcAddr)
+				addTunnelKeyAddr(aopts, nl.TCA_TUNNEL_KEY_ENC_IPV4_DST, nl.TCA_TUNNEL_KEY_ENC_IPV6_DST, action.DstAddr)
+				if action.KeyID != 0 {
+					nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_ENC_KEY_ID, htonl(action.KeyID))
+				}
+				if action.DestPort != 0 {
+					nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_ENC_DST_PORT, htons(action.DestPort))
+				}
+				if action.Tos != 0 {
+					nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_ENC_TOS, nl.Uint8Attr(action.Tos))
+				}
+				if action.TTL != 0 {
+					nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_ENC_TTL, nl.Uint8Attr(action.TTL))
+				}
+				if action.NoCsum {
+					nl.NewRtAttrChild(aopts, nl.TCA_TUNNEL_KEY_NO_CSUM, nl.Uint8Attr(1))
+				}
+			}
+		case *SkbEditAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("skbedit"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			skbedit := nl.TcSkbEdit{}
+			toTcGen(action.Attrs(), &skbedit.TcGen)
+			nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_PARMS, skbedit.Serialize())
+			if action.QueueMapping != nil {
+				nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_QUEUE_MAPPING, nl.Uint16Attr(*action.QueueMapping))
+			}
+			if action.Priority != nil {
+				nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_PRIORITY, nl.Uint32Attr(*action.Priority))
+			}
+			if action.PType != nil {
+				nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_PTYPE, nl.Uint16Attr(*action.PType))
+			}
+			if action.Mark != nil {
+				nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_MARK, nl.Uint32Attr(*action.Mark))
+			}
+			if action.Mask != nil {
+				nl.NewRtAttrChild(aopts, nl.TCA_SKBEDIT_MASK, nl.Uint32Attr(*action.Mask))
+			}
+		case *VlanAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("vlan"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			vlan := nl.TcVlan{
+				Action: int32(action.VlanAction),
+			}
+			toTcGen(action.Attrs(), &vlan.TcGen)
+			nl.NewRtAttrChild(aopts, nl.TCA_VLAN_PARMS, vlan.Serialize())
+			if action.VlanAction == TCA_VLAN_ACT_PUSH || action.VlanAction == TCA_VLAN_ACT_MODIFY {
+				nl.NewRtAttrChild(aopts, nl.TCA_VLAN_PUSH_VLAN_ID, nl.Uint16Attr(action.VlanId))
+				if action.VlanProtocol != 0 {
+					nl.NewRtAttrChild(aopts, nl.TCA_VLAN_PUSH_VLAN_PROTOCOL, htons(action.VlanProtocol))
+				}
+				nl.NewRtAttrChild(aopts, nl.TCA_VLAN_PUSH_VLAN_PRIORITY, nl.Uint8Attr(action.VlanPrio))
+			}
+		case *PeditAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("pedit"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			if len(action.Keys) > math.MaxUint8 {
+				return fmt.Errorf("too many pedit keys %d, the maximum is %d", len(action.Keys), math.MaxUint8)
+			}
+			sel := nl.TcPeditSel{
+				Nkeys: uint8(len(action.Keys)),
+			}
+			toTcGen(action.Attrs(), &sel.TcGen)
+			keysEx := nl.NewRtAttr(nl.TCA_PEDIT_KEYS_EX, nil)
+			for _, key := range action.Keys {
+				if key.Off%4 != 0 {
+					return fmt.Errorf("pedit key offset %d is not 4 byte aligned", key.Off)
+				}
+				sel.Keys = append(sel.Keys, nl.TcPeditKey{
+					Mask:    native.Uint32(htonl(key.Mask)),
+					Val:     native.Uint32(htonl(key.Val)),
+					Off:     key.Off,
+					At:      key.At,
+					OffMask: key.OffMask,
+					Shift:   key.Shift,
+				})
+				keyEx := nl.NewRtAttrChild(keysEx, nl.TCA_PEDIT_KEY_EX, nil)
+				nl.NewRtAttrChild(keyEx, nl.TCA_PEDIT_KEY_EX_HTYPE, nl.Uint16Attr(uint16(key.HeaderType)))
+				nl.NewRtAttrChild(keyEx, nl.TCA_PEDIT_KEY_EX_CMD, nl.Uint16Attr(uint16(key.Cmd)))
+			}
+			nl.NewRtAttrChild(aopts, nl.TCA_PEDIT_PARMS_EX, sel.Serialize())
+			aopts.AddChild(keysEx)
+		case *CsumAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("csum"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			csum := nl.TcCsum{
+				UpdateFlags: uint32(action.UpdateFlags),
+			}
+			toTcGen(action.Attrs(), &csum.TcGen)
+			nl.NewRtAttrChild(aopts, nl.TCA_CSUM_PARMS, csum.Serialize())
+		case *PoliceAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("police"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			police := nl.TcPolice{
+				Index:   uint32(action.Index),
+				Action:  int32(action.ExceedAction),
+				Mtu:     action.Mtu,
+				Refcnt:  int32(action.Refcnt),
+				Bindcnt: int32(action.Bindcnt),
+				Capab:   uint32(action.Capab),
+			}
+			linklayer := nl.LINKLAYER_ETHERNET
+			if action.LinkLayer != nl.LINKLAYER_UNSPEC {
+				linklayer = action.LinkLayer
+			}
+			var rtab, ptab [256]uint32
+			if action.Rate != 0 {
+				police.Rate.Rate = uint32(action.Rate)
+				if action.Rate >= uint64(1<<32) {
+					police.Rate.Rate = ^uint32(0)
+				}
+				police.Rate.Overhead = action.Overhead
+				if CalcRtable(&police.Rate, rtab[:], -1, action.Mtu, linklayer) < 0 {
+					return errors.New("POLICE: failed to calculate rate table")
+				}
+				police.Burst = uint32(Xmittime(action.Rate, action.Burst))
+			}
+			if action.PeakRate != 0 {
+				police.PeakRate.Rate = uint32(action.PeakRate)
+				if action.PeakRate >= uint64(1<<32) {
+					police.PeakRate.Rate = ^uint32(0)
+				}
+				police.PeakRate.Overhead = action.Overhead
+				if CalcRtable(&police.PeakRate, ptab[:], -1, action.Mtu, linklayer) < 0 {
+					return errors.New("POLICE: failed to calculate peak rate table")
+				}
+			}
+			nl.NewRtAttrChild(aopts, nl.TCA_POLICE_TBF, police.Serialize())
+			if action.Rate != 0 {
+				nl.NewRtAttrChild(aopts, nl.TCA_POLICE_RATE, SerializeRtab(rtab))
+				if action.Rate >= uint64(1<<32) {
+					nl.NewRtAttrChild(aopts, nl.TCA_POLICE_RATE64, nl.Uint64Attr(action.Rate))
+				}
+			}
+			if action.PeakRate != 0 {
+				nl.NewRtAttrChild(aopts, nl.TCA_POLICE_PEAKRATE, SerializeRtab(ptab))
+				if action.PeakRate >= uint64(1<<32) {
+					nl.NewRtAttrChild(aopts, nl.TCA_POLICE_PEAKRATE64, nl.Uint64Attr(action.PeakRate))
+				}
+			}
+			if action.AvRate != 0 {
+				nl.NewRtAttrChild(aopts, nl.TCA_POLICE_AVRATE, nl.Uint32Attr(action.AvRate))
+			}
+			nl.NewRtAttrChild(aopts, nl.TCA_POLICE_RESULT, nl.Uint32Attr(uint32(action.NotExceedAction)))
+		case *ConnmarkAction:
+			table := nl.NewRtAttrChild(attr, tabIndex, nil)
+			tabIndex++
+			nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("connmark"))
+			aopts := nl.NewRtAttrChild(table, nl.TCA_ACT_OPTIONS, nil)
+			connmark := nl.TcConnmark{
+				Zone: action.Zone,
+			}
+			toTcGen(action.Attrs(), &connmark.TcGen)
+			nl.NewRtAttrChild(aopts, nl.TCA_CONNMARK_PARMS, connmark.Serialize())
 		case *BpfAction:
 			table := nl.NewRtAttrChild(attr, tabIndex, nil)
 			tabIndex++
@@ -403,6 +675,14 @@ func EncodeActions(attr *nl.RtAttr, actions []Action) error {
 			gen := nl.TcGen{}
 			toTcGen(action.Attrs(), &gen)
 			nl.NewRtAttrChild(aopts, nl.TCA_GACT_PARMS, gen.Serialize())
+			if action.Random != nil {
+				prob := nl.TcGactP{
+					Ptype:   uint16(action.Random.Type),
+					Pval:    action.Random.Val,
+					Paction: int32(action.Random.Action),
+				}
+				nl.NewRtAttrChild(aopts, nl.TCA_GACT_PROB, prob.Serialize())
+			}
 		}
 	}
 	return nil
@@ -422,7 +702,7 @@ func parseActions(tables []syscall.NetlinkRouteAttr) ([]Action, error) {
 			switch aattr.Attr.Type {
 			case nl.TCA_KIND:
 				actionType = string(aattr.Value[:len(aattr.Value)-1])
-				// only parse if the action is mirred or bpf
+				// only parse if the action is one we understand
 				switch actionType {
 				case "mirred":
 					action = &MirredAction{}
@@ -430,14 +710,36 @@ func parseActions(tables []syscall.NetlinkRouteAttr) ([]Action, error) {
 					action = &BpfAction{}
 				case "gact":
 					action = &GenericAction{}
+				case "tunnel_key":
+					action = &TunnelKeyAction{}
+				case "skbedit":
+					action = &SkbEditAction{}
+				case "vlan":
+					action = &VlanAction{}
+				case "pedit":
+					action = &PeditAction{}
+				case "csum":
+					action = &CsumAction{}
+				case "police":
+					action = &PoliceAction{}
+				case "connmark":
+					action = &ConnmarkAction{}
 				default:
 					break nextattr
 				}
+			case nl.TCA_ACT_STATS:
+				stats, err := parseTcStats2(aattr.Value)
+				if err != nil {
+					return nil, err
+				}
+				action.Attrs().Statistics = (*ActionStatistic)(stats)
 			case nl.TCA_OPTIONS:
 				adata, err := nl.ParseRouteAttr(aattr.Value)
 				if err != nil {
 					return nil, err
 				}
+				var policeBurst uint32
+				var peditKeysEx []syscall.NetlinkRouteAttr
 				for _, adatum := range adata {
 					switch actionType {
 					case "mirred":
@@ -445,9 +747,144 @@ func parseActions(tables []syscall.NetlinkRouteAttr) ([]Action, error) {
 						case nl.TCA_MIRRED_PARMS:
 							mirred := *nl.DeserializeTcMirred(adatum.Value)
 							toAttrs(&mirred.TcGen, action.Attrs())
-							action.(*MirredAction).ActionAttrs = ActionAttrs{}
 							action.(*MirredAction).Ifindex = int(mirred.Ifindex)
 							action.(*MirredAction).MirredAction = MirredAct(mirred.Eaction)
+						case nl.TCA_MIRRED_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "tunnel_key":
+						tun := action.(*TunnelKeyAction)
+						switch adatum.Attr.Type {
+						case nl.TCA_TUNNEL_KEY_PARMS:
+							parms := *nl.DeserializeTcTunnelKey(adatum.Value)
+							toAttrs(&parms.TcGen, action.Attrs())
+							tun.TunnelKeyAction = TunnelKeyAct(parms.Action)
+						case nl.TCA_TUNNEL_KEY_ENC_IPV4_SRC, nl.TCA_TUNNEL_KEY_ENC_IPV6_SRC:
+							tun.SrcAddr = net.IP(adatum.Value)
+						case nl.TCA_TUNNEL_KEY_ENC_IPV4_DST, nl.TCA_TUNNEL_KEY_ENC_IPV6_DST:
+							tun.DstAddr = net.IP(adatum.Value)
+						case nl.TCA_TUNNEL_KEY_ENC_KEY_ID:
+							tun.KeyID = ntohl(adatum.Value[0:4])
+						case nl.TCA_TUNNEL_KEY_ENC_DST_PORT:
+							tun.DestPort = ntohs(adatum.Value[0:2])
+						case nl.TCA_TUNNEL_KEY_ENC_TOS:
+							tun.Tos = adatum.Value[0]
+						case nl.TCA_TUNNEL_KEY_ENC_TTL:
+							tun.TTL = adatum.Value[0]
+						case nl.TCA_TUNNEL_KEY_NO_CSUM:
+							tun.NoCsum = adatum.Value[0] != 0
+						case nl.TCA_TUNNEL_KEY_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "skbedit":
+						skbedit := action.(*SkbEditAction)
+						switch adatum.Attr.Type {
+						case nl.TCA_SKBEDIT_PARMS:
+							parms := *nl.DeserializeTcSkbEdit(adatum.Value)
+							toAttrs(&parms.TcGen, action.Attrs())
+						case nl.TCA_SKBEDIT_QUEUE_MAPPING:
+							queueMapping := native.Uint16(adatum.Value[0:2])
+							skbedit.QueueMapping = &queueMapping
+						case nl.TCA_SKBEDIT_PRIORITY:
+							priority := native.Uint32(adatum.Value[0:4])
+							skbedit.Priority = &priority
+						case nl.TCA_SKBEDIT_PTYPE:
+							ptype := native.Uint16(adatum.Value[0:2])
+							skbedit.PType = &ptype
+						case nl.TCA_SKBEDIT_MARK:
+							mark := native.Uint32(adatum.Value[0:4])
+							skbedit.Mark = &mark
+						case nl.TCA_SKBEDIT_MASK:
+							mask := native.Uint32(adatum.Value[0:4])
+							skbedit.Mask = &mask
+						case nl.TCA_SKBEDIT_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "vlan":
+						vlan := action.(*VlanAction)
+						switch adatum.Attr.Type {
+						case nl.TCA_VLAN_PARMS:
+							parms := *nl.DeserializeTcVlan(adatum.Value)
+							toAttrs(&parms.TcGen, action.Attrs())
+							vlan.VlanAction = VlanAct(parms.Action)
+						case nl.TCA_VLAN_PUSH_VLAN_ID:
+							vlan.VlanId = native.Uint16(adatum.Value[0:2])
+						case nl.TCA_VLAN_PUSH_VLAN_PROTOCOL:
+							vlan.VlanProtocol = ntohs(adatum.Value[0:2])
+						case nl.TCA_VLAN_PUSH_VLAN_PRIORITY:
+							vlan.VlanPrio = adatum.Value[0]
+						case nl.TCA_VLAN_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "pedit":
+						pedit := action.(*PeditAction)
+						switch adatum.Attr.Type {
+						case nl.TCA_PEDIT_PARMS, nl.TCA_PEDIT_PARMS_EX:
+							sel := nl.DeserializeTcPeditSel(adatum.Value)
+							toAttrs(&sel.TcGen, action.Attrs())
+							pedit.Keys = make([]PeditKey, 0, len(sel.Keys))
+							for _, key := range sel.Keys {
+								pedit.Keys = append(pedit.Keys, PeditKey{
+									Mask:    native.Uint32(htonl(key.Mask)),
+									Val:     native.Uint32(htonl(key.Val)),
+									Off:     key.Off,
+									At:      key.At,
+									OffMask: key.OffMask,
+									Shift:   key.Shift,
+								})
+							}
+						case nl.TCA_PEDIT_KEYS_EX:
+							peditKeysEx, err = nl.ParseRouteAttr(adatum.Value)
+							if err != nil {
+								return nil, err
+							}
+						case nl.TCA_PEDIT_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "csum":
+						switch adatum.Attr.Type {
+						case nl.TCA_CSUM_PARMS:
+							parms := *nl.DeserializeTcCsum(adatum.Value)
+							toAttrs(&parms.TcGen, action.Attrs())
+							action.(*CsumAction).UpdateFlags = CsumUpdateFlags(parms.UpdateFlags)
+						case nl.TCA_CSUM_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "police":
+						police := action.(*PoliceAction)
+						switch adatum.Attr.Type {
+						case nl.TCA_POLICE_TBF:
+							parms := *nl.DeserializeTcPolice(adatum.Value)
+							police.Index = int(parms.Index)
+							police.Capab = int(parms.Capab)
+							police.Refcnt = int(parms.Refcnt)
+							police.Bindcnt = int(parms.Bindcnt)
+							police.ExceedAction = TcPolAct(parms.Action)
+							police.Mtu = parms.Mtu
+							police.Rate = uint64(parms.Rate.Rate)
+							police.PeakRate = uint64(parms.PeakRate.Rate)
+							police.Overhead = parms.Rate.Overhead
+							police.LinkLayer = int(parms.Rate.Linklayer & nl.TC_LINKLAYER_MASK)
+							policeBurst = parms.Burst
+						case nl.TCA_POLICE_RATE64:
+							police.Rate = native.Uint64(adatum.Value[0:8])
+						case nl.TCA_POLICE_PEAKRATE64:
+							police.PeakRate = native.Uint64(adatum.Value[0:8])
+						case nl.TCA_POLICE_AVRATE:
+							police.AvRate = native.Uint32(adatum.Value[0:4])
+						case nl.TCA_POLICE_RESULT:
+							police.NotExceedAction = TcPolAct(native.Uint32(adatum.Value[0:4]))
+						case nl.TCA_POLICE_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					case "connmark":
+						switch adatum.Attr.Type {
+						case nl.TCA_CONNMARK_PARMS:
+							parms := *nl.DeserializeTcConnmark(adatum.Value)
+							toAttrs(&parms.TcGen, action.Attrs())
+							action.(*ConnmarkAction).Zone = parms.Zone
+						case nl.TCA_CONNMARK_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
 						}
 					case "bpf":
 						switch adatum.Attr.Type {
@@ -458,12 +895,48 @@ func parseActions(tables []syscall.NetlinkRouteAttr) ([]Action, error) {
 							action.(*BpfAction).Fd = int(native.Uint32(adatum.Value[0:4]))
 						case nl.TCA_ACT_BPF_NAME:
 							action.(*BpfAction).Name = string(adatum.Value[:len(adatum.Value)-1])
+						case nl.TCA_ACT_BPF_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
 						}
 					case "gact":
 						switch adatum.Attr.Type {
 						case nl.TCA_GACT_PARMS:
 							gen := *nl.DeserializeTcGen(adatum.Value)
 							toAttrs(&gen, action.Attrs())
+						case nl.TCA_GACT_PROB:
+							prob := *nl.DeserializeTcGactP(adatum.Value)
+							action.(*GenericAction).Random = &GactRandom{
+								Type:   GactRandomType(prob.Ptype),
+								Val:    prob.Pval,
+								Action: TcAct(prob.Paction),
+							}
+						case nl.TCA_GACT_TM:
+							action.Attrs().Timestamp = toTimeStamp(nl.DeserializeTcf(adatum.Value))
+						}
+					}
+				}
+				switch action := action.(type) {
+				case *PoliceAction:
+					if action.Rate != 0 {
+						action.Burst = burst(action.Rate, policeBurst)
+					}
+				case *PeditAction:
+					// the extended keys are dumped before the selector
+					for i, keyEx := range peditKeysEx {
+						if i >= len(action.Keys) {
+							break
+						}
+						exData, err := nl.ParseRouteAttr(keyEx.Value)
+						if err != nil {
+							return nil, err
+						}
+						for _, exDatum := range exData {
+							switch exDatum.Attr.Type {
+							case nl.TCA_PEDIT_KEY_EX_HTYPE:
+								action.Keys[i].HeaderType = PeditHeaderType(native.Uint16(exDatum.Value[0:2]))
+							case nl.TCA_PEDIT_KEY_EX_CMD:
+								action.Keys[i].Cmd = PeditCmd(native.Uint16(exDatum.Value[0:2]))
+							}
 						}
 					}
 				}
@@ -591,6 +1064,302 @@ func parseMatchAllData(filter Filter, data []syscall.NetlinkRouteAttr) (bool, er
 	return detailed, nil
 }
 
+// flowerPortKeys maps an IP protocol to its flower source port, source
+// port mask, destination port and destination port mask attributes.
+var flowerPortKeys = map[uint8][4]int{
+	unix.IPPROTO_TCP:  {nl.TCA_FLOWER_KEY_TCP_SRC, nl.TCA_FLOWER_KEY_TCP_SRC_MASK, nl.TCA_FLOWER_KEY_TCP_DST, nl.TCA_FLOWER_KEY_TCP_DST_MASK},
+	unix.IPPROTO_UDP:  {nl.TCA_FLOWER_KEY_UDP_SRC, nl.TCA_FLOWER_KEY_UDP_SRC_MASK, nl.TCA_FLOWER_KEY_UDP_DST, nl.TCA_FLOWER_KEY_UDP_DST_MASK},
+	unix.IPPROTO_SCTP: {nl.TCA_FLOWER_KEY_SCTP_SRC, nl.TCA_FLOWER_KEY_SCTP_SRC_MASK, nl.TCA_FLOWER_KEY_SCTP_DST, nl.TCA_FLOWER_KEY_SCTP_DST_MASK},
+}
+
+// addFlowerKey adds a flower key and its mask unless both are all zeros.
+// An empty mask is replaced by an exact match.
+func addFlowerKey(options *nl.RtAttr, keyType, maskType int, key, mask []byte) error {
+	zero := len(mask) == 0
+	for _, b := range key {
+		if b != 0 {
+			zero = false
+			break
+		}
+	}
+	if zero {
+		return nil
+	}
+	if len(mask) == 0 {
+		mask = bytes.Repeat([]byte{0xff}, len(key))
+	} else if len(mask) != len(key) {
+		return fmt.Errorf("flower mask length %d does not match key length %d", len(mask), len(key))
+	}
+	nl.NewRtAttrChild(options, keyType, key)
+	if maskType != nl.TCA_FLOWER_UNSPEC {
+		nl.NewRtAttrChild(options, maskType, mask)
+	}
+	return nil
+}
+
+// addFlowerIP adds an IPv4 or IPv6 address key, the mask attribute always
+// directly follows the address attribute.
+func addFlowerIP(options *nl.RtAttr, v4Type, v6Type int, ip net.IP, mask net.IPMask) error {
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		if len(mask) == net.IPv6len {
+			mask = mask[12:]
+		}
+		return addFlowerKey(options, v4Type, v4Type+1, ip4, mask)
+	}
+	return addFlowerKey(options, v6Type, v6Type+1, ip.To16(), mask)
+}
+
+func encodeFlowerData(options *nl.RtAttr, filter *Flower) error {
+	native = nl.NativeEndian()
+	if filter.ClassId != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_CLASSID, nl.Uint32Attr(filter.ClassId))
+	}
+	if filter.Indev != "" {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_INDEV, nl.ZeroTerminated(filter.Indev))
+	}
+
+	ethType := filter.EthType
+	if ethType == 0 && filter.Protocol != unix.ETH_P_ALL {
+		ethType = filter.Protocol
+	}
+	if ethType != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_KEY_ETH_TYPE, htons(ethType))
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ETH_DST, nl.TCA_FLOWER_KEY_ETH_DST_MASK, filter.DestMac, filter.DestMacMask); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ETH_SRC, nl.TCA_FLOWER_KEY_ETH_SRC_MASK, filter.SrcMac, filter.SrcMacMask); err != nil {
+		return err
+	}
+
+	if filter.VlanId != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_KEY_VLAN_ID, nl.Uint16Attr(filter.VlanId))
+	}
+	if filter.VlanPrio != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_KEY_VLAN_PRIO, nl.Uint8Attr(filter.VlanPrio))
+	}
+	if filter.VlanEthType != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_KEY_VLAN_ETH_TYPE, htons(filter.VlanEthType))
+	}
+
+	if filter.IPProto != 0 {
+		nl.NewRtAttrChild(options, nl.TCA_FLOWER_KEY_IP_PROTO, nl.Uint8Attr(filter.IPProto))
+	}
+	if err := addFlowerIP(options, nl.TCA_FLOWER_KEY_IPV4_SRC, nl.TCA_FLOWER_KEY_IPV6_SRC, filter.SrcIP, filter.SrcIPMask); err != nil {
+		return err
+	}
+	if err := addFlowerIP(options, nl.TCA_FLOWER_KEY_IPV4_DST, nl.TCA_FLOWER_KEY_IPV6_DST, filter.DestIP, filter.DestIPMask); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_IP_TOS, nl.TCA_FLOWER_KEY_IP_TOS_MASK, nl.Uint8Attr(filter.IPTos), maskUint8(filter.IPTosMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_IP_TTL, nl.TCA_FLOWER_KEY_IP_TTL_MASK, nl.Uint8Attr(filter.IPTTL), maskUint8(filter.IPTTLMask)); err != nil {
+		return err
+	}
+
+	if filter.SrcPort != 0 || filter.SrcPortMask != 0 || filter.DestPort != 0 || filter.DestPortMask != 0 {
+		keys, ok := flowerPortKeys[filter.IPProto]
+		if !ok {
+			return fmt.Errorf("flower port match requires IPProto TCP, UDP or SCTP, got %d", filter.IPProto)
+		}
+		if err := addFlowerKey(options, keys[0], keys[1], htons(filter.SrcPort), maskUint16(filter.SrcPortMask)); err != nil {
+			return err
+		}
+		if err := addFlowerKey(options, keys[2], keys[3], htons(filter.DestPort), maskUint16(filter.DestPortMask)); err != nil {
+			return err
+		}
+	}
+
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_CT_STATE, nl.TCA_FLOWER_KEY_CT_STATE_MASK, nl.Uint16Attr(filter.CtState), nativeMaskUint16(filter.CtStateMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_CT_ZONE, nl.TCA_FLOWER_KEY_CT_ZONE_MASK, nl.Uint16Attr(filter.CtZone), nativeMaskUint16(filter.CtZoneMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_CT_MARK, nl.TCA_FLOWER_KEY_CT_MARK_MASK, nl.Uint32Attr(filter.CtMark), nativeMaskUint32(filter.CtMarkMask)); err != nil {
+		return err
+	}
+
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ENC_KEY_ID, nl.TCA_FLOWER_UNSPEC, htonl(filter.EncKeyId), nil); err != nil {
+		return err
+	}
+	if err := addFlowerIP(options, nl.TCA_FLOWER_KEY_ENC_IPV4_SRC, nl.TCA_FLOWER_KEY_ENC_IPV6_SRC, filter.EncSrcIP, filter.EncSrcIPMask); err != nil {
+		return err
+	}
+	if err := addFlowerIP(options, nl.TCA_FLOWER_KEY_ENC_IPV4_DST, nl.TCA_FLOWER_KEY_ENC_IPV6_DST, filter.EncDestIP, filter.EncDestIPMask); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ENC_UDP_SRC_PORT, nl.TCA_FLOWER_KEY_ENC_UDP_SRC_PORT_MASK, htons(filter.EncSrcPort), maskUint16(filter.EncSrcPortMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ENC_UDP_DST_PORT, nl.TCA_FLOWER_KEY_ENC_UDP_DST_PORT_MASK, htons(filter.EncDestPort), maskUint16(filter.EncDestPortMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ENC_IP_TOS, nl.TCA_FLOWER_KEY_ENC_IP_TOS_MASK, nl.Uint8Attr(filter.EncTos), maskUint8(filter.EncTosMask)); err != nil {
+		return err
+	}
+	if err := addFlowerKey(options, nl.TCA_FLOWER_KEY_ENC_IP_TTL, nl.TCA_FLOWER_KEY_ENC_IP_TTL_MASK, nl.Uint8Attr(filter.EncTTL), maskUint8(filter.EncTTLMask)); err != nil {
+		return err
+	}
+
+	var flags uint32
+	if filter.SkipHw {
+		flags |= nl.TCA_CLS_FLAGS_SKIP_HW
+	}
+	if filter.SkipSw {
+		flags |= nl.TCA_CLS_FLAGS_SKIP_SW
+	}
+	nl.NewRtAttrChild(options, nl.TCA_FLOWER_FLAGS, nl.Uint32Attr(flags))
+
+	actionsAttr := nl.NewRtAttrChild(options, nl.TCA_FLOWER_ACT, nil)
+	return EncodeActions(actionsAttr, filter.Actions)
+}
+
+// maskUint8 and friends return nil for a zero mask so that addFlowerKey
+// falls back to an exact match.
+func maskUint8(mask uint8) []byte {
+	if mask == 0 {
+		return nil
+	}
+	return nl.Uint8Attr(mask)
+}
+
+func maskUint16(mask uint16) []byte {
+	if mask == 0 {
+		return nil
+	}
+	return htons(mask)
+}
+
+func nativeMaskUint16(mask uint16) []byte {
+	if mask == 0 {
+		return nil
+	}
+	return nl.Uint16Attr(mask)
+}
+
+func nativeMaskUint32(mask uint32) []byte {
+	if mask == 0 {
+		return nil
+	}
+	return nl.Uint32Attr(mask)
+}
+
+func parseFlowerData(filter Filter, data []syscall.NetlinkRouteAttr) (bool, error) {
+	native = nl.NativeEndian()
+	flower := filter.(*Flower)
+	detailed := true
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_FLOWER_CLASSID:
+			flower.ClassId = native.Uint32(datum.Value[0:4])
+		case nl.TCA_FLOWER_INDEV:
+			flower.Indev = string(datum.Value[:len(datum.Value)-1])
+		case nl.TCA_FLOWER_ACT:
+			tables, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return detailed, err
+			}
+			flower.Actions, err = parseActions(tables)
+			if err != nil {
+				return detailed, err
+			}
+		case nl.TCA_FLOWER_KEY_ETH_TYPE:
+			flower.EthType = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_ETH_DST:
+			flower.DestMac = net.HardwareAddr(datum.Value)
+		case nl.TCA_FLOWER_KEY_ETH_DST_MASK:
+			flower.DestMacMask = net.HardwareAddr(datum.Value)
+		case nl.TCA_FLOWER_KEY_ETH_SRC:
+			flower.SrcMac = net.HardwareAddr(datum.Value)
+		case nl.TCA_FLOWER_KEY_ETH_SRC_MASK:
+			flower.SrcMacMask = net.HardwareAddr(datum.Value)
+		case nl.TCA_FLOWER_KEY_VLAN_ID:
+			flower.VlanId = native.Uint16(datum.Value[0:2])
+		case nl.TCA_FLOWER_KEY_VLAN_PRIO:
+			flower.VlanPrio = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_VLAN_ETH_TYPE:
+			flower.VlanEthType = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_IP_PROTO:
+			flower.IPProto = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_IPV4_SRC, nl.TCA_FLOWER_KEY_IPV6_SRC:
+			flower.SrcIP = net.IP(datum.Value)
+		case nl.TCA_FLOWER_KEY_IPV4_SRC_MASK, nl.TCA_FLOWER_KEY_IPV6_SRC_MASK:
+			flower.SrcIPMask = net.IPMask(datum.Value)
+		case nl.TCA_FLOWER_KEY_IPV4_DST, nl.TCA_FLOWER_KEY_IPV6_DST:
+			flower.DestIP = net.IP(datum.Value)
+		case nl.TCA_FLOWER_KEY_IPV4_DST_MASK, nl.TCA_FLOWER_KEY_IPV6_DST_MASK:
+			flower.DestIPMask = net.IPMask(datum.Value)
+		case nl.TCA_FLOWER_KEY_IP_TOS:
+			flower.IPTos = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_IP_TOS_MASK:
+			flower.IPTosMask = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_IP_TTL:
+			flower.IPTTL = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_IP_TTL_MASK:
+			flower.IPTTLMask = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_TCP_SRC, nl.TCA_FLOWER_KEY_UDP_SRC, nl.TCA_FLOWER_KEY_SCTP_SRC:
+			flower.SrcPort = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_TCP_SRC_MASK, nl.TCA_FLOWER_KEY_UDP_SRC_MASK, nl.TCA_FLOWER_KEY_SCTP_SRC_MASK:
+			flower.SrcPortMask = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_TCP_DST, nl.TCA_FLOWER_KEY_UDP_DST, nl.TCA_FLOWER_KEY_SCTP_DST:
+			flower.DestPort = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_TCP_DST_MASK, nl.TCA_FLOWER_KEY_UDP_DST_MASK, nl.TCA_FLOWER_KEY_SCTP_DST_MASK:
+			flower.DestPortMask = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_CT_STATE:
+			flower.CtState = native.Uint16(datum.Value[0:2])
+		case nl.TCA_FLOWER_KEY_CT_STATE_MASK:
+			flower.CtStateMask = native.Uint16(datum.Value[0:2])
+		case nl.TCA_FLOWER_KEY_CT_ZONE:
+			flower.CtZone = native.Uint16(datum.Value[0:2])
+		case nl.TCA_FLOWER_KEY_CT_ZONE_MASK:
+			flower.CtZoneMask = native.Uint16(datum.Value[0:2])
+		case nl.TCA_FLOWER_KEY_CT_MARK:
+			flower.CtMark = native.Uint32(datum.Value[0:4])
+		case nl.TCA_FLOWER_KEY_CT_MARK_MASK:
+			flower.CtMarkMask = native.Uint32(datum.Value[0:4])
+		case nl.TCA_FLOWER_KEY_ENC_KEY_ID:
+			flower.EncKeyId = ntohl(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_IPV4_SRC, nl.TCA_FLOWER_KEY_ENC_IPV6_SRC:
+			flower.EncSrcIP = net.IP(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_IPV4_SRC_MASK, nl.TCA_FLOWER_KEY_ENC_IPV6_SRC_MASK:
+			flower.EncSrcIPMask = net.IPMask(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_IPV4_DST, nl.TCA_FLOWER_KEY_ENC_IPV6_DST:
+			flower.EncDestIP = net.IP(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_IPV4_DST_MASK, nl.TCA_FLOWER_KEY_ENC_IPV6_DST_MASK:
+			flower.EncDestIPMask = net.IPMask(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_UDP_SRC_PORT:
+			flower.EncSrcPort = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_UDP_SRC_PORT_MASK:
+			flower.EncSrcPortMask = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_UDP_DST_PORT:
+			flower.EncDestPort = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_UDP_DST_PORT_MASK:
+			flower.EncDestPortMask = ntohs(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_IP_TOS:
+			flower.EncTos = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_ENC_IP_TOS_MASK:
+			flower.EncTosMask = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_ENC_IP_TTL:
+			flower.EncTTL = datum.Value[0]
+		case nl.TCA_FLOWER_KEY_ENC_IP_TTL_MASK:
+			flower.EncTTLMask = datum.Value[0]
+		case nl.TCA_FLOWER_FLAGS:
+			flags := native.Uint32(datum.Value[0:4])
+			flower.SkipHw = flags&nl.TCA_CLS_FLAGS_SKIP_HW != 0
+			flower.SkipSw = flags&nl.TCA_CLS_FLAGS_SKIP_SW != 0
+			flower.InHw = flags&nl.TCA_CLS_FLAGS_IN_HW != 0
+		case nl.TCA_FLOWER_IN_HW_COUNT:
+			flower.InHwCount = native.Uint32(datum.Value[0:4])
+		}
+	}
+	return detailed, nil
+}
+
 func AlignToAtm(size uint) uint {
 	var linksize, cells int
 	cells = int(size / nl.ATM_CELL_PAYLOAD)
@@ -3,9 +3,12 @@
 package netlink
 
 import (
+	"math"
+	"net"
 	"reflect"
 	"testing"
 
+	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
 )
 
@@ -609,7 +612,7 @@ func setupLinkForTestWithQdisc(t *testing.T, linkName string) (Qdisc, Link) {
 	if len(qdiscs) != 1 {
 		t.Fatal("Failed to add qdisc", len(qdiscs))
 	}
-	if q, ok := qdiscs[0].(*GenericQdisc); !ok || q.Type() != "clsact" {
+	if q, ok := qdiscs[0].(*Clsact); !ok || q.Type() != "clsact" {
 		t.Fatal("qdisc is the wrong type")
 	}
 	return qdiscs[0], link
@@ -690,6 +693,176 @@ func TestFilterClsActBpfAddDel(t *testing.T) {
 	}
 }
 
+func TestFilterFlowerAddDel(t *testing.T) {
+	// ct_state and the enc_ip keys need kernel 5.3
+	minKernelRequired(t, 5, 3)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	_, link := setupLinkForTestWithQdisc(t, "foo")
+	_, link2 := setupLinkForTestWithQdisc(t, "bar")
+
+	dstMac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	filter := &Flower{
+		FilterAttrs: FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    HANDLE_MIN_INGRESS,
+			Priority:  1,
+			Protocol:  unix.ETH_P_IP,
+		},
+		DestMac:     dstMac,
+		IPProto:     unix.IPPROTO_TCP,
+		DestIP:      net.IPv4(192, 168, 0, 0),
+		DestIPMask:  net.CIDRMask(24, 32),
+		SrcIP:       net.IPv4(10, 0, 0, 1),
+		IPTTL:       64,
+		DestPort:    80,
+		SrcPort:     0x1000,
+		SrcPortMask: 0xf000,
+		CtState:     nl.TCA_FLOWER_KEY_CT_FLAGS_TRACKED | nl.TCA_FLOWER_KEY_CT_FLAGS_ESTABLISHED,
+		CtStateMask: nl.TCA_FLOWER_KEY_CT_FLAGS_TRACKED | nl.TCA_FLOWER_KEY_CT_FLAGS_ESTABLISHED,
+		CtMark:      7,
+		EncKeyId:    42,
+		EncDestIP:   net.IPv4(172, 16, 0, 1),
+		EncDestPort: 4789,
+		SkipHw:      true,
+		Actions: []Action{
+			&MirredAction{
+				ActionAttrs: ActionAttrs{
+					Action: TC_ACT_STOLEN,
+				},
+				MirredAction: TCA_EGRESS_REDIR,
+				Ifindex:      link2.Attrs().Index,
+			},
+		},
+	}
+	if err := FilterAdd(filter); err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := FilterList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 {
+		t.Fatal("Failed to add filter")
+	}
+	flower, ok := filters[0].(*Flower)
+	if !ok {
+		t.Fatal("Filter is the wrong type")
+	}
+
+	if flower.EthType != unix.ETH_P_IP {
+		t.Fatalf("Flower EthType does not match: %#x", flower.EthType)
+	}
+	if flower.DestMac.String() != dstMac.String() || flower.DestMacMask.String() != "ff:ff:ff:ff:ff:ff" {
+		t.Fatalf("Flower dst mac does not match: %s/%s", flower.DestMac, flower.DestMacMask)
+	}
+	if flower.IPProto != unix.IPPROTO_TCP {
+		t.Fatal("Flower ip proto does not match")
+	}
+	if !flower.DestIP.Equal(filter.DestIP) || flower.DestIPMask.String() != filter.DestIPMask.String() {
+		t.Fatalf("Flower dst ip does not match: %s/%s", flower.DestIP, flower.DestIPMask)
+	}
+	if !flower.SrcIP.Equal(filter.SrcIP) || flower.SrcIPMask.String() != net.CIDRMask(32, 32).String() {
+		t.Fatalf("Flower src ip does not match: %s/%s", flower.SrcIP, flower.SrcIPMask)
+	}
+	if flower.IPTTL != 64 || flower.IPTTLMask != 0xff {
+		t.Fatal("Flower ip ttl does not match")
+	}
+	if flower.DestPort != 80 || flower.DestPortMask != 0xffff {
+		t.Fatal("Flower dst port does not match")
+	}
+	if flower.SrcPort != 0x1000 || flower.SrcPortMask != 0xf000 {
+		t.Fatal("Flower src port does not match")
+	}
+	if flower.CtState != filter.CtState || flower.CtStateMask != filter.CtStateMask {
+		t.Fatal("Flower ct_state does not match")
+	}
+	if flower.CtMark != 7 || flower.CtMarkMask != 0xffffffff {
+		t.Fatal("Flower ct_mark does not match")
+	}
+	if flower.EncKeyId != 42 || !flower.EncDestIP.Equal(filter.EncDestIP) || flower.EncDestPort != 4789 {
+		t.Fatal("Flower tunnel keys do not match")
+	}
+	if !flower.SkipHw || flower.SkipSw || flower.InHw {
+		t.Fatal("Flower flags do not match")
+	}
+	if len(flower.Actions) != 1 {
+		t.Fatal("Filter has no actions")
+	}
+	mirredAction, ok := flower.Actions[0].(*MirredAction)
+	if !ok {
+		t.Fatal("Action does not match")
+	}
+	if mirredAction.Ifindex != link2.Attrs().Index {
+		t.Fatal("Action ifindex does not match")
+	}
+
+	if err := FilterDel(filter); err != nil {
+		t.Fatal(err)
+	}
+	filters, err = FilterList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 0 {
+		t.Fatal("Failed to remove filter")
+	}
+
+	// ports need a transport protocol
+	filter.IPProto = unix.IPPROTO_ICMP
+	if err := FilterAdd(filter); err == nil {
+		t.Fatal("Expected an error for a port match without TCP, UDP or SCTP")
+	}
+}
+
+func TestFlowerEncodeDecode(t *testing.T) {
+	srcMac, _ := net.ParseMAC("00:11:22:33:44:55")
+	filter := &Flower{
+		FilterAttrs: FilterAttrs{Protocol: unix.ETH_P_IPV6},
+		ClassId:     MakeHandle(1, 2),
+		SrcMac:      srcMac,
+		SrcMacMask:  net.HardwareAddr{0xff, 0xff, 0xff, 0, 0, 0},
+		VlanId:      100,
+		VlanPrio:    3,
+		IPProto:     unix.IPPROTO_UDP,
+		DestIP:      net.ParseIP("2001:db8::1"),
+		DestIPMask:  net.CIDRMask(64, 128),
+		SrcPortMask: 0xff00,
+		CtStateMask: nl.TCA_FLOWER_KEY_CT_FLAGS_TRACKED,
+		CtZone:      5,
+		EncKeyId:    0x123456,
+		EncSrcIP:    net.IPv4(10, 1, 1, 1),
+		EncTTL:      8,
+		EncTTLMask:  0xf0,
+		SkipSw:      true,
+	}
+	options := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
+	if err := encodeFlowerData(options, filter); err != nil {
+		t.Fatal(err)
+	}
+	data, err := nl.ParseRouteAttr(options.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	flower := &Flower{}
+	if _, err := parseFlowerData(flower, data); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := *filter
+	expected.FilterAttrs = FilterAttrs{}
+	expected.EthType = unix.ETH_P_IPV6
+	expected.EncSrcIP = filter.EncSrcIP.To4()
+	expected.EncSrcIPMask = net.CIDRMask(32, 32)
+	expected.CtZoneMask = 0xffff
+	flower.Actions = nil
+	if !reflect.DeepEqual(*flower, expected) {
+		t.Fatalf("Flower does not round trip:\n%+v\n%+v", *flower, expected)
+	}
+}
+
 func TestFilterMatchAllAddDel(t *testing.T) {
 	// This classifier was added in kernel 4.7
 	minKernelRequired(t, 4, 7)
@@ -760,3 +933,355 @@ func TestFilterMatchAllAddDel(t *testing.T) {
 	}
 
 }
+
+func testActions(ifindex int) []Action {
+	gact := &GenericAction{
+		ActionAttrs: ActionAttrs{Action: TC_ACT_PIPE},
+		Random: &GactRandom{
+			Type:   GACT_RAND_DETERM,
+			Val:    10,
+			Action: TcActGotoChain(3),
+		},
+	}
+	tunnelKey := NewTunnelKeyAction()
+	tunnelKey.SrcAddr = net.IPv4(10, 0, 0, 1).To4()
+	tunnelKey.DstAddr = net.IPv4(10, 0, 0, 2).To4()
+	tunnelKey.KeyID = 0x1234
+	tunnelKey.DestPort = 4789
+	tunnelKey.Tos = 0x10
+	tunnelKey.TTL = 64
+	tunnelKey.NoCsum = true
+	skbedit := NewSkbEditAction()
+	mark, mask, prio := uint32(0x10), uint32(0xff), uint32(MakeHandle(1, 2))
+	skbedit.Mark = &mark
+	skbedit.Mask = &mask
+	skbedit.Priority = &prio
+	vlan := NewVlanAction(TCA_VLAN_ACT_PUSH)
+	vlan.VlanId = 100
+	vlan.VlanProtocol = unix.ETH_P_8021AD
+	vlan.VlanPrio = 5
+	pedit := NewPeditAction()
+	pedit.Keys = []PeditKey{
+		// rewrite the IPv4 TTL to 1
+		{HeaderType: PEDIT_HDR_TYPE_IP4, Off: 8, Val: 0x01000000, Mask: 0x00ffffff},
+		// decrement the IPv6 hop limit
+		{HeaderType: PEDIT_HDR_TYPE_IP6, Cmd: PEDIT_CMD_ADD, Off: 4, Val: 0xff, Mask: 0xffffff00},
+	}
+	csum := NewCsumAction()
+	csum.UpdateFlags = TCA_CSUM_UPDATE_FLAG_IPV4HDR | TCA_CSUM_UPDATE_FLAG_TCP
+	police := NewPoliceAction()
+	police.Rate = 125000
+	police.Burst = 10000
+	police.Mtu = 1500
+	police.LinkLayer = nl.LINKLAYER_ETHERNET
+	police.NotExceedAction = TC_POLICE_PIPE
+	connmark := NewConnmarkAction()
+	connmark.Zone = 7
+	return []Action{
+		gact,
+		tunnelKey,
+		skbedit,
+		vlan,
+		&VlanAction{VlanAction: TCA_VLAN_ACT_POP, ActionAttrs: ActionAttrs{Action: TC_ACT_PIPE}},
+		pedit,
+		csum,
+		police,
+		connmark,
+		NewMirredAction(ifindex),
+	}
+}
+
+func TestActionsEncodeDecode(t *testing.T) {
+	actions := testActions(2)
+	attr := nl.NewRtAttr(nl.TCA_MATCHALL_ACT, nil)
+	if err := EncodeActions(attr, actions); err != nil {
+		t.Fatal(err)
+	}
+	tables, err := nl.ParseRouteAttr(attr.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseActions(tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(actions) {
+		t.Fatalf("Expected %d actions, got %d", len(actions), len(parsed))
+	}
+	for i := range actions {
+		if !reflect.DeepEqual(parsed[i], actions[i]) {
+			t.Fatalf("Action %s does not round trip:\n%+v\n%+v", actions[i].Type(), parsed[i], actions[i])
+		}
+	}
+
+	// the kernel reports statistics next to the options
+	stats := nl.NewRtAttr(nl.TCA_ACT_STATS, nil)
+	basic := make([]byte, 16)
+	native.PutUint64(basic[0:8], 1500)
+	native.PutUint32(basic[8:12], 1)
+	nl.NewRtAttrChild(stats, nl.TCA_STATS_BASIC, basic)
+	table := nl.NewRtAttr(nl.TCA_ACT_TAB, nil)
+	nl.NewRtAttrChild(table, nl.TCA_ACT_KIND, nl.ZeroTerminated("connmark"))
+	table.AddChild(stats)
+	tables, err = nl.ParseRouteAttr(table.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = parseActions(tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := parsed[0].Attrs().Statistics; s == nil || s.Basic.Bytes != 1500 || s.Basic.Packets != 1 {
+		t.Fatalf("Action statistics not parsed: %+v", parsed[0].Attrs())
+	}
+
+	pedit := NewPeditAction()
+	pedit.Keys = make([]PeditKey, math.MaxUint8+1)
+	if err := EncodeActions(nl.NewRtAttr(nl.TCA_MATCHALL_ACT, nil), []Action{pedit}); err == nil {
+		t.Fatal("Expected an error encoding more than 255 pedit keys")
+	}
+}
+
+func TestTcActString(t *testing.T) {
+	if s := TcActGotoChain(42).String(); s != "goto chain 42" {
+		t.Fatalf("Unexpected string %q", s)
+	}
+	if s := TC_ACT_SHOT.String(); s != "shot" {
+		t.Fatalf("Unexpected string %q", s)
+	}
+}
+
+func TestFilterActionsAddDel(t *testing.T) {
+	// connmark zones and tunnel_key tos/ttl were added in kernel 4.19
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	_, link := setupLinkForTestWithQdisc(t, "foo")
+	_, link2 := setupLinkForTestWithQdisc(t, "bar")
+	actions := testActions(link2.Attrs().Index)
+	filter := &MatchAll{
+		FilterAttrs: FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    HANDLE_MIN_INGRESS,
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: actions,
+	}
+	if err := FilterAdd(filter); err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := FilterList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 {
+		t.Fatal("Failed to add filter")
+	}
+	matchall, ok := filters[0].(*MatchAll)
+	if !ok {
+		t.Fatal("Filter is the wrong type")
+	}
+	if len(matchall.Actions) != len(actions) {
+		t.Fatalf("Expected %d actions, got %d", len(actions), len(matchall.Actions))
+	}
+	for i, action := range matchall.Actions {
+		if action == nil || action.Type() != actions[i].Type() {
+			t.Fatalf("Action %d has the wrong type: %v", i, action)
+		}
+		if action.Attrs().Statistics == nil || action.Attrs().Timestamp == nil {
+			t.Fatalf("Action %s has no statistics", action.Type())
+		}
+		if action.Attrs().Index == 0 {
+			t.Fatalf("Action %s has no index", action.Type())
+		}
+	}
+
+	gact := matchall.Actions[0].(*GenericAction)
+	if gact.Random == nil || *gact.Random != *actions[0].(*GenericAction).Random {
+		t.Fatalf("Gact random not parsed: %+v", gact.Random)
+	}
+	tunnelKey := matchall.Actions[1].(*TunnelKeyAction)
+	if !tunnelKey.DstAddr.Equal(net.IPv4(10, 0, 0, 2)) || tunnelKey.KeyID != 0x1234 ||
+		tunnelKey.DestPort != 4789 || tunnelKey.TTL != 64 || !tunnelKey.NoCsum {
+		t.Fatalf("Tunnel key not parsed: %+v", tunnelKey)
+	}
+	skbedit := matchall.Actions[2].(*SkbEditAction)
+	if skbedit.Mark == nil || *skbedit.Mark != 0x10 || skbedit.Priority == nil || *skbedit.Priority != MakeHandle(1, 2) {
+		t.Fatalf("Skbedit not parsed: %+v", skbedit)
+	}
+	vlan := matchall.Actions[3].(*VlanAction)
+	if vlan.VlanAction != TCA_VLAN_ACT_PUSH || vlan.VlanId != 100 || vlan.VlanProtocol != unix.ETH_P_8021AD || vlan.VlanPrio != 5 {
+		t.Fatalf("Vlan push not parsed: %+v", vlan)
+	}
+	if vlan := matchall.Actions[4].(*VlanAction); vlan.VlanAction != TCA_VLAN_ACT_POP {
+		t.Fatalf("Vlan pop not parsed: %+v", vlan)
+	}
+	pedit := matchall.Actions[5].(*PeditAction)
+	if !reflect.DeepEqual(pedit.Keys, actions[5].(*PeditAction).Keys) {
+		t.Fatalf("Pedit keys not parsed: %+v", pedit.Keys)
+	}
+	if csum := matchall.Actions[6].(*CsumAction); csum.UpdateFlags != actions[6].(*CsumAction).UpdateFlags {
+		t.Fatalf("Csum not parsed: %+v", csum)
+	}
+	police := matchall.Actions[7].(*PoliceAction)
+	if police.Rate != 125000 || police.Burst != 10000 || police.ExceedAction != TC_POLICE_SHOT || police.NotExceedAction != TC_POLICE_PIPE {
+		t.Fatalf("Police not parsed: %+v", police)
+	}
+	if connmark := matchall.Actions[8].(*ConnmarkAction); connmark.Zone != 7 {
+		t.Fatalf("Connmark not parsed: %+v", connmark)
+	}
+	if mirred := matchall.Actions[9].(*MirredAction); mirred.Ifindex != link2.Attrs().Index {
+		t.Fatalf("Mirred not parsed: %+v", mirred)
+	}
+
+	if err := FilterDel(filter); err != nil {
+		t.Fatal(err)
+	}
+	filters, err = FilterList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 0 {
+		t.Fatal("Failed to remove filter")
+	}
+}
+
+func TestChainAddDel(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Clsact{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    HANDLE_CLSACT,
+		},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := Chain{
+		LinkIndex: link.Attrs().Index,
+		Parent:    HANDLE_MIN_INGRESS,
+		Chain:     7,
+	}
+	if err := ChainAdd(chain); err != nil {
+		t.Fatal(err)
+	}
+	chains, err := ChainList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != 1 {
+		t.Fatalf("Expected 1 chain, got %v", chains)
+	}
+	if chains[0].Chain != 7 || chains[0].LinkIndex != link.Attrs().Index || chains[0].Template != nil {
+		t.Fatalf("Chain not added properly: %s", chains[0])
+	}
+	if err := ChainAdd(chain); err == nil {
+		t.Fatal("Adding an existing chain should fail")
+	}
+	if err := ChainDel(chain); err != nil {
+		t.Fatal(err)
+	}
+	chains, err = ChainList(link, HANDLE_MIN_INGRESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != 0 {
+		t.Fatalf("Chain not removed properly: %v", chains)
+	}
+}
+
+func TestFilterBlockChain(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Clsact{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    HANDLE_CLSACT,
+		},
+		IngressBlock: 10,
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+
+	template := &Flower{
+		FilterAttrs: FilterAttrs{Protocol: unix.ETH_P_IP},
+		IPProto:     unix.IPPROTO_TCP,
+		DestIPMask:  net.CIDRMask(24, 32),
+		DestIP:      net.IPv4zero,
+	}
+	if err := ChainAdd(Chain{Block: 10, Chain: 3, Template: template}); err != nil {
+		t.Fatal(err)
+	}
+	chains, err := ChainListBlock(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != 1 || chains[0].Block != 10 || chains[0].Chain != 3 || chains[0].Template == nil {
+		t.Fatalf("Chain template not added properly: %v", chains)
+	}
+
+	chain := uint32(3)
+	filter := &Flower{
+		FilterAttrs: FilterAttrs{
+			Block:    10,
+			Priority: 1,
+			Protocol: unix.ETH_P_IP,
+			Chain:    &chain,
+		},
+		IPProto:    unix.IPPROTO_TCP,
+		DestIP:     net.IPv4(10, 0, 0, 0),
+		DestIPMask: net.CIDRMask(24, 32),
+		Actions:    []Action{&GenericAction{ActionAttrs: ActionAttrs{Action: TC_ACT_OK}}},
+	}
+	if err := FilterAdd(filter); err != nil {
+		t.Fatal(err)
+	}
+	filters, err := FilterListBlock(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 {
+		t.Fatalf("Expected 1 filter, got %v", filters)
+	}
+	attrs := filters[0].Attrs()
+	if attrs.Block != 10 || attrs.LinkIndex != 0 || attrs.Chain == nil || *attrs.Chain != 3 {
+		t.Fatalf("Filter not added properly: %s", attrs)
+	}
+	if err := FilterDel(filter); err != nil {
+		t.Fatal(err)
+	}
+	filters, err = FilterListBlock(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 0 {
+		t.Fatal("Filter not removed properly")
+	}
+	if err := ChainDel(Chain{Block: 10, Chain: 3}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,6 +3,7 @@
 package netlink
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 
@@ -97,6 +98,12 @@ func (h *Handle) FouAdd(f Fou) error {
 	return nil
 }
 
+// FouAddContext is like FouAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FouAddContext(ctx context.Context, f Fou) error {
+	return h.withContext(ctx).FouAdd(f)
+}
+
 func FouDel(f Fou) error {
 	return pkgHandle.FouDel(f)
 }
@@ -132,6 +139,12 @@ func (h *Handle) FouDel(f Fou) error {
 	return nil
 }
 
+// FouDelContext is like FouDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FouDelContext(ctx context.Context, f Fou) error {
+	return h.withContext(ctx).FouDel(f)
+}
+
 func FouList(fam int) ([]Fou, error) {
 	return pkgHandle.FouList(fam)
 }
@@ -172,6 +185,12 @@ func (h *Handle) FouList(fam int) ([]Fou, error) {
 	return fous, nil
 }
 
+// FouListContext is like FouList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) FouListContext(ctx context.Context, fam int) ([]Fou, error) {
+	return h.withContext(ctx).FouList(fam)
+}
+
 func deserializeFouMsg(msg []byte) (Fou, error) {
 	// we'll skip to byte 4 to first attribute
 	msg = msg[3:]
@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -140,6 +141,12 @@ func (h *Handle) GenlFamilyList() ([]*GenlFamily, error) {
 	return parseFamilies(msgs)
 }
 
+// GenlFamilyListContext is like GenlFamilyList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GenlFamilyListContext(ctx context.Context) ([]*GenlFamily, error) {
+	return h.withContext(ctx).GenlFamilyList()
+}
+
 func GenlFamilyList() ([]*GenlFamily, error) {
 	return pkgHandle.GenlFamilyList()
 }
@@ -163,6 +170,12 @@ func (h *Handle) GenlFamilyGet(name string) (*GenlFamily, error) {
 	return families[0], nil
 }
 
+// GenlFamilyGetContext is like GenlFamilyGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GenlFamilyGetContext(ctx context.Context, name string) (*GenlFamily, error) {
+	return h.withContext(ctx).GenlFamilyGet(name)
+}
+
 func GenlFamilyGet(name string) (*GenlFamily, error) {
 	return pkgHandle.GenlFamilyGet(name)
 }
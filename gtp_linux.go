@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
@@ -92,6 +93,12 @@ func (h *Handle) GTPPDPList() ([]*PDP, error) {
 	return parsePDP(msgs)
 }
 
+// GTPPDPListContext is like GTPPDPList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPListContext(ctx context.Context) ([]*PDP, error) {
+	return h.withContext(ctx).GTPPDPList()
+}
+
 func GTPPDPList() ([]*PDP, error) {
 	return pkgHandle.GTPPDPList()
 }
@@ -128,6 +135,12 @@ func (h *Handle) GTPPDPByTID(link Link, tid int) (*PDP, error) {
 	return gtpPDPGet(req)
 }
 
+// GTPPDPByTIDContext is like GTPPDPByTID but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPByTIDContext(ctx context.Context, link Link, tid int) (*PDP, error) {
+	return h.withContext(ctx).GTPPDPByTID(link, tid)
+}
+
 func GTPPDPByTID(link Link, tid int) (*PDP, error) {
 	return pkgHandle.GTPPDPByTID(link, tid)
 }
@@ -149,6 +162,12 @@ func (h *Handle) GTPPDPByITEI(link Link, itei int) (*PDP, error) {
 	return gtpPDPGet(req)
 }
 
+// GTPPDPByITEIContext is like GTPPDPByITEI but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPByITEIContext(ctx context.Context, link Link, itei int) (*PDP, error) {
+	return h.withContext(ctx).GTPPDPByITEI(link, itei)
+}
+
 func GTPPDPByITEI(link Link, itei int) (*PDP, error) {
 	return pkgHandle.GTPPDPByITEI(link, itei)
 }
@@ -170,6 +189,12 @@ func (h *Handle) GTPPDPByMSAddress(link Link, addr net.IP) (*PDP, error) {
 	return gtpPDPGet(req)
 }
 
+// GTPPDPByMSAddressContext is like GTPPDPByMSAddress but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPByMSAddressContext(ctx context.Context, link Link, addr net.IP) (*PDP, error) {
+	return h.withContext(ctx).GTPPDPByMSAddress(link, addr)
+}
+
 func GTPPDPByMSAddress(link Link, addr net.IP) (*PDP, error) {
 	return pkgHandle.GTPPDPByMSAddress(link, addr)
 }
@@ -204,6 +229,12 @@ func (h *Handle) GTPPDPAdd(link Link, pdp *PDP) error {
 	return err
 }
 
+// GTPPDPAddContext is like GTPPDPAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPAddContext(ctx context.Context, link Link, pdp *PDP) error {
+	return h.withContext(ctx).GTPPDPAdd(link, pdp)
+}
+
 func GTPPDPAdd(link Link, pdp *PDP) error {
 	return pkgHandle.GTPPDPAdd(link, pdp)
 }
@@ -234,6 +265,12 @@ func (h *Handle) GTPPDPDel(link Link, pdp *PDP) error {
 	return err
 }
 
+// GTPPDPDelContext is like GTPPDPDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GTPPDPDelContext(ctx context.Context, link Link, pdp *PDP) error {
+	return h.withContext(ctx).GTPPDPDel(link, pdp)
+}
+
 func GTPPDPDel(link Link, pdp *PDP) error {
 	return pkgHandle.GTPPDPDel(link, pdp)
 }
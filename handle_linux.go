@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -19,6 +20,8 @@ var pkgHandle = &Handle{}
 type Handle struct {
 	sockets      map[int]*nl.SocketHandle
 	lookupByDump bool
+	dumpRetries  int
+	ctx          context.Context
 }
 
 // SupportsNetlinkFamily reports whether the passed netlink family is supported by this Handle
@@ -55,6 +58,27 @@ func (h *Handle) SetSocketTimeout(to time.Duration) error {
 	return nil
 }
 
+// SetDumpRetries sets how many times a dump request issued through the
+// package functions (e.g. LinkList or RouteList) is repeated when the kernel
+// reports that the table changed while it was being dumped. See
+// Handle.SetDumpRetries.
+func SetDumpRetries(retries int) error {
+	return pkgHandle.SetDumpRetries(retries)
+}
+
+// SetDumpRetries sets how many times a dump request (e.g. LinkList or
+// RouteList) issued through the handle is repeated when the kernel reports
+// that the table changed while it was being dumped. When the retries are
+// exhausted, or if retries is zero (the default), the possibly incomplete or
+// inconsistent result is returned along with ErrDumpInterrupted.
+func (h *Handle) SetDumpRetries(retries int) error {
+	if retries < 0 {
+		return fmt.Errorf("invalid number of dump retries %d", retries)
+	}
+	h.dumpRetries = retries
+	return nil
+}
+
 // SetSocketReceiveBufferSize sets the receive buffer size for each
 // socket in the netlink handle. The maximum value is capped by
 // /proc/sys/net/core/rmem_max.
@@ -128,17 +152,35 @@ func (h *Handle) Delete() {
 	h.sockets = nil
 }
 
+// withContext returns a shallow copy of the handle whose requests are bound
+// to ctx. The copy shares the sockets of h, so it must not be deleted.
+func (h *Handle) withContext(ctx context.Context) *Handle {
+	if ctx == nil {
+		panic("nil context")
+	}
+	hc := *h
+	hc.ctx = ctx
+	return &hc
+}
+
 func (h *Handle) newNetlinkRequest(proto, flags int) *nl.NetlinkRequest {
+	var req *nl.NetlinkRequest
 	// Do this so that package API still use nl package variable nextSeqNr
 	if h.sockets == nil {
-		return nl.NewNetlinkRequest(proto, flags)
+		req = nl.NewNetlinkRequest(proto, flags)
+	} else {
+		req = &nl.NetlinkRequest{
+			NlMsghdr: unix.NlMsghdr{
+				Len:   uint32(unix.SizeofNlMsghdr),
+				Type:  uint16(proto),
+				Flags: unix.NLM_F_REQUEST | uint16(flags),
+			},
+			Sockets: h.sockets,
+		}
 	}
-	return &nl.NetlinkRequest{
-		NlMsghdr: unix.NlMsghdr{
-			Len:   uint32(unix.SizeofNlMsghdr),
-			Type:  uint16(proto),
-			Flags: unix.NLM_F_REQUEST | uint16(flags),
-		},
-		Sockets: h.sockets,
+	req.DumpRetries = h.dumpRetries
+	if h.ctx != nil {
+		return req.WithContext(h.ctx)
 	}
+	return req
 }
@@ -3,6 +3,7 @@
 package netlink
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -157,6 +158,60 @@ func TestHandleReceiveBuffer(t *testing.T) {
 	}
 }
 
+func TestHandleContext(t *testing.T) {
+	h, err := NewHandle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.LinkListContext(ctx); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := h.LinkListContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The context must not leak into the handle it was derived from
+	if h.ctx != nil {
+		t.Fatal("Context leaked into the parent handle")
+	}
+}
+
+func TestHandleDumpRetries(t *testing.T) {
+	h, err := NewHandle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Delete()
+
+	if err := h.SetDumpRetries(-1); err == nil {
+		t.Fatal("Expected error for a negative number of retries")
+	}
+	if err := h.SetDumpRetries(3); err != nil {
+		t.Fatal(err)
+	}
+	req := h.newNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	if req.DumpRetries != 3 {
+		t.Fatalf("Expected request to be retried 3 times, got %d", req.DumpRetries)
+	}
+	if _, err := h.LinkList(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetDumpRetries(2); err != nil {
+		t.Fatal(err)
+	}
+	defer SetDumpRetries(0)
+	if req := pkgHandle.newNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP); req.DumpRetries != 2 {
+		t.Fatalf("Expected package requests to be retried 2 times, got %d", req.DumpRetries)
+	}
+}
+
 func verifySockTimeVal(t *testing.T, fd int, tv unix.Timeval) {
 	var (
 		tr unix.Timeval
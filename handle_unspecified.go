@@ -33,6 +33,10 @@ func (h *Handle) SetSocketTimeout(to time.Duration) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) SetDumpRetries(retries int) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) SetPromiscOn(link Link) error {
 	return ErrNotImplemented
 }
@@ -197,6 +201,50 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 	return nil, ErrNotImplemented
 }
 
+func (h *Handle) FilterListBlock(block uint32) ([]Filter, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) ChainAdd(chain Chain) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ChainDel(chain Chain) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ChainList(link Link, parent uint32) ([]Chain, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) ChainListBlock(block uint32) ([]Chain, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) ActionAdd(action Action) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ActionReplace(action Action) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ActionDel(action Action) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ActionFlush(kind string) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) ActionGet(kind string, index int) (Action, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) ActionList(kind string) ([]Action, error) {
+	return nil, ErrNotImplemented
+}
+
 func (h *Handle) NeighAdd(neigh *Neigh) error {
 	return ErrNotImplemented
 }
@@ -0,0 +1,729 @@
+package netlink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// IPSetEntry is an entry of an ipset, the fields used depend on the type of
+// the set: IP and CIDR for the hash:ip and hash:net types, IP, Protocol and
+// Port for hash:ip,port and Port for bitmap:port.
+type IPSetEntry struct {
+	IP   net.IP
+	CIDR uint8
+	// IPTo and PortTo make a range of entries, added or deleted at once
+	IPTo     net.IP
+	Protocol *uint8
+	Port     *uint16
+	PortTo   *uint16
+	// Timeout in seconds, zero makes the entry permanent in a set with a
+	// default timeout
+	Timeout *uint32
+	Packets *uint64
+	Bytes   *uint64
+	Comment string
+	// Replace updates an existing entry instead of failing
+	Replace bool
+}
+
+// IPSetResult is a set as listed by the kernel along with its entries
+type IPSetResult struct {
+	SetName  string
+	TypeName string
+	Revision uint8
+	Family   uint8
+
+	HashSize     uint32
+	MaxElements  uint32
+	References   uint32
+	SizeInMemory uint32
+	NumEntries   uint32
+	// CadtFlags is a mask of the nl.IPSET_FLAG_WITH_* flags
+	CadtFlags uint32
+	// Timeout is the default timeout of the entries in seconds
+	Timeout  *uint32
+	PortFrom uint16
+	PortTo   uint16
+
+	Entries []IPSetEntry
+}
+
+// IpsetCreateOptions are the options of a new set
+type IpsetCreateOptions struct {
+	// Replace does not fail when a set of the same type already exists
+	Replace bool
+	// Timeout is the default timeout of the entries in seconds
+	Timeout  *uint32
+	Counters bool
+	Comments bool
+
+	// Family is unix.AF_INET, the default for the hash types, or
+	// unix.AF_INET6
+	Family uint8
+	// Revision of the set type, the latest supported by the kernel when zero
+	Revision    uint8
+	HashSize    uint32
+	MaxElements uint32
+	// PortFrom and PortTo are the range of a bitmap:port set
+	PortFrom uint16
+	PortTo   uint16
+}
+
+// IPSetError is an ipset specific error reported by the kernel
+type IPSetError uintptr
+
+func (e IPSetError) Error() string {
+	switch int(e) {
+	case nl.IPSET_ERR_PROTOCOL:
+		return "ipset protocol error"
+	case nl.IPSET_ERR_FIND_TYPE:
+		return "set type not supported"
+	case nl.IPSET_ERR_MAX_SETS:
+		return "maximal number of sets reached"
+	case nl.IPSET_ERR_BUSY:
+		return "set is in use by a kernel component"
+	case nl.IPSET_ERR_EXIST_SETNAME2:
+		return "a set with the new name already exists"
+	case nl.IPSET_ERR_TYPE_MISMATCH:
+		return "the types of the sets do not match"
+	case nl.IPSET_ERR_EXIST:
+		return "entry already added or missing"
+	case nl.IPSET_ERR_INVALID_CIDR:
+		return "invalid CIDR"
+	case nl.IPSET_ERR_INVALID_NETMASK:
+		return "invalid netmask"
+	case nl.IPSET_ERR_INVALID_FAMILY:
+		return "invalid family"
+	case nl.IPSET_ERR_TIMEOUT:
+		return "timeout cannot be used: set was created without timeout support"
+	case nl.IPSET_ERR_REFERENCED:
+		return "set is referenced by another set"
+	case nl.IPSET_ERR_IPADDR_IPV4:
+		return "IPv4 address expected"
+	case nl.IPSET_ERR_IPADDR_IPV6:
+		return "IPv6 address expected"
+	case nl.IPSET_ERR_COUNTER:
+		return "packet/byte counters cannot be used: set was created without counter support"
+	case nl.IPSET_ERR_COMMENT:
+		return "comment cannot be used: set was created without comment support"
+	case nl.IPSET_ERR_INVALID_MARKMASK:
+		return "invalid markmask"
+	case nl.IPSET_ERR_SKBINFO:
+		return "skbinfo mapping cannot be used: set was created without skbinfo support"
+	}
+	if int(e) >= nl.IPSET_ERR_TYPE_SPECIFIC {
+		return fmt.Sprintf("set type specific error %d", int(e))
+	}
+	return fmt.Sprintf("ipset error %d", int(e))
+}
+
+// IpsetProtocol returns the ipset protocol version of the kernel and the
+// minimal version it supports
+func IpsetProtocol() (uint8, uint8, error) {
+	return pkgHandle.IpsetProtocol()
+}
+
+// IpsetCreate creates a set of type typename
+// ipset create setname typename [options]
+func IpsetCreate(setname, typename string, options IpsetCreateOptions) error {
+	return pkgHandle.IpsetCreate(setname, typename, options)
+}
+
+// IpsetDestroy destroys a set which is not referenced
+// ipset destroy setname
+func IpsetDestroy(setname string) error {
+	return pkgHandle.IpsetDestroy(setname)
+}
+
+// IpsetFlush deletes all the entries of a set
+// ipset flush setname
+func IpsetFlush(setname string) error {
+	return pkgHandle.IpsetFlush(setname)
+}
+
+// IpsetRename renames a set
+// ipset rename from to
+func IpsetRename(from, to string) error {
+	return pkgHandle.IpsetRename(from, to)
+}
+
+// IpsetSwap swaps the content of two sets of the same type
+// ipset swap from to
+func IpsetSwap(from, to string) error {
+	return pkgHandle.IpsetSwap(from, to)
+}
+
+// IpsetList returns a set along with its entries
+// ipset list setname
+func IpsetList(setname string) (*IPSetResult, error) {
+	return pkgHandle.IpsetList(setname)
+}
+
+// IpsetListAll returns all the sets along with their entries
+// ipset list
+func IpsetListAll() ([]IPSetResult, error) {
+	return pkgHandle.IpsetListAll()
+}
+
+// IpsetAdd adds an entry to a set
+// ipset add setname entry [options]
+func IpsetAdd(setname string, entry *IPSetEntry) error {
+	return pkgHandle.IpsetAdd(setname, entry)
+}
+
+// IpsetDel deletes an entry of a set
+// ipset del setname entry
+func IpsetDel(setname string, entry *IPSetEntry) error {
+	return pkgHandle.IpsetDel(setname, entry)
+}
+
+// IpsetTest returns whether an entry is in a set
+// ipset test setname entry
+func IpsetTest(setname string, entry *IPSetEntry) (bool, error) {
+	return pkgHandle.IpsetTest(setname, entry)
+}
+
+// IpsetProtocol returns the ipset protocol version of the kernel and the
+// minimal version it supports using the netlink handle passed
+func (h *Handle) IpsetProtocol() (uint8, uint8, error) {
+	req := h.newIpsetRequest(nl.IPSET_CMD_PROTOCOL, 0)
+	res, err := ipsetExecute(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	result, err := parseIpsetResults(res)
+	if err != nil || len(result) == 0 {
+		return 0, 0, err
+	}
+	return result[0].protocol, result[0].protocolMin, nil
+}
+
+// IpsetCreate creates a set of type typename using the netlink handle passed
+// ipset create setname typename [options]
+func (h *Handle) IpsetCreate(setname, typename string, options IpsetCreateOptions) error {
+	if err := checkIpsetName(setname); err != nil {
+		return err
+	}
+	family := ipsetFamily(typename, options.Family)
+	revision := options.Revision
+	if revision == 0 {
+		var err error
+		if revision, err = h.ipsetTypeRevision(typename, family); err != nil {
+			return err
+		}
+	}
+
+	flags := unix.NLM_F_ACK | unix.NLM_F_CREATE
+	if !options.Replace {
+		flags |= unix.NLM_F_EXCL
+	}
+	req := h.newIpsetRequest(nl.IPSET_CMD_CREATE, flags)
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_SETNAME, nl.ZeroTerminated(setname)))
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_TYPENAME, nl.ZeroTerminated(typename)))
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_REVISION, nl.Uint8Attr(revision)))
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_FAMILY, nl.Uint8Attr(family)))
+
+	data := nl.NewRtAttr(nl.IPSET_ATTR_DATA|int(nl.NLA_F_NESTED), nil)
+	if options.Timeout != nil {
+		ipsetUint32Attr(data, nl.IPSET_ATTR_TIMEOUT, *options.Timeout)
+	}
+	var cadtFlags uint32
+	if options.Counters {
+		cadtFlags |= nl.IPSET_FLAG_WITH_COUNTERS
+	}
+	if options.Comments {
+		cadtFlags |= nl.IPSET_FLAG_WITH_COMMENT
+	}
+	if cadtFlags != 0 {
+		ipsetUint32Attr(data, nl.IPSET_ATTR_CADT_FLAGS, cadtFlags)
+	}
+	if options.HashSize != 0 {
+		ipsetUint32Attr(data, nl.IPSET_ATTR_HASHSIZE, options.HashSize)
+	}
+	if options.MaxElements != 0 {
+		ipsetUint32Attr(data, nl.IPSET_ATTR_MAXELEM, options.MaxElements)
+	}
+	if strings.HasPrefix(typename, "bitmap:port") {
+		ipsetUint16Attr(data, nl.IPSET_ATTR_PORT_FROM, options.PortFrom)
+		ipsetUint16Attr(data, nl.IPSET_ATTR_PORT_TO, options.PortTo)
+	}
+	req.AddData(data)
+
+	_, err := ipsetExecute(req)
+	return err
+}
+
+// IpsetCreateContext is like IpsetCreate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetCreateContext(ctx context.Context, setname, typename string, options IpsetCreateOptions) error {
+	return h.withContext(ctx).IpsetCreate(setname, typename, options)
+}
+
+// IpsetDestroy destroys a set which is not referenced using the netlink handle passed
+// ipset destroy setname
+func (h *Handle) IpsetDestroy(setname string) error {
+	return h.ipsetSetCmd(nl.IPSET_CMD_DESTROY, setname)
+}
+
+// IpsetDestroyContext is like IpsetDestroy but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetDestroyContext(ctx context.Context, setname string) error {
+	return h.withContext(ctx).IpsetDestroy(setname)
+}
+
+// IpsetFlush deletes all the entries of a set using the netlink handle passed
+// ipset flush setname
+func (h *Handle) IpsetFlush(setname string) error {
+	return h.ipsetSetCmd(nl.IPSET_CMD_FLUSH, setname)
+}
+
+// IpsetFlushContext is like IpsetFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetFlushContext(ctx context.Context, setname string) error {
+	return h.withContext(ctx).IpsetFlush(setname)
+}
+
+// IpsetRename renames a set using the netlink handle passed
+// ipset rename from to
+func (h *Handle) IpsetRename(from, to string) error {
+	return h.ipsetSetCmd(nl.IPSET_CMD_RENAME, from, to)
+}
+
+// IpsetRenameContext is like IpsetRename but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetRenameContext(ctx context.Context, from, to string) error {
+	return h.withContext(ctx).IpsetRename(from, to)
+}
+
+// IpsetSwap swaps the content of two sets of the same type using the netlink handle passed
+// ipset swap from to
+func (h *Handle) IpsetSwap(from, to string) error {
+	return h.ipsetSetCmd(nl.IPSET_CMD_SWAP, from, to)
+}
+
+// IpsetSwapContext is like IpsetSwap but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetSwapContext(ctx context.Context, from, to string) error {
+	return h.withContext(ctx).IpsetSwap(from, to)
+}
+
+// IpsetList returns a set along with its entries using the netlink handle passed
+// ipset list setname
+func (h *Handle) IpsetList(setname string) (*IPSetResult, error) {
+	if err := checkIpsetName(setname); err != nil {
+		return nil, err
+	}
+	result, err := h.ipsetList(setname)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, unix.ENOENT
+	}
+	return &result[0], nil
+}
+
+// IpsetListContext is like IpsetList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetListContext(ctx context.Context, setname string) (*IPSetResult, error) {
+	return h.withContext(ctx).IpsetList(setname)
+}
+
+// IpsetListAll returns all the sets along with their entries using the netlink handle passed
+// ipset list
+func (h *Handle) IpsetListAll() ([]IPSetResult, error) {
+	return h.ipsetList("")
+}
+
+// IpsetListAllContext is like IpsetListAll but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetListAllContext(ctx context.Context) ([]IPSetResult, error) {
+	return h.withContext(ctx).IpsetListAll()
+}
+
+// IpsetAdd adds an entry to a set using the netlink handle passed
+// ipset add setname entry [options]
+func (h *Handle) IpsetAdd(setname string, entry *IPSetEntry) error {
+	flags := unix.NLM_F_ACK
+	if !entry.Replace {
+		flags |= unix.NLM_F_EXCL
+	}
+	_, err := h.ipsetEntryCmd(nl.IPSET_CMD_ADD, flags, setname, entry)
+	return err
+}
+
+// IpsetAddContext is like IpsetAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetAddContext(ctx context.Context, setname string, entry *IPSetEntry) error {
+	return h.withContext(ctx).IpsetAdd(setname, entry)
+}
+
+// IpsetDel deletes an entry of a set using the netlink handle passed
+// ipset del setname entry
+func (h *Handle) IpsetDel(setname string, entry *IPSetEntry) error {
+	_, err := h.ipsetEntryCmd(nl.IPSET_CMD_DEL, unix.NLM_F_ACK|unix.NLM_F_EXCL, setname, entry)
+	return err
+}
+
+// IpsetDelContext is like IpsetDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetDelContext(ctx context.Context, setname string, entry *IPSetEntry) error {
+	return h.withContext(ctx).IpsetDel(setname, entry)
+}
+
+// IpsetTest returns whether an entry is in a set using the netlink handle passed
+// ipset test setname entry
+func (h *Handle) IpsetTest(setname string, entry *IPSetEntry) (bool, error) {
+	_, err := h.ipsetEntryCmd(nl.IPSET_CMD_TEST, unix.NLM_F_ACK, setname, entry)
+	if err == IPSetError(nl.IPSET_ERR_EXIST) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IpsetTestContext is like IpsetTest but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) IpsetTestContext(ctx context.Context, setname string, entry *IPSetEntry) (bool, error) {
+	return h.withContext(ctx).IpsetTest(setname, entry)
+}
+
+func (h *Handle) newIpsetRequest(cmd, flags int) *nl.NetlinkRequest {
+	req := h.newNetlinkRequest((nl.NFNL_SUBSYS_IPSET<<8)|cmd, flags)
+	msg := &nl.Nfgenmsg{
+		NfgenFamily: uint8(unix.AF_INET),
+		Version:     nl.NFNETLINK_V0,
+		ResId:       0,
+	}
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_PROTOCOL, nl.Uint8Attr(nl.IPSET_PROTOCOL)))
+	return req
+}
+
+// ipsetExecute executes req and turns the ipset specific errnos into an
+// IPSetError
+func ipsetExecute(req *nl.NetlinkRequest) ([][]byte, error) {
+	res, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	var errno syscall.Errno
+	if errors.As(err, &errno) && errno >= nl.IPSET_ERR_PRIVATE {
+		return nil, IPSetError(errno)
+	}
+	return res, err
+}
+
+func checkIpsetName(setname string) error {
+	if setname == "" || len(setname) >= nl.IPSET_MAXNAMELEN {
+		return fmt.Errorf("invalid set name %q", setname)
+	}
+	return nil
+}
+
+// ipsetFamily returns the family of a new set: the bitmap and list types
+// have none, the hash types default to IPv4
+func ipsetFamily(typename string, family uint8) uint8 {
+	if !strings.HasPrefix(typename, "hash:") {
+		return unix.AF_UNSPEC
+	}
+	if family == 0 {
+		return unix.AF_INET
+	}
+	return family
+}
+
+// ipsetTypeRevision returns the latest revision of a set type supported by
+// the kernel
+func (h *Handle) ipsetTypeRevision(typename string, family uint8) (uint8, error) {
+	req := h.newIpsetRequest(nl.IPSET_CMD_TYPE, 0)
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_TYPENAME, nl.ZeroTerminated(typename)))
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_FAMILY, nl.Uint8Attr(family)))
+	res, err := ipsetExecute(req)
+	if err != nil {
+		return 0, err
+	}
+	result, err := parseIpsetResults(res)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("no revision returned for set type %s", typename)
+	}
+	return result[0].Revision, nil
+}
+
+// ipsetSetCmd runs a command taking one or two set names
+func (h *Handle) ipsetSetCmd(cmd int, setnames ...string) error {
+	req := h.newIpsetRequest(cmd, unix.NLM_F_ACK)
+	for i, setname := range setnames {
+		if err := checkIpsetName(setname); err != nil {
+			return err
+		}
+		attrType := nl.IPSET_ATTR_SETNAME
+		if i > 0 {
+			attrType = nl.IPSET_ATTR_SETNAME2
+		}
+		req.AddData(nl.NewRtAttr(attrType, nl.ZeroTerminated(setname)))
+	}
+	_, err := ipsetExecute(req)
+	return err
+}
+
+func (h *Handle) ipsetEntryCmd(cmd, flags int, setname string, entry *IPSetEntry) ([][]byte, error) {
+	if err := checkIpsetName(setname); err != nil {
+		return nil, err
+	}
+	req := h.newIpsetRequest(cmd, flags)
+	req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_SETNAME, nl.ZeroTerminated(setname)))
+	data, err := entry.toNlData()
+	if err != nil {
+		return nil, err
+	}
+	req.AddData(data)
+	return ipsetExecute(req)
+}
+
+func (h *Handle) ipsetList(setname string) ([]IPSetResult, error) {
+	req := h.newIpsetRequest(nl.IPSET_CMD_LIST, unix.NLM_F_DUMP)
+	if setname != "" {
+		req.AddData(nl.NewRtAttr(nl.IPSET_ATTR_SETNAME, nl.ZeroTerminated(setname)))
+	}
+	res, err := ipsetExecute(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseIpsetResults(res)
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]IPSetResult, len(result))
+	for i := range result {
+		sets[i] = result[i].IPSetResult
+	}
+	return sets, nil
+}
+
+// The integer attributes of ipset are in network byte order and flagged so
+func ipsetUint16Attr(parent *nl.RtAttr, attrType int, v uint16) {
+	nl.NewRtAttrChild(parent, attrType|int(nl.NLA_F_NET_BYTEORDER), htons(v))
+}
+
+func ipsetUint32Attr(parent *nl.RtAttr, attrType int, v uint32) {
+	nl.NewRtAttrChild(parent, attrType|int(nl.NLA_F_NET_BYTEORDER), htonl(v))
+}
+
+func ipsetUint64Attr(parent *nl.RtAttr, attrType int, v uint64) {
+	nl.NewRtAttrChild(parent, attrType|int(nl.NLA_F_NET_BYTEORDER), htonll(v))
+}
+
+func ipsetIPAttr(parent *nl.RtAttr, attrType int, ip net.IP) {
+	attr := nl.NewRtAttrChild(parent, attrType|int(nl.NLA_F_NESTED), nil)
+	if ip4 := ip.To4(); ip4 != nil {
+		nl.NewRtAttrChild(attr, nl.IPSET_ATTR_IPADDR_IPV4|int(nl.NLA_F_NET_BYTEORDER), ip4)
+	} else {
+		nl.NewRtAttrChild(attr, nl.IPSET_ATTR_IPADDR_IPV6|int(nl.NLA_F_NET_BYTEORDER), ip.To16())
+	}
+}
+
+// toNlData builds the data attribute of entry
+func (entry *IPSetEntry) toNlData() (*nl.RtAttr, error) {
+	data := nl.NewRtAttr(nl.IPSET_ATTR_DATA|int(nl.NLA_F_NESTED), nil)
+	if entry.IP != nil {
+		ipsetIPAttr(data, nl.IPSET_ATTR_IP, entry.IP)
+	}
+	if entry.IPTo != nil {
+		ipsetIPAttr(data, nl.IPSET_ATTR_IP_TO, entry.IPTo)
+	}
+	if entry.CIDR != 0 {
+		nl.NewRtAttrChild(data, nl.IPSET_ATTR_CIDR, nl.Uint8Attr(entry.CIDR))
+	}
+	if entry.Protocol != nil {
+		nl.NewRtAttrChild(data, nl.IPSET_ATTR_PROTO, nl.Uint8Attr(*entry.Protocol))
+	}
+	if entry.Port != nil {
+		ipsetUint16Attr(data, nl.IPSET_ATTR_PORT, *entry.Port)
+	}
+	if entry.PortTo != nil {
+		ipsetUint16Attr(data, nl.IPSET_ATTR_PORT_TO, *entry.PortTo)
+	}
+	if entry.Timeout != nil {
+		ipsetUint32Attr(data, nl.IPSET_ATTR_TIMEOUT, *entry.Timeout)
+	}
+	if entry.Packets != nil {
+		ipsetUint64Attr(data, nl.IPSET_ATTR_PACKETS, *entry.Packets)
+	}
+	if entry.Bytes != nil {
+		ipsetUint64Attr(data, nl.IPSET_ATTR_BYTES, *entry.Bytes)
+	}
+	if entry.Comment != "" {
+		if len(entry.Comment) > nl.IPSET_MAX_COMMENT_SIZE {
+			return nil, fmt.Errorf("comment longer than %d bytes", nl.IPSET_MAX_COMMENT_SIZE)
+		}
+		nl.NewRtAttrChild(data, nl.IPSET_ATTR_COMMENT, nl.ZeroTerminated(entry.Comment))
+	}
+	return data, nil
+}
+
+// ipsetResult is a parsed ipset message, the protocol versions are only
+// set by the replies of IPSET_CMD_PROTOCOL
+type ipsetResult struct {
+	IPSetResult
+	protocol    uint8
+	protocolMin uint8
+}
+
+// parseIpsetResults parses the messages of a reply. The entries of a large
+// set are split over several messages, which are merged.
+func parseIpsetResults(msgs [][]byte) ([]ipsetResult, error) {
+	var result []ipsetResult
+	for _, msg := range msgs {
+		if len(msg) < nl.SizeofNfgenmsg {
+			return nil, fmt.Errorf("ipset message too short")
+		}
+		attrs, err := nl.ParseRouteAttr(msg[nl.SizeofNfgenmsg:])
+		if err != nil {
+			return nil, err
+		}
+		var r ipsetResult
+		var entries []IPSetEntry
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.IPSET_ATTR_PROTOCOL:
+				r.protocol = attr.Value[0]
+			case nl.IPSET_ATTR_PROTOCOL_MIN:
+				r.protocolMin = attr.Value[0]
+			case nl.IPSET_ATTR_SETNAME:
+				r.SetName = ipsetString(attr.Value)
+			case nl.IPSET_ATTR_TYPENAME:
+				r.TypeName = ipsetString(attr.Value)
+			case nl.IPSET_ATTR_REVISION:
+				r.Revision = attr.Value[0]
+			case nl.IPSET_ATTR_FAMILY:
+				r.Family = attr.Value[0]
+			case nl.IPSET_ATTR_DATA:
+				if err := r.parseHeader(attr.Value); err != nil {
+					return nil, err
+				}
+			case nl.IPSET_ATTR_ADT:
+				adt, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, a := range adt {
+					entry, err := parseIPSetEntry(a.Value)
+					if err != nil {
+						return nil, err
+					}
+					entries = append(entries, entry)
+				}
+			}
+		}
+		if n := len(result); n > 0 && r.SetName != "" && result[n-1].SetName == r.SetName {
+			result[n-1].Entries = append(result[n-1].Entries, entries...)
+			continue
+		}
+		r.Entries = entries
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func ipsetString(b []byte) string {
+	return string(bytes.TrimRight(b, "\x00"))
+}
+
+func (r *IPSetResult) parseHeader(data []byte) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.IPSET_ATTR_HASHSIZE:
+			r.HashSize = ntohl(attr.Value)
+		case nl.IPSET_ATTR_MAXELEM:
+			r.MaxElements = ntohl(attr.Value)
+		case nl.IPSET_ATTR_REFERENCES:
+			r.References = ntohl(attr.Value)
+		case nl.IPSET_ATTR_MEMSIZE:
+			r.SizeInMemory = ntohl(attr.Value)
+		case nl.IPSET_ATTR_ELEMENTS:
+			r.NumEntries = ntohl(attr.Value)
+		case nl.IPSET_ATTR_CADT_FLAGS:
+			r.CadtFlags = ntohl(attr.Value)
+		case nl.IPSET_ATTR_TIMEOUT:
+			timeout := ntohl(attr.Value)
+			r.Timeout = &timeout
+		case nl.IPSET_ATTR_PORT_FROM:
+			r.PortFrom = ntohs(attr.Value)
+		case nl.IPSET_ATTR_PORT_TO:
+			r.PortTo = ntohs(attr.Value)
+		}
+	}
+	return nil
+}
+
+func parseIPSetEntry(data []byte) (IPSetEntry, error) {
+	var entry IPSetEntry
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return entry, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.IPSET_ATTR_IP:
+			if entry.IP, err = parseIpsetIP(attr.Value); err != nil {
+				return entry, err
+			}
+		case nl.IPSET_ATTR_IP_TO:
+			if entry.IPTo, err = parseIpsetIP(attr.Value); err != nil {
+				return entry, err
+			}
+		case nl.IPSET_ATTR_CIDR:
+			entry.CIDR = attr.Value[0]
+		case nl.IPSET_ATTR_PROTO:
+			protocol := attr.Value[0]
+			entry.Protocol = &protocol
+		case nl.IPSET_ATTR_PORT:
+			port := ntohs(attr.Value)
+			entry.Port = &port
+		case nl.IPSET_ATTR_PORT_TO:
+			port := ntohs(attr.Value)
+			entry.PortTo = &port
+		case nl.IPSET_ATTR_TIMEOUT:
+			timeout := ntohl(attr.Value)
+			entry.Timeout = &timeout
+		case nl.IPSET_ATTR_PACKETS:
+			packets := ntohll(attr.Value)
+			entry.Packets = &packets
+		case nl.IPSET_ATTR_BYTES:
+			bytes := ntohll(attr.Value)
+			entry.Bytes = &bytes
+		case nl.IPSET_ATTR_COMMENT:
+			entry.Comment = ipsetString(attr.Value)
+		}
+	}
+	return entry, nil
+}
+
+func parseIpsetIP(data []byte) (net.IP, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.IPSET_ATTR_IPADDR_IPV4, nl.IPSET_ATTR_IPADDR_IPV6:
+			return net.IP(append([]byte(nil), attr.Value...)), nil
+		}
+	}
+	return nil, nil
+}
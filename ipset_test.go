@@ -0,0 +1,279 @@
+// +build linux
+
+package netlink
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestIpsetProtocol(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	protocol, protocolMin, err := IpsetProtocol()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if protocol < nl.IPSET_PROTOCOL || protocolMin > nl.IPSET_PROTOCOL {
+		t.Fatalf("Unexpected protocol versions %d, %d", protocol, protocolMin)
+	}
+}
+
+func TestIpsetHashIP(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	timeout := uint32(300)
+	err := IpsetCreate("hash-ip", "hash:ip", IpsetCreateOptions{
+		Timeout:  &timeout,
+		Counters: true,
+		Comments: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := IpsetCreate("hash-ip", "hash:ip", IpsetCreateOptions{}); err == nil {
+		t.Fatal("Creating an existing set should fail")
+	}
+
+	permanent := uint32(0)
+	packets, bytes := uint64(10), uint64(1000)
+	entries := []*IPSetEntry{
+		{IP: net.ParseIP("10.0.0.1"), Comment: "first"},
+		{IP: net.ParseIP("10.0.0.2"), Timeout: &permanent, Packets: &packets, Bytes: &bytes},
+	}
+	for _, entry := range entries {
+		if err := IpsetAdd("hash-ip", entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := IpsetAdd("hash-ip", entries[0]); err != IPSetError(nl.IPSET_ERR_EXIST) {
+		t.Fatalf("Adding an existing entry should fail with IPSET_ERR_EXIST: %v", err)
+	}
+	entries[0].Replace = true
+	if err := IpsetAdd("hash-ip", entries[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := IpsetList("hash-ip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SetName != "hash-ip" || result.TypeName != "hash:ip" || result.Family != unix.AF_INET {
+		t.Fatalf("Unexpected set %+v", result)
+	}
+	if result.Timeout == nil || *result.Timeout != timeout {
+		t.Fatalf("Unexpected default timeout %v", result.Timeout)
+	}
+	want := uint32(nl.IPSET_FLAG_WITH_COUNTERS | nl.IPSET_FLAG_WITH_COMMENT)
+	if result.CadtFlags&want != want {
+		t.Fatalf("Unexpected cadt flags %#x", result.CadtFlags)
+	}
+	if result.NumEntries != 2 || len(result.Entries) != 2 {
+		t.Fatalf("Unexpected entries %+v", result.Entries)
+	}
+	for _, entry := range result.Entries {
+		switch entry.IP.String() {
+		case "10.0.0.1":
+			if entry.Comment != "first" || entry.Timeout == nil || *entry.Timeout == 0 || *entry.Timeout > timeout {
+				t.Fatalf("Unexpected entry %+v", entry)
+			}
+		case "10.0.0.2":
+			if entry.Timeout == nil || *entry.Timeout != 0 || entry.Packets == nil || *entry.Packets != packets || entry.Bytes == nil || *entry.Bytes != bytes {
+				t.Fatalf("Unexpected entry %+v", entry)
+			}
+		default:
+			t.Fatalf("Unexpected entry %+v", entry)
+		}
+	}
+
+	ok, err := IpsetTest("hash-ip", &IPSetEntry{IP: net.ParseIP("10.0.0.1")})
+	if err != nil || !ok {
+		t.Fatalf("Entry should be in the set: %v", err)
+	}
+	if err := IpsetDel("hash-ip", &IPSetEntry{IP: net.ParseIP("10.0.0.1")}); err != nil {
+		t.Fatal(err)
+	}
+	ok, err = IpsetTest("hash-ip", &IPSetEntry{IP: net.ParseIP("10.0.0.1")})
+	if err != nil || ok {
+		t.Fatalf("Entry should not be in the set: %v", err)
+	}
+
+	if err := IpsetFlush("hash-ip"); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = IpsetList("hash-ip"); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 0 {
+		t.Fatalf("Set should be empty: %+v", result.Entries)
+	}
+	if err := IpsetDestroy("hash-ip"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IpsetList("hash-ip"); err == nil {
+		t.Fatal("Listing a destroyed set should fail")
+	}
+}
+
+func TestIpsetHashNet(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	if err := IpsetCreate("hash-net", "hash:net", IpsetCreateOptions{Family: unix.AF_INET6, HashSize: 128, MaxElements: 1024}); err != nil {
+		t.Fatal(err)
+	}
+	entry := &IPSetEntry{IP: net.ParseIP("2001:db8::"), CIDR: 64}
+	if err := IpsetAdd("hash-net", entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := IpsetAdd("hash-net", &IPSetEntry{IP: net.ParseIP("10.0.0.0"), CIDR: 8}); err == nil {
+		t.Fatal("Adding an IPv4 entry to an IPv6 set should fail")
+	}
+	ok, err := IpsetTest("hash-net", &IPSetEntry{IP: net.ParseIP("2001:db8::1")})
+	if err != nil || !ok {
+		t.Fatalf("Address should match the network: %v", err)
+	}
+
+	result, err := IpsetList("hash-net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Family != unix.AF_INET6 || result.MaxElements != 1024 || result.HashSize < 128 {
+		t.Fatalf("Unexpected set %+v", result)
+	}
+	if len(result.Entries) != 1 || !result.Entries[0].IP.Equal(entry.IP) || result.Entries[0].CIDR != 64 {
+		t.Fatalf("Unexpected entries %+v", result.Entries)
+	}
+}
+
+func TestIpsetHashIPPort(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	if err := IpsetCreate("hash-ip-port", "hash:ip,port", IpsetCreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	protocol := uint8(unix.IPPROTO_TCP)
+	port, portTo := uint16(80), uint16(82)
+	entry := &IPSetEntry{IP: net.ParseIP("10.0.0.1"), Protocol: &protocol, Port: &port, PortTo: &portTo}
+	if err := IpsetAdd("hash-ip-port", entry); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := IpsetList("hash-ip-port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 3 {
+		t.Fatalf("Port range should add 3 entries: %+v", result.Entries)
+	}
+	for _, entry := range result.Entries {
+		if !entry.IP.Equal(net.ParseIP("10.0.0.1")) || entry.Protocol == nil || *entry.Protocol != protocol ||
+			entry.Port == nil || *entry.Port < port || *entry.Port > portTo {
+			t.Fatalf("Unexpected entry %+v", entry)
+		}
+	}
+
+	udp := uint8(unix.IPPROTO_UDP)
+	ok, err := IpsetTest("hash-ip-port", &IPSetEntry{IP: net.ParseIP("10.0.0.1"), Protocol: &udp, Port: &port})
+	if err != nil || ok {
+		t.Fatalf("UDP entry should not be in the set: %v", err)
+	}
+}
+
+func TestIpsetBitmapPortSwapRename(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	for _, name := range []string{"ports-a", "ports-b"} {
+		if err := IpsetCreate(name, "bitmap:port", IpsetCreateOptions{PortFrom: 1000, PortTo: 2000}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	port := uint16(1500)
+	if err := IpsetAdd("ports-a", &IPSetEntry{Port: &port}); err != nil {
+		t.Fatal(err)
+	}
+	outside := uint16(3000)
+	if err := IpsetAdd("ports-a", &IPSetEntry{Port: &outside}); err == nil {
+		t.Fatal("Adding a port outside of the range should fail")
+	}
+
+	if err := IpsetSwap("ports-a", "ports-b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := IpsetRename("ports-b", "ports-c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := IpsetRename("ports-a", "ports-c"); err != IPSetError(nl.IPSET_ERR_EXIST_SETNAME2) {
+		t.Fatalf("Renaming to an existing set should fail with IPSET_ERR_EXIST_SETNAME2: %v", err)
+	}
+
+	sets, err := IpsetListAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("Unexpected sets %+v", sets)
+	}
+	for _, set := range sets {
+		if set.TypeName != "bitmap:port" || set.PortFrom != 1000 || set.PortTo != 2000 {
+			t.Fatalf("Unexpected set %+v", set)
+		}
+		switch set.SetName {
+		case "ports-a":
+			if len(set.Entries) != 0 {
+				t.Fatalf("Unexpected entries %+v", set.Entries)
+			}
+		case "ports-c":
+			if len(set.Entries) != 1 || set.Entries[0].Port == nil || *set.Entries[0].Port != port {
+				t.Fatalf("Unexpected entries %+v", set.Entries)
+			}
+		default:
+			t.Fatalf("Unexpected set %+v", set)
+		}
+	}
+
+	if err := IpsetCreate("hash", "hash:ip", IpsetCreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := IpsetSwap("ports-a", "hash"); err != IPSetError(nl.IPSET_ERR_TYPE_MISMATCH) {
+		t.Fatalf("Swapping sets of different types should fail with IPSET_ERR_TYPE_MISMATCH: %v", err)
+	}
+}
+
+func TestIpsetEntrySerialize(t *testing.T) {
+	protocol := uint8(unix.IPPROTO_TCP)
+	port := uint16(443)
+	timeout := uint32(60)
+	packets, bytes := uint64(1), uint64(1<<40)
+	entries := []IPSetEntry{
+		{IP: net.ParseIP("192.168.0.1").To4(), CIDR: 24, Comment: "lan"},
+		{IP: net.ParseIP("2001:db8::1"), IPTo: net.ParseIP("2001:db8::ff")},
+		{IP: net.ParseIP("10.0.0.1").To4(), Protocol: &protocol, Port: &port, Timeout: &timeout, Packets: &packets, Bytes: &bytes},
+	}
+	for _, entry := range entries {
+		data, err := entry.toNlData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := parseIPSetEntry(data.Serialize()[unix.SizeofRtAttr:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, entry) {
+			t.Fatalf("Entry %+v parsed as %+v", entry, parsed)
+		}
+	}
+
+	long := IPSetEntry{Comment: string(make([]byte, nl.IPSET_MAX_COMMENT_SIZE+1))}
+	if _, err := long.toNlData(); err == nil {
+		t.Fatal("Too long comment should fail")
+	}
+}
@@ -0,0 +1,68 @@
+// +build !linux
+
+package netlink
+
+// IPSetEntry placeholder
+type IPSetEntry struct{}
+
+// IPSetResult placeholder
+type IPSetResult struct{}
+
+// IpsetCreateOptions placeholder
+type IpsetCreateOptions struct{}
+
+// IpsetProtocol returns the ipset protocol version of the kernel and the
+// minimal version it supports
+func IpsetProtocol() (uint8, uint8, error) {
+	return 0, 0, ErrNotImplemented
+}
+
+// IpsetCreate creates a set of type typename
+func IpsetCreate(setname, typename string, options IpsetCreateOptions) error {
+	return ErrNotImplemented
+}
+
+// IpsetDestroy destroys a set which is not referenced
+func IpsetDestroy(setname string) error {
+	return ErrNotImplemented
+}
+
+// IpsetFlush deletes all the entries of a set
+func IpsetFlush(setname string) error {
+	return ErrNotImplemented
+}
+
+// IpsetRename renames a set
+func IpsetRename(from, to string) error {
+	return ErrNotImplemented
+}
+
+// IpsetSwap swaps the content of two sets of the same type
+func IpsetSwap(from, to string) error {
+	return ErrNotImplemented
+}
+
+// IpsetList returns a set along with its entries
+func IpsetList(setname string) (*IPSetResult, error) {
+	return nil, ErrNotImplemented
+}
+
+// IpsetListAll returns all the sets along with their entries
+func IpsetListAll() ([]IPSetResult, error) {
+	return nil, ErrNotImplemented
+}
+
+// IpsetAdd adds an entry to a set
+func IpsetAdd(setname string, entry *IPSetEntry) error {
+	return ErrNotImplemented
+}
+
+// IpsetDel deletes an entry of a set
+func IpsetDel(setname string, entry *IPSetEntry) error {
+	return ErrNotImplemented
+}
+
+// IpsetTest returns whether an entry is in a set
+func IpsetTest(setname string, entry *IPSetEntry) (bool, error) {
+	return false, ErrNotImplemented
+}
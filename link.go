@@ -381,6 +381,48 @@ func (vxlan *Vxlan) Type() string {
 	return "vxlan"
 }
 
+// Geneve devices must specify ID and Remote on create, unless FlowBased
+type Geneve struct {
+	LinkAttrs
+	ID             uint32
+	Remote         net.IP
+	Ttl            uint8
+	TtlInherit     bool
+	Tos            uint8
+	Dport          uint16
+	FlowLabel      uint32
+	UDPCSum        bool
+	UDP6ZeroCSumTx bool
+	UDP6ZeroCSumRx bool
+	FlowBased      bool
+}
+
+func (geneve *Geneve) Attrs() *LinkAttrs {
+	return &geneve.LinkAttrs
+}
+
+func (geneve *Geneve) Type() string {
+	return "geneve"
+}
+
+// Bareudp tunnels the EtherType protocol over UDP to Port, MultiProto also
+// tunnels MPLS multicast along MPLS unicast and IPv6 along IPv4
+type Bareudp struct {
+	LinkAttrs
+	Port       uint16
+	EtherType  uint16
+	SrcPortMin uint16
+	MultiProto bool
+}
+
+func (bareudp *Bareudp) Attrs() *LinkAttrs {
+	return &bareudp.LinkAttrs
+}
+
+func (bareudp *Bareudp) Type() string {
+	return "bareudp"
+}
+
 type IPVlanMode uint16
 
 const (
@@ -712,6 +754,11 @@ type Gretap struct {
 	EncapFlags uint16
 	Link       uint32
 	FlowBased  bool
+	// EncapLimit, FlowLabel and Ip6Flags, the IP6_TNL_F_* flags, are only
+	// used by ip6gretap. A nil EncapLimit keeps the kernel default of 4.
+	EncapLimit *uint8
+	FlowLabel  uint32
+	Ip6Flags   uint32
 }
 
 func (gretap *Gretap) Attrs() *LinkAttrs {
@@ -748,6 +795,36 @@ func (iptun *Iptun) Type() string {
 	return "ipip"
 }
 
+// Ip6tnl tunnels IPv6 (ip6ip6) or IPv4 (ipip6) over IPv6 depending on
+// Proto, IPPROTO_IPV6 or IPPROTO_IPIP, or both when Proto is 0. A nil
+// EncapLimit keeps the kernel default of 4, the IP6_TNL_F_IGN_ENCAP_LIMIT flag
+// disables it.
+type Ip6tnl struct {
+	LinkAttrs
+	Link       uint32
+	Local      net.IP
+	Remote     net.IP
+	Ttl        uint8
+	EncapLimit *uint8
+	FlowLabel  uint32
+	// Flags are the IP6_TNL_F_* flags
+	Flags      uint32
+	Proto      uint8
+	EncapType  uint16
+	EncapFlags uint16
+	EncapSport uint16
+	EncapDport uint16
+	FlowBased  bool
+}
+
+func (ip6tnl *Ip6tnl) Attrs() *LinkAttrs {
+	return &ip6tnl.LinkAttrs
+}
+
+func (ip6tnl *Ip6tnl) Type() string {
+	return "ip6tnl"
+}
+
 type Sittun struct {
 	LinkAttrs
 	Link       uint32
@@ -790,6 +867,8 @@ func (vti *Vti) Type() string {
 	return "vti"
 }
 
+// Gretun is a GRE tunnel, over IPv6 when Local or Remote is an IPv6 address
+// or, in flow based mode, when IPv6 is set.
 type Gretun struct {
 	LinkAttrs
 	Link       uint32
@@ -806,6 +885,13 @@ type Gretun struct {
 	EncapFlags uint16
 	EncapSport uint16
 	EncapDport uint16
+	FlowBased  bool
+	IPv6       bool
+	// EncapLimit, FlowLabel and Ip6Flags, the IP6_TNL_F_* flags, are only
+	// used by ip6gre. A nil EncapLimit keeps the kernel default of 4.
+	EncapLimit *uint8
+	FlowLabel  uint32
+	Ip6Flags   uint32
 }
 
 func (gretun *Gretun) Attrs() *LinkAttrs {
@@ -813,12 +899,56 @@ func (gretun *Gretun) Attrs() *LinkAttrs {
 }
 
 func (gretun *Gretun) Type() string {
-	if gretun.Local.To4() == nil {
+	if gretun.IPv6 ||
+		(gretun.Local != nil && gretun.Local.To4() == nil) ||
+		(gretun.Remote != nil && gretun.Remote.To4() == nil) {
 		return "ip6gre"
 	}
 	return "gre"
 }
 
+// Erspan mirrors traffic over GRE, over IPv6 when Local or Remote is an IPv6
+// address or, in flow based mode, when IPv6 is set. IKey and OKey are the
+// session ids. Version, 0 for type I, defaults to 1 when nil. Index is only
+// used by version 1 and Direction and HardwareID by version 2.
+type Erspan struct {
+	LinkAttrs
+	Link       uint32
+	Local      net.IP
+	Remote     net.IP
+	IKey       uint32
+	OKey       uint32
+	IFlags     uint16
+	OFlags     uint16
+	Ttl        uint8
+	Tos        uint8
+	PMtuDisc   uint8
+	Version    *uint8
+	Index      uint32
+	Direction  uint8
+	HardwareID uint16
+	FlowBased  bool
+	IPv6       bool
+	// EncapLimit, FlowLabel and Ip6Flags, the IP6_TNL_F_* flags, are only
+	// used by ip6erspan. A nil EncapLimit keeps the kernel default of 4.
+	EncapLimit *uint8
+	FlowLabel  uint32
+	Ip6Flags   uint32
+}
+
+func (erspan *Erspan) Attrs() *LinkAttrs {
+	return &erspan.LinkAttrs
+}
+
+func (erspan *Erspan) Type() string {
+	if erspan.IPv6 ||
+		(erspan.Local != nil && erspan.Local.To4() == nil) ||
+		(erspan.Remote != nil && erspan.Remote.To4() == nil) {
+		return "ip6erspan"
+	}
+	return "erspan"
+}
+
 type Vrf struct {
 	LinkAttrs
 	Table uint32
@@ -848,6 +978,72 @@ func (gtp *GTP) Type() string {
 	return "gtp"
 }
 
+// MACsec cipher suites
+const (
+	MACSEC_CIPHER_ID_GCM_AES_128     uint64 = 0x0080C20001000001
+	MACSEC_CIPHER_ID_GCM_AES_256     uint64 = 0x0080C20001000002
+	MACSEC_CIPHER_ID_GCM_AES_XPN_128 uint64 = 0x0080C20001000003
+	MACSEC_CIPHER_ID_GCM_AES_XPN_256 uint64 = 0x0080C20001000004
+)
+
+type MacsecValidation uint8
+
+const (
+	MACSEC_VALIDATE_DEFAULT MacsecValidation = iota
+	MACSEC_VALIDATE_DISABLED
+	MACSEC_VALIDATE_CHECK
+	MACSEC_VALIDATE_STRICT
+)
+
+type MacsecOffload uint8
+
+const (
+	MACSEC_OFFLOAD_DEFAULT MacsecOffload = iota
+	MACSEC_OFFLOAD_OFF
+	MACSEC_OFFLOAD_PHY
+	MACSEC_OFFLOAD_MAC
+)
+
+// Macsec links have ParentIndex set in their Attrs(). The SCI is made of
+// the address of the link and Port unless set, the kernel defaults are used
+// for the other zero or nil values.
+type Macsec struct {
+	LinkAttrs
+	SCI         uint64
+	Port        uint16
+	CipherSuite uint64
+	ICVLen      uint8
+	EncodingSA  uint8
+	Encrypt     *bool
+	Protect     *bool
+	IncludeSCI  *bool
+	EndStation  *bool
+	SCB         *bool
+	// ReplayWindow is only used with ReplayProtect
+	ReplayProtect *bool
+	ReplayWindow  uint32
+	Validation    MacsecValidation
+	Offload       MacsecOffload
+}
+
+func (macsec *Macsec) Attrs() *LinkAttrs {
+	return &macsec.LinkAttrs
+}
+
+func (macsec *Macsec) Type() string {
+	return "macsec"
+}
+
+// MacsecSCI returns the secure channel identifier of the port of the
+// system with address addr
+func MacsecSCI(addr net.HardwareAddr, port uint16) uint64 {
+	var sci uint64
+	for _, b := range addr {
+		sci = sci<<8 | uint64(b)
+	}
+	return sci<<16 | uint64(port)
+}
+
 // iproute2 supported devices;
 // vlan | veth | vcan | dummy | ifb | macvlan | macvtap |
 // bridge | bond | ipoib | ip6tnl | ipip | sit | vxlan |
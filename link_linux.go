@@ -2,7 +2,9 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -43,6 +45,20 @@ var macvlanModes = [...]uint32{
 	nl.MACVLAN_MODE_SOURCE,
 }
 
+var macsecValidations = [...]uint8{
+	0,
+	nl.MACSEC_VALIDATE_DISABLED,
+	nl.MACSEC_VALIDATE_CHECK,
+	nl.MACSEC_VALIDATE_STRICT,
+}
+
+var macsecOffloads = [...]uint8{
+	0,
+	nl.MACSEC_OFFLOAD_OFF,
+	nl.MACSEC_OFFLOAD_PHY,
+	nl.MACSEC_OFFLOAD_MAC,
+}
+
 func ensureIndex(link *LinkAttrs) {
 	if link != nil && link.Index == 0 {
 		newlink, _ := LinkByName(link.Name)
@@ -76,6 +92,12 @@ func (h *Handle) LinkSetARPOff(link Link) error {
 	return err
 }
 
+// LinkSetARPOffContext is like LinkSetARPOff but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetARPOffContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetARPOff(link)
+}
+
 func LinkSetARPOff(link Link) error {
 	return pkgHandle.LinkSetARPOff(link)
 }
@@ -95,6 +117,12 @@ func (h *Handle) LinkSetARPOn(link Link) error {
 	return err
 }
 
+// LinkSetARPOnContext is like LinkSetARPOn but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetARPOnContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetARPOn(link)
+}
+
 func LinkSetARPOn(link Link) error {
 	return pkgHandle.LinkSetARPOn(link)
 }
@@ -114,6 +142,12 @@ func (h *Handle) SetPromiscOn(link Link) error {
 	return err
 }
 
+// SetPromiscOnContext is like SetPromiscOn but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) SetPromiscOnContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).SetPromiscOn(link)
+}
+
 // LinkSetAllmulticastOn enables the reception of all hardware multicast packets for the link device.
 // Equivalent to: `ip link set $link allmulticast on`
 func LinkSetAllmulticastOn(link Link) error {
@@ -138,6 +172,12 @@ func (h *Handle) LinkSetAllmulticastOn(link Link) error {
 	return err
 }
 
+// LinkSetAllmulticastOnContext is like LinkSetAllmulticastOn but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetAllmulticastOnContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetAllmulticastOn(link)
+}
+
 // LinkSetAllmulticastOff disables the reception of all hardware multicast packets for the link device.
 // Equivalent to: `ip link set $link allmulticast off`
 func LinkSetAllmulticastOff(link Link) error {
@@ -160,6 +200,12 @@ func (h *Handle) LinkSetAllmulticastOff(link Link) error {
 	return err
 }
 
+// LinkSetAllmulticastOffContext is like LinkSetAllmulticastOff but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetAllmulticastOffContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetAllmulticastOff(link)
+}
+
 func MacvlanMACAddrAdd(link Link, addr net.HardwareAddr) error {
 	return pkgHandle.MacvlanMACAddrAdd(link, addr)
 }
@@ -168,6 +214,12 @@ func (h *Handle) MacvlanMACAddrAdd(link Link, addr net.HardwareAddr) error {
 	return h.macvlanMACAddrChange(link, []net.HardwareAddr{addr}, nl.MACVLAN_MACADDR_ADD)
 }
 
+// MacvlanMACAddrAddContext is like MacvlanMACAddrAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacvlanMACAddrAddContext(ctx context.Context, link Link, addr net.HardwareAddr) error {
+	return h.withContext(ctx).MacvlanMACAddrAdd(link, addr)
+}
+
 func MacvlanMACAddrDel(link Link, addr net.HardwareAddr) error {
 	return pkgHandle.MacvlanMACAddrDel(link, addr)
 }
@@ -176,6 +228,12 @@ func (h *Handle) MacvlanMACAddrDel(link Link, addr net.HardwareAddr) error {
 	return h.macvlanMACAddrChange(link, []net.HardwareAddr{addr}, nl.MACVLAN_MACADDR_DEL)
 }
 
+// MacvlanMACAddrDelContext is like MacvlanMACAddrDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacvlanMACAddrDelContext(ctx context.Context, link Link, addr net.HardwareAddr) error {
+	return h.withContext(ctx).MacvlanMACAddrDel(link, addr)
+}
+
 func MacvlanMACAddrFlush(link Link) error {
 	return pkgHandle.MacvlanMACAddrFlush(link)
 }
@@ -184,6 +242,12 @@ func (h *Handle) MacvlanMACAddrFlush(link Link) error {
 	return h.macvlanMACAddrChange(link, nil, nl.MACVLAN_MACADDR_FLUSH)
 }
 
+// MacvlanMACAddrFlushContext is like MacvlanMACAddrFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacvlanMACAddrFlushContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).MacvlanMACAddrFlush(link)
+}
+
 func MacvlanMACAddrSet(link Link, addrs []net.HardwareAddr) error {
 	return pkgHandle.MacvlanMACAddrSet(link, addrs)
 }
@@ -192,6 +256,12 @@ func (h *Handle) MacvlanMACAddrSet(link Link, addrs []net.HardwareAddr) error {
 	return h.macvlanMACAddrChange(link, addrs, nl.MACVLAN_MACADDR_SET)
 }
 
+// MacvlanMACAddrSetContext is like MacvlanMACAddrSet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacvlanMACAddrSetContext(ctx context.Context, link Link, addrs []net.HardwareAddr) error {
+	return h.withContext(ctx).MacvlanMACAddrSet(link, addrs)
+}
+
 func (h *Handle) macvlanMACAddrChange(link Link, addrs []net.HardwareAddr, mode uint32) error {
 	base := link.Attrs()
 	h.ensureIndex(base)
@@ -239,6 +309,12 @@ func (h *Handle) BridgeSetMcastSnoop(link Link, on bool) error {
 	return h.linkModify(bridge, unix.NLM_F_ACK)
 }
 
+// BridgeSetMcastSnoopContext is like BridgeSetMcastSnoop but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) BridgeSetMcastSnoopContext(ctx context.Context, link Link, on bool) error {
+	return h.withContext(ctx).BridgeSetMcastSnoop(link, on)
+}
+
 func SetPromiscOn(link Link) error {
 	return pkgHandle.SetPromiscOn(link)
 }
@@ -257,6 +333,12 @@ func (h *Handle) SetPromiscOff(link Link) error {
 	return err
 }
 
+// SetPromiscOffContext is like SetPromiscOff but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) SetPromiscOffContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).SetPromiscOff(link)
+}
+
 func SetPromiscOff(link Link) error {
 	return pkgHandle.SetPromiscOff(link)
 }
@@ -284,6 +366,12 @@ func (h *Handle) LinkSetUp(link Link) error {
 	return err
 }
 
+// LinkSetUpContext is like LinkSetUp but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetUpContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetUp(link)
+}
+
 // LinkSetDown disables link device.
 // Equivalent to: `ip link set $link down`
 func LinkSetDown(link Link) error {
@@ -306,6 +394,12 @@ func (h *Handle) LinkSetDown(link Link) error {
 	return err
 }
 
+// LinkSetDownContext is like LinkSetDown but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetDownContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetDown(link)
+}
+
 // LinkSetMTU sets the mtu of the link device.
 // Equivalent to: `ip link set $link mtu $mtu`
 func LinkSetMTU(link Link, mtu int) error {
@@ -333,6 +427,12 @@ func (h *Handle) LinkSetMTU(link Link, mtu int) error {
 	return err
 }
 
+// LinkSetMTUContext is like LinkSetMTU but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetMTUContext(ctx context.Context, link Link, mtu int) error {
+	return h.withContext(ctx).LinkSetMTU(link, mtu)
+}
+
 // LinkSetName sets the name of the link device.
 // Equivalent to: `ip link set $link name $name`
 func LinkSetName(link Link, name string) error {
@@ -357,6 +457,12 @@ func (h *Handle) LinkSetName(link Link, name string) error {
 	return err
 }
 
+// LinkSetNameContext is like LinkSetName but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetNameContext(ctx context.Context, link Link, name string) error {
+	return h.withContext(ctx).LinkSetName(link, name)
+}
+
 // LinkSetAlias sets the alias of the link device.
 // Equivalent to: `ip link set dev $link alias $name`
 func LinkSetAlias(link Link, name string) error {
@@ -381,6 +487,12 @@ func (h *Handle) LinkSetAlias(link Link, name string) error {
 	return err
 }
 
+// LinkSetAliasContext is like LinkSetAlias but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetAliasContext(ctx context.Context, link Link, name string) error {
+	return h.withContext(ctx).LinkSetAlias(link, name)
+}
+
 // LinkSetHardwareAddr sets the hardware address of the link device.
 // Equivalent to: `ip link set $link address $hwaddr`
 func LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
@@ -405,6 +517,12 @@ func (h *Handle) LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
 	return err
 }
 
+// LinkSetHardwareAddrContext is like LinkSetHardwareAddr but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetHardwareAddrContext(ctx context.Context, link Link, hwaddr net.HardwareAddr) error {
+	return h.withContext(ctx).LinkSetHardwareAddr(link, hwaddr)
+}
+
 // LinkSetVfHardwareAddr sets the hardware address of a vf for the link.
 // Equivalent to: `ip link set $link vf $vf mac $hwaddr`
 func LinkSetVfHardwareAddr(link Link, vf int, hwaddr net.HardwareAddr) error {
@@ -435,6 +553,12 @@ func (h *Handle) LinkSetVfHardwareAddr(link Link, vf int, hwaddr net.HardwareAdd
 	return err
 }
 
+// LinkSetVfHardwareAddrContext is like LinkSetVfHardwareAddr but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfHardwareAddrContext(ctx context.Context, link Link, vf int, hwaddr net.HardwareAddr) error {
+	return h.withContext(ctx).LinkSetVfHardwareAddr(link, vf, hwaddr)
+}
+
 // LinkSetVfVlan sets the vlan of a vf for the link.
 // Equivalent to: `ip link set $link vf $vf vlan $vlan`
 func LinkSetVfVlan(link Link, vf, vlan int) error {
@@ -465,6 +589,12 @@ func (h *Handle) LinkSetVfVlan(link Link, vf, vlan int) error {
 	return err
 }
 
+// LinkSetVfVlanContext is like LinkSetVfVlan but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfVlanContext(ctx context.Context, link Link, vf, vlan int) error {
+	return h.withContext(ctx).LinkSetVfVlan(link, vf, vlan)
+}
+
 // LinkSetVfTxRate sets the tx rate of a vf for the link.
 // Equivalent to: `ip link set $link vf $vf rate $rate`
 func LinkSetVfTxRate(link Link, vf, rate int) error {
@@ -495,6 +625,12 @@ func (h *Handle) LinkSetVfTxRate(link Link, vf, rate int) error {
 	return err
 }
 
+// LinkSetVfTxRateContext is like LinkSetVfTxRate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfTxRateContext(ctx context.Context, link Link, vf, rate int) error {
+	return h.withContext(ctx).LinkSetVfTxRate(link, vf, rate)
+}
+
 // LinkSetVfSpoofchk enables/disables spoof check on a vf for the link.
 // Equivalent to: `ip link set $link vf $vf spoofchk $check`
 func LinkSetVfSpoofchk(link Link, vf int, check bool) error {
@@ -529,6 +665,12 @@ func (h *Handle) LinkSetVfSpoofchk(link Link, vf int, check bool) error {
 	return err
 }
 
+// LinkSetVfSpoofchkContext is like LinkSetVfSpoofchk but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfSpoofchkContext(ctx context.Context, link Link, vf int, check bool) error {
+	return h.withContext(ctx).LinkSetVfSpoofchk(link, vf, check)
+}
+
 // LinkSetVfTrust enables/disables trust state on a vf for the link.
 // Equivalent to: `ip link set $link vf $vf trust $state`
 func LinkSetVfTrust(link Link, vf int, state bool) error {
@@ -563,6 +705,12 @@ func (h *Handle) LinkSetVfTrust(link Link, vf int, state bool) error {
 	return err
 }
 
+// LinkSetVfTrustContext is like LinkSetVfTrust but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfTrustContext(ctx context.Context, link Link, vf int, state bool) error {
+	return h.withContext(ctx).LinkSetVfTrust(link, vf, state)
+}
+
 // LinkSetVfNodeGUID sets the node GUID of a vf for the link.
 // Equivalent to: `ip link set dev $link vf $vf node_guid $nodeguid`
 func LinkSetVfNodeGUID(link Link, vf int, nodeguid net.HardwareAddr) error {
@@ -607,6 +755,12 @@ func (h *Handle) LinkSetVfGUID(link Link, vf int, vfGuid net.HardwareAddr, guidT
 	return err
 }
 
+// LinkSetVfGUIDContext is like LinkSetVfGUID but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetVfGUIDContext(ctx context.Context, link Link, vf int, vfGuid net.HardwareAddr, guidType int) error {
+	return h.withContext(ctx).LinkSetVfGUID(link, vf, vfGuid, guidType)
+}
+
 // LinkSetMaster sets the master of the link device.
 // Equivalent to: `ip link set $link master $master`
 func LinkSetMaster(link Link, master *Bridge) error {
@@ -628,6 +782,12 @@ func (h *Handle) LinkSetMaster(link Link, master *Bridge) error {
 	return h.LinkSetMasterByIndex(link, index)
 }
 
+// LinkSetMasterContext is like LinkSetMaster but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetMasterContext(ctx context.Context, link Link, master *Bridge) error {
+	return h.withContext(ctx).LinkSetMaster(link, master)
+}
+
 // LinkSetNoMaster removes the master of the link device.
 // Equivalent to: `ip link set $link nomaster`
 func LinkSetNoMaster(link Link) error {
@@ -640,6 +800,12 @@ func (h *Handle) LinkSetNoMaster(link Link) error {
 	return h.LinkSetMasterByIndex(link, 0)
 }
 
+// LinkSetNoMasterContext is like LinkSetNoMaster but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetNoMasterContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkSetNoMaster(link)
+}
+
 // LinkSetMasterByIndex sets the master of the link device.
 // Equivalent to: `ip link set $link master $master`
 func LinkSetMasterByIndex(link Link, masterIndex int) error {
@@ -667,6 +833,12 @@ func (h *Handle) LinkSetMasterByIndex(link Link, masterIndex int) error {
 	return err
 }
 
+// LinkSetMasterByIndexContext is like LinkSetMasterByIndex but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetMasterByIndexContext(ctx context.Context, link Link, masterIndex int) error {
+	return h.withContext(ctx).LinkSetMasterByIndex(link, masterIndex)
+}
+
 // LinkSetNsPid puts the device into a new network namespace. The
 // pid must be a pid of a running process.
 // Equivalent to: `ip link set $link netns $pid`
@@ -696,6 +868,12 @@ func (h *Handle) LinkSetNsPid(link Link, nspid int) error {
 	return err
 }
 
+// LinkSetNsPidContext is like LinkSetNsPid but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetNsPidContext(ctx context.Context, link Link, nspid int) error {
+	return h.withContext(ctx).LinkSetNsPid(link, nspid)
+}
+
 // LinkSetNsFd puts the device into a new network namespace. The
 // fd must be an open file descriptor to a network namespace.
 // Similar to: `ip link set $link netns $ns`
@@ -725,6 +903,12 @@ func (h *Handle) LinkSetNsFd(link Link, fd int) error {
 	return err
 }
 
+// LinkSetNsFdContext is like LinkSetNsFd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetNsFdContext(ctx context.Context, link Link, fd int) error {
+	return h.withContext(ctx).LinkSetNsFd(link, fd)
+}
+
 // LinkSetXdpFd adds a bpf function to the driver. The fd must be a bpf
 // program loaded with bpf(type=BPF_PROG_TYPE_XDP)
 func LinkSetXdpFd(link Link, fd int) error {
@@ -948,6 +1132,12 @@ func (h *Handle) LinkAdd(link Link) error {
 	return h.linkModify(link, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
 }
 
+// LinkAddContext is like LinkAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkAddContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkAdd(link)
+}
+
 func (h *Handle) linkModify(link Link, flags int) error {
 	// TODO: support extra data for macvlan
 	base := link.Attrs()
@@ -1190,6 +1380,16 @@ func (h *Handle) linkModify(link Link, flags int) error {
 		addBridgeAttrs(link, linkInfo)
 	case *GTP:
 		addGTPAttrs(link, linkInfo)
+	case *Macsec:
+		addMacsecAttrs(link, linkInfo)
+	case *Geneve:
+		addGeneveAttrs(link, linkInfo)
+	case *Bareudp:
+		addBareudpAttrs(link, linkInfo)
+	case *Ip6tnl:
+		addIp6tnlAttrs(link, linkInfo)
+	case *Erspan:
+		addErspanAttrs(link, linkInfo)
 	}
 
 	req.AddData(linkInfo)
@@ -1234,6 +1434,12 @@ func (h *Handle) LinkDel(link Link) error {
 	return err
 }
 
+// LinkDelContext is like LinkDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkDelContext(ctx context.Context, link Link) error {
+	return h.withContext(ctx).LinkDel(link)
+}
+
 func (h *Handle) linkByNameDump(name string) (Link, error) {
 	links, err := h.LinkList()
 	if err != nil {
@@ -1285,7 +1491,7 @@ func (h *Handle) LinkByName(name string) (Link, error) {
 	req.AddData(nameData)
 
 	link, err := execGetLink(req)
-	if err == unix.EINVAL {
+	if errors.Is(err, unix.EINVAL) {
 		// older kernels don't support looking up via IFLA_IFNAME
 		// so fall back to dumping all links
 		h.lookupByDump = true
@@ -1295,6 +1501,12 @@ func (h *Handle) LinkByName(name string) (Link, error) {
 	return link, err
 }
 
+// LinkByNameContext is like LinkByName but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkByNameContext(ctx context.Context, name string) (Link, error) {
+	return h.withContext(ctx).LinkByName(name)
+}
+
 // LinkByAlias finds a link by its alias and returns a pointer to the object.
 // If there are multiple links with the alias it returns the first one
 func LinkByAlias(alias string) (Link, error) {
@@ -1320,7 +1532,7 @@ func (h *Handle) LinkByAlias(alias string) (Link, error) {
 	req.AddData(nameData)
 
 	link, err := execGetLink(req)
-	if err == unix.EINVAL {
+	if errors.Is(err, unix.EINVAL) {
 		// older kernels don't support looking up via IFLA_IFALIAS
 		// so fall back to dumping all links
 		h.lookupByDump = true
@@ -1330,6 +1542,12 @@ func (h *Handle) LinkByAlias(alias string) (Link, error) {
 	return link, err
 }
 
+// LinkByAliasContext is like LinkByAlias but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkByAliasContext(ctx context.Context, alias string) (Link, error) {
+	return h.withContext(ctx).LinkByAlias(alias)
+}
+
 // LinkByIndex finds a link by index and returns a pointer to the object.
 func LinkByIndex(index int) (Link, error) {
 	return pkgHandle.LinkByIndex(index)
@@ -1348,13 +1566,17 @@ func (h *Handle) LinkByIndex(index int) (Link, error) {
 	return execGetLink(req)
 }
 
+// LinkByIndexContext is like LinkByIndex but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkByIndexContext(ctx context.Context, index int) (Link, error) {
+	return h.withContext(ctx).LinkByIndex(index)
+}
+
 func execGetLink(req *nl.NetlinkRequest) (Link, error) {
 	msgs, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	if err != nil {
-		if errno, ok := err.(syscall.Errno); ok {
-			if errno == unix.ENODEV {
-				return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
-			}
+		if errors.Is(err, unix.ENODEV) {
+			return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
 		}
 		return nil, err
 	}
@@ -1434,13 +1656,25 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 					case "gre":
 						link = &Gretun{}
 					case "ip6gre":
-						link = &Gretun{}
+						link = &Gretun{IPv6: true}
 					case "vti", "vti6":
 						link = &Vti{}
 					case "vrf":
 						link = &Vrf{}
 					case "gtp":
 						link = &GTP{}
+					case "macsec":
+						link = &Macsec{}
+					case "geneve":
+						link = &Geneve{}
+					case "bareudp":
+						link = &Bareudp{}
+					case "ip6tnl":
+						link = &Ip6tnl{}
+					case "erspan":
+						link = &Erspan{}
+					case "ip6erspan":
+						link = &Erspan{IPv6: true}
 					default:
 						link = &GenericLink{LinkType: linkType}
 					}
@@ -1482,6 +1716,16 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						parseBridgeData(link, data)
 					case "gtp":
 						parseGTPData(link, data)
+					case "macsec":
+						parseMacsecData(link, data)
+					case "geneve":
+						parseGeneveData(link, data)
+					case "bareudp":
+						parseBareudpData(link, data)
+					case "ip6tnl":
+						parseIp6tnlData(link, data)
+					case "erspan", "ip6erspan":
+						parseErspanData(link, data)
 					}
 				}
 			}
@@ -1576,9 +1820,9 @@ func (h *Handle) LinkList() ([]Link, error) {
 	attr := nl.NewRtAttr(unix.IFLA_EXT_MASK, nl.Uint32Attr(nl.RTEXT_FILTER_VF))
 	req.AddData(attr)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Link
@@ -1590,7 +1834,13 @@ func (h *Handle) LinkList() ([]Link, error) {
 		res = append(res, link)
 	}
 
-	return res, nil
+	return res, executeErr
+}
+
+// LinkListContext is like LinkList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkListContext(ctx context.Context) ([]Link, error) {
+	return h.withContext(ctx).LinkList()
 }
 
 // LinkUpdate is used to pass information back from LinkSubscribe()
@@ -1701,6 +1951,12 @@ func (h *Handle) LinkSetHairpin(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_MODE)
 }
 
+// LinkSetHairpinContext is like LinkSetHairpin but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetHairpinContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetHairpin(link, mode)
+}
+
 func LinkSetGuard(link Link, mode bool) error {
 	return pkgHandle.LinkSetGuard(link, mode)
 }
@@ -1709,6 +1965,12 @@ func (h *Handle) LinkSetGuard(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_GUARD)
 }
 
+// LinkSetGuardContext is like LinkSetGuard but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetGuardContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetGuard(link, mode)
+}
+
 func LinkSetFastLeave(link Link, mode bool) error {
 	return pkgHandle.LinkSetFastLeave(link, mode)
 }
@@ -1717,6 +1979,12 @@ func (h *Handle) LinkSetFastLeave(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_FAST_LEAVE)
 }
 
+// LinkSetFastLeaveContext is like LinkSetFastLeave but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetFastLeaveContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetFastLeave(link, mode)
+}
+
 func LinkSetLearning(link Link, mode bool) error {
 	return pkgHandle.LinkSetLearning(link, mode)
 }
@@ -1725,6 +1993,12 @@ func (h *Handle) LinkSetLearning(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_LEARNING)
 }
 
+// LinkSetLearningContext is like LinkSetLearning but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetLearningContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetLearning(link, mode)
+}
+
 func LinkSetRootBlock(link Link, mode bool) error {
 	return pkgHandle.LinkSetRootBlock(link, mode)
 }
@@ -1733,6 +2007,12 @@ func (h *Handle) LinkSetRootBlock(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_PROTECT)
 }
 
+// LinkSetRootBlockContext is like LinkSetRootBlock but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetRootBlockContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetRootBlock(link, mode)
+}
+
 func LinkSetFlood(link Link, mode bool) error {
 	return pkgHandle.LinkSetFlood(link, mode)
 }
@@ -1741,6 +2021,12 @@ func (h *Handle) LinkSetFlood(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_UNICAST_FLOOD)
 }
 
+// LinkSetFloodContext is like LinkSetFlood but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetFloodContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetFlood(link, mode)
+}
+
 func LinkSetBrProxyArp(link Link, mode bool) error {
 	return pkgHandle.LinkSetBrProxyArp(link, mode)
 }
@@ -1749,6 +2035,12 @@ func (h *Handle) LinkSetBrProxyArp(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_PROXYARP)
 }
 
+// LinkSetBrProxyArpContext is like LinkSetBrProxyArp but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetBrProxyArpContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetBrProxyArp(link, mode)
+}
+
 func LinkSetBrProxyArpWiFi(link Link, mode bool) error {
 	return pkgHandle.LinkSetBrProxyArpWiFi(link, mode)
 }
@@ -1757,6 +2049,12 @@ func (h *Handle) LinkSetBrProxyArpWiFi(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_PROXYARP_WIFI)
 }
 
+// LinkSetBrProxyArpWiFiContext is like LinkSetBrProxyArpWiFi but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetBrProxyArpWiFiContext(ctx context.Context, link Link, mode bool) error {
+	return h.withContext(ctx).LinkSetBrProxyArpWiFi(link, mode)
+}
+
 func (h *Handle) setProtinfoAttr(link Link, mode bool, attr int) error {
 	base := link.Attrs()
 	h.ensureIndex(base)
@@ -1803,6 +2101,12 @@ func (h *Handle) LinkSetTxQLen(link Link, qlen int) error {
 	return err
 }
 
+// LinkSetTxQLenContext is like LinkSetTxQLen but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkSetTxQLenContext(ctx context.Context, link Link, qlen int) error {
+	return h.withContext(ctx).LinkSetTxQLen(link, qlen)
+}
+
 func parseVlanData(link Link, data []syscall.NetlinkRouteAttr) {
 	vlan := link.(*Vlan)
 	for _, datum := range data {
@@ -2047,6 +2351,20 @@ func addGretapAttrs(gretap *Gretap, linkInfo *nl.RtAttr) {
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_FLAGS, nl.Uint16Attr(gretap.EncapFlags))
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_SPORT, htons(gretap.EncapSport))
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_DPORT, htons(gretap.EncapDport))
+	addIp6greAttrs(data, gretap.EncapLimit, gretap.FlowLabel, gretap.Ip6Flags)
+}
+
+// addIp6greAttrs adds the attributes only used by the GRE links over IPv6
+func addIp6greAttrs(data *nl.RtAttr, encapLimit *uint8, flowLabel, flags uint32) {
+	if encapLimit != nil {
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_LIMIT, nl.Uint8Attr(*encapLimit))
+	}
+	if flowLabel != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_FLOWINFO, htonl(flowLabel&nl.IPV6_FLOWINFO_FLOWLABEL))
+	}
+	if flags != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_FLAGS, nl.Uint32Attr(flags))
+	}
 }
 
 func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
@@ -2083,6 +2401,13 @@ func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
 			if len(datum.Value) > 0 {
 				gre.FlowBased = int8(datum.Value[0]) != 0
 			}
+		case nl.IFLA_GRE_ENCAP_LIMIT:
+			encapLimit := datum.Value[0]
+			gre.EncapLimit = &encapLimit
+		case nl.IFLA_GRE_FLOWINFO:
+			gre.FlowLabel = ntohl(datum.Value[0:4]) & nl.IPV6_FLOWINFO_FLOWLABEL
+		case nl.IFLA_GRE_FLAGS:
+			gre.Ip6Flags = native.Uint32(datum.Value[0:4])
 		}
 	}
 }
@@ -2090,6 +2415,12 @@ func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
 func addGretunAttrs(gre *Gretun, linkInfo *nl.RtAttr) {
 	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
 
+	if gre.FlowBased {
+		// In flow based mode, no other attributes need to be configured
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_COLLECT_METADATA, []byte{})
+		return
+	}
+
 	if ip := gre.Local; ip != nil {
 		if ip.To4() != nil {
 			ip = ip.To4()
@@ -2128,6 +2459,7 @@ func addGretunAttrs(gre *Gretun, linkInfo *nl.RtAttr) {
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_FLAGS, nl.Uint16Attr(gre.EncapFlags))
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_SPORT, htons(gre.EncapSport))
 	nl.NewRtAttrChild(data, nl.IFLA_GRE_ENCAP_DPORT, htons(gre.EncapDport))
+	addIp6greAttrs(data, gre.EncapLimit, gre.FlowLabel, gre.Ip6Flags)
 }
 
 func parseGretunData(link Link, data []syscall.NetlinkRouteAttr) {
@@ -2160,6 +2492,116 @@ func parseGretunData(link Link, data []syscall.NetlinkRouteAttr) {
 			gre.EncapSport = ntohs(datum.Value[0:2])
 		case nl.IFLA_GRE_ENCAP_DPORT:
 			gre.EncapDport = ntohs(datum.Value[0:2])
+		case nl.IFLA_GRE_COLLECT_METADATA:
+			gre.FlowBased = true
+		case nl.IFLA_GRE_ENCAP_LIMIT:
+			encapLimit := datum.Value[0]
+			gre.EncapLimit = &encapLimit
+		case nl.IFLA_GRE_FLOWINFO:
+			gre.FlowLabel = ntohl(datum.Value[0:4]) & nl.IPV6_FLOWINFO_FLOWLABEL
+		case nl.IFLA_GRE_FLAGS:
+			gre.Ip6Flags = native.Uint32(datum.Value[0:4])
+		}
+	}
+}
+
+func addErspanAttrs(erspan *Erspan, linkInfo *nl.RtAttr) {
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+
+	if erspan.FlowBased {
+		// In flow based mode, no other attributes need to be configured
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_COLLECT_METADATA, []byte{})
+		return
+	}
+
+	if ip := erspan.Local; ip != nil {
+		if ip.To4() != nil {
+			ip = ip.To4()
+		}
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_LOCAL, []byte(ip))
+	}
+
+	if ip := erspan.Remote; ip != nil {
+		if ip.To4() != nil {
+			ip = ip.To4()
+		}
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_REMOTE, []byte(ip))
+	}
+
+	// The kernel only accepts the key and sequence flags, the keys being
+	// the session ids
+	erspan.IFlags |= uint16(nl.GRE_KEY | nl.GRE_SEQ)
+	erspan.OFlags |= uint16(nl.GRE_KEY | nl.GRE_SEQ)
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_IKEY, htonl(erspan.IKey))
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_OKEY, htonl(erspan.OKey))
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_IFLAGS, htons(erspan.IFlags))
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_OFLAGS, htons(erspan.OFlags))
+
+	if erspan.Link != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_LINK, nl.Uint32Attr(erspan.Link))
+	}
+
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_PMTUDISC, nl.Uint8Attr(erspan.PMtuDisc))
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_TTL, nl.Uint8Attr(erspan.Ttl))
+	nl.NewRtAttrChild(data, nl.IFLA_GRE_TOS, nl.Uint8Attr(erspan.Tos))
+	addIp6greAttrs(data, erspan.EncapLimit, erspan.FlowLabel, erspan.Ip6Flags)
+
+	version := uint8(1)
+	if erspan.Version != nil {
+		version = *erspan.Version
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_ERSPAN_VER, nl.Uint8Attr(version))
+	}
+	switch version {
+	case 1:
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_ERSPAN_INDEX, nl.Uint32Attr(erspan.Index))
+	case 2:
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_ERSPAN_DIR, nl.Uint8Attr(erspan.Direction))
+		nl.NewRtAttrChild(data, nl.IFLA_GRE_ERSPAN_HWID, nl.Uint16Attr(erspan.HardwareID))
+	}
+}
+
+func parseErspanData(link Link, data []syscall.NetlinkRouteAttr) {
+	erspan := link.(*Erspan)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_GRE_LINK:
+			erspan.Link = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_IKEY:
+			erspan.IKey = ntohl(datum.Value[0:4])
+		case nl.IFLA_GRE_OKEY:
+			erspan.OKey = ntohl(datum.Value[0:4])
+		case nl.IFLA_GRE_LOCAL:
+			erspan.Local = net.IP(datum.Value)
+		case nl.IFLA_GRE_REMOTE:
+			erspan.Remote = net.IP(datum.Value)
+		case nl.IFLA_GRE_IFLAGS:
+			erspan.IFlags = ntohs(datum.Value[0:2])
+		case nl.IFLA_GRE_OFLAGS:
+			erspan.OFlags = ntohs(datum.Value[0:2])
+		case nl.IFLA_GRE_TTL:
+			erspan.Ttl = datum.Value[0]
+		case nl.IFLA_GRE_TOS:
+			erspan.Tos = datum.Value[0]
+		case nl.IFLA_GRE_PMTUDISC:
+			erspan.PMtuDisc = datum.Value[0]
+		case nl.IFLA_GRE_ENCAP_LIMIT:
+			encapLimit := datum.Value[0]
+			erspan.EncapLimit = &encapLimit
+		case nl.IFLA_GRE_FLOWINFO:
+			erspan.FlowLabel = ntohl(datum.Value[0:4]) & nl.IPV6_FLOWINFO_FLOWLABEL
+		case nl.IFLA_GRE_FLAGS:
+			erspan.Ip6Flags = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_COLLECT_METADATA:
+			erspan.FlowBased = true
+		case nl.IFLA_GRE_ERSPAN_VER:
+			version := datum.Value[0]
+			erspan.Version = &version
+		case nl.IFLA_GRE_ERSPAN_INDEX:
+			erspan.Index = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_ERSPAN_DIR:
+			erspan.Direction = datum.Value[0]
+		case nl.IFLA_GRE_ERSPAN_HWID:
+			erspan.HardwareID = native.Uint16(datum.Value[0:2])
 		}
 	}
 }
@@ -2265,6 +2707,75 @@ func parseIptunData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func addIp6tnlAttrs(ip6tnl *Ip6tnl, linkInfo *nl.RtAttr) {
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+
+	if ip6tnl.FlowBased {
+		// In flow based mode, only the tunneled protocol can be configured
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_COLLECT_METADATA, []byte{})
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_PROTO, nl.Uint8Attr(ip6tnl.Proto))
+		return
+	}
+
+	if ip := ip6tnl.Local.To16(); ip != nil {
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_LOCAL, []byte(ip))
+	}
+
+	if ip := ip6tnl.Remote.To16(); ip != nil {
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_REMOTE, []byte(ip))
+	}
+
+	if ip6tnl.Link != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_LINK, nl.Uint32Attr(ip6tnl.Link))
+	}
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_TTL, nl.Uint8Attr(ip6tnl.Ttl))
+	if ip6tnl.EncapLimit != nil {
+		nl.NewRtAttrChild(data, nl.IFLA_IPTUN_ENCAP_LIMIT, nl.Uint8Attr(*ip6tnl.EncapLimit))
+	}
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_FLOWINFO, htonl(ip6tnl.FlowLabel&nl.IPV6_FLOWINFO_FLOWLABEL))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_FLAGS, nl.Uint32Attr(ip6tnl.Flags))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_PROTO, nl.Uint8Attr(ip6tnl.Proto))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_ENCAP_TYPE, nl.Uint16Attr(ip6tnl.EncapType))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_ENCAP_FLAGS, nl.Uint16Attr(ip6tnl.EncapFlags))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_ENCAP_SPORT, htons(ip6tnl.EncapSport))
+	nl.NewRtAttrChild(data, nl.IFLA_IPTUN_ENCAP_DPORT, htons(ip6tnl.EncapDport))
+}
+
+func parseIp6tnlData(link Link, data []syscall.NetlinkRouteAttr) {
+	ip6tnl := link.(*Ip6tnl)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_IPTUN_LINK:
+			ip6tnl.Link = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_IPTUN_LOCAL:
+			ip6tnl.Local = net.IP(datum.Value[0:16])
+		case nl.IFLA_IPTUN_REMOTE:
+			ip6tnl.Remote = net.IP(datum.Value[0:16])
+		case nl.IFLA_IPTUN_TTL:
+			ip6tnl.Ttl = datum.Value[0]
+		case nl.IFLA_IPTUN_ENCAP_LIMIT:
+			encapLimit := datum.Value[0]
+			ip6tnl.EncapLimit = &encapLimit
+		case nl.IFLA_IPTUN_FLOWINFO:
+			ip6tnl.FlowLabel = ntohl(datum.Value[0:4]) & nl.IPV6_FLOWINFO_FLOWLABEL
+		case nl.IFLA_IPTUN_FLAGS:
+			ip6tnl.Flags = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_IPTUN_PROTO:
+			ip6tnl.Proto = datum.Value[0]
+		case nl.IFLA_IPTUN_ENCAP_TYPE:
+			ip6tnl.EncapType = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_IPTUN_ENCAP_FLAGS:
+			ip6tnl.EncapFlags = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_IPTUN_ENCAP_SPORT:
+			ip6tnl.EncapSport = ntohs(datum.Value[0:2])
+		case nl.IFLA_IPTUN_ENCAP_DPORT:
+			ip6tnl.EncapDport = ntohs(datum.Value[0:2])
+		case nl.IFLA_IPTUN_COLLECT_METADATA:
+			ip6tnl.FlowBased = true
+		}
+	}
+}
+
 func addSittunAttrs(sittun *Sittun, linkInfo *nl.RtAttr) {
 	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
 
@@ -2446,6 +2957,204 @@ func parseGTPData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func addMacsecAttrs(macsec *Macsec, linkInfo *nl.RtAttr) {
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+	// The kernel rejects both the SCI and the port
+	if macsec.SCI != 0 {
+		sci := make([]byte, 8)
+		networkOrder.PutUint64(sci, macsec.SCI)
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_SCI, sci)
+	} else if macsec.Port != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_PORT, htons(macsec.Port))
+	}
+	if macsec.CipherSuite != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_CIPHER_SUITE, nl.Uint64Attr(macsec.CipherSuite))
+	}
+	if macsec.ICVLen != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_ICV_LEN, nl.Uint8Attr(macsec.ICVLen))
+	}
+	if macsec.EncodingSA != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_ENCODING_SA, nl.Uint8Attr(macsec.EncodingSA))
+	}
+	for _, flag := range []struct {
+		attr  int
+		value *bool
+	}{
+		{nl.IFLA_MACSEC_ENCRYPT, macsec.Encrypt},
+		{nl.IFLA_MACSEC_PROTECT, macsec.Protect},
+		{nl.IFLA_MACSEC_INC_SCI, macsec.IncludeSCI},
+		{nl.IFLA_MACSEC_ES, macsec.EndStation},
+		{nl.IFLA_MACSEC_SCB, macsec.SCB},
+		{nl.IFLA_MACSEC_REPLAY_PROTECT, macsec.ReplayProtect},
+	} {
+		if flag.value != nil {
+			nl.NewRtAttrChild(data, flag.attr, boolAttr(*flag.value))
+		}
+	}
+	if macsec.ReplayProtect != nil && *macsec.ReplayProtect {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_WINDOW, nl.Uint32Attr(macsec.ReplayWindow))
+	}
+	if macsec.Validation != MACSEC_VALIDATE_DEFAULT {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_VALIDATION, nl.Uint8Attr(macsecValidations[macsec.Validation]))
+	}
+	if macsec.Offload != MACSEC_OFFLOAD_DEFAULT {
+		nl.NewRtAttrChild(data, nl.IFLA_MACSEC_OFFLOAD, nl.Uint8Attr(macsecOffloads[macsec.Offload]))
+	}
+}
+
+func parseMacsecData(link Link, data []syscall.NetlinkRouteAttr) {
+	macsec := link.(*Macsec)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_MACSEC_SCI:
+			macsec.SCI = networkOrder.Uint64(datum.Value[0:8])
+			macsec.Port = uint16(macsec.SCI)
+		case nl.IFLA_MACSEC_CIPHER_SUITE:
+			macsec.CipherSuite = native.Uint64(datum.Value[0:8])
+		case nl.IFLA_MACSEC_ICV_LEN:
+			macsec.ICVLen = datum.Value[0]
+		case nl.IFLA_MACSEC_ENCODING_SA:
+			macsec.EncodingSA = datum.Value[0]
+		case nl.IFLA_MACSEC_WINDOW:
+			macsec.ReplayWindow = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_MACSEC_ENCRYPT:
+			encrypt := datum.Value[0] == 1
+			macsec.Encrypt = &encrypt
+		case nl.IFLA_MACSEC_PROTECT:
+			protect := datum.Value[0] == 1
+			macsec.Protect = &protect
+		case nl.IFLA_MACSEC_INC_SCI:
+			includeSCI := datum.Value[0] == 1
+			macsec.IncludeSCI = &includeSCI
+		case nl.IFLA_MACSEC_ES:
+			endStation := datum.Value[0] == 1
+			macsec.EndStation = &endStation
+		case nl.IFLA_MACSEC_SCB:
+			scb := datum.Value[0] == 1
+			macsec.SCB = &scb
+		case nl.IFLA_MACSEC_REPLAY_PROTECT:
+			replayProtect := datum.Value[0] == 1
+			macsec.ReplayProtect = &replayProtect
+		case nl.IFLA_MACSEC_VALIDATION:
+			for validation, value := range macsecValidations {
+				if validation != 0 && value == datum.Value[0] {
+					macsec.Validation = MacsecValidation(validation)
+				}
+			}
+		case nl.IFLA_MACSEC_OFFLOAD:
+			for offload, value := range macsecOffloads {
+				if offload != 0 && value == datum.Value[0] {
+					macsec.Offload = MacsecOffload(offload)
+				}
+			}
+		}
+	}
+}
+
+func addGeneveAttrs(geneve *Geneve, linkInfo *nl.RtAttr) {
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+
+	if geneve.Dport != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_PORT, htons(geneve.Dport))
+	}
+
+	if geneve.FlowBased {
+		// In flow based mode, no other attributes than the port can be
+		// configured
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_COLLECT_METADATA, []byte{})
+		return
+	}
+
+	nl.NewRtAttrChild(data, nl.IFLA_GENEVE_ID, nl.Uint32Attr(geneve.ID))
+
+	if ip := geneve.Remote; ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			nl.NewRtAttrChild(data, nl.IFLA_GENEVE_REMOTE, []byte(ip4))
+		} else {
+			nl.NewRtAttrChild(data, nl.IFLA_GENEVE_REMOTE6, []byte(ip.To16()))
+		}
+	}
+
+	if geneve.TtlInherit {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_TTL_INHERIT, boolAttr(geneve.TtlInherit))
+	} else {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_TTL, nl.Uint8Attr(geneve.Ttl))
+	}
+	nl.NewRtAttrChild(data, nl.IFLA_GENEVE_TOS, nl.Uint8Attr(geneve.Tos))
+
+	if geneve.FlowLabel != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_LABEL, htonl(geneve.FlowLabel&nl.IPV6_FLOWINFO_FLOWLABEL))
+	}
+	if geneve.UDPCSum {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_UDP_CSUM, boolAttr(geneve.UDPCSum))
+	}
+	if geneve.UDP6ZeroCSumTx {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_UDP_ZERO_CSUM6_TX, boolAttr(geneve.UDP6ZeroCSumTx))
+	}
+	if geneve.UDP6ZeroCSumRx {
+		nl.NewRtAttrChild(data, nl.IFLA_GENEVE_UDP_ZERO_CSUM6_RX, boolAttr(geneve.UDP6ZeroCSumRx))
+	}
+}
+
+func parseGeneveData(link Link, data []syscall.NetlinkRouteAttr) {
+	geneve := link.(*Geneve)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_GENEVE_ID:
+			geneve.ID = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GENEVE_REMOTE, nl.IFLA_GENEVE_REMOTE6:
+			geneve.Remote = net.IP(datum.Value)
+		case nl.IFLA_GENEVE_TTL:
+			geneve.Ttl = datum.Value[0]
+		case nl.IFLA_GENEVE_TTL_INHERIT:
+			geneve.TtlInherit = datum.Value[0] == 1
+		case nl.IFLA_GENEVE_TOS:
+			geneve.Tos = datum.Value[0]
+		case nl.IFLA_GENEVE_PORT:
+			geneve.Dport = ntohs(datum.Value[0:2])
+		case nl.IFLA_GENEVE_LABEL:
+			geneve.FlowLabel = ntohl(datum.Value[0:4])
+		case nl.IFLA_GENEVE_UDP_CSUM:
+			geneve.UDPCSum = datum.Value[0] == 1
+		case nl.IFLA_GENEVE_UDP_ZERO_CSUM6_TX:
+			geneve.UDP6ZeroCSumTx = datum.Value[0] == 1
+		case nl.IFLA_GENEVE_UDP_ZERO_CSUM6_RX:
+			geneve.UDP6ZeroCSumRx = datum.Value[0] == 1
+		case nl.IFLA_GENEVE_COLLECT_METADATA:
+			geneve.FlowBased = true
+		}
+	}
+}
+
+func addBareudpAttrs(bareudp *Bareudp, linkInfo *nl.RtAttr) {
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+
+	nl.NewRtAttrChild(data, nl.IFLA_BAREUDP_PORT, htons(bareudp.Port))
+	nl.NewRtAttrChild(data, nl.IFLA_BAREUDP_ETHERTYPE, htons(bareudp.EtherType))
+	if bareudp.SrcPortMin != 0 {
+		nl.NewRtAttrChild(data, nl.IFLA_BAREUDP_SRCPORT_MIN, nl.Uint16Attr(bareudp.SrcPortMin))
+	}
+	if bareudp.MultiProto {
+		nl.NewRtAttrChild(data, nl.IFLA_BAREUDP_MULTIPROTO_MODE, []byte{})
+	}
+}
+
+func parseBareudpData(link Link, data []syscall.NetlinkRouteAttr) {
+	bareudp := link.(*Bareudp)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_BAREUDP_PORT:
+			bareudp.Port = ntohs(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_ETHERTYPE:
+			bareudp.EtherType = ntohs(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_SRCPORT_MIN:
+			bareudp.SrcPortMin = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_MULTIPROTO_MODE:
+			bareudp.MultiProto = true
+		}
+	}
+}
+
 func parseVfInfoList(data []syscall.NetlinkRouteAttr) ([]VfInfo, error) {
 	var vfs []VfInfo
 
@@ -4,8 +4,10 @@ package netlink
 
 import (
 	"bytes"
+	"errors"
 	"net"
 	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -201,6 +203,38 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareGretun(t, gretun, other)
 	}
 
+	if geneve, ok := link.(*Geneve); ok {
+		other, ok := result.(*Geneve)
+		if !ok {
+			t.Fatal("Result of create is not a Geneve")
+		}
+		compareGeneve(t, geneve, other)
+	}
+
+	if bareudp, ok := link.(*Bareudp); ok {
+		other, ok := result.(*Bareudp)
+		if !ok {
+			t.Fatal("Result of create is not a Bareudp")
+		}
+		compareBareudp(t, bareudp, other)
+	}
+
+	if ip6tnl, ok := link.(*Ip6tnl); ok {
+		other, ok := result.(*Ip6tnl)
+		if !ok {
+			t.Fatal("Result of create is not a Ip6tnl")
+		}
+		compareIp6tnl(t, ip6tnl, other)
+	}
+
+	if erspan, ok := link.(*Erspan); ok {
+		other, ok := result.(*Erspan)
+		if !ok {
+			t.Fatal("Result of create is not a Erspan")
+		}
+		compareErspan(t, erspan, other)
+	}
+
 	if err = LinkDel(link); err != nil {
 		t.Fatal(err)
 	}
@@ -340,6 +374,149 @@ func compareGretun(t *testing.T, expected, actual *Gretun) {
 	if actual.EncapDport != expected.EncapDport {
 		t.Fatal("Gretun.EncapDport doesn't match")
 	}
+
+	if actual.FlowBased != expected.FlowBased {
+		t.Fatal("Gretun.FlowBased doesn't match")
+	}
+
+	if actual.Type() != expected.Type() {
+		t.Fatal("Gretun.Type doesn't match")
+	}
+}
+
+func compareGeneve(t *testing.T, expected, actual *Geneve) {
+	if actual.ID != expected.ID {
+		t.Fatal("Geneve.ID doesn't match")
+	}
+
+	if expected.Remote != nil && !actual.Remote.Equal(expected.Remote) {
+		t.Fatal("Geneve.Remote doesn't match")
+	}
+
+	if actual.Ttl != expected.Ttl {
+		t.Fatal("Geneve.Ttl doesn't match")
+	}
+
+	if actual.Tos != expected.Tos {
+		t.Fatal("Geneve.Tos doesn't match")
+	}
+
+	if expected.Dport != 0 && actual.Dport != expected.Dport {
+		t.Fatal("Geneve.Dport doesn't match")
+	}
+
+	if actual.FlowLabel != expected.FlowLabel {
+		t.Fatal("Geneve.FlowLabel doesn't match")
+	}
+
+	if actual.FlowBased != expected.FlowBased {
+		t.Fatal("Geneve.FlowBased doesn't match")
+	}
+}
+
+func compareBareudp(t *testing.T, expected, actual *Bareudp) {
+	if actual.Port != expected.Port {
+		t.Fatal("Bareudp.Port doesn't match")
+	}
+
+	if actual.EtherType != expected.EtherType {
+		t.Fatal("Bareudp.EtherType doesn't match")
+	}
+
+	if expected.SrcPortMin != 0 && actual.SrcPortMin != expected.SrcPortMin {
+		t.Fatal("Bareudp.SrcPortMin doesn't match")
+	}
+
+	if actual.MultiProto != expected.MultiProto {
+		t.Fatal("Bareudp.MultiProto doesn't match")
+	}
+}
+
+func compareIp6tnl(t *testing.T, expected, actual *Ip6tnl) {
+	if expected.Local != nil && !actual.Local.Equal(expected.Local) {
+		t.Fatal("Ip6tnl.Local doesn't match")
+	}
+
+	if expected.Remote != nil && !actual.Remote.Equal(expected.Remote) {
+		t.Fatal("Ip6tnl.Remote doesn't match")
+	}
+
+	if actual.Ttl != expected.Ttl {
+		t.Fatal("Ip6tnl.Ttl doesn't match")
+	}
+
+	// A nil limit keeps the kernel default
+	encapLimit := uint8(4)
+	if expected.EncapLimit != nil {
+		encapLimit = *expected.EncapLimit
+	}
+	if actual.EncapLimit == nil || *actual.EncapLimit != encapLimit {
+		t.Fatal("Ip6tnl.EncapLimit doesn't match")
+	}
+
+	if actual.FlowLabel != expected.FlowLabel {
+		t.Fatal("Ip6tnl.FlowLabel doesn't match")
+	}
+
+	// The kernel reports the capabilities of the tunnel with the flags
+	capabilities := uint32(nl.IP6_TNL_F_CAP_XMIT | nl.IP6_TNL_F_CAP_RCV | nl.IP6_TNL_F_CAP_PER_PACKET)
+	if actual.Flags&^capabilities != expected.Flags {
+		t.Fatal("Ip6tnl.Flags doesn't match")
+	}
+
+	if actual.Proto != expected.Proto {
+		t.Fatal("Ip6tnl.Proto doesn't match")
+	}
+
+	if actual.FlowBased != expected.FlowBased {
+		t.Fatal("Ip6tnl.FlowBased doesn't match")
+	}
+}
+
+func compareErspan(t *testing.T, expected, actual *Erspan) {
+	if actual.IKey != expected.IKey {
+		t.Fatal("Erspan.IKey doesn't match")
+	}
+
+	if actual.OKey != expected.OKey {
+		t.Fatal("Erspan.OKey doesn't match")
+	}
+
+	if expected.Local != nil && !actual.Local.Equal(expected.Local) {
+		t.Fatal("Erspan.Local doesn't match")
+	}
+
+	if expected.Remote != nil && !actual.Remote.Equal(expected.Remote) {
+		t.Fatal("Erspan.Remote doesn't match")
+	}
+
+	version := uint8(1)
+	if expected.Version != nil {
+		version = *expected.Version
+		if actual.Version == nil || *actual.Version != version {
+			t.Fatal("Erspan.Version doesn't match")
+		}
+	}
+
+	if version == 1 && actual.Index != expected.Index {
+		t.Fatal("Erspan.Index doesn't match")
+	}
+
+	if version == 2 && (actual.Direction != expected.Direction || actual.HardwareID != expected.HardwareID) {
+		t.Fatal("Erspan.Direction or Erspan.HardwareID doesn't match")
+	}
+
+	if actual.FlowLabel != expected.FlowLabel {
+		t.Fatal("Erspan.FlowLabel doesn't match")
+	}
+
+	if actual.FlowBased != expected.FlowBased {
+		t.Fatal("Erspan.FlowBased doesn't match")
+	}
+
+	if actual.Type() != expected.Type() {
+		t.Fatal("Erspan type doesn't match")
+	}
 }
 
 func compareVxlan(t *testing.T, expected, actual *Vxlan) {
@@ -472,6 +649,22 @@ func TestLinkAddDelGretun(t *testing.T) {
 		Remote:    net.ParseIP("2001:db8:ef33::2")})
 }
 
+func TestLinkAddDelGretunFlowBased(t *testing.T) {
+	minKernelRequired(t, 4, 3)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Gretun{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		FlowBased: true})
+
+	testLinkAddDel(t, &Gretun{
+		LinkAttrs: LinkAttrs{Name: "foo6"},
+		FlowBased: true,
+		IPv6:      true})
+}
+
 func TestLinkAddDelGretunPointToMultiPoint(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -500,6 +693,266 @@ func TestLinkAddDelGretapFlowBased(t *testing.T) {
 		FlowBased: true})
 }
 
+func TestLinkAddDelGeneve(t *testing.T) {
+	minKernelRequired(t, 4, 6)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Geneve{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		ID:        0x1000,
+		Remote:    net.IPv4(127, 0, 0, 1),
+		Ttl:       64,
+		Tos:       0x10,
+		Dport:     6082})
+
+	testLinkAddDel(t, &Geneve{
+		LinkAttrs: LinkAttrs{Name: "foo6"},
+		ID:        0x1001,
+		Remote:    net.ParseIP("2001:db8::1"),
+		FlowLabel: 0xbeef})
+}
+
+func TestLinkAddDelGeneveFlowBased(t *testing.T) {
+	minKernelRequired(t, 4, 6)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Geneve{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		Dport:     1234,
+		FlowBased: true})
+}
+
+func TestLinkAddDelBareudp(t *testing.T) {
+	minKernelRequired(t, 5, 8)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Bareudp{
+		LinkAttrs:  LinkAttrs{Name: "foo"},
+		Port:       6635,
+		EtherType:  unix.ETH_P_MPLS_UC,
+		SrcPortMin: 1000,
+		MultiProto: true})
+}
+
+func TestLinkAddDelIp6tnl(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	encapLimit := uint8(0)
+	testLinkAddDel(t, &Ip6tnl{
+		LinkAttrs:  LinkAttrs{Name: "foo"},
+		Local:      net.ParseIP("2001:db8::1"),
+		Remote:     net.ParseIP("2001:db8::2"),
+		Ttl:        64,
+		EncapLimit: &encapLimit,
+		FlowLabel:  0x12345,
+		Proto:      unix.IPPROTO_IPV6})
+
+	testLinkAddDel(t, &Ip6tnl{
+		LinkAttrs: LinkAttrs{Name: "bar"},
+		Local:     net.ParseIP("2001:db8::1"),
+		Remote:    net.ParseIP("2001:db8::3"),
+		Flags:     nl.IP6_TNL_F_IGN_ENCAP_LIMIT | nl.IP6_TNL_F_USE_ORIG_FLOWLABEL,
+		Proto:     unix.IPPROTO_IPIP})
+}
+
+func TestLinkAddDelIp6tnlFlowBased(t *testing.T) {
+	minKernelRequired(t, 4, 9)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Ip6tnl{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		FlowBased: true})
+}
+
+func TestLinkAddDelErspan(t *testing.T) {
+	minKernelRequired(t, 4, 16)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	v0, v1, v2 := uint8(0), uint8(1), uint8(2)
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		Local:     net.IPv4(127, 0, 0, 1),
+		Remote:    net.IPv4(127, 0, 0, 2),
+		IKey:      100,
+		OKey:      100,
+		Version:   &v1,
+		Index:     123})
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "bar"},
+		Local:     net.IPv4(127, 0, 0, 1),
+		Remote:    net.IPv4(127, 0, 0, 3),
+		IKey:      101,
+		OKey:      101,
+		Index:     321})
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "baz"},
+		Local:     net.IPv4(127, 0, 0, 1),
+		Remote:    net.IPv4(127, 0, 0, 4),
+		IKey:      102,
+		OKey:      102,
+		Version:   &v0})
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs:  LinkAttrs{Name: "foo6"},
+		Local:      net.ParseIP("2001:db8::1"),
+		Remote:     net.ParseIP("2001:db8::2"),
+		IKey:       200,
+		OKey:       200,
+		Version:    &v2,
+		Direction:  nl.ERSPAN_DIRECTION_EGRESS,
+		HardwareID: 7,
+		FlowLabel:  0xabc})
+}
+
+func TestLinkAddDelErspanFlowBased(t *testing.T) {
+	minKernelRequired(t, 4, 16)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "foo"},
+		FlowBased: true})
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "foo6"},
+		FlowBased: true,
+		IPv6:      true})
+}
+
+func TestLinkTunnelDataSerialize(t *testing.T) {
+	parseData := func(add func(linkInfo *nl.RtAttr)) []syscall.NetlinkRouteAttr {
+		linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+		add(linkInfo)
+		infos, err := nl.ParseRouteAttr(linkInfo.Serialize()[unix.SizeofRtAttr:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := nl.ParseRouteAttr(infos[0].Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	geneve := &Geneve{ID: 42, Remote: net.ParseIP("2001:db8::1"), Ttl: 3, Dport: 6081, FlowLabel: 0xfffff}
+	data := parseData(func(linkInfo *nl.RtAttr) { addGeneveAttrs(geneve, linkInfo) })
+	other := &Geneve{}
+	parseGeneveData(other, data)
+	compareGeneve(t, geneve, other)
+
+	geneve = &Geneve{Dport: 6081, FlowBased: true}
+	data = parseData(func(linkInfo *nl.RtAttr) { addGeneveAttrs(geneve, linkInfo) })
+	if len(data) != 2 {
+		t.Fatalf("Flow based Geneve has %d attributes, should have the port and collect metadata", len(data))
+	}
+	other = &Geneve{}
+	parseGeneveData(other, data)
+	compareGeneve(t, geneve, other)
+
+	bareudp := &Bareudp{Port: 6635, EtherType: unix.ETH_P_IP, SrcPortMin: 1024, MultiProto: true}
+	data = parseData(func(linkInfo *nl.RtAttr) { addBareudpAttrs(bareudp, linkInfo) })
+	otherBareudp := &Bareudp{}
+	parseBareudpData(otherBareudp, data)
+	compareBareudp(t, bareudp, otherBareudp)
+
+	encapLimit := uint8(3)
+	ip6tnl := &Ip6tnl{
+		Local:      net.ParseIP("2001:db8::1"),
+		Remote:     net.ParseIP("2001:db8::2"),
+		Ttl:        64,
+		EncapLimit: &encapLimit,
+		FlowLabel:  0x12345,
+		Flags:      nl.IP6_TNL_F_USE_ORIG_TCLASS,
+		Proto:      unix.IPPROTO_IPIP,
+	}
+	data = parseData(func(linkInfo *nl.RtAttr) { addIp6tnlAttrs(ip6tnl, linkInfo) })
+	otherIp6tnl := &Ip6tnl{}
+	parseIp6tnlData(otherIp6tnl, data)
+	compareIp6tnl(t, ip6tnl, otherIp6tnl)
+	data = parseData(func(linkInfo *nl.RtAttr) { addIp6tnlAttrs(&Ip6tnl{}, linkInfo) })
+	for _, datum := range data {
+		if datum.Attr.Type == nl.IFLA_IPTUN_ENCAP_LIMIT {
+			t.Fatal("Ip6tnl without EncapLimit should keep the kernel default")
+		}
+	}
+
+	version := uint8(2)
+	erspan := &Erspan{
+		Local:      net.ParseIP("2001:db8::1"),
+		Remote:     net.ParseIP("2001:db8::2"),
+		IKey:       5,
+		OKey:       6,
+		Version:    &version,
+		Direction:  nl.ERSPAN_DIRECTION_EGRESS,
+		HardwareID: 9,
+		FlowLabel:  0x54321,
+	}
+	if erspan.Type() != "ip6erspan" {
+		t.Fatalf("Erspan over IPv6 is %s, should be ip6erspan", erspan.Type())
+	}
+	data = parseData(func(linkInfo *nl.RtAttr) { addErspanAttrs(erspan, linkInfo) })
+	otherErspan := &Erspan{}
+	parseErspanData(otherErspan, data)
+	compareErspan(t, erspan, otherErspan)
+	if flags := uint16(nl.GRE_KEY | nl.GRE_SEQ); otherErspan.IFlags != flags || otherErspan.OFlags != flags {
+		t.Fatalf("Erspan flags are %#x/%#x, should be %#x", otherErspan.IFlags, otherErspan.OFlags, flags)
+	}
+	erspan = &Erspan{Local: net.IPv4(127, 0, 0, 1), Index: 456}
+	data = parseData(func(linkInfo *nl.RtAttr) { addErspanAttrs(erspan, linkInfo) })
+	otherErspan = &Erspan{}
+	parseErspanData(otherErspan, data)
+	if otherErspan.Index != 456 {
+		t.Fatalf("Erspan.Index is %d without version, should be 456", otherErspan.Index)
+	}
+	version = 0
+	erspan = &Erspan{Local: net.IPv4(127, 0, 0, 1), Version: &version, Index: 456}
+	data = parseData(func(linkInfo *nl.RtAttr) { addErspanAttrs(erspan, linkInfo) })
+	otherErspan = &Erspan{}
+	parseErspanData(otherErspan, data)
+	if otherErspan.Version == nil || *otherErspan.Version != 0 || otherErspan.Index != 0 {
+		t.Fatalf("Erspan type I is version %v with index %d, should be version 0 without index", otherErspan.Version, otherErspan.Index)
+	}
+
+	if erspan := (&Erspan{Remote: net.ParseIP("2001:db8::2")}); erspan.Type() != "ip6erspan" {
+		t.Fatalf("Erspan with an IPv6 remote address is %s, should be ip6erspan", erspan.Type())
+	}
+	if gretun := (&Gretun{Remote: net.IPv4(192, 0, 2, 1)}); gretun.Type() != "gre" {
+		t.Fatalf("Gretun with an IPv4 remote address is %s, should be gre", gretun.Type())
+	}
+	if gretun := (&Gretun{FlowBased: true}); gretun.Type() != "gre" {
+		t.Fatalf("Flow based Gretun is %s, should be gre", gretun.Type())
+	}
+	if gretun := (&Gretun{FlowBased: true, IPv6: true}); gretun.Type() != "ip6gre" {
+		t.Fatalf("Flow based Gretun over IPv6 is %s, should be ip6gre", gretun.Type())
+	}
+	if erspan := (&Erspan{FlowBased: true, IPv6: true}); erspan.Type() != "ip6erspan" {
+		t.Fatalf("Flow based Erspan over IPv6 is %s, should be ip6erspan", erspan.Type())
+	}
+
+	encapLimit = 2
+	gretun := &Gretun{Local: net.ParseIP("2001:db8::1"), EncapLimit: &encapLimit, FlowLabel: 0x11, Ip6Flags: nl.IP6_TNL_F_IGN_ENCAP_LIMIT}
+	data = parseData(func(linkInfo *nl.RtAttr) { addGretunAttrs(gretun, linkInfo) })
+	otherGretun := &Gretun{}
+	parseGretunData(otherGretun, data)
+	if otherGretun.EncapLimit == nil || *otherGretun.EncapLimit != 2 || otherGretun.FlowLabel != 0x11 || otherGretun.Ip6Flags != nl.IP6_TNL_F_IGN_ENCAP_LIMIT {
+		t.Fatalf("Unexpected ip6gre attributes %+v", otherGretun)
+	}
+}
+
 func TestLinkAddDelVlan(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -1441,7 +1894,7 @@ func TestLinkXdp(t *testing.T) {
 	if err := LinkSetXdpFd(testXdpLink, fd); err != nil {
 		t.Fatal(err)
 	}
-	if err := LinkSetXdpFdWithFlags(testXdpLink, fd, nl.XDP_FLAGS_UPDATE_IF_NOEXIST); err != unix.EBUSY {
+	if err := LinkSetXdpFdWithFlags(testXdpLink, fd, nl.XDP_FLAGS_UPDATE_IF_NOEXIST); !errors.Is(err, unix.EBUSY) {
 		t.Fatal(err)
 	}
 	if err := LinkSetXdpFd(testXdpLink, -1); err != nil {
@@ -1746,6 +2199,67 @@ func TestLinkAddDelGTP(t *testing.T) {
 	testLinkAddDel(t, gtp)
 }
 
+func TestLinkAddDelMacsec(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "macsec")
+	defer tearDown()
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypt := true
+	replayProtect := true
+	macsec := &Macsec{
+		LinkAttrs:     LinkAttrs{Name: "bar", ParentIndex: parent.Attrs().Index},
+		Port:          11,
+		CipherSuite:   MACSEC_CIPHER_ID_GCM_AES_256,
+		ICVLen:        16,
+		EncodingSA:    2,
+		Encrypt:       &encrypt,
+		ReplayProtect: &replayProtect,
+		ReplayWindow:  32,
+		Validation:    MACSEC_VALIDATE_CHECK,
+	}
+	if err := LinkAdd(macsec); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := LinkByName("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, ok := link.(*Macsec)
+	if !ok {
+		t.Fatalf("Result of create is %T, not a macsec", link)
+	}
+	sci := MacsecSCI(parent.Attrs().HardwareAddr, 11)
+	if other.SCI != sci || other.Port != 11 {
+		t.Fatalf("SCI is %#x and port %d, should be %#x and 11", other.SCI, other.Port, sci)
+	}
+	if other.CipherSuite != macsec.CipherSuite || other.ICVLen != macsec.ICVLen || other.EncodingSA != macsec.EncodingSA {
+		t.Fatalf("Cipher is %#x/%d/%d, should be %#x/%d/%d", other.CipherSuite, other.ICVLen, other.EncodingSA,
+			macsec.CipherSuite, macsec.ICVLen, macsec.EncodingSA)
+	}
+	if other.Encrypt == nil || !*other.Encrypt || other.ReplayProtect == nil || !*other.ReplayProtect {
+		t.Fatal("Macsec.Encrypt and Macsec.ReplayProtect should be set")
+	}
+	if other.ReplayWindow != macsec.ReplayWindow || other.Validation != macsec.Validation {
+		t.Fatalf("Replay window is %d and validation %d, should be %d and %d", other.ReplayWindow, other.Validation,
+			macsec.ReplayWindow, macsec.Validation)
+	}
+
+	if err := LinkDel(macsec); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkDel(parent); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestLinkByNameWhenLinkIsNotFound(t *testing.T) {
 	_, err := LinkByName("iammissing")
 	if err == nil {
@@ -0,0 +1,95 @@
+package netlink
+
+// MacsecSA is a secure association of a transmit or receive secure channel
+// of a MACsec link, identified by its association number
+type MacsecSA struct {
+	AN     uint8
+	Active bool
+	// PN is the next packet number, of 64 bits with the XPN cipher suites
+	PN uint64
+	// Key is only sent when adding the SA, it is identified by KeyID
+	Key   []byte
+	KeyID []byte
+	// SSCI and Salt are only used with the XPN cipher suites, the Salt is
+	// not reported
+	SSCI  uint32
+	Salt  []byte
+	Stats MacsecSAStats
+}
+
+// MacsecSAStats are the counters of a secure association, the In ones of a
+// receive SA and the Out ones of a transmit SA
+type MacsecSAStats struct {
+	InPktsOK         uint32
+	InPktsInvalid    uint32
+	InPktsNotValid   uint32
+	InPktsNotUsingSA uint32
+	InPktsUnusedSA   uint32
+	OutPktsProtected uint32
+	OutPktsEncrypted uint32
+}
+
+// MacsecRxSC is a receive secure channel of a MACsec link, identified by the
+// SCI of the peer. A nil Active leaves the kernel default, an active channel.
+type MacsecRxSC struct {
+	SCI    uint64
+	Active *bool
+	// SAs and Stats are only reported
+	SAs   []*MacsecSA
+	Stats MacsecRxSCStats
+}
+
+type MacsecRxSCStats struct {
+	InOctetsValidated uint64
+	InOctetsDecrypted uint64
+	InPktsUnchecked   uint64
+	InPktsDelayed     uint64
+	InPktsOK          uint64
+	InPktsInvalid     uint64
+	InPktsLate        uint64
+	InPktsNotValid    uint64
+	InPktsNotUsingSA  uint64
+	InPktsUnusedSA    uint64
+}
+
+type MacsecTxSCStats struct {
+	OutPktsProtected   uint64
+	OutPktsEncrypted   uint64
+	OutOctetsProtected uint64
+	OutOctetsEncrypted uint64
+}
+
+type MacsecSecYStats struct {
+	OutPktsUntagged  uint64
+	InPktsUntagged   uint64
+	OutPktsTooLong   uint64
+	InPktsNoTag      uint64
+	InPktsBadTag     uint64
+	InPktsUnknownSCI uint64
+	InPktsNoSCI      uint64
+	InPktsOverrun    uint64
+}
+
+// MacsecSecY is the security entity of a MACsec link, with its transmit
+// secure channel and its receive secure channels
+type MacsecSecY struct {
+	LinkIndex     int
+	SCI           uint64
+	CipherSuite   uint64
+	ICVLen        uint8
+	EncodingSA    uint8
+	Operational   bool
+	Encrypt       bool
+	Protect       bool
+	IncludeSCI    bool
+	EndStation    bool
+	SCB           bool
+	ReplayProtect bool
+	ReplayWindow  uint32
+	Validation    MacsecValidation
+	Offload       MacsecOffload
+	TxSAs         []*MacsecSA
+	TxSCStats     MacsecTxSCStats
+	SecYStats     MacsecSecYStats
+	RxSCs         []*MacsecRxSC
+}
@@ -0,0 +1,641 @@
+package netlink
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// MacsecList returns the security entities of the MACsec links, with their
+// secure channels, associations and statistics.
+// Equivalent to: `ip macsec show`
+func MacsecList() ([]*MacsecSecY, error) {
+	return pkgHandle.MacsecList()
+}
+
+// MacsecList returns the security entities of the MACsec links, with their
+// secure channels, associations and statistics, using the netlink handle
+// passed.
+// Equivalent to: `ip macsec show`
+func (h *Handle) MacsecList() ([]*MacsecSecY, error) {
+	req, err := h.newMacsecRequest(nl.MACSEC_CMD_GET_TXSC, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	secys := make([]*MacsecSecY, 0, len(msgs))
+	for _, m := range msgs {
+		secy, err := parseMacsecSecY(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		secys = append(secys, secy)
+	}
+	return secys, nil
+}
+
+// MacsecListContext is like MacsecList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacsecListContext(ctx context.Context) ([]*MacsecSecY, error) {
+	return h.withContext(ctx).MacsecList()
+}
+
+// MacsecGet returns the security entity of the MACsec link.
+// Equivalent to: `ip macsec show $link`
+func MacsecGet(link Link) (*MacsecSecY, error) {
+	return pkgHandle.MacsecGet(link)
+}
+
+// MacsecGet returns the security entity of the MACsec link using the netlink
+// handle passed.
+// Equivalent to: `ip macsec show $link`
+func (h *Handle) MacsecGet(link Link) (*MacsecSecY, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	secys, err := h.MacsecList()
+	if err != nil {
+		return nil, err
+	}
+	for _, secy := range secys {
+		if secy.LinkIndex == base.Index {
+			return secy, nil
+		}
+	}
+	return nil, LinkNotFoundError{fmt.Errorf("MACsec link not found")}
+}
+
+// MacsecGetContext is like MacsecGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) MacsecGetContext(ctx context.Context, link Link) (*MacsecSecY, error) {
+	return h.withContext(ctx).MacsecGet(link)
+}
+
+// MacsecRxSCAdd adds the receive secure channel sc to the MACsec link, active
+// unless sc.Active is false.
+// Equivalent to: `ip macsec add $link rx sci $sci`
+func MacsecRxSCAdd(link Link, sc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCAdd(link, sc)
+}
+
+// MacsecRxSCAdd adds the receive secure channel sc to the MACsec link using
+// the netlink handle passed, active unless sc.Active is false.
+// Equivalent to: `ip macsec add $link rx sci $sci`
+func (h *Handle) MacsecRxSCAdd(link Link, sc *MacsecRxSC) error {
+	return h.macsecExecute(nl.MACSEC_CMD_ADD_RXSC, link, macsecRxSCAttr(sc.SCI, sc.Active), nil)
+}
+
+// MacsecRxSCAddContext is like MacsecRxSCAdd but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSCAddContext(ctx context.Context, link Link, sc *MacsecRxSC) error {
+	return h.withContext(ctx).MacsecRxSCAdd(link, sc)
+}
+
+// MacsecRxSCDel deletes the receive secure channel sc, and its secure
+// associations, from the MACsec link.
+// Equivalent to: `ip macsec del $link rx sci $sci`
+func MacsecRxSCDel(link Link, sc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCDel(link, sc)
+}
+
+// MacsecRxSCDel deletes the receive secure channel sc, and its secure
+// associations, from the MACsec link using the netlink handle passed.
+// Equivalent to: `ip macsec del $link rx sci $sci`
+func (h *Handle) MacsecRxSCDel(link Link, sc *MacsecRxSC) error {
+	return h.macsecExecute(nl.MACSEC_CMD_DEL_RXSC, link, macsecRxSCAttr(sc.SCI, nil), nil)
+}
+
+// MacsecRxSCDelContext is like MacsecRxSCDel but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSCDelContext(ctx context.Context, link Link, sc *MacsecRxSC) error {
+	return h.withContext(ctx).MacsecRxSCDel(link, sc)
+}
+
+// MacsecRxSCUpdate activates or deactivates the receive secure channel sc of
+// the MACsec link, as set by sc.Active.
+// Equivalent to: `ip macsec set $link rx sci $sci on|off`
+func MacsecRxSCUpdate(link Link, sc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCUpdate(link, sc)
+}
+
+// MacsecRxSCUpdate activates or deactivates the receive secure channel sc of
+// the MACsec link, as set by sc.Active, using the netlink handle passed.
+// Equivalent to: `ip macsec set $link rx sci $sci on|off`
+func (h *Handle) MacsecRxSCUpdate(link Link, sc *MacsecRxSC) error {
+	return h.macsecExecute(nl.MACSEC_CMD_UPD_RXSC, link, macsecRxSCAttr(sc.SCI, sc.Active), nil)
+}
+
+// MacsecRxSCUpdateContext is like MacsecRxSCUpdate but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSCUpdateContext(ctx context.Context, link Link, sc *MacsecRxSC) error {
+	return h.withContext(ctx).MacsecRxSCUpdate(link, sc)
+}
+
+// MacsecTxSAAdd adds the secure association sa to the transmit secure
+// channel of the MACsec link, its PN, Key and KeyID are mandatory.
+// Equivalent to: `ip macsec add $link tx sa $an pn $pn on key $keyid $key`
+func MacsecTxSAAdd(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSAAdd(link, sa)
+}
+
+// MacsecTxSAAdd adds the secure association sa to the transmit secure
+// channel of the MACsec link using the netlink handle passed, its PN, Key
+// and KeyID are mandatory.
+// Equivalent to: `ip macsec add $link tx sa $an pn $pn on key $keyid $key`
+func (h *Handle) MacsecTxSAAdd(link Link, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_ADD_TXSA, link, nil, sa)
+}
+
+// MacsecTxSAAddContext is like MacsecTxSAAdd but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecTxSAAddContext(ctx context.Context, link Link, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecTxSAAdd(link, sa)
+}
+
+// MacsecTxSADel deletes the secure association sa from the transmit secure
+// channel of the MACsec link.
+// Equivalent to: `ip macsec del $link tx sa $an`
+func MacsecTxSADel(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSADel(link, sa)
+}
+
+// MacsecTxSADel deletes the secure association sa from the transmit secure
+// channel of the MACsec link using the netlink handle passed.
+// Equivalent to: `ip macsec del $link tx sa $an`
+func (h *Handle) MacsecTxSADel(link Link, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_DEL_TXSA, link, nil, sa)
+}
+
+// MacsecTxSADelContext is like MacsecTxSADel but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecTxSADelContext(ctx context.Context, link Link, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecTxSADel(link, sa)
+}
+
+// MacsecTxSAUpdate updates the activity, and the PN when not zero, of the
+// secure association sa of the transmit secure channel of the MACsec link.
+// Equivalent to: `ip macsec set $link tx sa $an pn $pn on|off`
+func MacsecTxSAUpdate(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSAUpdate(link, sa)
+}
+
+// MacsecTxSAUpdate updates the activity, and the PN when not zero, of the
+// secure association sa of the transmit secure channel of the MACsec link
+// using the netlink handle passed.
+// Equivalent to: `ip macsec set $link tx sa $an pn $pn on|off`
+func (h *Handle) MacsecTxSAUpdate(link Link, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_UPD_TXSA, link, nil, sa)
+}
+
+// MacsecTxSAUpdateContext is like MacsecTxSAUpdate but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecTxSAUpdateContext(ctx context.Context, link Link, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecTxSAUpdate(link, sa)
+}
+
+// MacsecRxSAAdd adds the secure association sa to the receive secure
+// channel sci of the MACsec link, its Key and KeyID are mandatory.
+// Equivalent to: `ip macsec add $link rx sci $sci sa $an pn $pn on key $keyid $key`
+func MacsecRxSAAdd(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSAAdd(link, sci, sa)
+}
+
+// MacsecRxSAAdd adds the secure association sa to the receive secure
+// channel sci of the MACsec link using the netlink handle passed, its Key
+// and KeyID are mandatory.
+// Equivalent to: `ip macsec add $link rx sci $sci sa $an pn $pn on key $keyid $key`
+func (h *Handle) MacsecRxSAAdd(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_ADD_RXSA, link, macsecRxSCAttr(sci, nil), sa)
+}
+
+// MacsecRxSAAddContext is like MacsecRxSAAdd but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSAAddContext(ctx context.Context, link Link, sci uint64, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecRxSAAdd(link, sci, sa)
+}
+
+// MacsecRxSADel deletes the secure association sa from the receive secure
+// channel sci of the MACsec link.
+// Equivalent to: `ip macsec del $link rx sci $sci sa $an`
+func MacsecRxSADel(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSADel(link, sci, sa)
+}
+
+// MacsecRxSADel deletes the secure association sa from the receive secure
+// channel sci of the MACsec link using the netlink handle passed.
+// Equivalent to: `ip macsec del $link rx sci $sci sa $an`
+func (h *Handle) MacsecRxSADel(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_DEL_RXSA, link, macsecRxSCAttr(sci, nil), sa)
+}
+
+// MacsecRxSADelContext is like MacsecRxSADel but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSADelContext(ctx context.Context, link Link, sci uint64, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecRxSADel(link, sci, sa)
+}
+
+// MacsecRxSAUpdate updates the activity, and the PN when not zero, of the
+// secure association sa of the receive secure channel sci of the MACsec
+// link.
+// Equivalent to: `ip macsec set $link rx sci $sci sa $an pn $pn on|off`
+func MacsecRxSAUpdate(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSAUpdate(link, sci, sa)
+}
+
+// MacsecRxSAUpdate updates the activity, and the PN when not zero, of the
+// secure association sa of the receive secure channel sci of the MACsec link
+// using the netlink handle passed.
+// Equivalent to: `ip macsec set $link rx sci $sci sa $an pn $pn on|off`
+func (h *Handle) MacsecRxSAUpdate(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAExecute(nl.MACSEC_CMD_UPD_RXSA, link, macsecRxSCAttr(sci, nil), sa)
+}
+
+// MacsecRxSAUpdateContext is like MacsecRxSAUpdate but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) MacsecRxSAUpdateContext(ctx context.Context, link Link, sci uint64, sa *MacsecSA) error {
+	return h.withContext(ctx).MacsecRxSAUpdate(link, sci, sa)
+}
+
+func (h *Handle) newMacsecRequest(cmd uint8, flags int) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.MACSEC_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	req := h.newNetlinkRequest(int(f.ID), flags)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: nl.MACSEC_GENL_VERSION,
+	})
+	return req, nil
+}
+
+func (h *Handle) macsecExecute(cmd uint8, link Link, rxsc, sa *nl.RtAttr) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req, err := h.newMacsecRequest(cmd, unix.NLM_F_ACK)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(nl.MACSEC_ATTR_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	if rxsc != nil {
+		req.AddData(rxsc)
+	}
+	if sa != nil {
+		req.AddData(sa)
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// macsecSAExecute runs cmd on the secure association sa of the MACsec link.
+// The kernel expects a PN of 64 bits with the XPN cipher suites, the cipher
+// suite is looked up unless sa holds a Salt or link is a *Macsec with its
+// CipherSuite set.
+func (h *Handle) macsecSAExecute(cmd uint8, link Link, rxsc *nl.RtAttr, sa *MacsecSA) error {
+	xpn := len(sa.Salt) != 0
+	if !xpn && sa.PN != 0 && cmd != nl.MACSEC_CMD_DEL_TXSA && cmd != nl.MACSEC_CMD_DEL_RXSA {
+		var cipherSuite uint64
+		if macsec, ok := link.(*Macsec); ok {
+			cipherSuite = macsec.CipherSuite
+		}
+		if cipherSuite == 0 {
+			secy, err := h.MacsecGet(link)
+			if err != nil {
+				return err
+			}
+			cipherSuite = secy.CipherSuite
+		}
+		xpn = cipherSuite == MACSEC_CIPHER_ID_GCM_AES_XPN_128 || cipherSuite == MACSEC_CIPHER_ID_GCM_AES_XPN_256
+	}
+	return h.macsecExecute(cmd, link, rxsc, macsecSAAttr(cmd, sa, xpn))
+}
+
+func macsecSCIAttr(sci uint64) []byte {
+	b := make([]byte, 8)
+	networkOrder.PutUint64(b, sci)
+	return b
+}
+
+func macsecRxSCAttr(sci uint64, active *bool) *nl.RtAttr {
+	attr := nl.NewRtAttr(nl.MACSEC_ATTR_RXSC_CONFIG, nil)
+	nl.NewRtAttrChild(attr, nl.MACSEC_RXSC_ATTR_SCI, macsecSCIAttr(sci))
+	if active != nil {
+		nl.NewRtAttrChild(attr, nl.MACSEC_RXSC_ATTR_ACTIVE, boolAttr(*active))
+	}
+	return attr
+}
+
+// macsecSAAttr returns the configuration of sa for cmd, with a PN of 64 bits
+// for the XPN cipher suites.
+func macsecSAAttr(cmd uint8, sa *MacsecSA, xpn bool) *nl.RtAttr {
+	attr := nl.NewRtAttr(nl.MACSEC_ATTR_SA_CONFIG, nil)
+	nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_AN, nl.Uint8Attr(sa.AN))
+	if cmd == nl.MACSEC_CMD_DEL_TXSA || cmd == nl.MACSEC_CMD_DEL_RXSA {
+		return attr
+	}
+	nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_ACTIVE, boolAttr(sa.Active))
+	if sa.PN != 0 {
+		if xpn {
+			nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_PN, nl.Uint64Attr(sa.PN))
+		} else {
+			nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_PN, nl.Uint32Attr(uint32(sa.PN)))
+		}
+	}
+	if cmd == nl.MACSEC_CMD_UPD_TXSA || cmd == nl.MACSEC_CMD_UPD_RXSA {
+		return attr
+	}
+	if sa.Key != nil {
+		nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_KEY, sa.Key)
+	}
+	if sa.KeyID != nil {
+		// The key ids shorter than MACSEC_KEYID_LEN are zero padded
+		keyID := make([]byte, nl.MACSEC_KEYID_LEN)
+		copy(keyID, sa.KeyID)
+		if len(sa.KeyID) > nl.MACSEC_KEYID_LEN {
+			keyID = sa.KeyID
+		}
+		nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_KEYID, keyID)
+	}
+	if sa.Salt != nil {
+		nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_SSCI, nl.Uint32Attr(sa.SSCI))
+		nl.NewRtAttrChild(attr, nl.MACSEC_SA_ATTR_SALT, sa.Salt)
+	}
+	return attr
+}
+
+func parseMacsecSecY(b []byte) (*MacsecSecY, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	secy := &MacsecSecY{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_ATTR_IFINDEX:
+			secy.LinkIndex = int(native.Uint32(attr.Value))
+		case nl.MACSEC_ATTR_OFFLOAD:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, datum := range data {
+				if datum.Attr.Type&nl.NLA_TYPE_MASK != nl.MACSEC_OFFLOAD_ATTR_TYPE {
+					continue
+				}
+				for offload, value := range macsecOffloads {
+					if offload != 0 && value == datum.Value[0] {
+						secy.Offload = MacsecOffload(offload)
+					}
+				}
+			}
+		case nl.MACSEC_ATTR_SECY:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			secy.parseAttributes(data)
+		case nl.MACSEC_ATTR_TXSC_STATS:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			secy.TxSCStats.parseAttributes(data)
+		case nl.MACSEC_ATTR_SECY_STATS:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			secy.SecYStats.parseAttributes(data)
+		case nl.MACSEC_ATTR_TXSA_LIST:
+			if secy.TxSAs, err = parseMacsecSAList(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.MACSEC_ATTR_RXSC_LIST:
+			list, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range list {
+				sc, err := parseMacsecRxSC(item.Value)
+				if err != nil {
+					return nil, err
+				}
+				secy.RxSCs = append(secy.RxSCs, sc)
+			}
+		}
+	}
+	return secy, nil
+}
+
+func (secy *MacsecSecY) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_SECY_ATTR_SCI:
+			secy.SCI = networkOrder.Uint64(attr.Value)
+		case nl.MACSEC_SECY_ATTR_CIPHER_SUITE:
+			secy.CipherSuite = native.Uint64(attr.Value)
+		case nl.MACSEC_SECY_ATTR_ICV_LEN:
+			secy.ICVLen = attr.Value[0]
+		case nl.MACSEC_SECY_ATTR_ENCODING_SA:
+			secy.EncodingSA = attr.Value[0]
+		case nl.MACSEC_SECY_ATTR_WINDOW:
+			secy.ReplayWindow = native.Uint32(attr.Value)
+		case nl.MACSEC_SECY_ATTR_OPER:
+			secy.Operational = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_ENCRYPT:
+			secy.Encrypt = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_PROTECT:
+			secy.Protect = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_INC_SCI:
+			secy.IncludeSCI = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_ES:
+			secy.EndStation = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_SCB:
+			secy.SCB = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_REPLAY:
+			secy.ReplayProtect = attr.Value[0] == 1
+		case nl.MACSEC_SECY_ATTR_VALIDATE:
+			for validation, value := range macsecValidations {
+				if validation != 0 && value == attr.Value[0] {
+					secy.Validation = MacsecValidation(validation)
+				}
+			}
+		}
+	}
+}
+
+func parseMacsecRxSC(b []byte) (*MacsecRxSC, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	sc := &MacsecRxSC{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_RXSC_ATTR_SCI:
+			sc.SCI = networkOrder.Uint64(attr.Value)
+		case nl.MACSEC_RXSC_ATTR_ACTIVE:
+			active := attr.Value[0] == 1
+			sc.Active = &active
+		case nl.MACSEC_RXSC_ATTR_STATS:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			sc.Stats.parseAttributes(data)
+		case nl.MACSEC_RXSC_ATTR_SA_LIST:
+			if sc.SAs, err = parseMacsecSAList(attr.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return sc, nil
+}
+
+// parseMacsecSAList parses the nested secure associations of a list, typed
+// by their position
+func parseMacsecSAList(b []byte) ([]*MacsecSA, error) {
+	list, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	sas := make([]*MacsecSA, 0, len(list))
+	for _, item := range list {
+		attrs, err := nl.ParseRouteAttr(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		sa := &MacsecSA{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.MACSEC_SA_ATTR_AN:
+				sa.AN = attr.Value[0]
+			case nl.MACSEC_SA_ATTR_ACTIVE:
+				sa.Active = attr.Value[0] == 1
+			case nl.MACSEC_SA_ATTR_PN:
+				// The PN has 64 bits with the XPN cipher suites
+				if len(attr.Value) >= nl.MACSEC_XPN_PN_LEN {
+					sa.PN = native.Uint64(attr.Value)
+				} else {
+					sa.PN = uint64(native.Uint32(attr.Value))
+				}
+			case nl.MACSEC_SA_ATTR_KEYID:
+				sa.KeyID = attr.Value
+			case nl.MACSEC_SA_ATTR_SSCI:
+				sa.SSCI = native.Uint32(attr.Value)
+			case nl.MACSEC_SA_ATTR_STATS:
+				stats, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				sa.Stats.parseAttributes(stats)
+			}
+		}
+		sas = append(sas, sa)
+	}
+	return sas, nil
+}
+
+func (s *MacsecSAStats) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		v := native.Uint32(attr.Value)
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_SA_STATS_ATTR_IN_PKTS_OK:
+			s.InPktsOK = v
+		case nl.MACSEC_SA_STATS_ATTR_IN_PKTS_INVALID:
+			s.InPktsInvalid = v
+		case nl.MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_VALID:
+			s.InPktsNotValid = v
+		case nl.MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_USING_SA:
+			s.InPktsNotUsingSA = v
+		case nl.MACSEC_SA_STATS_ATTR_IN_PKTS_UNUSED_SA:
+			s.InPktsUnusedSA = v
+		case nl.MACSEC_SA_STATS_ATTR_OUT_PKTS_PROTECTED:
+			s.OutPktsProtected = v
+		case nl.MACSEC_SA_STATS_ATTR_OUT_PKTS_ENCRYPTED:
+			s.OutPktsEncrypted = v
+		}
+	}
+}
+
+func (s *MacsecRxSCStats) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.MACSEC_RXSC_STATS_ATTR_PAD {
+			continue
+		}
+		v := native.Uint64(attr.Value)
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_OCTETS_VALIDATED:
+			s.InOctetsValidated = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_OCTETS_DECRYPTED:
+			s.InOctetsDecrypted = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNCHECKED:
+			s.InPktsUnchecked = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_DELAYED:
+			s.InPktsDelayed = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_OK:
+			s.InPktsOK = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_INVALID:
+			s.InPktsInvalid = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_LATE:
+			s.InPktsLate = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_VALID:
+			s.InPktsNotValid = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_USING_SA:
+			s.InPktsNotUsingSA = v
+		case nl.MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNUSED_SA:
+			s.InPktsUnusedSA = v
+		}
+	}
+}
+
+func (s *MacsecTxSCStats) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.MACSEC_TXSC_STATS_ATTR_PAD {
+			continue
+		}
+		v := native.Uint64(attr.Value)
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_TXSC_STATS_ATTR_OUT_PKTS_PROTECTED:
+			s.OutPktsProtected = v
+		case nl.MACSEC_TXSC_STATS_ATTR_OUT_PKTS_ENCRYPTED:
+			s.OutPktsEncrypted = v
+		case nl.MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_PROTECTED:
+			s.OutOctetsProtected = v
+		case nl.MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_ENCRYPTED:
+			s.OutOctetsEncrypted = v
+		}
+	}
+}
+
+func (s *MacsecSecYStats) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.MACSEC_SECY_STATS_ATTR_PAD {
+			continue
+		}
+		v := native.Uint64(attr.Value)
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.MACSEC_SECY_STATS_ATTR_OUT_PKTS_UNTAGGED:
+			s.OutPktsUntagged = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_UNTAGGED:
+			s.InPktsUntagged = v
+		case nl.MACSEC_SECY_STATS_ATTR_OUT_PKTS_TOO_LONG:
+			s.OutPktsTooLong = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_TAG:
+			s.InPktsNoTag = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_BAD_TAG:
+			s.InPktsBadTag = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_UNKNOWN_SCI:
+			s.InPktsUnknownSCI = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_SCI:
+			s.InPktsNoSCI = v
+		case nl.MACSEC_SECY_STATS_ATTR_IN_PKTS_OVERRUN:
+			s.InPktsOverrun = v
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// +build linux
+
+package netlink
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+)
+
+func TestMacsecSCAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "macsec")
+	defer tearDown()
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt := true
+	macsec := &Macsec{
+		LinkAttrs:  LinkAttrs{Name: "bar", ParentIndex: parent.Attrs().Index},
+		Port:       1,
+		EncodingSA: 1,
+		Encrypt:    &encrypt,
+	}
+	if err := LinkAdd(macsec); err != nil {
+		t.Fatal(err)
+	}
+
+	key := bytes.Repeat([]byte{0xaa}, 16)
+	txSA := &MacsecSA{AN: 1, Active: true, PN: 10, Key: key, KeyID: []byte{1}}
+	if err := MacsecTxSAAdd(macsec, txSA); err != nil {
+		t.Fatal(err)
+	}
+	// The channel is added active when Active is left unset
+	rxSC := &MacsecRxSC{SCI: 0x0200000000010001}
+	if err := MacsecRxSCAdd(macsec, rxSC); err != nil {
+		t.Fatal(err)
+	}
+	rxSA := &MacsecSA{AN: 0, Active: true, PN: 1, Key: key, KeyID: []byte{2}}
+	if err := MacsecRxSAAdd(macsec, rxSC.SCI, rxSA); err != nil {
+		t.Fatal(err)
+	}
+
+	secy, err := MacsecGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secy.Encrypt || secy.EncodingSA != 1 || secy.CipherSuite != MACSEC_CIPHER_ID_GCM_AES_128 {
+		t.Fatalf("Unexpected security entity %+v", secy)
+	}
+	if len(secy.TxSAs) != 1 || secy.TxSAs[0].AN != 1 || secy.TxSAs[0].PN != 10 || !secy.TxSAs[0].Active {
+		t.Fatalf("Unexpected transmit SAs %+v", secy.TxSAs)
+	}
+	if keyID := secy.TxSAs[0].KeyID; len(keyID) != nl.MACSEC_KEYID_LEN || keyID[0] != 1 {
+		t.Fatalf("Key id is %x, should be zero padded 01", keyID)
+	}
+	if len(secy.RxSCs) != 1 || secy.RxSCs[0].SCI != rxSC.SCI || secy.RxSCs[0].Active == nil || !*secy.RxSCs[0].Active || len(secy.RxSCs[0].SAs) != 1 {
+		t.Fatalf("Unexpected receive SCs %+v", secy.RxSCs)
+	}
+
+	inactive := false
+	rxSC.Active = &inactive
+	if err := MacsecRxSCUpdate(macsec, rxSC); err != nil {
+		t.Fatal(err)
+	}
+	if secy, err = MacsecGet(macsec); err != nil {
+		t.Fatal(err)
+	}
+	if len(secy.RxSCs) != 1 || secy.RxSCs[0].Active == nil || *secy.RxSCs[0].Active {
+		t.Fatalf("Receive SC not deactivated %+v", secy.RxSCs)
+	}
+
+	txSA.Active = false
+	txSA.PN = 0
+	if err := MacsecTxSAUpdate(macsec, txSA); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSADel(macsec, rxSC.SCI, rxSA); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSCDel(macsec, rxSC); err != nil {
+		t.Fatal(err)
+	}
+	secy, err = MacsecGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secy.TxSAs) != 1 || secy.TxSAs[0].Active || secy.TxSAs[0].PN != 10 {
+		t.Fatalf("Transmit SA not updated %+v", secy.TxSAs)
+	}
+	if len(secy.RxSCs) != 0 {
+		t.Fatalf("Receive SCs not deleted %+v", secy.RxSCs)
+	}
+	if err := MacsecTxSADel(macsec, txSA); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMacsecXPNSA(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "macsec")
+	defer tearDown()
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	macsec := &Macsec{
+		LinkAttrs:   LinkAttrs{Name: "bar", ParentIndex: parent.Attrs().Index},
+		CipherSuite: MACSEC_CIPHER_ID_GCM_AES_XPN_128,
+	}
+	if err := LinkAdd(macsec); err != nil {
+		t.Fatal(err)
+	}
+
+	sa := &MacsecSA{AN: 0, PN: 1, Key: bytes.Repeat([]byte{0xaa}, 16), KeyID: []byte{1}, SSCI: 1, Salt: make([]byte, 12)}
+	if err := MacsecTxSAAdd(macsec, sa); err != nil {
+		t.Fatal(err)
+	}
+	// The cipher suite of a link without it is looked up to send a 64 bits PN
+	update := &MacsecSA{AN: 0, PN: 1 << 40}
+	if err := MacsecTxSAUpdate(&GenericLink{LinkAttrs: LinkAttrs{Name: "bar"}}, update); err != nil {
+		t.Fatal(err)
+	}
+	secy, err := MacsecGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secy.TxSAs) != 1 || secy.TxSAs[0].PN != 1<<40 {
+		t.Fatalf("Transmit SA not updated %+v", secy.TxSAs)
+	}
+}
+
+func TestMacsecSAAttr(t *testing.T) {
+	sa := &MacsecSA{AN: 2, Active: true, PN: 7, Key: []byte{1, 2, 3}, KeyID: []byte{9}}
+	parse := func(attr *nl.RtAttr) map[uint16][]byte {
+		attrs, err := nl.ParseRouteAttr(attr.Serialize()[4:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := make(map[uint16][]byte)
+		for _, attr := range attrs {
+			values[attr.Attr.Type] = attr.Value
+		}
+		return values
+	}
+
+	values := parse(macsecSAAttr(nl.MACSEC_CMD_DEL_TXSA, sa, false))
+	if len(values) != 1 || values[nl.MACSEC_SA_ATTR_AN][0] != 2 {
+		t.Fatalf("Deletion should only hold the AN: %v", values)
+	}
+
+	values = parse(macsecSAAttr(nl.MACSEC_CMD_UPD_RXSA, sa, false))
+	if len(values) != 3 || len(values[nl.MACSEC_SA_ATTR_PN]) != nl.MACSEC_DEFAULT_PN_LEN {
+		t.Fatalf("Update should hold the AN, active and a 32 bits PN: %v", values)
+	}
+
+	values = parse(macsecSAAttr(nl.MACSEC_CMD_ADD_TXSA, sa, false))
+	if !bytes.Equal(values[nl.MACSEC_SA_ATTR_KEY], sa.Key) {
+		t.Fatalf("Key is %x, should be %x", values[nl.MACSEC_SA_ATTR_KEY], sa.Key)
+	}
+	if keyID := values[nl.MACSEC_SA_ATTR_KEYID]; len(keyID) != nl.MACSEC_KEYID_LEN || keyID[0] != 9 {
+		t.Fatalf("Key id is %x, should be zero padded 09", keyID)
+	}
+	if _, ok := values[nl.MACSEC_SA_ATTR_SALT]; ok {
+		t.Fatal("Salt should only be sent with the XPN cipher suites")
+	}
+
+	values = parse(macsecSAAttr(nl.MACSEC_CMD_ADD_RXSA, sa, true))
+	if pn := values[nl.MACSEC_SA_ATTR_PN]; len(pn) != nl.MACSEC_XPN_PN_LEN || native.Uint64(pn) != 7 {
+		t.Fatalf("PN is %x, should be 7 on 64 bits", pn)
+	}
+}
+
+func TestParseMacsecSecY(t *testing.T) {
+	secyAttr := nl.NewRtAttr(nl.MACSEC_ATTR_SECY, nil)
+	nl.NewRtAttrChild(secyAttr, nl.MACSEC_SECY_ATTR_SCI, macsecSCIAttr(0x0011223344550001))
+	nl.NewRtAttrChild(secyAttr, nl.MACSEC_SECY_ATTR_CIPHER_SUITE, nl.Uint64Attr(MACSEC_CIPHER_ID_GCM_AES_XPN_256))
+	nl.NewRtAttrChild(secyAttr, nl.MACSEC_SECY_ATTR_ICV_LEN, nl.Uint8Attr(16))
+	nl.NewRtAttrChild(secyAttr, nl.MACSEC_SECY_ATTR_PROTECT, nl.Uint8Attr(1))
+	nl.NewRtAttrChild(secyAttr, nl.MACSEC_SECY_ATTR_VALIDATE, nl.Uint8Attr(nl.MACSEC_VALIDATE_STRICT))
+
+	rxsa := nl.NewRtAttr(1, nil)
+	nl.NewRtAttrChild(rxsa, nl.MACSEC_SA_ATTR_AN, nl.Uint8Attr(3))
+	nl.NewRtAttrChild(rxsa, nl.MACSEC_SA_ATTR_PN, nl.Uint64Attr(1<<40))
+	nl.NewRtAttrChild(rxsa, nl.MACSEC_SA_ATTR_SSCI, nl.Uint32Attr(5))
+	stats := nl.NewRtAttrChild(rxsa, nl.MACSEC_SA_ATTR_STATS, nil)
+	nl.NewRtAttrChild(stats, nl.MACSEC_SA_STATS_ATTR_IN_PKTS_OK, nl.Uint32Attr(42))
+	rxsc := nl.NewRtAttr(1, nil)
+	nl.NewRtAttrChild(rxsc, nl.MACSEC_RXSC_ATTR_SCI, macsecSCIAttr(0x0066778899aa0001))
+	nl.NewRtAttrChild(rxsc, nl.MACSEC_RXSC_ATTR_SA_LIST, nil).AddChild(rxsa)
+	rxscs := nl.NewRtAttr(nl.MACSEC_ATTR_RXSC_LIST, nil)
+	rxscs.AddChild(rxsc)
+
+	txStats := nl.NewRtAttr(nl.MACSEC_ATTR_TXSC_STATS, nil)
+	nl.NewRtAttrChild(txStats, nl.MACSEC_TXSC_STATS_ATTR_PAD, nil)
+	nl.NewRtAttrChild(txStats, nl.MACSEC_TXSC_STATS_ATTR_OUT_PKTS_ENCRYPTED, nl.Uint64Attr(12))
+	offload := nl.NewRtAttr(nl.MACSEC_ATTR_OFFLOAD, nil)
+	nl.NewRtAttrChild(offload, nl.MACSEC_OFFLOAD_ATTR_TYPE, nl.Uint8Attr(nl.MACSEC_OFFLOAD_MAC))
+
+	var b []byte
+	for _, attr := range []*nl.RtAttr{
+		nl.NewRtAttr(nl.MACSEC_ATTR_IFINDEX, nl.Uint32Attr(4)),
+		secyAttr, rxscs, txStats, offload,
+	} {
+		b = append(b, attr.Serialize()...)
+	}
+	secy, err := parseMacsecSecY(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secy.LinkIndex != 4 || secy.SCI != 0x0011223344550001 || secy.CipherSuite != MACSEC_CIPHER_ID_GCM_AES_XPN_256 {
+		t.Fatalf("Unexpected security entity %+v", secy)
+	}
+	if secy.ICVLen != 16 || !secy.Protect || secy.Encrypt || secy.Validation != MACSEC_VALIDATE_STRICT || secy.Offload != MACSEC_OFFLOAD_MAC {
+		t.Fatalf("Unexpected security entity %+v", secy)
+	}
+	if secy.TxSCStats.OutPktsEncrypted != 12 {
+		t.Fatalf("Transmit SC stats are %+v", secy.TxSCStats)
+	}
+	if len(secy.RxSCs) != 1 || secy.RxSCs[0].SCI != 0x0066778899aa0001 || len(secy.RxSCs[0].SAs) != 1 {
+		t.Fatalf("Unexpected receive SCs %+v", secy.RxSCs)
+	}
+	sa := secy.RxSCs[0].SAs[0]
+	if sa.AN != 3 || sa.PN != 1<<40 || sa.SSCI != 5 || sa.Stats.InPktsOK != 42 {
+		t.Fatalf("Unexpected receive SA %+v", sa)
+	}
+}
@@ -0,0 +1,47 @@
+// +build !linux
+
+package netlink
+
+func MacsecList() ([]*MacsecSecY, error) {
+	return nil, ErrNotImplemented
+}
+
+func MacsecGet(link Link) (*MacsecSecY, error) {
+	return nil, ErrNotImplemented
+}
+
+func MacsecRxSCAdd(link Link, sc *MacsecRxSC) error {
+	return ErrNotImplemented
+}
+
+func MacsecRxSCDel(link Link, sc *MacsecRxSC) error {
+	return ErrNotImplemented
+}
+
+func MacsecRxSCUpdate(link Link, sc *MacsecRxSC) error {
+	return ErrNotImplemented
+}
+
+func MacsecTxSAAdd(link Link, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
+
+func MacsecTxSADel(link Link, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
+
+func MacsecTxSAUpdate(link Link, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
+
+func MacsecRxSAAdd(link Link, sci uint64, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
+
+func MacsecRxSADel(link Link, sci uint64, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
+
+func MacsecRxSAUpdate(link Link, sci uint64, sa *MacsecSA) error {
+	return ErrNotImplemented
+}
@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"net"
 	"unsafe"
 
@@ -76,6 +78,12 @@ func (h *Handle) NeighAdd(neigh *Neigh) error {
 	return h.neighAdd(neigh, unix.NLM_F_CREATE|unix.NLM_F_EXCL)
 }
 
+// NeighAddContext is like NeighAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighAddContext(ctx context.Context, neigh *Neigh) error {
+	return h.withContext(ctx).NeighAdd(neigh)
+}
+
 // NeighSet will add or replace an IP to MAC mapping to the ARP table
 // Equivalent to: `ip neigh replace....`
 func NeighSet(neigh *Neigh) error {
@@ -88,6 +96,12 @@ func (h *Handle) NeighSet(neigh *Neigh) error {
 	return h.neighAdd(neigh, unix.NLM_F_CREATE|unix.NLM_F_REPLACE)
 }
 
+// NeighSetContext is like NeighSet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighSetContext(ctx context.Context, neigh *Neigh) error {
+	return h.withContext(ctx).NeighSet(neigh)
+}
+
 // NeighAppend will append an entry to FDB
 // Equivalent to: `bridge fdb append...`
 func NeighAppend(neigh *Neigh) error {
@@ -100,6 +114,12 @@ func (h *Handle) NeighAppend(neigh *Neigh) error {
 	return h.neighAdd(neigh, unix.NLM_F_CREATE|unix.NLM_F_APPEND)
 }
 
+// NeighAppendContext is like NeighAppend but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighAppendContext(ctx context.Context, neigh *Neigh) error {
+	return h.withContext(ctx).NeighAppend(neigh)
+}
+
 // NeighAppend will append an entry to FDB
 // Equivalent to: `bridge fdb append...`
 func neighAdd(neigh *Neigh, mode int) error {
@@ -126,6 +146,12 @@ func (h *Handle) NeighDel(neigh *Neigh) error {
 	return neighHandle(neigh, req)
 }
 
+// NeighDelContext is like NeighDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighDelContext(ctx context.Context, neigh *Neigh) error {
+	return h.withContext(ctx).NeighDel(neigh)
+}
+
 func neighHandle(neigh *Neigh, req *nl.NetlinkRequest) error {
 	var family int
 
@@ -195,6 +221,12 @@ func (h *Handle) NeighList(linkIndex, family int) ([]Neigh, error) {
 	return h.neighList(linkIndex, family, 0)
 }
 
+// NeighListContext is like NeighList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighListContext(ctx context.Context, linkIndex, family int) ([]Neigh, error) {
+	return h.withContext(ctx).NeighList(linkIndex, family)
+}
+
 // NeighProxyList gets a list of neighbor proxies in the system.
 // Equivalent to: `ip neighbor show proxy`.
 // The list can be filtered by link, ip family.
@@ -202,6 +234,12 @@ func (h *Handle) NeighProxyList(linkIndex, family int) ([]Neigh, error) {
 	return h.neighList(linkIndex, family, NTF_PROXY)
 }
 
+// NeighProxyListContext is like NeighProxyList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NeighProxyListContext(ctx context.Context, linkIndex, family int) ([]Neigh, error) {
+	return h.withContext(ctx).NeighProxyList(linkIndex, family)
+}
+
 func (h *Handle) neighList(linkIndex, family, flags int) ([]Neigh, error) {
 	req := h.newNetlinkRequest(unix.RTM_GETNEIGH, unix.NLM_F_DUMP)
 	msg := Ndmsg{
@@ -211,9 +249,9 @@ func (h *Handle) neighList(linkIndex, family, flags int) ([]Neigh, error) {
 	}
 	req.AddData(&msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNEIGH)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNEIGH)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Neigh
@@ -232,7 +270,7 @@ func (h *Handle) neighList(linkIndex, family, flags int) ([]Neigh, error) {
 		res = append(res, *neigh)
 	}
 
-	return res, nil
+	return res, executeErr
 }
 
 func NeighDeserialize(m []byte) (*Neigh, error) {
@@ -9,3 +9,8 @@ const (
 	FAMILY_V6   = nl.FAMILY_V6
 	FAMILY_MPLS = nl.FAMILY_MPLS
 )
+
+// ErrDumpInterrupted is returned by the list functions when the kernel
+// reports that the table changed while it was being dumped, along with the
+// result which may be incomplete or inconsistent. See SetDumpRetries.
+var ErrDumpInterrupted = nl.ErrDumpInterrupted
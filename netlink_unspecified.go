@@ -4,6 +4,10 @@ package netlink
 
 import "net"
 
+func SetDumpRetries(retries int) error {
+	return ErrNotImplemented
+}
+
 func LinkSetUp(link Link) error {
 	return ErrNotImplemented
 }
@@ -223,3 +227,7 @@ func NeighDeserialize(m []byte) (*Neigh, error) {
 func SocketGet(local, remote net.Addr) (*Socket, error) {
 	return nil, ErrNotImplemented
 }
+
+func SocketDestroy(protocol uint8, id SocketID) error {
+	return ErrNotImplemented
+}
@@ -13,6 +13,7 @@ package netlink
 // in to that namespace.
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vishvananda/netlink/nl"
@@ -33,6 +34,12 @@ func (h *Handle) GetNetNsIdByPid(pid int) (int, error) {
 	return h.getNetNsId(NETNSA_PID, uint32(pid))
 }
 
+// GetNetNsIdByPidContext is like GetNetNsIdByPid but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GetNetNsIdByPidContext(ctx context.Context, pid int) (int, error) {
+	return h.withContext(ctx).GetNetNsIdByPid(pid)
+}
+
 // GetNetNsIdByPid looks up the network namespace ID for a given pid (really thread id).
 // Returns -1 if the namespace does not have an ID set.
 func GetNetNsIdByPid(pid int) (int, error) {
@@ -45,6 +52,12 @@ func (h *Handle) SetNetNsIdByPid(pid, nsid int) error {
 	return h.setNetNsId(NETNSA_PID, uint32(pid), uint32(nsid))
 }
 
+// SetNetNsIdByPidContext is like SetNetNsIdByPid but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) SetNetNsIdByPidContext(ctx context.Context, pid, nsid int) error {
+	return h.withContext(ctx).SetNetNsIdByPid(pid, nsid)
+}
+
 // SetNetNSIdByPid sets the ID of the network namespace for a given pid (really thread id).
 // The ID can only be set for namespaces without an ID already set.
 func SetNetNsIdByPid(pid, nsid int) error {
@@ -58,6 +71,12 @@ func (h *Handle) GetNetNsIdByFd(fd int) (int, error) {
 	return h.getNetNsId(NETNSA_FD, uint32(fd))
 }
 
+// GetNetNsIdByFdContext is like GetNetNsIdByFd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) GetNetNsIdByFdContext(ctx context.Context, fd int) (int, error) {
+	return h.withContext(ctx).GetNetNsIdByFd(fd)
+}
+
 // GetNetNsIdByPid looks up the network namespace ID for a given fd.
 // fd must be an open file descriptor to a namespace file.
 // Returns -1 if the namespace does not have an ID set.
@@ -72,6 +91,12 @@ func (h *Handle) SetNetNsIdByFd(fd, nsid int) error {
 	return h.setNetNsId(NETNSA_FD, uint32(fd), uint32(nsid))
 }
 
+// SetNetNsIdByFdContext is like SetNetNsIdByFd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) SetNetNsIdByFdContext(ctx context.Context, fd, nsid int) error {
+	return h.withContext(ctx).SetNetNsIdByFd(fd, nsid)
+}
+
 // SetNetNSIdByFd sets the ID of the network namespace for a given fd.
 // fd must be an open file descriptor to a namespace file.
 // The ID can only be set for namespaces without an ID already set.
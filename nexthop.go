@@ -0,0 +1,102 @@
+package netlink
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// NexthopGroupType selects how traffic is spread over the members of a
+// nexthop group.
+type NexthopGroupType uint16
+
+const (
+	NEXTHOP_GROUP_TYPE_MPATH NexthopGroupType = iota // hash-threshold
+	NEXTHOP_GROUP_TYPE_RES                           // resilient
+)
+
+func (t NexthopGroupType) String() string {
+	switch t {
+	case NEXTHOP_GROUP_TYPE_MPATH:
+		return "mpath"
+	case NEXTHOP_GROUP_TYPE_RES:
+		return "resilient"
+	}
+	return fmt.Sprintf("unknown(%d)", uint16(t))
+}
+
+// Nexthop represents a kernel nexthop object, which routes can reference
+// by ID instead of carrying their own gateway.
+// A nexthop is either a gateway and/or device, a blackhole or a group of
+// other nexthops.
+type Nexthop struct {
+	ID        uint32
+	Family    int
+	LinkIndex int
+	Gw        net.IP
+	Protocol  int
+	Scope     Scope
+	Flags     int
+	Blackhole bool
+	FDB       bool
+	Encap     Encap
+	Group     []NexthopGroupEntry
+	GroupType NexthopGroupType
+	// Resilient holds the parameters of a NEXTHOP_GROUP_TYPE_RES group.
+	Resilient *NexthopResilience
+}
+
+// NexthopGroupEntry is a member of a nexthop group.
+type NexthopGroupEntry struct {
+	ID uint32
+	// Weight is in the range 1 to 256, zero is treated as 1.
+	Weight int
+}
+
+// NexthopResilience describes the hash table of a resilient nexthop group.
+type NexthopResilience struct {
+	Buckets         uint16
+	IdleTimer       time.Duration
+	UnbalancedTimer time.Duration
+	// UnbalancedTime is how long the group has been unbalanced. It is
+	// only reported by the kernel and ignored when adding the group.
+	UnbalancedTime time.Duration
+}
+
+func (n Nexthop) String() string {
+	elems := []string{}
+	elems = append(elems, fmt.Sprintf("ID: %d", n.ID))
+	switch {
+	case len(n.Group) > 0:
+		elems = append(elems, fmt.Sprintf("Group: %v", n.Group))
+		elems = append(elems, fmt.Sprintf("Type: %s", n.GroupType))
+		if n.Resilient != nil {
+			elems = append(elems, fmt.Sprintf("Buckets: %d", n.Resilient.Buckets))
+		}
+	case n.Blackhole:
+		elems = append(elems, "Blackhole")
+	default:
+		elems = append(elems, fmt.Sprintf("Ifindex: %d", n.LinkIndex))
+		elems = append(elems, fmt.Sprintf("Gw: %s", n.Gw))
+		if n.Encap != nil {
+			elems = append(elems, fmt.Sprintf("Encap: %s", n.Encap))
+		}
+	}
+	if n.FDB {
+		elems = append(elems, "FDB")
+	}
+	elems = append(elems, fmt.Sprintf("Flags: %s", n.ListFlags()))
+	return fmt.Sprintf("{%s}", strings.Join(elems, " "))
+}
+
+func (e NexthopGroupEntry) String() string {
+	return fmt.Sprintf("%d,%d", e.ID, e.Weight)
+}
+
+// NexthopUpdate is sent when a nexthop changes - type is RTM_NEWNEXTHOP or
+// RTM_DELNEXTHOP
+type NexthopUpdate struct {
+	Type uint16
+	Nexthop
+}
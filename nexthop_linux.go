@@ -0,0 +1,397 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// The kernel reports the timers of resilient nexthop groups in clock_t
+// units, which are always USER_HZ (100) ticks per second.
+const userHz = 100
+
+func (n *Nexthop) ListFlags() []string {
+	return listFlags(n.Flags)
+}
+
+// NexthopAdd will add a nexthop object to the system.
+// Equivalent to: `ip nexthop add $nexthop`
+func NexthopAdd(nh *Nexthop) error {
+	return pkgHandle.NexthopAdd(nh)
+}
+
+// NexthopAdd will add a nexthop object to the system.
+// Equivalent to: `ip nexthop add $nexthop`
+func (h *Handle) NexthopAdd(nh *Nexthop) error {
+	flags := unix.NLM_F_CREATE | unix.NLM_F_EXCL | unix.NLM_F_ACK
+	req := h.newNetlinkRequest(nl.RTM_NEWNEXTHOP, flags)
+	return h.nexthopHandle(nh, req)
+}
+
+// NexthopAddContext is like NexthopAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NexthopAddContext(ctx context.Context, nh *Nexthop) error {
+	return h.withContext(ctx).NexthopAdd(nh)
+}
+
+// NexthopReplace will add a nexthop object to the system or replace the
+// one with the same ID.
+// Equivalent to: `ip nexthop replace $nexthop`
+func NexthopReplace(nh *Nexthop) error {
+	return pkgHandle.NexthopReplace(nh)
+}
+
+// NexthopReplace will add a nexthop object to the system or replace the
+// one with the same ID.
+// Equivalent to: `ip nexthop replace $nexthop`
+func (h *Handle) NexthopReplace(nh *Nexthop) error {
+	flags := unix.NLM_F_CREATE | unix.NLM_F_REPLACE | unix.NLM_F_ACK
+	req := h.newNetlinkRequest(nl.RTM_NEWNEXTHOP, flags)
+	return h.nexthopHandle(nh, req)
+}
+
+// NexthopReplaceContext is like NexthopReplace but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) NexthopReplaceContext(ctx context.Context, nh *Nexthop) error {
+	return h.withContext(ctx).NexthopReplace(nh)
+}
+
+// NexthopDel will delete the nexthop object with the ID of nh.
+// Equivalent to: `ip nexthop del id $id`
+func NexthopDel(nh *Nexthop) error {
+	return pkgHandle.NexthopDel(nh)
+}
+
+// NexthopDel will delete the nexthop object with the ID of nh.
+// Equivalent to: `ip nexthop del id $id`
+func (h *Handle) NexthopDel(nh *Nexthop) error {
+	if nh.ID == 0 {
+		return fmt.Errorf("nexthop ID must be set")
+	}
+	req := h.newNetlinkRequest(nl.RTM_DELNEXTHOP, unix.NLM_F_ACK)
+	req.AddData(nl.NewNhMsg(unix.AF_UNSPEC))
+	req.AddData(nl.NewRtAttr(nl.NHA_ID, nl.Uint32Attr(nh.ID)))
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// NexthopDelContext is like NexthopDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NexthopDelContext(ctx context.Context, nh *Nexthop) error {
+	return h.withContext(ctx).NexthopDel(nh)
+}
+
+func (h *Handle) nexthopHandle(nh *Nexthop, req *nl.NetlinkRequest) error {
+	msg := nl.NewNhMsg(nh.Family)
+	msg.Protocol = uint8(nh.Protocol)
+	msg.Flags = uint32(nh.Flags)
+	req.AddData(msg)
+
+	if nh.ID > 0 {
+		req.AddData(nl.NewRtAttr(nl.NHA_ID, nl.Uint32Attr(nh.ID)))
+	}
+
+	if len(nh.Group) > 0 {
+		if nh.Gw != nil || nh.LinkIndex > 0 || nh.Blackhole || nh.Encap != nil {
+			return fmt.Errorf("a nexthop group cannot have a gateway, device, encap or be a blackhole")
+		}
+		// groups are family independent
+		msg.Family = unix.AF_UNSPEC
+
+		buf := make([]byte, 0, len(nh.Group)*nl.SizeofNexthopGrp)
+		for _, e := range nh.Group {
+			grp := &nl.NexthopGrp{Id: e.ID}
+			if e.Weight > 1 {
+				if e.Weight > 256 {
+					return fmt.Errorf("invalid weight %d for nexthop %d", e.Weight, e.ID)
+				}
+				grp.Weight = uint8(e.Weight - 1)
+			}
+			buf = append(buf, grp.Serialize()...)
+		}
+		req.AddData(nl.NewRtAttr(nl.NHA_GROUP, buf))
+		req.AddData(nl.NewRtAttr(nl.NHA_GROUP_TYPE, nl.Uint16Attr(uint16(nh.GroupType))))
+
+		if nh.GroupType == NEXTHOP_GROUP_TYPE_RES && nh.Resilient != nil {
+			res := nl.NewRtAttr(nl.NHA_RES_GROUP|unix.NLA_F_NESTED, nil)
+			if nh.Resilient.Buckets > 0 {
+				nl.NewRtAttrChild(res, nl.NHA_RES_GROUP_BUCKETS, nl.Uint16Attr(nh.Resilient.Buckets))
+			}
+			if nh.Resilient.IdleTimer > 0 {
+				nl.NewRtAttrChild(res, nl.NHA_RES_GROUP_IDLE_TIMER, nl.Uint32Attr(durationToClock(nh.Resilient.IdleTimer)))
+			}
+			if nh.Resilient.UnbalancedTimer > 0 {
+				nl.NewRtAttrChild(res, nl.NHA_RES_GROUP_UNBALANCED_TIMER, nl.Uint32Attr(durationToClock(nh.Resilient.UnbalancedTimer)))
+			}
+			req.AddData(res)
+		}
+	} else if nh.Blackhole {
+		if nh.Gw != nil || nh.LinkIndex > 0 || nh.Encap != nil {
+			return fmt.Errorf("a blackhole nexthop cannot have a gateway, device or encap")
+		}
+		if msg.Family == unix.AF_UNSPEC {
+			msg.Family = FAMILY_V4
+		}
+		req.AddData(nl.NewRtAttr(nl.NHA_BLACKHOLE, nil))
+	} else {
+		if nh.Gw != nil {
+			gwFamily := nl.GetIPFamily(nh.Gw)
+			if nh.Family != FAMILY_ALL && nh.Family != gwFamily {
+				return fmt.Errorf("gateway and nexthop are not the same IP family")
+			}
+			msg.Family = uint8(gwFamily)
+			var gwData []byte
+			if gwFamily == FAMILY_V4 {
+				gwData = nh.Gw.To4()
+			} else {
+				gwData = nh.Gw.To16()
+			}
+			req.AddData(nl.NewRtAttr(nl.NHA_GATEWAY, gwData))
+		} else if msg.Family == unix.AF_UNSPEC && !nh.FDB {
+			msg.Family = FAMILY_V4
+		}
+		if nh.LinkIndex > 0 {
+			req.AddData(nl.NewRtAttr(nl.NHA_OIF, nl.Uint32Attr(uint32(nh.LinkIndex))))
+		}
+		if nh.Encap != nil {
+			req.AddData(nl.NewRtAttr(nl.NHA_ENCAP_TYPE, nl.Uint16Attr(uint16(nh.Encap.Type()))))
+			buf, err := nh.Encap.Encode()
+			if err != nil {
+				return err
+			}
+			req.AddData(nl.NewRtAttr(nl.NHA_ENCAP|unix.NLA_F_NESTED, buf))
+		}
+	}
+
+	if nh.FDB {
+		req.AddData(nl.NewRtAttr(nl.NHA_FDB, nil))
+	}
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// NexthopList gets a list of nexthop objects in the system.
+// Equivalent to: `ip nexthop show`.
+// The list can be filtered by ip family.
+func NexthopList(family int) ([]Nexthop, error) {
+	return pkgHandle.NexthopList(family)
+}
+
+// NexthopList gets a list of nexthop objects in the system.
+// Equivalent to: `ip nexthop show`.
+// The list can be filtered by ip family.
+func (h *Handle) NexthopList(family int) ([]Nexthop, error) {
+	req := h.newNetlinkRequest(nl.RTM_GETNEXTHOP, unix.NLM_F_DUMP)
+	req.AddData(nl.NewNhMsg(family))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, nl.RTM_NEWNEXTHOP)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	var res []Nexthop
+	for _, m := range msgs {
+		nh, err := deserializeNexthop(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, nh)
+	}
+	return res, executeErr
+}
+
+// NexthopListContext is like NexthopList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NexthopListContext(ctx context.Context, family int) ([]Nexthop, error) {
+	return h.withContext(ctx).NexthopList(family)
+}
+
+// deserializeNexthop decodes a binary netlink message into a Nexthop struct
+func deserializeNexthop(m []byte) (Nexthop, error) {
+	msg := nl.DeserializeNhMsg(m)
+	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+	if err != nil {
+		return Nexthop{}, err
+	}
+	nh := Nexthop{
+		Family:   int(msg.Family),
+		Protocol: int(msg.Protocol),
+		Scope:    Scope(msg.Scope),
+		Flags:    int(msg.Flags),
+	}
+
+	var encap, encapType syscall.NetlinkRouteAttr
+	for _, attr := range attrs {
+		// nested attributes carry NLA_F_NESTED
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NHA_ID:
+			nh.ID = native.Uint32(attr.Value[0:4])
+		case nl.NHA_OIF:
+			nh.LinkIndex = int(native.Uint32(attr.Value[0:4]))
+		case nl.NHA_GATEWAY:
+			nh.Gw = net.IP(attr.Value)
+		case nl.NHA_BLACKHOLE:
+			nh.Blackhole = true
+		case nl.NHA_FDB:
+			nh.FDB = true
+		case nl.NHA_GROUP:
+			for b := attr.Value; len(b) >= nl.SizeofNexthopGrp; b = b[nl.SizeofNexthopGrp:] {
+				grp := nl.DeserializeNexthopGrp(b)
+				nh.Group = append(nh.Group, NexthopGroupEntry{
+					ID:     grp.Id,
+					Weight: int(grp.Weight) + 1,
+				})
+			}
+		case nl.NHA_GROUP_TYPE:
+			nh.GroupType = NexthopGroupType(native.Uint16(attr.Value[0:2]))
+		case nl.NHA_RES_GROUP:
+			res, err := parseNexthopResilience(attr.Value)
+			if err != nil {
+				return nh, err
+			}
+			nh.Resilient = res
+		case nl.NHA_ENCAP_TYPE:
+			encapType = attr
+		case nl.NHA_ENCAP:
+			encap = attr
+		}
+	}
+
+	if len(encap.Value) != 0 && len(encapType.Value) != 0 {
+		e, err := decodeEncap(encapType, encap)
+		if err != nil {
+			return nh, err
+		}
+		nh.Encap = e
+	}
+
+	return nh, nil
+}
+
+func parseNexthopResilience(data []byte) (*NexthopResilience, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	res := &NexthopResilience{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.NHA_RES_GROUP_BUCKETS:
+			res.Buckets = native.Uint16(attr.Value[0:2])
+		case nl.NHA_RES_GROUP_IDLE_TIMER:
+			res.IdleTimer = clockToDuration(uint64(native.Uint32(attr.Value[0:4])))
+		case nl.NHA_RES_GROUP_UNBALANCED_TIMER:
+			res.UnbalancedTimer = clockToDuration(uint64(native.Uint32(attr.Value[0:4])))
+		case nl.NHA_RES_GROUP_UNBALANCED_TIME:
+			res.UnbalancedTime = clockToDuration(native.Uint64(attr.Value[0:8]))
+		}
+	}
+	return res, nil
+}
+
+func durationToClock(d time.Duration) uint32 {
+	return uint32(d * userHz / time.Second)
+}
+
+func clockToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / userHz
+}
+
+// NexthopSubscribe takes a chan down which notifications will be sent
+// when nexthop objects are added or deleted. Close the 'done' chan to stop
+// subscription.
+func NexthopSubscribe(ch chan<- NexthopUpdate, done <-chan struct{}) error {
+	return nexthopSubscribeAt(netns.None(), netns.None(), ch, done, nil, false)
+}
+
+// NexthopSubscribeAt works like NexthopSubscribe plus it allows the caller
+// to choose the network namespace in which to subscribe (ns).
+func NexthopSubscribeAt(ns netns.NsHandle, ch chan<- NexthopUpdate, done <-chan struct{}) error {
+	return nexthopSubscribeAt(ns, netns.None(), ch, done, nil, false)
+}
+
+// NexthopSubscribeOptions contains a set of options to use with
+// NexthopSubscribeWithOptions.
+type NexthopSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+	ListExisting  bool
+}
+
+// NexthopSubscribeWithOptions work like NexthopSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func NexthopSubscribeWithOptions(ch chan<- NexthopUpdate, done <-chan struct{}, options NexthopSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return nexthopSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting)
+}
+
+func nexthopSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NexthopUpdate, done <-chan struct{}, cberr func(error), listExisting bool) error {
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_ROUTE, nl.RTNLGRP_NEXTHOP)
+	if err != nil {
+		return err
+	}
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	if listExisting {
+		req := pkgHandle.newNetlinkRequest(nl.RTM_GETNEXTHOP,
+			unix.NLM_F_DUMP)
+		req.AddData(nl.NewNhMsg(unix.AF_UNSPEC))
+		if err := s.Send(req); err != nil {
+			return err
+		}
+	}
+	go func() {
+		defer close(ch)
+		for {
+			msgs, err := s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				return
+			}
+			for _, m := range msgs {
+				if m.Header.Type == unix.NLMSG_DONE {
+					continue
+				}
+				if m.Header.Type == unix.NLMSG_ERROR {
+					native := nl.NativeEndian()
+					error := int32(native.Uint32(m.Data[0:4]))
+					if error == 0 {
+						continue
+					}
+					if cberr != nil {
+						cberr(syscall.Errno(-error))
+					}
+					return
+				}
+				nh, err := deserializeNexthop(m.Data)
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					return
+				}
+				ch <- NexthopUpdate{Type: m.Header.Type, Nexthop: nh}
+			}
+		}
+	}()
+
+	return nil
+}
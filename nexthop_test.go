@@ -0,0 +1,219 @@
+// +build linux
+
+package netlink
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestNexthopAddDel(t *testing.T) {
+	minKernelRequired(t, 5, 3)
+	tearDown := setUpNetlinkTestWithLoopback(t)
+	defer tearDown()
+
+	link, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nh := Nexthop{ID: 10, LinkIndex: link.Attrs().Index, Gw: net.IPv4(127, 0, 0, 2)}
+	if err := NexthopAdd(&nh); err != nil {
+		t.Fatal(err)
+	}
+	if err := NexthopAdd(&Nexthop{ID: 20, Blackhole: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	nhs, err := NexthopList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nhs) != 2 {
+		t.Fatalf("Expected 2 nexthops, got %d", len(nhs))
+	}
+	for _, n := range nhs {
+		switch n.ID {
+		case 10:
+			if n.LinkIndex != link.Attrs().Index || !n.Gw.Equal(nh.Gw) || n.Family != FAMILY_V4 {
+				t.Fatalf("Nexthop not added properly: %s", n)
+			}
+		case 20:
+			if !n.Blackhole {
+				t.Fatalf("Blackhole nexthop not added properly: %s", n)
+			}
+		default:
+			t.Fatalf("Unexpected nexthop %s", n)
+		}
+	}
+
+	nh.Gw = net.IPv4(127, 0, 0, 3)
+	if err := NexthopReplace(&nh); err != nil {
+		t.Fatal(err)
+	}
+	nhs, err = NexthopList(FAMILY_V4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, n := range nhs {
+		if n.ID == 10 {
+			found = n.Gw.Equal(nh.Gw)
+		}
+	}
+	if !found {
+		t.Fatal("Nexthop not replaced properly")
+	}
+
+	if err := NexthopDel(&nh); err != nil {
+		t.Fatal(err)
+	}
+	if err := NexthopDel(&Nexthop{ID: 20}); err != nil {
+		t.Fatal(err)
+	}
+	nhs, err = NexthopList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nhs) != 0 {
+		t.Fatal("Nexthops not removed properly")
+	}
+}
+
+func TestNexthopGroup(t *testing.T) {
+	minKernelRequired(t, 5, 13)
+	tearDown := setUpNetlinkTestWithLoopback(t)
+	defer tearDown()
+
+	link, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, gw := range []net.IP{net.IPv4(127, 0, 0, 2), net.IPv4(127, 0, 0, 3)} {
+		nh := Nexthop{ID: uint32(i + 1), LinkIndex: link.Attrs().Index, Gw: gw}
+		if err := NexthopAdd(&nh); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mpath := Nexthop{
+		ID:    100,
+		Group: []NexthopGroupEntry{{ID: 1, Weight: 1}, {ID: 2, Weight: 3}},
+	}
+	if err := NexthopAdd(&mpath); err != nil {
+		t.Fatal(err)
+	}
+	res := Nexthop{
+		ID:        200,
+		Group:     []NexthopGroupEntry{{ID: 1, Weight: 1}, {ID: 2, Weight: 1}},
+		GroupType: NEXTHOP_GROUP_TYPE_RES,
+		Resilient: &NexthopResilience{
+			Buckets:   32,
+			IdleTimer: 10 * time.Second,
+		},
+	}
+	if err := NexthopAdd(&res); err != nil {
+		t.Fatal(err)
+	}
+
+	nhs, err := NexthopList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotMpath, gotRes bool
+	for _, n := range nhs {
+		switch n.ID {
+		case 100:
+			gotMpath = true
+			if n.GroupType != NEXTHOP_GROUP_TYPE_MPATH || len(n.Group) != 2 ||
+				n.Group[0] != mpath.Group[0] || n.Group[1] != mpath.Group[1] {
+				t.Fatalf("Multipath group not added properly: %s", n)
+			}
+		case 200:
+			gotRes = true
+			if n.GroupType != NEXTHOP_GROUP_TYPE_RES || n.Resilient == nil {
+				t.Fatalf("Resilient group not added properly: %s", n)
+			}
+			if n.Resilient.Buckets != 32 || n.Resilient.IdleTimer != 10*time.Second {
+				t.Fatalf("Resilient group parameters not set properly: %+v", *n.Resilient)
+			}
+		}
+	}
+	if !gotMpath || !gotRes {
+		t.Fatalf("Nexthop groups not found: %v", nhs)
+	}
+
+	// point a route at the group
+	dst := &net.IPNet{IP: net.IPv4(192, 168, 0, 0), Mask: net.CIDRMask(24, 32)}
+	route := Route{Dst: dst, NhID: 100}
+	if err := RouteAdd(&route); err != nil {
+		t.Fatal(err)
+	}
+	routes, err := RouteListFiltered(FAMILY_V4, &Route{Dst: dst}, RT_FILTER_DST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].NhID != 100 {
+		t.Fatalf("Route not added properly: %v", routes)
+	}
+	if err := RouteDel(&route); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNexthopSubscribe(t *testing.T) {
+	minKernelRequired(t, 5, 3)
+	tearDown := setUpNetlinkTestWithLoopback(t)
+	defer tearDown()
+
+	ch := make(chan NexthopUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	if err := NexthopSubscribe(ch, done); err != nil {
+		t.Fatal(err)
+	}
+
+	nh := Nexthop{ID: 7, Blackhole: true}
+	if err := NexthopAdd(&nh); err != nil {
+		t.Fatal(err)
+	}
+	if !expectNexthopUpdate(ch, nl.RTM_NEWNEXTHOP, nh.ID) {
+		t.Fatal("Add update not received as expected")
+	}
+	if err := NexthopDel(&nh); err != nil {
+		t.Fatal(err)
+	}
+	if !expectNexthopUpdate(ch, nl.RTM_DELNEXTHOP, nh.ID) {
+		t.Fatal("Del update not received as expected")
+	}
+}
+
+func expectNexthopUpdate(ch <-chan NexthopUpdate, t uint16, id uint32) bool {
+	for {
+		timeout := time.After(time.Minute)
+		select {
+		case update := <-ch:
+			if update.Type == t && update.ID == id {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func TestNexthopGroupValidation(t *testing.T) {
+	err := pkgHandle.nexthopHandle(&Nexthop{
+		ID:        1,
+		LinkIndex: 1,
+		Group:     []NexthopGroupEntry{{ID: 2}},
+	}, pkgHandle.newNetlinkRequest(nl.RTM_NEWNEXTHOP, unix.NLM_F_ACK))
+	if err == nil {
+		t.Fatal("Expected an error for a group with a device")
+	}
+}
@@ -0,0 +1,237 @@
+package netlink
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// NflogPacket is a packet logged to a group by the NFLOG target or the
+// nftables log statement
+type NflogPacket struct {
+	Group      uint16
+	Family     uint8
+	HwProtocol uint16
+	Hook       uint8
+	// Prefix is the prefix of the rule which logged the packet
+	Prefix     string
+	Mark       uint32
+	Timestamp  time.Time
+	InDev      int
+	OutDev     int
+	PhysInDev  int
+	PhysOutDev int
+	HwAddr     net.HardwareAddr
+	HwType     uint16
+	HwHeader   []byte
+	Payload    []byte
+	// Seq and SeqGlobal are only set with the Sequence options
+	Seq           *uint32
+	SeqGlobal     *uint32
+	Conntrack     *ConntrackFlow
+	ConntrackInfo *uint32
+	UID           *uint32
+	GID           *uint32
+}
+
+// NflogSubscribeOptions contains a set of options to use with
+// NflogSubscribe.
+type NflogSubscribeOptions struct {
+	Namespace *netns.NsHandle
+	// ErrorCallback is called with the error that stops the subscription.
+	// It is also called with unix.ENOBUFS, without stopping the
+	// subscription, when the socket overran and packets were lost.
+	ErrorCallback func(error)
+	CopyMode      NfCopyMode
+	// CopyRange is the maximal number of bytes of the packets copied with
+	// NF_COPY_PACKET, the whole packets when zero
+	CopyRange uint32
+	// The kernel batches the packets in one message until QueueThreshold
+	// packets or BufferSize bytes are logged or FlushTimeout elapsed, the
+	// kernel defaults are used when zero
+	QueueThreshold uint32
+	BufferSize     uint32
+	FlushTimeout   time.Duration
+	// Sequence and GlobalSequence number the packets of the group and of
+	// all the groups
+	Sequence       bool
+	GlobalSequence bool
+	// Conntrack adds the conntrack entry of the packets
+	Conntrack bool
+	// ReceiveBufferSize sets the socket receive buffer size, see
+	// ConntrackSubscribeOptions
+	ReceiveBufferSize      int
+	ReceiveBufferForceSize bool
+}
+
+// NflogSubscribe takes a chan down which the packets logged to group will
+// be sent. Close the 'done' chan to stop subscription.
+func NflogSubscribe(group uint16, ch chan<- NflogPacket, done <-chan struct{}, options NflogSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	s, err := nfnlSocketAt(*options.Namespace, netns.None(), options.ReceiveBufferSize, options.ReceiveBufferForceSize)
+	if err != nil {
+		return err
+	}
+
+	req := nl.NewNetlinkRequest(nl.NFNL_SUBSYS_ULOG<<8|nl.NFULNL_MSG_CONFIG, unix.NLM_F_ACK)
+	req.AddData(&nl.Nfgenmsg{
+		NfgenFamily: uint8(unix.AF_UNSPEC),
+		Version:     nl.NFNETLINK_V0,
+		ResId:       nl.Swap16(group),
+	})
+	req.AddData(nl.NewRtAttr(nl.NFULA_CFG_CMD, []byte{nl.NFULNL_CFG_CMD_BIND}))
+	mode := make([]byte, nl.SizeofNfulnlMsgConfigMode)
+	binary.BigEndian.PutUint32(mode, options.CopyRange)
+	mode[4] = uint8(options.CopyMode)
+	req.AddData(nl.NewRtAttr(nl.NFULA_CFG_MODE, mode))
+	if options.QueueThreshold != 0 {
+		req.AddData(nl.NewRtAttr(nl.NFULA_CFG_QTHRESH, htonl(options.QueueThreshold)))
+	}
+	if options.BufferSize != 0 {
+		req.AddData(nl.NewRtAttr(nl.NFULA_CFG_NLBUFSIZ, htonl(options.BufferSize)))
+	}
+	if options.FlushTimeout != 0 {
+		// The timeout is in hundredths of a second
+		req.AddData(nl.NewRtAttr(nl.NFULA_CFG_TIMEOUT, htonl(uint32(options.FlushTimeout/(10*time.Millisecond)))))
+	}
+	var flags uint16
+	if options.Sequence {
+		flags |= nl.NFULNL_CFG_F_SEQ
+	}
+	if options.GlobalSequence {
+		flags |= nl.NFULNL_CFG_F_SEQ_GLOBAL
+	}
+	if options.Conntrack {
+		flags |= nl.NFULNL_CFG_F_CONNTRACK
+	}
+	if flags != 0 {
+		req.AddData(nl.NewRtAttr(nl.NFULA_CFG_FLAGS, htons(flags)))
+	}
+	pending, err := nfnlExecute(s, req)
+	if err != nil {
+		s.Close()
+		return err
+	}
+
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	cberr := options.ErrorCallback
+	go func() {
+		defer close(ch)
+		msgs := pending
+		for {
+			for _, m := range msgs {
+				if m.Header.Type == unix.NLMSG_ERROR {
+					if err := nfnlError(m); err != nil {
+						if cberr != nil {
+							cberr(err)
+						}
+						return
+					}
+					continue
+				}
+				if m.Header.Type != nl.NFNL_SUBSYS_ULOG<<8|nl.NFULNL_MSG_PACKET {
+					continue
+				}
+				p, err := parseNflogPacket(m.Data)
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					return
+				}
+				ch <- *p
+			}
+			msgs, err = s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				if err == unix.ENOBUFS {
+					msgs = nil
+					continue
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func parseNflogPacket(data []byte) (*NflogPacket, error) {
+	if len(data) < nl.SizeofNfgenmsg {
+		return nil, fmt.Errorf("log message too short")
+	}
+	msg := nl.DeserializeNfgenmsg(data)
+	p := &NflogPacket{Family: msg.NfgenFamily, Group: nl.Swap16(msg.ResId)}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFULA_PACKET_HDR:
+			if len(attr.Value) < nl.SizeofNfulnlMsgPacketHdr {
+				return nil, fmt.Errorf("log packet header too short")
+			}
+			p.HwProtocol = binary.BigEndian.Uint16(attr.Value)
+			p.Hook = attr.Value[2]
+		case nl.NFULA_MARK:
+			p.Mark = ntohl(attr.Value)
+		case nl.NFULA_TIMESTAMP:
+			if p.Timestamp, err = parseNfTimestamp(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.NFULA_IFINDEX_INDEV:
+			p.InDev = int(ntohl(attr.Value))
+		case nl.NFULA_IFINDEX_OUTDEV:
+			p.OutDev = int(ntohl(attr.Value))
+		case nl.NFULA_IFINDEX_PHYSINDEV:
+			p.PhysInDev = int(ntohl(attr.Value))
+		case nl.NFULA_IFINDEX_PHYSOUTDEV:
+			p.PhysOutDev = int(ntohl(attr.Value))
+		case nl.NFULA_HWADDR:
+			if p.HwAddr, err = parseNfHwAddr(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.NFULA_HWTYPE:
+			p.HwType = ntohs(attr.Value)
+		case nl.NFULA_HWHEADER:
+			p.HwHeader = attr.Value
+		case nl.NFULA_PAYLOAD:
+			p.Payload = attr.Value
+		case nl.NFULA_PREFIX:
+			p.Prefix = nl.BytesToString(attr.Value)
+		case nl.NFULA_SEQ:
+			seq := ntohl(attr.Value)
+			p.Seq = &seq
+		case nl.NFULA_SEQ_GLOBAL:
+			seq := ntohl(attr.Value)
+			p.SeqGlobal = &seq
+		case nl.NFULA_CT:
+			p.Conntrack = parseNfConntrack(p.Family, attr.Value)
+		case nl.NFULA_CT_INFO:
+			info := ntohl(attr.Value)
+			p.ConntrackInfo = &info
+		case nl.NFULA_UID:
+			uid := ntohl(attr.Value)
+			p.UID = &uid
+		case nl.NFULA_GID:
+			gid := ntohl(attr.Value)
+			p.GID = &gid
+		}
+	}
+	return p, nil
+}
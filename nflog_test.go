@@ -0,0 +1,79 @@
+// +build linux
+
+package netlink
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// nftLogExpr logs the packets to group with prefix, using the raw
+// NFTA_LOG_GROUP and NFTA_LOG_PREFIX attributes of the log expression
+func nftLogExpr(group uint16, prefix string) *NftGenericExpr {
+	data := nl.NewRtAttr(1, htons(group)).Serialize()
+	data = append(data, nl.NewRtAttr(2, nl.ZeroTerminated(prefix)).Serialize()...)
+	return &NftGenericExpr{Name: "log", Data: data}
+}
+
+func TestNflogSubscribe(t *testing.T) {
+	tearDown := setUpNetlinkTestWithLoopback(t)
+	defer tearDown()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9998})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	addNftUDPRule(t, 9998, nftLogExpr(5, "test: "))
+
+	ch := make(chan NflogPacket)
+	done := make(chan struct{})
+	defer close(done)
+	// The conntrack entries need CONFIG_NETFILTER_NETLINK_GLUE_CT
+	options := NflogSubscribeOptions{
+		CopyMode:  NF_COPY_PACKET,
+		CopyRange: 64,
+		// Deliver each packet without waiting for more
+		QueueThreshold: 1,
+		Sequence:       true,
+		Conntrack:      true,
+		ErrorCallback: func(err error) {
+			t.Log(err)
+		},
+	}
+	err = NflogSubscribe(5, ch, done, options)
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		options.Conntrack = false
+		err = NflogSubscribe(5, ch, done, options)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint32(0); i < 2; i++ {
+		sendUDP(t, conn, "logged")
+		select {
+		case p := <-ch:
+			if p.Group != 5 || p.Prefix != "test: " || p.Hook != 3 {
+				t.Fatalf("Unexpected packet %+v", p)
+			}
+			if !bytes.HasSuffix(p.Payload, []byte("logged")) {
+				t.Fatalf("Unexpected payload %x", p.Payload)
+			}
+			if p.Seq == nil || *p.Seq != i {
+				t.Fatalf("Unexpected sequence number %v", p.Seq)
+			}
+			if options.Conntrack && (p.Conntrack == nil || p.Conntrack.Forward.DstPort != 9998) {
+				t.Fatalf("Unexpected conntrack entry %+v", p.Conntrack)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("No packet logged")
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package netlink
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// NfCopyMode selects how much of the packets is copied to userspace by the
+// queue and log subsystems
+type NfCopyMode uint8
+
+const (
+	// NF_COPY_NONE copies nothing, the kernel default
+	NF_COPY_NONE NfCopyMode = nl.NFQNL_COPY_NONE
+	// NF_COPY_META copies the metadata of the packets only
+	NF_COPY_META NfCopyMode = nl.NFQNL_COPY_META
+	// NF_COPY_PACKET copies the metadata and up to copy range bytes of
+	// the packets
+	NF_COPY_PACKET NfCopyMode = nl.NFQNL_COPY_PACKET
+)
+
+// NfVerdict is the verdict on a queued packet
+type NfVerdict uint32
+
+const (
+	NF_DROP   NfVerdict = 0
+	NF_ACCEPT NfVerdict = 1
+	NF_STOLEN NfVerdict = 2
+	NF_QUEUE  NfVerdict = 3
+	NF_REPEAT NfVerdict = 4
+	NF_STOP   NfVerdict = 5
+)
+
+// NfVerdictQueue returns the verdict sending a packet to another queue
+func NfVerdictQueue(num uint16) NfVerdict {
+	return NF_QUEUE | NfVerdict(num)<<16
+}
+
+func (v NfVerdict) String() string {
+	switch v & 0xffff {
+	case NF_DROP:
+		return "drop"
+	case NF_ACCEPT:
+		return "accept"
+	case NF_STOLEN:
+		return "stolen"
+	case NF_QUEUE:
+		return fmt.Sprintf("queue %d", v>>16)
+	case NF_REPEAT:
+		return "repeat"
+	case NF_STOP:
+		return "stop"
+	}
+	return fmt.Sprintf("verdict(%d)", uint32(v))
+}
+
+// NfqueueOptions contains the configuration of a queue opened with
+// NfqueueOpen.
+type NfqueueOptions struct {
+	Namespace *netns.NsHandle
+	CopyMode  NfCopyMode
+	// CopyRange is the maximal number of bytes of the packets copied with
+	// NF_COPY_PACKET, the whole packets when zero
+	CopyRange uint32
+	// MaxQueueLen is the number of packets waiting for a verdict after
+	// which the kernel drops the new ones, the kernel default when zero
+	MaxQueueLen uint32
+	// FailOpen accepts the packets instead of dropping them once the
+	// queue is full
+	FailOpen bool
+	// Conntrack adds the conntrack entry of the packets
+	Conntrack bool
+	// GSO receives the GSO packets without segmenting them
+	GSO bool
+	// UIDGID adds the owner of the socket of the packets
+	UIDGID bool
+	// ReceiveBufferSize sets the socket receive buffer size, see
+	// ConntrackSubscribeOptions
+	ReceiveBufferSize      int
+	ReceiveBufferForceSize bool
+}
+
+// NfqueuePacket is a packet waiting for a verdict
+type NfqueuePacket struct {
+	// ID identifies the packet in the verdict
+	ID         uint32
+	Family     uint8
+	HwProtocol uint16
+	Hook       uint8
+	Mark       uint32
+	// Timestamp is the time the packet was received, zero when unknown
+	Timestamp  time.Time
+	InDev      int
+	OutDev     int
+	PhysInDev  int
+	PhysOutDev int
+	HwAddr     net.HardwareAddr
+	Payload    []byte
+	// CapLen is the length of the packet when Payload was truncated
+	CapLen uint32
+	// SkbInfo is a mask of the nl.NFQA_SKB_* flags
+	SkbInfo       uint32
+	Conntrack     *ConntrackFlow
+	ConntrackInfo *uint32
+	UID           *uint32
+	GID           *uint32
+}
+
+// NfqueueVerdictOptions changes a packet along with its verdict
+type NfqueueVerdictOptions struct {
+	Mark *uint32
+	// Payload replaces the packet
+	Payload []byte
+}
+
+// Nfqueue is a queue bound to a socket, the packets sent to the queue by
+// the NFQUEUE target or the nftables queue statement are received on it and
+// wait for a verdict.
+type Nfqueue struct {
+	num uint16
+	s   *nl.NetlinkSocket
+	// pending holds the messages received while waiting for an ack
+	pending []syscall.NetlinkMessage
+}
+
+// NfqueueOpen binds the queue num and configures it
+func NfqueueOpen(num uint16, options NfqueueOptions) (*Nfqueue, error) {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	s, err := nfnlSocketAt(*options.Namespace, netns.None(), options.ReceiveBufferSize, options.ReceiveBufferForceSize)
+	if err != nil {
+		return nil, err
+	}
+	q := &Nfqueue{num: num, s: s}
+
+	req := q.newRequest(nl.NFQNL_MSG_CONFIG, unix.NLM_F_ACK)
+	req.AddData(nl.NewRtAttr(nl.NFQA_CFG_CMD, []byte{nl.NFQNL_CFG_CMD_BIND, 0, 0, 0}))
+	params := make([]byte, nl.SizeofNfqnlMsgConfigParams)
+	binary.BigEndian.PutUint32(params, options.CopyRange)
+	params[4] = uint8(options.CopyMode)
+	req.AddData(nl.NewRtAttr(nl.NFQA_CFG_PARAMS, params))
+	if options.MaxQueueLen != 0 {
+		req.AddData(nl.NewRtAttr(nl.NFQA_CFG_QUEUE_MAXLEN, htonl(options.MaxQueueLen)))
+	}
+	var flags uint32
+	if options.FailOpen {
+		flags |= nl.NFQA_CFG_F_FAIL_OPEN
+	}
+	if options.Conntrack {
+		flags |= nl.NFQA_CFG_F_CONNTRACK
+	}
+	if options.GSO {
+		flags |= nl.NFQA_CFG_F_GSO
+	}
+	if options.UIDGID {
+		flags |= nl.NFQA_CFG_F_UID_GID
+	}
+	if flags != 0 {
+		req.AddData(nl.NewRtAttr(nl.NFQA_CFG_MASK, htonl(flags)))
+		req.AddData(nl.NewRtAttr(nl.NFQA_CFG_FLAGS, htonl(flags)))
+	}
+	pending, err := nfnlExecute(s, req)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	q.pending = pending
+	return q, nil
+}
+
+// Close unbinds the queue, the packets still waiting for a verdict are
+// dropped, or accepted with FailOpen
+func (q *Nfqueue) Close() {
+	req := q.newRequest(nl.NFQNL_MSG_CONFIG, 0)
+	req.AddData(nl.NewRtAttr(nl.NFQA_CFG_CMD, []byte{nl.NFQNL_CFG_CMD_UNBIND, 0, 0, 0}))
+	// Closing the socket releases the queue anyway
+	_ = q.s.Send(req)
+	q.s.Close()
+}
+
+// Receive waits for packets of the queue, a verdict must be set on each of
+// them. unix.ENOBUFS is returned when packets were lost because the socket
+// overran, which does not prevent receiving the next ones.
+func (q *Nfqueue) Receive() ([]*NfqueuePacket, error) {
+	return q.ReceiveContext(context.Background())
+}
+
+// ReceiveContext is like Receive but gives up and returns ctx.Err()
+// once ctx is done.
+func (q *Nfqueue) ReceiveContext(ctx context.Context) ([]*NfqueuePacket, error) {
+	for {
+		msgs := q.pending
+		q.pending = nil
+		if len(msgs) == 0 {
+			var err error
+			if msgs, err = q.s.ReceiveContext(ctx); err != nil {
+				return nil, err
+			}
+		}
+		var packets []*NfqueuePacket
+		for _, m := range msgs {
+			if m.Header.Type == unix.NLMSG_ERROR {
+				// The verdicts are not acked, but their errors are
+				// still reported
+				if err := nfnlError(m); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if m.Header.Type != nl.NFNL_SUBSYS_QUEUE<<8|nl.NFQNL_MSG_PACKET {
+				continue
+			}
+			p, err := parseNfqueuePacket(m.Data)
+			if err != nil {
+				return nil, err
+			}
+			packets = append(packets, p)
+		}
+		if len(packets) > 0 {
+			return packets, nil
+		}
+	}
+}
+
+// SetVerdict sets the verdict on the packet id
+func (q *Nfqueue) SetVerdict(id uint32, verdict NfVerdict) error {
+	return q.SetVerdictWithOptions(id, verdict, NfqueueVerdictOptions{})
+}
+
+// SetVerdictWithOptions sets the verdict on the packet id and changes its
+// mark or content
+func (q *Nfqueue) SetVerdictWithOptions(id uint32, verdict NfVerdict, options NfqueueVerdictOptions) error {
+	req := q.newRequest(nl.NFQNL_MSG_VERDICT, 0)
+	req.AddData(nl.NewRtAttr(nl.NFQA_VERDICT_HDR, nfqueueVerdictHdr(id, verdict)))
+	if options.Mark != nil {
+		req.AddData(nl.NewRtAttr(nl.NFQA_MARK, htonl(*options.Mark)))
+	}
+	if options.Payload != nil {
+		req.AddData(nl.NewRtAttr(nl.NFQA_PAYLOAD, options.Payload))
+	}
+	return q.s.Send(req)
+}
+
+// SetVerdictBatch sets the verdict on all the packets waiting in the queue
+// up to the packet id included
+func (q *Nfqueue) SetVerdictBatch(id uint32, verdict NfVerdict) error {
+	req := q.newRequest(nl.NFQNL_MSG_VERDICT_BATCH, 0)
+	req.AddData(nl.NewRtAttr(nl.NFQA_VERDICT_HDR, nfqueueVerdictHdr(id, verdict)))
+	return q.s.Send(req)
+}
+
+func (q *Nfqueue) newRequest(msgType, flags int) *nl.NetlinkRequest {
+	req := nl.NewNetlinkRequest(nl.NFNL_SUBSYS_QUEUE<<8|msgType, flags)
+	req.AddData(&nl.Nfgenmsg{
+		NfgenFamily: uint8(unix.AF_UNSPEC),
+		Version:     nl.NFNETLINK_V0,
+		ResId:       nl.Swap16(q.num),
+	})
+	return req
+}
+
+func nfqueueVerdictHdr(id uint32, verdict NfVerdict) []byte {
+	b := make([]byte, nl.SizeofNfqnlMsgVerdictHdr)
+	binary.BigEndian.PutUint32(b, uint32(verdict))
+	binary.BigEndian.PutUint32(b[4:], id)
+	return b
+}
+
+// nfnlSocketAt opens a netfilter socket in newNs to bind a queue or a log
+// group to
+func nfnlSocketAt(newNs, curNs netns.NsHandle, rcvbuf int, force bool) (*nl.NetlinkSocket, error) {
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_NETFILTER)
+	if err != nil {
+		return nil, err
+	}
+	if rcvbuf > 0 {
+		opt := unix.SO_RCVBUF
+		if force {
+			opt = unix.SO_RCVBUFFORCE
+		}
+		if err := unix.SetsockoptInt(s.GetFd(), unix.SOL_SOCKET, opt, rcvbuf); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// nfnlExecute sends req on s and waits for its ack. The packets received
+// meanwhile are returned so that they are not lost.
+func nfnlExecute(s *nl.NetlinkSocket, req *nl.NetlinkRequest) ([]syscall.NetlinkMessage, error) {
+	if err := s.Send(req); err != nil {
+		return nil, err
+	}
+	var pending []syscall.NetlinkMessage
+	for {
+		msgs, err := s.Receive()
+		if err != nil {
+			return nil, err
+		}
+		for i, m := range msgs {
+			if m.Header.Type == unix.NLMSG_ERROR && m.Header.Seq == req.Seq {
+				if err := nfnlError(m); err != nil {
+					return nil, err
+				}
+				return append(pending, msgs[i+1:]...), nil
+			}
+			pending = append(pending, m)
+		}
+	}
+}
+
+// nfnlError returns the error of an NLMSG_ERROR message, nil for an ack
+func nfnlError(m syscall.NetlinkMessage) error {
+	if len(m.Data) < 4 {
+		return fmt.Errorf("netlink error message too short")
+	}
+	errno := int32(nl.NativeEndian().Uint32(m.Data[0:4]))
+	if errno == 0 {
+		return nil
+	}
+	return syscall.Errno(-errno)
+}
+
+func parseNfqueuePacket(data []byte) (*NfqueuePacket, error) {
+	if len(data) < nl.SizeofNfgenmsg {
+		return nil, fmt.Errorf("queue message too short")
+	}
+	p := &NfqueuePacket{Family: data[0]}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFQA_PACKET_HDR:
+			if len(attr.Value) < nl.SizeofNfqnlMsgPacketHdr {
+				return nil, fmt.Errorf("queue packet header too short")
+			}
+			p.ID = binary.BigEndian.Uint32(attr.Value)
+			p.HwProtocol = binary.BigEndian.Uint16(attr.Value[4:])
+			p.Hook = attr.Value[6]
+		case nl.NFQA_MARK:
+			p.Mark = ntohl(attr.Value)
+		case nl.NFQA_TIMESTAMP:
+			if p.Timestamp, err = parseNfTimestamp(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.NFQA_IFINDEX_INDEV:
+			p.InDev = int(ntohl(attr.Value))
+		case nl.NFQA_IFINDEX_OUTDEV:
+			p.OutDev = int(ntohl(attr.Value))
+		case nl.NFQA_IFINDEX_PHYSINDEV:
+			p.PhysInDev = int(ntohl(attr.Value))
+		case nl.NFQA_IFINDEX_PHYSOUTDEV:
+			p.PhysOutDev = int(ntohl(attr.Value))
+		case nl.NFQA_HWADDR:
+			if p.HwAddr, err = parseNfHwAddr(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.NFQA_PAYLOAD:
+			p.Payload = attr.Value
+		case nl.NFQA_CT:
+			p.Conntrack = parseNfConntrack(p.Family, attr.Value)
+		case nl.NFQA_CT_INFO:
+			info := ntohl(attr.Value)
+			p.ConntrackInfo = &info
+		case nl.NFQA_CAP_LEN:
+			p.CapLen = ntohl(attr.Value)
+		case nl.NFQA_SKB_INFO:
+			p.SkbInfo = ntohl(attr.Value)
+		case nl.NFQA_UID:
+			uid := ntohl(attr.Value)
+			p.UID = &uid
+		case nl.NFQA_GID:
+			gid := ntohl(attr.Value)
+			p.GID = &gid
+		}
+	}
+	return p, nil
+}
+
+// parseNfTimestamp parses the timestamp of a queue or log packet
+func parseNfTimestamp(b []byte) (time.Time, error) {
+	if len(b) < nl.SizeofNfqnlMsgPacketTimestamp {
+		return time.Time{}, fmt.Errorf("packet timestamp too short")
+	}
+	sec := binary.BigEndian.Uint64(b)
+	usec := binary.BigEndian.Uint64(b[8:])
+	return time.Unix(int64(sec), int64(usec)*int64(time.Microsecond)), nil
+}
+
+// parseNfHwAddr parses the hardware address of a queue or log packet
+func parseNfHwAddr(b []byte) (net.HardwareAddr, error) {
+	if len(b) < nl.SizeofNfqnlMsgPacketHw {
+		return nil, fmt.Errorf("packet hardware address too short")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if n > len(b)-4 {
+		n = len(b) - 4
+	}
+	return net.HardwareAddr(append([]byte(nil), b[4:4+n]...)), nil
+}
+
+// parseNfConntrack parses the conntrack entry of a queue or log packet,
+// whose attributes are those of a conntrack message
+func parseNfConntrack(family uint8, b []byte) *ConntrackFlow {
+	data := make([]byte, nl.SizeofNfgenmsg, nl.SizeofNfgenmsg+len(b))
+	data[0] = family
+	return parseRawData(append(data, b...))
+}
@@ -0,0 +1,174 @@
+// +build linux
+
+package netlink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// addNftUDPRule adds a rule matching the UDP packets sent to port in the
+// output hook of a new ip table
+func addNftUDPRule(t *testing.T, port uint16, exprs ...NftExpr) {
+	t.Helper()
+	b := NewNftBatch()
+	b.AddTable(&NftTable{Family: NFT_FAMILY_IPV4, Name: "test"})
+	b.AddChain(&NftChain{
+		Family: NFT_FAMILY_IPV4,
+		Table:  "test",
+		Name:   "output",
+		Hook:   &NftChainHook{Num: NFT_HOOK_OUTPUT, Priority: NFT_PRIORITY_FILTER},
+	})
+	b.AddRule(&NftRule{
+		Family: NFT_FAMILY_IPV4,
+		Table:  "test",
+		Chain:  "output",
+		Exprs: append([]NftExpr{
+			&NftMetaExpr{Key: NFT_META_L4PROTO, DestRegister: NFT_REG_1},
+			&NftCmpExpr{Op: NFT_CMP_EQ, SourceRegister: NFT_REG_1, Data: []byte{unix.IPPROTO_UDP}},
+			&NftPayloadExpr{Base: NFT_PAYLOAD_TRANSPORT_HEADER, Offset: 2, Len: 2, DestRegister: NFT_REG_1},
+			&NftCmpExpr{Op: NFT_CMP_EQ, SourceRegister: NFT_REG_1, Data: htons(port)},
+		}, exprs...),
+	})
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// nftQueueExpr sends the packets to the queue num with the NFQUEUE xtables
+// target, using the raw NFTA_TARGET_NAME, NFTA_TARGET_REV and
+// NFTA_TARGET_INFO attributes of the compat target expression
+func nftQueueExpr(num uint16) *NftGenericExpr {
+	info := make([]byte, 8)
+	nl.NativeEndian().PutUint16(info, num)
+	data := nl.NewRtAttr(1, nl.ZeroTerminated("NFQUEUE")).Serialize()
+	data = append(data, nl.NewRtAttr(2, htonl(0)).Serialize()...)
+	data = append(data, nl.NewRtAttr(3, info).Serialize()...)
+	return &NftGenericExpr{Name: "target", Data: data}
+}
+
+// sendUDP sends payload to the UDP socket conn
+func sendUDP(t *testing.T, conn *net.UDPConn, payload string) {
+	t.Helper()
+	c, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.Write([]byte(payload)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNfqueue(t *testing.T) {
+	tearDown := setUpNetlinkTestWithLoopback(t)
+	defer tearDown()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9999})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	addNftUDPRule(t, 9999, nftQueueExpr(0))
+
+	// The conntrack entries need CONFIG_NETFILTER_NETLINK_GLUE_CT
+	options := NfqueueOptions{CopyMode: NF_COPY_PACKET, Conntrack: true, FailOpen: true}
+	q, err := NfqueueOpen(0, options)
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		options.Conntrack = false
+		q, err = NfqueueOpen(0, options)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	if _, err := NfqueueOpen(0, NfqueueOptions{}); err == nil {
+		t.Fatal("Binding a queue bound to another socket should fail")
+	}
+
+	receive := func() *NfqueuePacket {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		packets, err := q.ReceiveContext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(packets) != 1 {
+			t.Fatalf("Unexpected packets %+v", packets)
+		}
+		return packets[0]
+	}
+
+	lo, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendUDP(t, conn, "hello")
+	p := receive()
+	if p.Family != unix.AF_INET || p.HwProtocol != unix.ETH_P_IP || p.Hook != 3 || p.OutDev != lo.Attrs().Index {
+		t.Fatalf("Unexpected packet %+v", p)
+	}
+	if !bytes.HasSuffix(p.Payload, []byte("hello")) {
+		t.Fatalf("Unexpected payload %x", p.Payload)
+	}
+	if options.Conntrack && (p.Conntrack == nil || p.Conntrack.Forward.DstPort != 9999 || p.ConntrackInfo == nil) {
+		t.Fatalf("Unexpected conntrack entry %+v", p.Conntrack)
+	}
+
+	// Accept the packet with another payload, the UDP checksum is cleared
+	// as it is not updated by the kernel
+	payload := append([]byte(nil), p.Payload...)
+	copy(payload[len(payload)-5:], "HELLO")
+	ihl := int(payload[0]&0xf) * 4
+	payload[ihl+6], payload[ihl+7] = 0, 0
+	mark := uint32(42)
+	if err := q.SetVerdictWithOptions(p.ID, NF_ACCEPT, NfqueueVerdictOptions{Mark: &mark, Payload: payload}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "HELLO" {
+		t.Fatalf("Unexpected data received %q", buf[:n])
+	}
+
+	sendUDP(t, conn, "dropped")
+	p = receive()
+	if err := q.SetVerdict(p.ID, NF_DROP); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if n, err := conn.Read(buf); err == nil {
+		t.Fatalf("Dropped packet received %q", buf[:n])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.ReceiveContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNfVerdictString(t *testing.T) {
+	for verdict, want := range map[NfVerdict]string{
+		NF_ACCEPT:          "accept",
+		NF_DROP:            "drop",
+		NfVerdictQueue(0):  "queue 0",
+		NfVerdictQueue(12): "queue 12",
+		NfVerdict(7):       "verdict(7)",
+	} {
+		if got := verdict.String(); got != want {
+			t.Errorf("Verdict %#x is %q, want %q", uint32(verdict), got, want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package netlink
+
+// NfqueueOptions placeholder
+type NfqueueOptions struct{}
+
+// Nfqueue placeholder
+type Nfqueue struct{}
+
+// NfqueueOpen binds the queue num and configures it
+func NfqueueOpen(num uint16, options NfqueueOptions) (*Nfqueue, error) {
+	return nil, ErrNotImplemented
+}
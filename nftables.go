@@ -0,0 +1,538 @@
+package netlink
+
+import (
+	"fmt"
+)
+
+// NftFamily is the family of a nftables table, it selects the netfilter
+// hooks the chains of the table can be attached to.
+type NftFamily uint8
+
+const (
+	NFT_FAMILY_ALL    NftFamily = 0
+	NFT_FAMILY_INET   NftFamily = 1
+	NFT_FAMILY_IPV4   NftFamily = 2
+	NFT_FAMILY_ARP    NftFamily = 3
+	NFT_FAMILY_NETDEV NftFamily = 5
+	NFT_FAMILY_BRIDGE NftFamily = 7
+	NFT_FAMILY_IPV6   NftFamily = 10
+)
+
+func (f NftFamily) String() string {
+	switch f {
+	case NFT_FAMILY_ALL:
+		return "all"
+	case NFT_FAMILY_INET:
+		return "inet"
+	case NFT_FAMILY_IPV4:
+		return "ip"
+	case NFT_FAMILY_ARP:
+		return "arp"
+	case NFT_FAMILY_NETDEV:
+		return "netdev"
+	case NFT_FAMILY_BRIDGE:
+		return "bridge"
+	case NFT_FAMILY_IPV6:
+		return "ip6"
+	}
+	return fmt.Sprintf("family(%d)", uint8(f))
+}
+
+const (
+	NFT_TABLE_F_DORMANT = 0x1
+)
+
+// NftTable is a nftables table, the container of chains and sets
+type NftTable struct {
+	Family NftFamily
+	Name   string
+	// Flags is a mask of the NFT_TABLE_F_* flags
+	Flags  uint32
+	Handle uint64
+	// Use is the number of chains and sets of the table, it is read only
+	Use uint32
+}
+
+func (t NftTable) String() string {
+	return fmt.Sprintf("table %s %s", t.Family, t.Name)
+}
+
+// NftHook is the netfilter hook of a base chain
+type NftHook uint32
+
+const (
+	NFT_HOOK_PREROUTING  NftHook = 0
+	NFT_HOOK_INPUT       NftHook = 1
+	NFT_HOOK_FORWARD     NftHook = 2
+	NFT_HOOK_OUTPUT      NftHook = 3
+	NFT_HOOK_POSTROUTING NftHook = 4
+	// Hooks of the netdev family
+	NFT_HOOK_INGRESS NftHook = 0
+	NFT_HOOK_EGRESS  NftHook = 1
+)
+
+// Standard priorities of the base chains, lower values run first
+const (
+	NFT_PRIORITY_RAW      = -300
+	NFT_PRIORITY_MANGLE   = -150
+	NFT_PRIORITY_DSTNAT   = -100
+	NFT_PRIORITY_FILTER   = 0
+	NFT_PRIORITY_SECURITY = 50
+	NFT_PRIORITY_SRCNAT   = 100
+)
+
+// NftChainType is the type of a base chain
+type NftChainType string
+
+const (
+	NFT_CHAIN_TYPE_FILTER NftChainType = "filter"
+	NFT_CHAIN_TYPE_NAT    NftChainType = "nat"
+	NFT_CHAIN_TYPE_ROUTE  NftChainType = "route"
+)
+
+// NftChainHook attaches a base chain to a netfilter hook
+type NftChainHook struct {
+	Num      NftHook
+	Priority int32
+	// Device is the interface of the ingress and egress hooks of the
+	// netdev family
+	Device string
+}
+
+// NftChain is a nftables chain. A chain with a Hook is a base chain, which
+// sees the packets of its hook, other chains are only reached by jumps.
+type NftChain struct {
+	Family NftFamily
+	Table  string
+	Name   string
+	Handle uint64
+	Hook   *NftChainHook
+	// Type of a base chain, defaults to NFT_CHAIN_TYPE_FILTER
+	Type NftChainType
+	// Policy of a base chain, NFT_ACCEPT or NFT_DROP
+	Policy *NftVerdictCode
+	// Use is the number of references to the chain, it is read only
+	Use uint32
+}
+
+func (c NftChain) String() string {
+	res := fmt.Sprintf("chain %s %s %s", c.Family, c.Table, c.Name)
+	if c.Hook != nil {
+		res += fmt.Sprintf(" type %s hook %d priority %d", c.Type, c.Hook.Num, c.Hook.Priority)
+		if c.Hook.Device != "" {
+			res += " device " + c.Hook.Device
+		}
+	}
+	if c.Policy != nil {
+		res += " policy " + c.Policy.String()
+	}
+	return res
+}
+
+// NftRule is a nftables rule, its expressions are evaluated in order until
+// one of them breaks the evaluation or issues a verdict.
+type NftRule struct {
+	Family NftFamily
+	Table  string
+	Chain  string
+	Handle uint64
+	// Position is the handle of the rule after which the rule is added,
+	// or before which it is inserted. Zero stands for the end or the
+	// start of the chain.
+	Position uint64
+	Exprs    []NftExpr
+	UserData []byte
+}
+
+// NftExpr is an expression of a nftables rule. The expressions of a rule
+// exchange data through registers, the NFT_REG_* values.
+type NftExpr interface {
+	Type() string
+}
+
+// Registers of the expressions. NFT_REG_VERDICT holds the verdict of the
+// rule, the others hold data, either as 16 bytes or as 4 bytes registers.
+// The kernel reports the 4 bytes registers starting a 16 bytes register,
+// like NFT_REG32_00, as the latter.
+const (
+	NFT_REG_VERDICT = 0
+	NFT_REG_1       = 1
+	NFT_REG_2       = 2
+	NFT_REG_3       = 3
+	NFT_REG_4       = 4
+	NFT_REG32_00    = 8
+	NFT_REG32_01    = 9
+	NFT_REG32_02    = 10
+	NFT_REG32_03    = 11
+	NFT_REG32_04    = 12
+	NFT_REG32_05    = 13
+	NFT_REG32_06    = 14
+	NFT_REG32_07    = 15
+	NFT_REG32_08    = 16
+	NFT_REG32_09    = 17
+	NFT_REG32_10    = 18
+	NFT_REG32_11    = 19
+	NFT_REG32_12    = 20
+	NFT_REG32_13    = 21
+	NFT_REG32_14    = 22
+	NFT_REG32_15    = 23
+)
+
+// NftVerdictCode is the verdict of a rule, NFT_JUMP and NFT_GOTO continue in
+// another chain.
+type NftVerdictCode int32
+
+const (
+	NFT_DROP     NftVerdictCode = 0
+	NFT_ACCEPT   NftVerdictCode = 1
+	NFT_CONTINUE NftVerdictCode = -1
+	NFT_BREAK    NftVerdictCode = -2
+	NFT_JUMP     NftVerdictCode = -3
+	NFT_GOTO     NftVerdictCode = -4
+	NFT_RETURN   NftVerdictCode = -5
+)
+
+func (v NftVerdictCode) String() string {
+	switch v {
+	case NFT_DROP:
+		return "drop"
+	case NFT_ACCEPT:
+		return "accept"
+	case NFT_CONTINUE:
+		return "continue"
+	case NFT_BREAK:
+		return "break"
+	case NFT_JUMP:
+		return "jump"
+	case NFT_GOTO:
+		return "goto"
+	case NFT_RETURN:
+		return "return"
+	}
+	return fmt.Sprintf("verdict(%d)", int32(v))
+}
+
+// NftVerdict is a verdict, Chain is the target of NFT_JUMP and NFT_GOTO
+type NftVerdict struct {
+	Code  NftVerdictCode
+	Chain string
+}
+
+// NftPayloadBase is the header the offset of a payload expression is
+// relative to
+type NftPayloadBase uint32
+
+const (
+	NFT_PAYLOAD_LL_HEADER        NftPayloadBase = 0
+	NFT_PAYLOAD_NETWORK_HEADER   NftPayloadBase = 1
+	NFT_PAYLOAD_TRANSPORT_HEADER NftPayloadBase = 2
+)
+
+// NftPayloadExpr loads Len bytes of the packet at Offset of Base into
+// DestRegister
+type NftPayloadExpr struct {
+	Base         NftPayloadBase
+	Offset       uint32
+	Len          uint32
+	DestRegister uint32
+}
+
+func (e *NftPayloadExpr) Type() string {
+	return "payload"
+}
+
+// NftCmpOp is the operator of a cmp expression
+type NftCmpOp uint32
+
+const (
+	NFT_CMP_EQ  NftCmpOp = 0
+	NFT_CMP_NEQ NftCmpOp = 1
+	NFT_CMP_LT  NftCmpOp = 2
+	NFT_CMP_LTE NftCmpOp = 3
+	NFT_CMP_GT  NftCmpOp = 4
+	NFT_CMP_GTE NftCmpOp = 5
+)
+
+// NftCmpExpr compares SourceRegister with Data and breaks the evaluation of
+// the rule when the comparison fails
+type NftCmpExpr struct {
+	Op             NftCmpOp
+	SourceRegister uint32
+	Data           []byte
+}
+
+func (e *NftCmpExpr) Type() string {
+	return "cmp"
+}
+
+// NftMetaKey selects the packet metadata of a meta expression
+type NftMetaKey uint32
+
+const (
+	NFT_META_LEN         NftMetaKey = 0
+	NFT_META_PROTOCOL    NftMetaKey = 1
+	NFT_META_PRIORITY    NftMetaKey = 2
+	NFT_META_MARK        NftMetaKey = 3
+	NFT_META_IIF         NftMetaKey = 4
+	NFT_META_OIF         NftMetaKey = 5
+	NFT_META_IIFNAME     NftMetaKey = 6
+	NFT_META_OIFNAME     NftMetaKey = 7
+	NFT_META_IIFTYPE     NftMetaKey = 8
+	NFT_META_OIFTYPE     NftMetaKey = 9
+	NFT_META_SKUID       NftMetaKey = 10
+	NFT_META_SKGID       NftMetaKey = 11
+	NFT_META_NFTRACE     NftMetaKey = 12
+	NFT_META_RTCLASSID   NftMetaKey = 13
+	NFT_META_SECMARK     NftMetaKey = 14
+	NFT_META_NFPROTO     NftMetaKey = 15
+	NFT_META_L4PROTO     NftMetaKey = 16
+	NFT_META_BRI_IIFNAME NftMetaKey = 17
+	NFT_META_BRI_OIFNAME NftMetaKey = 18
+	NFT_META_PKTTYPE     NftMetaKey = 19
+	NFT_META_CPU         NftMetaKey = 20
+	NFT_META_IIFGROUP    NftMetaKey = 21
+	NFT_META_OIFGROUP    NftMetaKey = 22
+	NFT_META_CGROUP      NftMetaKey = 23
+	NFT_META_PRANDOM     NftMetaKey = 24
+)
+
+// NftMetaExpr loads the Key metadata of the packet into DestRegister, or
+// sets it from SourceRegister when SourceRegister is not zero
+type NftMetaExpr struct {
+	Key            NftMetaKey
+	DestRegister   uint32
+	SourceRegister uint32
+}
+
+func (e *NftMetaExpr) Type() string {
+	return "meta"
+}
+
+// NftImmediateExpr loads Data into DestRegister, or sets the verdict of the
+// rule when Verdict is not nil
+type NftImmediateExpr struct {
+	DestRegister uint32
+	Data         []byte
+	Verdict      *NftVerdict
+}
+
+func (e *NftImmediateExpr) Type() string {
+	return "immediate"
+}
+
+// NftCounterExpr counts the packets reaching it, the counters are read only
+type NftCounterExpr struct {
+	Bytes   uint64
+	Packets uint64
+}
+
+func (e *NftCounterExpr) Type() string {
+	return "counter"
+}
+
+// NftCtKey selects the conntrack data of a ct expression
+type NftCtKey uint32
+
+const (
+	NFT_CT_STATE      NftCtKey = 0
+	NFT_CT_DIRECTION  NftCtKey = 1
+	NFT_CT_STATUS     NftCtKey = 2
+	NFT_CT_MARK       NftCtKey = 3
+	NFT_CT_SECMARK    NftCtKey = 4
+	NFT_CT_EXPIRATION NftCtKey = 5
+	NFT_CT_HELPER     NftCtKey = 6
+	NFT_CT_L3PROTOCOL NftCtKey = 7
+	NFT_CT_SRC        NftCtKey = 8
+	NFT_CT_DST        NftCtKey = 9
+	NFT_CT_PROTOCOL   NftCtKey = 10
+	NFT_CT_PROTO_SRC  NftCtKey = 11
+	NFT_CT_PROTO_DST  NftCtKey = 12
+	NFT_CT_LABELS     NftCtKey = 13
+	NFT_CT_PKTS       NftCtKey = 14
+	NFT_CT_BYTES      NftCtKey = 15
+	NFT_CT_AVGPKT     NftCtKey = 16
+	NFT_CT_ZONE       NftCtKey = 17
+	NFT_CT_EVENTMASK  NftCtKey = 18
+	NFT_CT_SRC_IP     NftCtKey = 19
+	NFT_CT_DST_IP     NftCtKey = 20
+	NFT_CT_SRC_IP6    NftCtKey = 21
+	NFT_CT_DST_IP6    NftCtKey = 22
+	NFT_CT_ID         NftCtKey = 23
+)
+
+const (
+	NFT_CT_DIR_ORIGINAL = 0
+	NFT_CT_DIR_REPLY    = 1
+)
+
+// Bits of the value loaded by the NFT_CT_STATE key
+const (
+	NFT_CT_STATE_BIT_INVALID     = 0x1
+	NFT_CT_STATE_BIT_ESTABLISHED = 0x2
+	NFT_CT_STATE_BIT_RELATED     = 0x4
+	NFT_CT_STATE_BIT_NEW         = 0x8
+	NFT_CT_STATE_BIT_UNTRACKED   = 0x40
+)
+
+// NftCtExpr loads the Key conntrack data of the packet into DestRegister,
+// or sets it from SourceRegister when SourceRegister is not zero. The
+// address and port keys need a Direction, NFT_CT_DIR_ORIGINAL or
+// NFT_CT_DIR_REPLY.
+type NftCtExpr struct {
+	Key            NftCtKey
+	Direction      *uint8
+	DestRegister   uint32
+	SourceRegister uint32
+}
+
+func (e *NftCtExpr) Type() string {
+	return "ct"
+}
+
+// NftNatType is the type of a nat expression
+type NftNatType uint32
+
+const (
+	NFT_NAT_SNAT NftNatType = 0
+	NFT_NAT_DNAT NftNatType = 1
+)
+
+const (
+	NF_NAT_RANGE_MAP_IPS            = 0x1
+	NF_NAT_RANGE_PROTO_SPECIFIED    = 0x2
+	NF_NAT_RANGE_PROTO_RANDOM       = 0x4
+	NF_NAT_RANGE_PERSISTENT         = 0x8
+	NF_NAT_RANGE_PROTO_RANDOM_FULLY = 0x10
+)
+
+// NftNatExpr translates the source or destination of the connection to the
+// addresses and ports held by the registers, zero registers are unused.
+type NftNatExpr struct {
+	NatType     NftNatType
+	Family      NftFamily
+	RegAddrMin  uint32
+	RegAddrMax  uint32
+	RegProtoMin uint32
+	RegProtoMax uint32
+	// Flags is a mask of the NF_NAT_RANGE_* flags
+	Flags uint32
+}
+
+func (e *NftNatExpr) Type() string {
+	return "nat"
+}
+
+const (
+	NFT_LOOKUP_F_INV = 0x1
+)
+
+// NftLookupExpr looks SourceRegister up in a set and breaks the evaluation
+// of the rule when it is missing, or when it is found if Invert is set. For
+// maps the data of the element is loaded into DestRegister. SetID also
+// references a set created in the same batch, whose name may not be known
+// yet, like the "__set%d" names of the anonymous sets.
+type NftLookupExpr struct {
+	SetName        string
+	SetID          uint32
+	SourceRegister uint32
+	DestRegister   uint32
+	Invert         bool
+}
+
+func (e *NftLookupExpr) Type() string {
+	return "lookup"
+}
+
+// NftGenericExpr represents expressions that are not currently understood by
+// this netlink library, Data holds their raw attributes.
+type NftGenericExpr struct {
+	Name string
+	Data []byte
+}
+
+func (e *NftGenericExpr) Type() string {
+	return e.Name
+}
+
+// Flags of the sets
+const (
+	NFT_SET_ANONYMOUS = 0x1
+	NFT_SET_CONSTANT  = 0x2
+	NFT_SET_INTERVAL  = 0x4
+	NFT_SET_MAP       = 0x8
+	NFT_SET_TIMEOUT   = 0x10
+	NFT_SET_EVAL      = 0x20
+	NFT_SET_OBJECT    = 0x40
+	NFT_SET_CONCAT    = 0x80
+)
+
+// Types of the keys and data of the sets. The kernel only stores them, they
+// tell the nft tool how to print the elements.
+const (
+	NFT_TYPE_INTEGER      = 4
+	NFT_TYPE_STRING       = 5
+	NFT_TYPE_IPADDR       = 7
+	NFT_TYPE_IP6ADDR      = 8
+	NFT_TYPE_ETHERADDR    = 9
+	NFT_TYPE_INET_PROTO   = 12
+	NFT_TYPE_INET_SERVICE = 13
+	NFT_TYPE_MARK         = 19
+	NFT_TYPE_IFINDEX      = 20
+	// NFT_DATA_VERDICT is the data type of the verdict maps
+	NFT_DATA_VERDICT = 0xffffff00
+)
+
+// NftSet is a named set, or a map when Flags has NFT_SET_MAP
+type NftSet struct {
+	Family NftFamily
+	Table  string
+	Name   string
+	Handle uint64
+	// ID identifies the set within the batch creating it, see
+	// NftLookupExpr.SetID
+	ID uint32
+	// Flags is a mask of the NFT_SET_* flags
+	Flags    uint32
+	KeyType  uint32
+	KeyLen   uint32
+	DataType uint32
+	DataLen  uint32
+	// Timeout is the default timeout of the elements in milliseconds
+	Timeout uint64
+}
+
+func (s NftSet) String() string {
+	kind := "set"
+	if s.Flags&NFT_SET_MAP != 0 {
+		kind = "map"
+	}
+	return fmt.Sprintf("%s %s %s %s", kind, s.Family, s.Table, s.Name)
+}
+
+const (
+	NFT_SET_ELEM_INTERVAL_END = 0x1
+)
+
+// NftSetElem is an element of a set. The elements of the maps also have
+// either Data or a Verdict.
+type NftSetElem struct {
+	Key []byte
+	// KeyEnd is the end of the range of the key of the concatenated
+	// interval sets
+	KeyEnd  []byte
+	Data    []byte
+	Verdict *NftVerdict
+	// Flags is a mask of the NFT_SET_ELEM_* flags
+	Flags uint32
+	// Timeout and Expiration are in milliseconds, Expiration is read only
+	Timeout    uint64
+	Expiration uint64
+}
+
+// NftSetElems are elements of a set
+type NftSetElems struct {
+	Family NftFamily
+	Table  string
+	Set    string
+	Elems  []NftSetElem
+}
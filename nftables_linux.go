@@ -0,0 +1,1308 @@
+package netlink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// NftBatch collects nftables changes which are committed to the kernel as
+// one transaction: either all of them are applied or none is.
+type NftBatch struct {
+	h    *Handle
+	reqs []*nl.NetlinkRequest
+	err  error
+}
+
+// NewNftBatch returns an empty nftables batch
+func NewNftBatch() *NftBatch {
+	return pkgHandle.NewNftBatch()
+}
+
+// NewNftBatch returns an empty nftables batch using the netlink handle passed
+func (h *Handle) NewNftBatch() *NftBatch {
+	return &NftBatch{h: h}
+}
+
+func (h *Handle) newNftRequest(family NftFamily, msgType, flags int) *nl.NetlinkRequest {
+	req := h.newNetlinkRequest((nl.NFNL_SUBSYS_NFTABLES<<8)|msgType, flags)
+	msg := &nl.Nfgenmsg{
+		NfgenFamily: uint8(family),
+		Version:     nl.NFNETLINK_V0,
+		ResId:       0,
+	}
+	req.AddData(msg)
+	return req
+}
+
+func (b *NftBatch) add(family NftFamily, msgType, flags int, attrs []*nl.RtAttr, err error) {
+	if b.err != nil {
+		return
+	}
+	if err != nil {
+		b.err = err
+		return
+	}
+	req := b.h.newNftRequest(family, msgType, flags)
+	for _, a := range attrs {
+		req.AddData(a)
+	}
+	b.reqs = append(b.reqs, req)
+}
+
+// AddTable adds the creation of table to the batch, the flags of an
+// existing table are updated
+func (b *NftBatch) AddTable(table *NftTable) {
+	attrs, err := table.toNlData()
+	b.add(table.Family, nl.NFT_MSG_NEWTABLE, unix.NLM_F_CREATE, attrs, err)
+}
+
+// DelTable adds the deletion of table, along with its chains, rules and
+// sets, to the batch
+func (b *NftBatch) DelTable(table *NftTable) {
+	if table.Name == "" {
+		b.add(table.Family, nl.NFT_MSG_DELTABLE, 0, nil, fmt.Errorf("table name is required"))
+		return
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_TABLE_NAME, nl.ZeroTerminated(table.Name)),
+	}
+	b.add(table.Family, nl.NFT_MSG_DELTABLE, 0, attrs, nil)
+}
+
+// AddChain adds the creation of chain to the batch, the policy of an
+// existing base chain is updated
+func (b *NftBatch) AddChain(chain *NftChain) {
+	attrs, err := chain.toNlData()
+	b.add(chain.Family, nl.NFT_MSG_NEWCHAIN, unix.NLM_F_CREATE, attrs, err)
+}
+
+// DelChain adds the deletion of chain to the batch, the chain must be empty
+// and not referenced
+func (b *NftBatch) DelChain(chain *NftChain) {
+	if chain.Table == "" || chain.Name == "" {
+		b.add(chain.Family, nl.NFT_MSG_DELCHAIN, 0, nil, fmt.Errorf("chain table and name are required"))
+		return
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_CHAIN_TABLE, nl.ZeroTerminated(chain.Table)),
+		nl.NewRtAttr(nl.NFTA_CHAIN_NAME, nl.ZeroTerminated(chain.Name)),
+	}
+	b.add(chain.Family, nl.NFT_MSG_DELCHAIN, 0, attrs, nil)
+}
+
+// FlushChain adds the deletion of all the rules of chain to the batch
+func (b *NftBatch) FlushChain(chain *NftChain) {
+	attrs, err := chain.toNlData()
+	if err == nil {
+		attrs = []*nl.RtAttr{
+			nl.NewRtAttr(nl.NFTA_RULE_TABLE, nl.ZeroTerminated(chain.Table)),
+			nl.NewRtAttr(nl.NFTA_RULE_CHAIN, nl.ZeroTerminated(chain.Name)),
+		}
+	}
+	b.add(chain.Family, nl.NFT_MSG_DELRULE, 0, attrs, err)
+}
+
+// AddRule adds the appending of rule to its chain, or after the rule at
+// Position, to the batch
+func (b *NftBatch) AddRule(rule *NftRule) {
+	attrs, err := rule.toNlData()
+	b.add(rule.Family, nl.NFT_MSG_NEWRULE, unix.NLM_F_CREATE|unix.NLM_F_APPEND, attrs, err)
+}
+
+// InsertRule adds the insertion of rule at the start of its chain, or
+// before the rule at Position, to the batch
+func (b *NftBatch) InsertRule(rule *NftRule) {
+	attrs, err := rule.toNlData()
+	b.add(rule.Family, nl.NFT_MSG_NEWRULE, unix.NLM_F_CREATE, attrs, err)
+}
+
+// DelRule adds the deletion of the rule with the handle of rule to the batch
+func (b *NftBatch) DelRule(rule *NftRule) {
+	if rule.Handle == 0 {
+		b.add(rule.Family, nl.NFT_MSG_DELRULE, 0, nil, fmt.Errorf("rule handle is required"))
+		return
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_RULE_TABLE, nl.ZeroTerminated(rule.Table)),
+		nl.NewRtAttr(nl.NFTA_RULE_CHAIN, nl.ZeroTerminated(rule.Chain)),
+		nl.NewRtAttr(nl.NFTA_RULE_HANDLE, htonll(rule.Handle)),
+	}
+	b.add(rule.Family, nl.NFT_MSG_DELRULE, 0, attrs, nil)
+}
+
+// AddSet adds the creation of set to the batch, along with its elems
+func (b *NftBatch) AddSet(set *NftSet, elems ...NftSetElem) {
+	attrs, err := set.toNlData()
+	b.add(set.Family, nl.NFT_MSG_NEWSET, unix.NLM_F_CREATE, attrs, err)
+	if len(elems) > 0 {
+		b.AddSetElems(set, elems...)
+	}
+}
+
+// DelSet adds the deletion of set to the batch, the set must not be
+// referenced by rules
+func (b *NftBatch) DelSet(set *NftSet) {
+	if set.Table == "" || set.Name == "" {
+		b.add(set.Family, nl.NFT_MSG_DELSET, 0, nil, fmt.Errorf("set table and name are required"))
+		return
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_SET_TABLE, nl.ZeroTerminated(set.Table)),
+		nl.NewRtAttr(nl.NFTA_SET_NAME, nl.ZeroTerminated(set.Name)),
+	}
+	b.add(set.Family, nl.NFT_MSG_DELSET, 0, attrs, nil)
+}
+
+// AddSetElems adds the addition of elems to set to the batch
+func (b *NftBatch) AddSetElems(set *NftSet, elems ...NftSetElem) {
+	attrs, err := set.elemsToNlData(elems)
+	b.add(set.Family, nl.NFT_MSG_NEWSETELEM, unix.NLM_F_CREATE, attrs, err)
+}
+
+// DelSetElems adds the deletion of elems from set to the batch
+func (b *NftBatch) DelSetElems(set *NftSet, elems ...NftSetElem) {
+	attrs, err := set.elemsToNlData(elems)
+	b.add(set.Family, nl.NFT_MSG_DELSETELEM, 0, attrs, err)
+}
+
+// Commit sends the changes of the batch to the kernel as one transaction.
+// The batch is emptied, even if the transaction failed.
+func (b *NftBatch) Commit() error {
+	reqs, err := b.reqs, b.err
+	b.reqs, b.err = nil, nil
+	if err != nil {
+		return err
+	}
+	return nl.ExecuteBatch(nl.NFNL_SUBSYS_NFTABLES, reqs)
+}
+
+// CommitContext is like Commit but gives up and returns ctx.Err()
+// once ctx is done.
+func (b *NftBatch) CommitContext(ctx context.Context) error {
+	if len(b.reqs) > 0 {
+		b.reqs[0] = b.reqs[0].WithContext(ctx)
+	}
+	return b.Commit()
+}
+
+// NftTableAdd creates a table
+// nft add table [family] name
+func NftTableAdd(table *NftTable) error {
+	return pkgHandle.NftTableAdd(table)
+}
+
+// NftTableDel deletes a table along with its chains, rules and sets
+// nft delete table [family] name
+func NftTableDel(table *NftTable) error {
+	return pkgHandle.NftTableDel(table)
+}
+
+// NftTableList returns the tables of a family, NFT_FAMILY_ALL for all of them
+// nft list tables [family]
+func NftTableList(family NftFamily) ([]*NftTable, error) {
+	return pkgHandle.NftTableList(family)
+}
+
+// NftChainAdd creates a chain
+// nft add chain [family] table name [{ type type hook hook priority priority; }]
+func NftChainAdd(chain *NftChain) error {
+	return pkgHandle.NftChainAdd(chain)
+}
+
+// NftChainDel deletes an empty chain
+// nft delete chain [family] table name
+func NftChainDel(chain *NftChain) error {
+	return pkgHandle.NftChainDel(chain)
+}
+
+// NftChainList returns the chains of a family, all of them with
+// NFT_FAMILY_ALL, only those of table when it is not empty
+// nft list chains [family]
+func NftChainList(family NftFamily, table string) ([]*NftChain, error) {
+	return pkgHandle.NftChainList(family, table)
+}
+
+// NftRuleAdd appends a rule to its chain, or adds it after the rule at Position
+// nft add rule [family] table chain [position handle] statement...
+func NftRuleAdd(rule *NftRule) error {
+	return pkgHandle.NftRuleAdd(rule)
+}
+
+// NftRuleInsert inserts a rule at the start of its chain, or before the rule
+// at Position
+// nft insert rule [family] table chain [position handle] statement...
+func NftRuleInsert(rule *NftRule) error {
+	return pkgHandle.NftRuleInsert(rule)
+}
+
+// NftRuleDel deletes the rule with the handle of rule
+// nft delete rule [family] table chain handle handle
+func NftRuleDel(rule *NftRule) error {
+	return pkgHandle.NftRuleDel(rule)
+}
+
+// NftRuleList returns the rules of a family, all of them with
+// NFT_FAMILY_ALL, only those of table and chain when they are not empty
+// nft -a list chain [family] table chain
+func NftRuleList(family NftFamily, table, chain string) ([]*NftRule, error) {
+	return pkgHandle.NftRuleList(family, table, chain)
+}
+
+// NftSetAdd creates a set or a map along with its elements
+// nft add set [family] table name { type type; [flags flags;] [elements = { element... }] }
+func NftSetAdd(set *NftSet, elems ...NftSetElem) error {
+	return pkgHandle.NftSetAdd(set, elems...)
+}
+
+// NftSetDel deletes a set or a map
+// nft delete set [family] table name
+func NftSetDel(set *NftSet) error {
+	return pkgHandle.NftSetDel(set)
+}
+
+// NftSetList returns the sets and maps of a family, all of them with
+// NFT_FAMILY_ALL, only those of table when it is not empty
+// nft list sets [family]
+func NftSetList(family NftFamily, table string) ([]*NftSet, error) {
+	return pkgHandle.NftSetList(family, table)
+}
+
+// NftSetElemAdd adds elements to a set or a map
+// nft add element [family] table set { element... }
+func NftSetElemAdd(set *NftSet, elems ...NftSetElem) error {
+	return pkgHandle.NftSetElemAdd(set, elems...)
+}
+
+// NftSetElemDel deletes elements of a set or a map
+// nft delete element [family] table set { element... }
+func NftSetElemDel(set *NftSet, elems ...NftSetElem) error {
+	return pkgHandle.NftSetElemDel(set, elems...)
+}
+
+// NftSetElemList returns the elements of a set or a map
+// nft list set [family] table name
+func NftSetElemList(set *NftSet) ([]NftSetElem, error) {
+	return pkgHandle.NftSetElemList(set)
+}
+
+// NftTableAdd creates a table using the netlink handle passed
+// nft add table [family] name
+func (h *Handle) NftTableAdd(table *NftTable) error {
+	b := h.NewNftBatch()
+	b.AddTable(table)
+	return b.Commit()
+}
+
+// NftTableAddContext is like NftTableAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftTableAddContext(ctx context.Context, table *NftTable) error {
+	return h.withContext(ctx).NftTableAdd(table)
+}
+
+// NftTableDel deletes a table along with its chains, rules and sets using the netlink handle passed
+// nft delete table [family] name
+func (h *Handle) NftTableDel(table *NftTable) error {
+	b := h.NewNftBatch()
+	b.DelTable(table)
+	return b.Commit()
+}
+
+// NftTableDelContext is like NftTableDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftTableDelContext(ctx context.Context, table *NftTable) error {
+	return h.withContext(ctx).NftTableDel(table)
+}
+
+// NftTableList returns the tables of a family, NFT_FAMILY_ALL for all of them, using the netlink handle passed
+// nft list tables [family]
+func (h *Handle) NftTableList(family NftFamily) ([]*NftTable, error) {
+	req := h.newNftRequest(family, nl.NFT_MSG_GETTABLE, unix.NLM_F_DUMP)
+	res, err := req.Execute(unix.NETLINK_NETFILTER, nftMsgType(nl.NFT_MSG_NEWTABLE))
+	if err != nil {
+		return nil, err
+	}
+	var result []*NftTable
+	for _, data := range res {
+		table, err := parseNftTable(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, table)
+	}
+	return result, nil
+}
+
+// NftTableListContext is like NftTableList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftTableListContext(ctx context.Context, family NftFamily) ([]*NftTable, error) {
+	return h.withContext(ctx).NftTableList(family)
+}
+
+// NftChainAdd creates a chain using the netlink handle passed
+// nft add chain [family] table name [{ type type hook hook priority priority; }]
+func (h *Handle) NftChainAdd(chain *NftChain) error {
+	b := h.NewNftBatch()
+	b.AddChain(chain)
+	return b.Commit()
+}
+
+// NftChainAddContext is like NftChainAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftChainAddContext(ctx context.Context, chain *NftChain) error {
+	return h.withContext(ctx).NftChainAdd(chain)
+}
+
+// NftChainDel deletes an empty chain using the netlink handle passed
+// nft delete chain [family] table name
+func (h *Handle) NftChainDel(chain *NftChain) error {
+	b := h.NewNftBatch()
+	b.DelChain(chain)
+	return b.Commit()
+}
+
+// NftChainDelContext is like NftChainDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftChainDelContext(ctx context.Context, chain *NftChain) error {
+	return h.withContext(ctx).NftChainDel(chain)
+}
+
+// NftChainList returns the chains of a family using the netlink handle passed, all of them with
+// NFT_FAMILY_ALL, only those of table when it is not empty
+// nft list chains [family]
+func (h *Handle) NftChainList(family NftFamily, table string) ([]*NftChain, error) {
+	req := h.newNftRequest(family, nl.NFT_MSG_GETCHAIN, unix.NLM_F_DUMP)
+	res, err := req.Execute(unix.NETLINK_NETFILTER, nftMsgType(nl.NFT_MSG_NEWCHAIN))
+	if err != nil {
+		return nil, err
+	}
+	var result []*NftChain
+	for _, data := range res {
+		chain, err := parseNftChain(data)
+		if err != nil {
+			return nil, err
+		}
+		if table != "" && chain.Table != table {
+			continue
+		}
+		result = append(result, chain)
+	}
+	return result, nil
+}
+
+// NftChainListContext is like NftChainList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftChainListContext(ctx context.Context, family NftFamily, table string) ([]*NftChain, error) {
+	return h.withContext(ctx).NftChainList(family, table)
+}
+
+// NftRuleAdd appends a rule to its chain, or adds it after the rule at Position, using the netlink handle passed
+// nft add rule [family] table chain [position handle] statement...
+func (h *Handle) NftRuleAdd(rule *NftRule) error {
+	b := h.NewNftBatch()
+	b.AddRule(rule)
+	return b.Commit()
+}
+
+// NftRuleAddContext is like NftRuleAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftRuleAddContext(ctx context.Context, rule *NftRule) error {
+	return h.withContext(ctx).NftRuleAdd(rule)
+}
+
+// NftRuleInsert inserts a rule at the start of its chain, or before the rule at Position, using the netlink handle passed
+// nft insert rule [family] table chain [position handle] statement...
+func (h *Handle) NftRuleInsert(rule *NftRule) error {
+	b := h.NewNftBatch()
+	b.InsertRule(rule)
+	return b.Commit()
+}
+
+// NftRuleInsertContext is like NftRuleInsert but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftRuleInsertContext(ctx context.Context, rule *NftRule) error {
+	return h.withContext(ctx).NftRuleInsert(rule)
+}
+
+// NftRuleDel deletes the rule with the handle of rule using the netlink handle passed
+// nft delete rule [family] table chain handle handle
+func (h *Handle) NftRuleDel(rule *NftRule) error {
+	b := h.NewNftBatch()
+	b.DelRule(rule)
+	return b.Commit()
+}
+
+// NftRuleDelContext is like NftRuleDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftRuleDelContext(ctx context.Context, rule *NftRule) error {
+	return h.withContext(ctx).NftRuleDel(rule)
+}
+
+// NftRuleList returns the rules of a family using the netlink handle passed, all of them with
+// NFT_FAMILY_ALL, only those of table and chain when they are not empty
+// nft -a list chain [family] table chain
+func (h *Handle) NftRuleList(family NftFamily, table, chain string) ([]*NftRule, error) {
+	req := h.newNftRequest(family, nl.NFT_MSG_GETRULE, unix.NLM_F_DUMP)
+	// Recent kernels only dump the rules of the table and chain passed
+	if table != "" {
+		req.AddData(nl.NewRtAttr(nl.NFTA_RULE_TABLE, nl.ZeroTerminated(table)))
+		if chain != "" {
+			req.AddData(nl.NewRtAttr(nl.NFTA_RULE_CHAIN, nl.ZeroTerminated(chain)))
+		}
+	}
+	res, err := req.Execute(unix.NETLINK_NETFILTER, nftMsgType(nl.NFT_MSG_NEWRULE))
+	if err != nil {
+		return nil, err
+	}
+	var result []*NftRule
+	for _, data := range res {
+		rule, err := parseNftRule(data)
+		if err != nil {
+			return nil, err
+		}
+		if (table != "" && rule.Table != table) || (chain != "" && rule.Chain != chain) {
+			continue
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+// NftRuleListContext is like NftRuleList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftRuleListContext(ctx context.Context, family NftFamily, table, chain string) ([]*NftRule, error) {
+	return h.withContext(ctx).NftRuleList(family, table, chain)
+}
+
+// NftSetAdd creates a set or a map along with its elements using the netlink handle passed
+// nft add set [family] table name { type type; [flags flags;] [elements = { element... }] }
+func (h *Handle) NftSetAdd(set *NftSet, elems ...NftSetElem) error {
+	b := h.NewNftBatch()
+	b.AddSet(set, elems...)
+	return b.Commit()
+}
+
+// NftSetAddContext is like NftSetAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetAddContext(ctx context.Context, set *NftSet, elems ...NftSetElem) error {
+	return h.withContext(ctx).NftSetAdd(set, elems...)
+}
+
+// NftSetDel deletes a set or a map using the netlink handle passed
+// nft delete set [family] table name
+func (h *Handle) NftSetDel(set *NftSet) error {
+	b := h.NewNftBatch()
+	b.DelSet(set)
+	return b.Commit()
+}
+
+// NftSetDelContext is like NftSetDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetDelContext(ctx context.Context, set *NftSet) error {
+	return h.withContext(ctx).NftSetDel(set)
+}
+
+// NftSetList returns the sets and maps of a family using the netlink handle passed, all of them
+// with NFT_FAMILY_ALL, only those of table when it is not empty
+// nft list sets [family]
+func (h *Handle) NftSetList(family NftFamily, table string) ([]*NftSet, error) {
+	req := h.newNftRequest(family, nl.NFT_MSG_GETSET, unix.NLM_F_DUMP)
+	if table != "" {
+		req.AddData(nl.NewRtAttr(nl.NFTA_SET_TABLE, nl.ZeroTerminated(table)))
+	}
+	res, err := req.Execute(unix.NETLINK_NETFILTER, nftMsgType(nl.NFT_MSG_NEWSET))
+	if err != nil {
+		return nil, err
+	}
+	var result []*NftSet
+	for _, data := range res {
+		set, err := parseNftSet(data)
+		if err != nil {
+			return nil, err
+		}
+		if table != "" && set.Table != table {
+			continue
+		}
+		result = append(result, set)
+	}
+	return result, nil
+}
+
+// NftSetListContext is like NftSetList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetListContext(ctx context.Context, family NftFamily, table string) ([]*NftSet, error) {
+	return h.withContext(ctx).NftSetList(family, table)
+}
+
+// NftSetElemAdd adds elements to a set or a map using the netlink handle passed
+// nft add element [family] table set { element... }
+func (h *Handle) NftSetElemAdd(set *NftSet, elems ...NftSetElem) error {
+	b := h.NewNftBatch()
+	b.AddSetElems(set, elems...)
+	return b.Commit()
+}
+
+// NftSetElemAddContext is like NftSetElemAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetElemAddContext(ctx context.Context, set *NftSet, elems ...NftSetElem) error {
+	return h.withContext(ctx).NftSetElemAdd(set, elems...)
+}
+
+// NftSetElemDel deletes elements of a set or a map using the netlink handle passed
+// nft delete element [family] table set { element... }
+func (h *Handle) NftSetElemDel(set *NftSet, elems ...NftSetElem) error {
+	b := h.NewNftBatch()
+	b.DelSetElems(set, elems...)
+	return b.Commit()
+}
+
+// NftSetElemDelContext is like NftSetElemDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetElemDelContext(ctx context.Context, set *NftSet, elems ...NftSetElem) error {
+	return h.withContext(ctx).NftSetElemDel(set, elems...)
+}
+
+// NftSetElemList returns the elements of a set or a map using the netlink handle passed
+// nft list set [family] table name
+func (h *Handle) NftSetElemList(set *NftSet) ([]NftSetElem, error) {
+	req := h.newNftRequest(set.Family, nl.NFT_MSG_GETSETELEM, unix.NLM_F_DUMP)
+	req.AddData(nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_TABLE, nl.ZeroTerminated(set.Table)))
+	req.AddData(nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_SET, nl.ZeroTerminated(set.Name)))
+	res, err := req.Execute(unix.NETLINK_NETFILTER, nftMsgType(nl.NFT_MSG_NEWSETELEM))
+	if err != nil {
+		return nil, err
+	}
+	var result []NftSetElem
+	for _, data := range res {
+		elems, err := parseNftSetElems(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, elems.Elems...)
+	}
+	return result, nil
+}
+
+// NftSetElemListContext is like NftSetElemList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) NftSetElemListContext(ctx context.Context, set *NftSet) ([]NftSetElem, error) {
+	return h.withContext(ctx).NftSetElemList(set)
+}
+
+// NftEvent is sent when the nftables ruleset changes. Object is a *NftTable,
+// *NftChain, *NftRule, *NftSet or *NftSetElems depending on Type.
+type NftEvent struct {
+	// Type is the nl.NFT_MSG_* type of the change, for example
+	// nl.NFT_MSG_NEWRULE or nl.NFT_MSG_DELRULE
+	Type   int
+	Object interface{}
+}
+
+// NftSubscribeOptions contains a set of options to use with
+// NftSubscribeWithOptions.
+type NftSubscribeOptions struct {
+	Namespace *netns.NsHandle
+	// ErrorCallback is called with the error that stops the subscription.
+	// It is also called with unix.ENOBUFS, without stopping the
+	// subscription, when the socket overran and events were lost.
+	ErrorCallback func(error)
+}
+
+// NftSubscribe takes a chan down which nftables events will be sent when
+// tables, chains, rules, sets or their elements are added or deleted. Close
+// the 'done' chan to stop subscription.
+// nft monitor
+func NftSubscribe(ch chan<- NftEvent, done <-chan struct{}) error {
+	return NftSubscribeWithOptions(ch, done, NftSubscribeOptions{})
+}
+
+// NftSubscribeWithOptions work like NftSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func NftSubscribeWithOptions(ch chan<- NftEvent, done <-chan struct{}, options NftSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return nftSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback)
+}
+
+func nftSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NftEvent, done <-chan struct{}, cberr func(error)) error {
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_NETFILTER, nl.NFNLGRP_NFTABLES)
+	if err != nil {
+		return err
+	}
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	go func() {
+		defer close(ch)
+		for {
+			msgs, err := s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				if err == unix.ENOBUFS {
+					continue
+				}
+				return
+			}
+			for _, m := range msgs {
+				if m.Header.Type == unix.NLMSG_DONE {
+					continue
+				}
+				if m.Header.Type == unix.NLMSG_ERROR {
+					native := nl.NativeEndian()
+					error := int32(native.Uint32(m.Data[0:4]))
+					if error == 0 {
+						continue
+					}
+					if cberr != nil {
+						cberr(syscall.Errno(-error))
+					}
+					return
+				}
+				if int(m.Header.Type>>8) != nl.NFNL_SUBSYS_NFTABLES {
+					continue
+				}
+				msgType := int(m.Header.Type & 0xff)
+				var (
+					obj interface{}
+					err error
+				)
+				switch msgType {
+				case nl.NFT_MSG_NEWTABLE, nl.NFT_MSG_DELTABLE:
+					obj, err = parseNftTable(m.Data)
+				case nl.NFT_MSG_NEWCHAIN, nl.NFT_MSG_DELCHAIN:
+					obj, err = parseNftChain(m.Data)
+				case nl.NFT_MSG_NEWRULE, nl.NFT_MSG_DELRULE:
+					obj, err = parseNftRule(m.Data)
+				case nl.NFT_MSG_NEWSET, nl.NFT_MSG_DELSET:
+					obj, err = parseNftSet(m.Data)
+				case nl.NFT_MSG_NEWSETELEM, nl.NFT_MSG_DELSETELEM:
+					obj, err = parseNftSetElems(m.Data)
+				default:
+					continue
+				}
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					continue
+				}
+				ch <- NftEvent{Type: msgType, Object: obj}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func nftMsgType(msgType int) uint16 {
+	return uint16(nl.NFNL_SUBSYS_NFTABLES<<8 | msgType)
+}
+
+// toNlData builds the attributes of table
+func (t *NftTable) toNlData() ([]*nl.RtAttr, error) {
+	if t.Name == "" {
+		return nil, fmt.Errorf("table name is required")
+	}
+	return []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_TABLE_NAME, nl.ZeroTerminated(t.Name)),
+		nl.NewRtAttr(nl.NFTA_TABLE_FLAGS, htonl(t.Flags)),
+	}, nil
+}
+
+// toNlData builds the attributes of chain
+func (c *NftChain) toNlData() ([]*nl.RtAttr, error) {
+	if c.Table == "" || c.Name == "" {
+		return nil, fmt.Errorf("chain table and name are required")
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_CHAIN_TABLE, nl.ZeroTerminated(c.Table)),
+		nl.NewRtAttr(nl.NFTA_CHAIN_NAME, nl.ZeroTerminated(c.Name)),
+	}
+	if c.Hook != nil {
+		hook := nl.NewRtAttr(nl.NFTA_CHAIN_HOOK|unix.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(hook, nl.NFTA_HOOK_HOOKNUM, htonl(uint32(c.Hook.Num)))
+		nl.NewRtAttrChild(hook, nl.NFTA_HOOK_PRIORITY, htonl(uint32(c.Hook.Priority)))
+		if c.Hook.Device != "" {
+			nl.NewRtAttrChild(hook, nl.NFTA_HOOK_DEV, nl.ZeroTerminated(c.Hook.Device))
+		}
+		chainType := c.Type
+		if chainType == "" {
+			chainType = NFT_CHAIN_TYPE_FILTER
+		}
+		attrs = append(attrs, hook, nl.NewRtAttr(nl.NFTA_CHAIN_TYPE, nl.ZeroTerminated(string(chainType))))
+	}
+	if c.Policy != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_CHAIN_POLICY, htonl(uint32(*c.Policy))))
+	}
+	return attrs, nil
+}
+
+func (r *NftRule) toNlData() ([]*nl.RtAttr, error) {
+	if r.Table == "" || r.Chain == "" {
+		return nil, fmt.Errorf("rule table and chain are required")
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_RULE_TABLE, nl.ZeroTerminated(r.Table)),
+		nl.NewRtAttr(nl.NFTA_RULE_CHAIN, nl.ZeroTerminated(r.Chain)),
+	}
+	exprs := nl.NewRtAttr(nl.NFTA_RULE_EXPRESSIONS|unix.NLA_F_NESTED, nil)
+	for _, expr := range r.Exprs {
+		elem := nl.NewRtAttrChild(exprs, nl.NFTA_LIST_ELEM|unix.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(elem, nl.NFTA_EXPR_NAME, nl.ZeroTerminated(expr.Type()))
+		if err := encodeNftExpr(nl.NewRtAttrChild(elem, nl.NFTA_EXPR_DATA|unix.NLA_F_NESTED, nil), expr); err != nil {
+			return nil, err
+		}
+	}
+	attrs = append(attrs, exprs)
+	if r.Position != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_RULE_POSITION, htonll(r.Position)))
+	}
+	if len(r.UserData) > 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_RULE_USERDATA, r.UserData))
+	}
+	return attrs, nil
+}
+
+func encodeNftExpr(data *nl.RtAttr, expr NftExpr) error {
+	switch e := expr.(type) {
+	case *NftPayloadExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_PAYLOAD_DREG, htonl(e.DestRegister))
+		nl.NewRtAttrChild(data, nl.NFTA_PAYLOAD_BASE, htonl(uint32(e.Base)))
+		nl.NewRtAttrChild(data, nl.NFTA_PAYLOAD_OFFSET, htonl(e.Offset))
+		nl.NewRtAttrChild(data, nl.NFTA_PAYLOAD_LEN, htonl(e.Len))
+	case *NftCmpExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_CMP_SREG, htonl(e.SourceRegister))
+		nl.NewRtAttrChild(data, nl.NFTA_CMP_OP, htonl(uint32(e.Op)))
+		encodeNftData(data, nl.NFTA_CMP_DATA, e.Data, nil)
+	case *NftMetaExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_META_KEY, htonl(uint32(e.Key)))
+		if e.SourceRegister != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_META_SREG, htonl(e.SourceRegister))
+		} else {
+			nl.NewRtAttrChild(data, nl.NFTA_META_DREG, htonl(e.DestRegister))
+		}
+	case *NftImmediateExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_IMMEDIATE_DREG, htonl(e.DestRegister))
+		encodeNftData(data, nl.NFTA_IMMEDIATE_DATA, e.Data, e.Verdict)
+	case *NftCounterExpr:
+		if e.Bytes != 0 || e.Packets != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_COUNTER_BYTES, htonll(e.Bytes))
+			nl.NewRtAttrChild(data, nl.NFTA_COUNTER_PACKETS, htonll(e.Packets))
+		}
+	case *NftCtExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_CT_KEY, htonl(uint32(e.Key)))
+		if e.Direction != nil {
+			nl.NewRtAttrChild(data, nl.NFTA_CT_DIRECTION, []byte{*e.Direction})
+		}
+		if e.SourceRegister != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_CT_SREG, htonl(e.SourceRegister))
+		} else {
+			nl.NewRtAttrChild(data, nl.NFTA_CT_DREG, htonl(e.DestRegister))
+		}
+	case *NftNatExpr:
+		nl.NewRtAttrChild(data, nl.NFTA_NAT_TYPE, htonl(uint32(e.NatType)))
+		nl.NewRtAttrChild(data, nl.NFTA_NAT_FAMILY, htonl(uint32(e.Family)))
+		for _, reg := range []struct {
+			attr int
+			reg  uint32
+		}{
+			{nl.NFTA_NAT_REG_ADDR_MIN, e.RegAddrMin},
+			{nl.NFTA_NAT_REG_ADDR_MAX, e.RegAddrMax},
+			{nl.NFTA_NAT_REG_PROTO_MIN, e.RegProtoMin},
+			{nl.NFTA_NAT_REG_PROTO_MAX, e.RegProtoMax},
+		} {
+			if reg.reg != 0 {
+				nl.NewRtAttrChild(data, reg.attr, htonl(reg.reg))
+			}
+		}
+		if e.Flags != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_NAT_FLAGS, htonl(e.Flags))
+		}
+	case *NftLookupExpr:
+		if e.SetName == "" {
+			return fmt.Errorf("lookup set name is required")
+		}
+		nl.NewRtAttrChild(data, nl.NFTA_LOOKUP_SET, nl.ZeroTerminated(e.SetName))
+		if e.SetID != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_LOOKUP_SET_ID, htonl(e.SetID))
+		}
+		nl.NewRtAttrChild(data, nl.NFTA_LOOKUP_SREG, htonl(e.SourceRegister))
+		if e.DestRegister != 0 {
+			nl.NewRtAttrChild(data, nl.NFTA_LOOKUP_DREG, htonl(e.DestRegister))
+		}
+		if e.Invert {
+			nl.NewRtAttrChild(data, nl.NFTA_LOOKUP_FLAGS, htonl(NFT_LOOKUP_F_INV))
+		}
+	case *NftGenericExpr:
+		data.Data = e.Data
+	default:
+		return fmt.Errorf("unsupported nftables expression %q", expr.Type())
+	}
+	return nil
+}
+
+// encodeNftData adds a nested data attribute holding either value or verdict
+func encodeNftData(parent *nl.RtAttr, attrType int, value []byte, verdict *NftVerdict) {
+	data := nl.NewRtAttrChild(parent, attrType|unix.NLA_F_NESTED, nil)
+	if verdict == nil {
+		nl.NewRtAttrChild(data, nl.NFTA_DATA_VALUE, value)
+		return
+	}
+	v := nl.NewRtAttrChild(data, nl.NFTA_DATA_VERDICT|unix.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(v, nl.NFTA_VERDICT_CODE, htonl(uint32(verdict.Code)))
+	if verdict.Chain != "" {
+		nl.NewRtAttrChild(v, nl.NFTA_VERDICT_CHAIN, nl.ZeroTerminated(verdict.Chain))
+	}
+}
+
+// toNlData builds the attributes of set
+func (s *NftSet) toNlData() ([]*nl.RtAttr, error) {
+	if s.Table == "" || s.Name == "" {
+		return nil, fmt.Errorf("set table and name are required")
+	}
+	attrs := []*nl.RtAttr{
+		nl.NewRtAttr(nl.NFTA_SET_TABLE, nl.ZeroTerminated(s.Table)),
+		nl.NewRtAttr(nl.NFTA_SET_NAME, nl.ZeroTerminated(s.Name)),
+		nl.NewRtAttr(nl.NFTA_SET_FLAGS, htonl(s.Flags)),
+		nl.NewRtAttr(nl.NFTA_SET_KEY_TYPE, htonl(s.KeyType)),
+		nl.NewRtAttr(nl.NFTA_SET_KEY_LEN, htonl(s.KeyLen)),
+		nl.NewRtAttr(nl.NFTA_SET_ID, htonl(s.ID)),
+	}
+	if s.Flags&NFT_SET_MAP != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_SET_DATA_TYPE, htonl(s.DataType)))
+		if s.DataType != NFT_DATA_VERDICT {
+			attrs = append(attrs, nl.NewRtAttr(nl.NFTA_SET_DATA_LEN, htonl(s.DataLen)))
+		}
+	}
+	if s.Timeout != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_SET_TIMEOUT, htonll(s.Timeout)))
+	}
+	return attrs, nil
+}
+
+func (s *NftSet) elemsToNlData(elems []NftSetElem) ([]*nl.RtAttr, error) {
+	if s.Table == "" || (s.Name == "" && s.ID == 0) {
+		return nil, fmt.Errorf("set table and name are required")
+	}
+	attrs := []*nl.RtAttr{nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_TABLE, nl.ZeroTerminated(s.Table))}
+	if s.Name != "" {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_SET, nl.ZeroTerminated(s.Name)))
+	} else {
+		attrs = append(attrs, nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_SET_ID, htonl(s.ID)))
+	}
+	list := nl.NewRtAttr(nl.NFTA_SET_ELEM_LIST_ELEMENTS|unix.NLA_F_NESTED, nil)
+	for _, e := range elems {
+		elem := nl.NewRtAttrChild(list, nl.NFTA_LIST_ELEM|unix.NLA_F_NESTED, nil)
+		encodeNftData(elem, nl.NFTA_SET_ELEM_KEY, e.Key, nil)
+		if len(e.KeyEnd) > 0 {
+			encodeNftData(elem, nl.NFTA_SET_ELEM_KEY_END, e.KeyEnd, nil)
+		}
+		if len(e.Data) > 0 || e.Verdict != nil {
+			encodeNftData(elem, nl.NFTA_SET_ELEM_DATA, e.Data, e.Verdict)
+		}
+		if e.Flags != 0 {
+			nl.NewRtAttrChild(elem, nl.NFTA_SET_ELEM_FLAGS, htonl(e.Flags))
+		}
+		if e.Timeout != 0 {
+			nl.NewRtAttrChild(elem, nl.NFTA_SET_ELEM_TIMEOUT, htonll(e.Timeout))
+		}
+	}
+	return append(attrs, list), nil
+}
+
+// parseNftAttrs skips the nfgenmsg header of data, which holds the family,
+// and parses the attributes that follow
+func parseNftAttrs(data []byte) (NftFamily, []syscall.NetlinkRouteAttr, error) {
+	if len(data) < nl.SizeofNfgenmsg {
+		return 0, nil, fmt.Errorf("nftables message too short")
+	}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	return NftFamily(data[0]), attrs, err
+}
+
+func nftString(b []byte) string {
+	return string(bytes.TrimRight(b, "\x00"))
+}
+
+func parseNftTable(data []byte) (*NftTable, error) {
+	family, attrs, err := parseNftAttrs(data)
+	if err != nil {
+		return nil, err
+	}
+	t := &NftTable{Family: family}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_TABLE_NAME:
+			t.Name = nftString(attr.Value)
+		case nl.NFTA_TABLE_FLAGS:
+			t.Flags = ntohl(attr.Value)
+		case nl.NFTA_TABLE_USE:
+			t.Use = ntohl(attr.Value)
+		case nl.NFTA_TABLE_HANDLE:
+			t.Handle = ntohll(attr.Value)
+		}
+	}
+	return t, nil
+}
+
+func parseNftChain(data []byte) (*NftChain, error) {
+	family, attrs, err := parseNftAttrs(data)
+	if err != nil {
+		return nil, err
+	}
+	c := &NftChain{Family: family}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_CHAIN_TABLE:
+			c.Table = nftString(attr.Value)
+		case nl.NFTA_CHAIN_NAME:
+			c.Name = nftString(attr.Value)
+		case nl.NFTA_CHAIN_HANDLE:
+			c.Handle = ntohll(attr.Value)
+		case nl.NFTA_CHAIN_HOOK:
+			hookAttrs, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			c.Hook = &NftChainHook{}
+			for _, hookAttr := range hookAttrs {
+				switch hookAttr.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.NFTA_HOOK_HOOKNUM:
+					c.Hook.Num = NftHook(ntohl(hookAttr.Value))
+				case nl.NFTA_HOOK_PRIORITY:
+					c.Hook.Priority = int32(ntohl(hookAttr.Value))
+				case nl.NFTA_HOOK_DEV:
+					c.Hook.Device = nftString(hookAttr.Value)
+				}
+			}
+		case nl.NFTA_CHAIN_TYPE:
+			c.Type = NftChainType(nftString(attr.Value))
+		case nl.NFTA_CHAIN_POLICY:
+			policy := NftVerdictCode(ntohl(attr.Value))
+			c.Policy = &policy
+		case nl.NFTA_CHAIN_USE:
+			c.Use = ntohl(attr.Value)
+		}
+	}
+	return c, nil
+}
+
+func parseNftRule(data []byte) (*NftRule, error) {
+	family, attrs, err := parseNftAttrs(data)
+	if err != nil {
+		return nil, err
+	}
+	r := &NftRule{Family: family}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_RULE_TABLE:
+			r.Table = nftString(attr.Value)
+		case nl.NFTA_RULE_CHAIN:
+			r.Chain = nftString(attr.Value)
+		case nl.NFTA_RULE_HANDLE:
+			r.Handle = ntohll(attr.Value)
+		case nl.NFTA_RULE_POSITION:
+			r.Position = ntohll(attr.Value)
+		case nl.NFTA_RULE_USERDATA:
+			r.UserData = append([]byte(nil), attr.Value...)
+		case nl.NFTA_RULE_EXPRESSIONS:
+			elems, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, elem := range elems {
+				expr, err := parseNftExpr(elem.Value)
+				if err != nil {
+					return nil, err
+				}
+				r.Exprs = append(r.Exprs, expr)
+			}
+		}
+	}
+	return r, nil
+}
+
+func parseNftExpr(data []byte) (NftExpr, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		name    string
+		rawData []byte
+	)
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_EXPR_NAME:
+			name = nftString(attr.Value)
+		case nl.NFTA_EXPR_DATA:
+			rawData = attr.Value
+		}
+	}
+	attrs, err = nl.ParseRouteAttr(rawData)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "payload":
+		e := &NftPayloadExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_PAYLOAD_DREG:
+				e.DestRegister = ntohl(attr.Value)
+			case nl.NFTA_PAYLOAD_BASE:
+				e.Base = NftPayloadBase(ntohl(attr.Value))
+			case nl.NFTA_PAYLOAD_OFFSET:
+				e.Offset = ntohl(attr.Value)
+			case nl.NFTA_PAYLOAD_LEN:
+				e.Len = ntohl(attr.Value)
+			}
+		}
+		return e, nil
+	case "cmp":
+		e := &NftCmpExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_CMP_SREG:
+				e.SourceRegister = ntohl(attr.Value)
+			case nl.NFTA_CMP_OP:
+				e.Op = NftCmpOp(ntohl(attr.Value))
+			case nl.NFTA_CMP_DATA:
+				if e.Data, _, err = parseNftData(attr.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return e, nil
+	case "meta":
+		e := &NftMetaExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_META_KEY:
+				e.Key = NftMetaKey(ntohl(attr.Value))
+			case nl.NFTA_META_DREG:
+				e.DestRegister = ntohl(attr.Value)
+			case nl.NFTA_META_SREG:
+				e.SourceRegister = ntohl(attr.Value)
+			}
+		}
+		return e, nil
+	case "immediate":
+		e := &NftImmediateExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_IMMEDIATE_DREG:
+				e.DestRegister = ntohl(attr.Value)
+			case nl.NFTA_IMMEDIATE_DATA:
+				if e.Data, e.Verdict, err = parseNftData(attr.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return e, nil
+	case "counter":
+		e := &NftCounterExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_COUNTER_BYTES:
+				e.Bytes = ntohll(attr.Value)
+			case nl.NFTA_COUNTER_PACKETS:
+				e.Packets = ntohll(attr.Value)
+			}
+		}
+		return e, nil
+	case "ct":
+		e := &NftCtExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_CT_KEY:
+				e.Key = NftCtKey(ntohl(attr.Value))
+			case nl.NFTA_CT_DIRECTION:
+				direction := attr.Value[0]
+				e.Direction = &direction
+			case nl.NFTA_CT_DREG:
+				e.DestRegister = ntohl(attr.Value)
+			case nl.NFTA_CT_SREG:
+				e.SourceRegister = ntohl(attr.Value)
+			}
+		}
+		return e, nil
+	case "nat":
+		e := &NftNatExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_NAT_TYPE:
+				e.NatType = NftNatType(ntohl(attr.Value))
+			case nl.NFTA_NAT_FAMILY:
+				e.Family = NftFamily(ntohl(attr.Value))
+			case nl.NFTA_NAT_REG_ADDR_MIN:
+				e.RegAddrMin = ntohl(attr.Value)
+			case nl.NFTA_NAT_REG_ADDR_MAX:
+				e.RegAddrMax = ntohl(attr.Value)
+			case nl.NFTA_NAT_REG_PROTO_MIN:
+				e.RegProtoMin = ntohl(attr.Value)
+			case nl.NFTA_NAT_REG_PROTO_MAX:
+				e.RegProtoMax = ntohl(attr.Value)
+			case nl.NFTA_NAT_FLAGS:
+				e.Flags = ntohl(attr.Value)
+			}
+		}
+		return e, nil
+	case "lookup":
+		e := &NftLookupExpr{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NFTA_LOOKUP_SET:
+				e.SetName = nftString(attr.Value)
+			case nl.NFTA_LOOKUP_SET_ID:
+				e.SetID = ntohl(attr.Value)
+			case nl.NFTA_LOOKUP_SREG:
+				e.SourceRegister = ntohl(attr.Value)
+			case nl.NFTA_LOOKUP_DREG:
+				e.DestRegister = ntohl(attr.Value)
+			case nl.NFTA_LOOKUP_FLAGS:
+				e.Invert = ntohl(attr.Value)&NFT_LOOKUP_F_INV != 0
+			}
+		}
+		return e, nil
+	}
+	return &NftGenericExpr{Name: name, Data: append([]byte(nil), rawData...)}, nil
+}
+
+// parseNftData parses a nested data attribute holding either a value or a
+// verdict
+func parseNftData(data []byte) ([]byte, *NftVerdict, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_DATA_VALUE:
+			return append([]byte(nil), attr.Value...), nil, nil
+		case nl.NFTA_DATA_VERDICT:
+			verdictAttrs, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, nil, err
+			}
+			verdict := &NftVerdict{}
+			for _, verdictAttr := range verdictAttrs {
+				switch verdictAttr.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.NFTA_VERDICT_CODE:
+					verdict.Code = NftVerdictCode(ntohl(verdictAttr.Value))
+				case nl.NFTA_VERDICT_CHAIN:
+					verdict.Chain = nftString(verdictAttr.Value)
+				}
+			}
+			return nil, verdict, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+func parseNftSet(data []byte) (*NftSet, error) {
+	family, attrs, err := parseNftAttrs(data)
+	if err != nil {
+		return nil, err
+	}
+	s := &NftSet{Family: family}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_SET_TABLE:
+			s.Table = nftString(attr.Value)
+		case nl.NFTA_SET_NAME:
+			s.Name = nftString(attr.Value)
+		case nl.NFTA_SET_HANDLE:
+			s.Handle = ntohll(attr.Value)
+		case nl.NFTA_SET_ID:
+			s.ID = ntohl(attr.Value)
+		case nl.NFTA_SET_FLAGS:
+			s.Flags = ntohl(attr.Value)
+		case nl.NFTA_SET_KEY_TYPE:
+			s.KeyType = ntohl(attr.Value)
+		case nl.NFTA_SET_KEY_LEN:
+			s.KeyLen = ntohl(attr.Value)
+		case nl.NFTA_SET_DATA_TYPE:
+			s.DataType = ntohl(attr.Value)
+		case nl.NFTA_SET_DATA_LEN:
+			s.DataLen = ntohl(attr.Value)
+		case nl.NFTA_SET_TIMEOUT:
+			s.Timeout = ntohll(attr.Value)
+		}
+	}
+	return s, nil
+}
+
+func parseNftSetElems(data []byte) (*NftSetElems, error) {
+	family, attrs, err := parseNftAttrs(data)
+	if err != nil {
+		return nil, err
+	}
+	res := &NftSetElems{Family: family}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_SET_ELEM_LIST_TABLE:
+			res.Table = nftString(attr.Value)
+		case nl.NFTA_SET_ELEM_LIST_SET:
+			res.Set = nftString(attr.Value)
+		case nl.NFTA_SET_ELEM_LIST_ELEMENTS:
+			elems, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, elem := range elems {
+				e, err := parseNftSetElem(elem.Value)
+				if err != nil {
+					return nil, err
+				}
+				res.Elems = append(res.Elems, e)
+			}
+		}
+	}
+	return res, nil
+}
+
+func parseNftSetElem(data []byte) (NftSetElem, error) {
+	var e NftSetElem
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return e, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NFTA_SET_ELEM_KEY:
+			if e.Key, _, err = parseNftData(attr.Value); err != nil {
+				return e, err
+			}
+		case nl.NFTA_SET_ELEM_KEY_END:
+			if e.KeyEnd, _, err = parseNftData(attr.Value); err != nil {
+				return e, err
+			}
+		case nl.NFTA_SET_ELEM_DATA:
+			if e.Data, e.Verdict, err = parseNftData(attr.Value); err != nil {
+				return e, err
+			}
+		case nl.NFTA_SET_ELEM_FLAGS:
+			e.Flags = ntohl(attr.Value)
+		case nl.NFTA_SET_ELEM_TIMEOUT:
+			e.Timeout = ntohll(attr.Value)
+		case nl.NFTA_SET_ELEM_EXPIRATION:
+			e.Expiration = ntohll(attr.Value)
+		}
+	}
+	return e, nil
+}
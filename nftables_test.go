@@ -0,0 +1,490 @@
+// +build linux
+
+package netlink
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// tcpDportExprs matches the TCP packets to port
+func tcpDportExprs(port uint16) []NftExpr {
+	return []NftExpr{
+		&NftMetaExpr{Key: NFT_META_L4PROTO, DestRegister: NFT_REG_1},
+		&NftCmpExpr{Op: NFT_CMP_EQ, SourceRegister: NFT_REG_1, Data: []byte{unix.IPPROTO_TCP}},
+		&NftPayloadExpr{Base: NFT_PAYLOAD_TRANSPORT_HEADER, Offset: 2, Len: 2, DestRegister: NFT_REG_1},
+		&NftCmpExpr{Op: NFT_CMP_EQ, SourceRegister: NFT_REG_1, Data: htons(port)},
+	}
+}
+
+func TestNftTableChainRule(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	table := &NftTable{Family: NFT_FAMILY_INET, Name: "filter"}
+	if err := NftTableAdd(table); err != nil {
+		t.Fatal(err)
+	}
+	tables, err := NftTableList(NFT_FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Name != "filter" || tables[0].Family != NFT_FAMILY_INET || tables[0].Handle == 0 {
+		t.Fatalf("Unexpected tables %+v", tables)
+	}
+
+	accept := NFT_ACCEPT
+	input := &NftChain{
+		Family: NFT_FAMILY_INET,
+		Table:  "filter",
+		Name:   "input",
+		Hook:   &NftChainHook{Num: NFT_HOOK_INPUT, Priority: NFT_PRIORITY_FILTER},
+		Policy: &accept,
+	}
+	ssh := &NftChain{Family: NFT_FAMILY_INET, Table: "filter", Name: "ssh"}
+	b := NewNftBatch()
+	b.AddChain(input)
+	b.AddChain(ssh)
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	chains, err := NftChainList(NFT_FAMILY_INET, "filter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != 2 {
+		t.Fatalf("Found %d chains instead of 2", len(chains))
+	}
+	for _, c := range chains {
+		switch c.Name {
+		case "input":
+			if c.Hook == nil || c.Hook.Num != NFT_HOOK_INPUT || c.Hook.Priority != 0 ||
+				c.Type != NFT_CHAIN_TYPE_FILTER || c.Policy == nil || *c.Policy != NFT_ACCEPT {
+				t.Fatalf("Unexpected base chain %s", c)
+			}
+		case "ssh":
+			if c.Hook != nil || c.Policy != nil {
+				t.Fatalf("Unexpected regular chain %s", c)
+			}
+		default:
+			t.Fatalf("Unexpected chain %s", c)
+		}
+	}
+
+	jump := &NftRule{
+		Family: NFT_FAMILY_INET,
+		Table:  "filter",
+		Chain:  "input",
+		Exprs: append(tcpDportExprs(22),
+			&NftImmediateExpr{DestRegister: NFT_REG_VERDICT, Verdict: &NftVerdict{Code: NFT_JUMP, Chain: "ssh"}}),
+	}
+	established := &NftRule{
+		Family: NFT_FAMILY_INET,
+		Table:  "filter",
+		Chain:  "input",
+		Exprs: []NftExpr{
+			&NftCtExpr{Key: NFT_CT_STATE, DestRegister: NFT_REG32_01},
+			&NftCmpExpr{Op: NFT_CMP_NEQ, SourceRegister: NFT_REG32_01, Data: []byte{0, 0, 0, 0}},
+			&NftCounterExpr{},
+			&NftImmediateExpr{DestRegister: NFT_REG_VERDICT, Verdict: &NftVerdict{Code: NFT_ACCEPT}},
+		},
+		UserData: []byte("established"),
+	}
+	if err := NftRuleAdd(jump); err != nil {
+		t.Fatal(err)
+	}
+	if err := NftRuleInsert(established); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := NftRuleList(NFT_FAMILY_INET, "filter", "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Found %d rules instead of 2", len(rules))
+	}
+	if !reflect.DeepEqual(rules[0].Exprs, established.Exprs) || string(rules[0].UserData) != "established" {
+		t.Fatalf("The inserted rule is not the first one: %+v", rules[0])
+	}
+	if !reflect.DeepEqual(rules[1].Exprs, jump.Exprs) {
+		t.Fatalf("The added rule is not the last one: %+v", rules[1])
+	}
+	if rules[0].Handle == 0 || rules[1].Handle == 0 || rules[1].Position != rules[0].Handle {
+		t.Fatalf("Unexpected rule handles %d %d, position %d", rules[0].Handle, rules[1].Handle, rules[1].Position)
+	}
+
+	// Add a rule between the two others
+	middle := &NftRule{
+		Family:   NFT_FAMILY_INET,
+		Table:    "filter",
+		Chain:    "input",
+		Position: rules[0].Handle,
+		Exprs:    []NftExpr{&NftCounterExpr{}},
+	}
+	if err := NftRuleAdd(middle); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = NftRuleList(NFT_FAMILY_INET, "filter", "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 || !reflect.DeepEqual(rules[1].Exprs, middle.Exprs) {
+		t.Fatalf("The rule was not added after the first one: %+v", rules)
+	}
+
+	// The chain is referenced by the jump
+	if err := NftChainDel(ssh); !errors.Is(err, unix.EBUSY) {
+		t.Fatalf("Deleting a referenced chain should fail with EBUSY, got %v", err)
+	}
+	if err := NftRuleDel(rules[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := NftChainDel(ssh); err != nil {
+		t.Fatal(err)
+	}
+	b.FlushChain(input)
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = NftRuleList(NFT_FAMILY_ALL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("Found %d rules after the flush", len(rules))
+	}
+
+	if err := NftTableDel(table); err != nil {
+		t.Fatal(err)
+	}
+	tables, err = NftTableList(NFT_FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 0 {
+		t.Fatalf("Found %d tables after the deletion", len(tables))
+	}
+}
+
+func TestNftSetMap(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	b := NewNftBatch()
+	b.AddTable(&NftTable{Family: NFT_FAMILY_IPV4, Name: "nat"})
+	b.AddChain(&NftChain{
+		Family: NFT_FAMILY_IPV4,
+		Table:  "nat",
+		Name:   "postrouting",
+		Type:   NFT_CHAIN_TYPE_NAT,
+		Hook:   &NftChainHook{Num: NFT_HOOK_POSTROUTING, Priority: NFT_PRIORITY_SRCNAT},
+	})
+	b.AddChain(&NftChain{Family: NFT_FAMILY_IPV4, Table: "nat", Name: "blocked"})
+	blocked := &NftSet{
+		Family:  NFT_FAMILY_IPV4,
+		Table:   "nat",
+		Name:    "blocked",
+		ID:      1,
+		KeyType: NFT_TYPE_IPADDR,
+		KeyLen:  4,
+		Flags:   NFT_SET_TIMEOUT,
+	}
+	b.AddSet(blocked,
+		NftSetElem{Key: net.ParseIP("192.0.2.1").To4()},
+		NftSetElem{Key: net.ParseIP("192.0.2.2").To4(), Timeout: 3600000})
+	ports := &NftSet{
+		Family:   NFT_FAMILY_IPV4,
+		Table:    "nat",
+		Name:     "ports",
+		ID:       2,
+		KeyType:  NFT_TYPE_INET_SERVICE,
+		KeyLen:   2,
+		Flags:    NFT_SET_MAP,
+		DataType: NFT_DATA_VERDICT,
+	}
+	b.AddSet(ports, NftSetElem{Key: htons(25), Verdict: &NftVerdict{Code: NFT_JUMP, Chain: "blocked"}})
+	natRule := &NftRule{
+		Family: NFT_FAMILY_IPV4,
+		Table:  "nat",
+		Chain:  "postrouting",
+		Exprs: []NftExpr{
+			&NftPayloadExpr{Base: NFT_PAYLOAD_NETWORK_HEADER, Offset: 16, Len: 4, DestRegister: NFT_REG_1},
+			&NftLookupExpr{SetName: "blocked", SourceRegister: NFT_REG_1, Invert: true},
+			&NftImmediateExpr{DestRegister: NFT_REG32_01, Data: net.ParseIP("198.51.100.1").To4()},
+			&NftNatExpr{NatType: NFT_NAT_SNAT, Family: NFT_FAMILY_IPV4, RegAddrMin: NFT_REG32_01},
+		},
+	}
+	b.AddRule(natRule)
+	b.AddRule(&NftRule{
+		Family: NFT_FAMILY_IPV4,
+		Table:  "nat",
+		Chain:  "postrouting",
+		Exprs: []NftExpr{
+			&NftPayloadExpr{Base: NFT_PAYLOAD_TRANSPORT_HEADER, Offset: 2, Len: 2, DestRegister: NFT_REG32_00},
+			&NftLookupExpr{SetName: "ports", SetID: 2, SourceRegister: NFT_REG32_00, DestRegister: NFT_REG_VERDICT},
+		},
+	})
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := NftSetList(NFT_FAMILY_IPV4, "nat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("Found %d sets instead of 2", len(sets))
+	}
+	for _, s := range sets {
+		var expected *NftSet
+		switch s.Name {
+		case "blocked":
+			expected = blocked
+		case "ports":
+			expected = ports
+		default:
+			t.Fatalf("Unexpected set %s", s)
+		}
+		if s.Flags != expected.Flags || s.KeyType != expected.KeyType || s.KeyLen != expected.KeyLen ||
+			s.DataType != expected.DataType || s.Handle == 0 {
+			t.Fatalf("Unexpected %s, %+v", s, *s)
+		}
+	}
+
+	elems, err := NftSetElemList(blocked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elems) != 2 {
+		t.Fatalf("Found %d elements instead of 2", len(elems))
+	}
+	for _, e := range elems {
+		if net.IP(e.Key).Equal(net.ParseIP("192.0.2.2")) && (e.Timeout != 3600000 || e.Expiration == 0) {
+			t.Fatalf("Unexpected timeout of element %+v", e)
+		}
+	}
+	elems, err = NftSetElemList(ports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elems) != 1 || !reflect.DeepEqual(elems[0].Verdict, &NftVerdict{Code: NFT_JUMP, Chain: "blocked"}) {
+		t.Fatalf("Unexpected map elements %+v", elems)
+	}
+
+	rules, err := NftRuleList(NFT_FAMILY_IPV4, "nat", "postrouting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Found %d rules instead of 2", len(rules))
+	}
+	// The kernel completes the range and the flags of the nat expression
+	nat := natRule.Exprs[3].(*NftNatExpr)
+	nat.RegAddrMax = nat.RegAddrMin
+	nat.Flags = NF_NAT_RANGE_MAP_IPS
+	if !reflect.DeepEqual(rules[0].Exprs, natRule.Exprs) {
+		t.Fatalf("Unexpected nat rule %+v", rules[0].Exprs)
+	}
+	if lookup := rules[1].Exprs[1].(*NftLookupExpr); lookup.SetName != "ports" || lookup.DestRegister != NFT_REG_VERDICT {
+		t.Fatalf("Unexpected map lookup %+v", lookup)
+	}
+
+	if err := NftSetElemDel(blocked, NftSetElem{Key: net.ParseIP("192.0.2.1").To4()}); err != nil {
+		t.Fatal(err)
+	}
+	elems, err = NftSetElemList(blocked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elems) != 1 || !net.IP(elems[0].Key).Equal(net.ParseIP("192.0.2.2")) {
+		t.Fatalf("Unexpected elements after the deletion %+v", elems)
+	}
+
+	if err := NftSetDel(blocked); !errors.Is(err, unix.EBUSY) {
+		t.Fatalf("Deleting a referenced set should fail with EBUSY, got %v", err)
+	}
+	b.FlushChain(&NftChain{Family: NFT_FAMILY_IPV4, Table: "nat", Name: "postrouting"})
+	b.DelSet(blocked)
+	b.DelSet(ports)
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	sets, err = NftSetList(NFT_FAMILY_ALL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 0 {
+		t.Fatalf("Found %d sets after the deletion", len(sets))
+	}
+}
+
+// TestNftBatchAbort checks that a failing batch is not applied at all
+func TestNftBatchAbort(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	b := NewNftBatch()
+	b.AddTable(&NftTable{Family: NFT_FAMILY_INET, Name: "t1"})
+	b.AddChain(&NftChain{Family: NFT_FAMILY_INET, Table: "missing", Name: "c"})
+	b.AddTable(&NftTable{Family: NFT_FAMILY_INET, Name: "t2"})
+	if err := b.Commit(); !errors.Is(err, unix.ENOENT) {
+		t.Fatalf("Adding a chain to a missing table should fail with ENOENT, got %v", err)
+	}
+	tables, err := NftTableList(NFT_FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 0 {
+		t.Fatalf("Found %d tables after the aborted batch", len(tables))
+	}
+
+	// Errors found while building the batch are returned by Commit
+	b.AddTable(&NftTable{Family: NFT_FAMILY_INET})
+	if err := b.Commit(); err == nil {
+		t.Fatal("Adding a table without name should fail")
+	}
+	if err := b.Commit(); err != nil {
+		t.Fatalf("Committing an empty batch should succeed, got %v", err)
+	}
+}
+
+func TestNftSubscribe(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	ch := make(chan NftEvent)
+	done := make(chan struct{})
+	defer close(done)
+	if err := NftSubscribe(ch, done); err != nil {
+		t.Fatal(err)
+	}
+
+	table := &NftTable{Family: NFT_FAMILY_IPV6, Name: "mon"}
+	chain := &NftChain{Family: NFT_FAMILY_IPV6, Table: "mon", Name: "c"}
+	b := NewNftBatch()
+	b.AddTable(table)
+	b.AddChain(chain)
+	b.AddRule(&NftRule{Family: NFT_FAMILY_IPV6, Table: "mon", Chain: "c", Exprs: []NftExpr{&NftCounterExpr{}}})
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NftTableDel(table); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int{nl.NFT_MSG_NEWTABLE, nl.NFT_MSG_NEWCHAIN, nl.NFT_MSG_NEWRULE,
+		nl.NFT_MSG_DELRULE, nl.NFT_MSG_DELCHAIN, nl.NFT_MSG_DELTABLE}
+	for _, tp := range expected {
+		select {
+		case event := <-ch:
+			if event.Type != tp {
+				t.Fatalf("Received event of type %d, expected %d", event.Type, tp)
+			}
+			switch obj := event.Object.(type) {
+			case *NftTable:
+				if obj.Name != "mon" || obj.Family != NFT_FAMILY_IPV6 {
+					t.Fatalf("Unexpected table %s", obj)
+				}
+			case *NftChain:
+				if obj.Name != "c" || obj.Table != "mon" {
+					t.Fatalf("Unexpected chain %s", obj)
+				}
+			case *NftRule:
+				if obj.Chain != "c" || obj.Handle == 0 {
+					t.Fatalf("Unexpected rule %+v", obj)
+				}
+			default:
+				t.Fatalf("Unexpected event object %+v", obj)
+			}
+		case <-time.After(time.Minute):
+			t.Fatalf("Timeout waiting for event of type %d", tp)
+		}
+	}
+}
+
+type unsupportedExpr struct{}
+
+func (e *unsupportedExpr) Type() string {
+	return "unsupported"
+}
+
+func TestNftRuleSerialize(t *testing.T) {
+	direction := uint8(NFT_CT_DIR_REPLY)
+	rule := NftRule{
+		Family: NFT_FAMILY_BRIDGE,
+		Table:  "t",
+		Chain:  "c",
+		Exprs: []NftExpr{
+			&NftMetaExpr{Key: NFT_META_MARK, SourceRegister: NFT_REG_1},
+			&NftCtExpr{Key: NFT_CT_PROTO_DST, Direction: &direction, DestRegister: NFT_REG_2},
+			&NftCounterExpr{Bytes: 1500, Packets: 1},
+			&NftNatExpr{NatType: NFT_NAT_DNAT, Family: NFT_FAMILY_IPV6, RegAddrMin: NFT_REG_1,
+				RegProtoMin: NFT_REG_2, RegProtoMax: NFT_REG_3, Flags: NF_NAT_RANGE_PERSISTENT},
+			&NftLookupExpr{SetName: "s", SetID: 7, SourceRegister: NFT_REG_1, Invert: true},
+			&NftGenericExpr{Name: "limit", Data: nl.NewRtAttr(1, htonll(10)).Serialize()},
+			&NftImmediateExpr{Verdict: &NftVerdict{Code: NFT_GOTO, Chain: "other"}},
+		},
+		Position: 4,
+		UserData: []byte{1, 2, 3},
+	}
+	attrs, err := rule.toNlData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte{byte(NFT_FAMILY_BRIDGE), 0, 0, 0}
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	res, err := parseNftRule(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*res, rule) {
+		t.Fatalf("Parsed rule\n%+v\ndoes not match\n%+v", *res, rule)
+	}
+
+	rule.Exprs = append(rule.Exprs, &unsupportedExpr{})
+	if _, err := rule.toNlData(); err == nil {
+		t.Fatal("Serializing an unsupported expression should fail")
+	}
+}
+
+func TestNftDelSerialize(t *testing.T) {
+	policy := NFT_ACCEPT
+	b := NewNftBatch()
+	b.DelTable(&NftTable{Family: NFT_FAMILY_INET, Name: "t", Flags: NFT_TABLE_F_DORMANT})
+	b.DelChain(&NftChain{Family: NFT_FAMILY_INET, Table: "t", Name: "c",
+		Hook: &NftChainHook{Num: NFT_HOOK_INPUT}, Policy: &policy})
+	b.DelSet(&NftSet{Family: NFT_FAMILY_INET, Table: "t", Name: "s", Flags: NFT_SET_MAP,
+		KeyType: 7, KeyLen: 4, Timeout: 1000})
+	expected := [][]int{
+		{nl.NFTA_TABLE_NAME},
+		{nl.NFTA_CHAIN_TABLE, nl.NFTA_CHAIN_NAME},
+		{nl.NFTA_SET_TABLE, nl.NFTA_SET_NAME},
+	}
+	if len(b.reqs) != len(expected) {
+		t.Fatalf("Expected %d requests, got %d", len(expected), len(b.reqs))
+	}
+	for i, req := range b.reqs {
+		var types []int
+		for _, data := range req.Data[1:] {
+			types = append(types, int(data.(*nl.RtAttr).Type))
+		}
+		if !reflect.DeepEqual(types, expected[i]) {
+			t.Fatalf("Request %d has attributes %v, expected %v", i, types, expected[i])
+		}
+	}
+
+	b = NewNftBatch()
+	b.DelSet(&NftSet{Table: "t", ID: 1})
+	if b.Commit() == nil {
+		t.Fatal("Deleting a set without a name should fail")
+	}
+}
@@ -0,0 +1,163 @@
+// +build !linux
+
+package netlink
+
+// NftTableAdd creates a table
+func NftTableAdd(table *NftTable) error {
+	return ErrNotImplemented
+}
+
+// NftTableDel deletes a table along with its chains, rules and sets
+func NftTableDel(table *NftTable) error {
+	return ErrNotImplemented
+}
+
+// NftTableList returns the tables of a family
+func NftTableList(family NftFamily) ([]*NftTable, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftChainAdd creates a chain
+func NftChainAdd(chain *NftChain) error {
+	return ErrNotImplemented
+}
+
+// NftChainDel deletes an empty chain
+func NftChainDel(chain *NftChain) error {
+	return ErrNotImplemented
+}
+
+// NftChainList returns the chains of a family
+func NftChainList(family NftFamily, table string) ([]*NftChain, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftRuleAdd appends a rule to its chain
+func NftRuleAdd(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleInsert inserts a rule at the start of its chain
+func NftRuleInsert(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleDel deletes a rule
+func NftRuleDel(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleList returns the rules of a family
+func NftRuleList(family NftFamily, table, chain string) ([]*NftRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftSetAdd creates a set or a map along with its elements
+func NftSetAdd(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetDel deletes a set or a map
+func NftSetDel(set *NftSet) error {
+	return ErrNotImplemented
+}
+
+// NftSetList returns the sets and maps of a family
+func NftSetList(family NftFamily, table string) ([]*NftSet, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftSetElemAdd adds elements to a set or a map
+func NftSetElemAdd(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetElemDel deletes elements of a set or a map
+func NftSetElemDel(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetElemList returns the elements of a set or a map
+func NftSetElemList(set *NftSet) ([]NftSetElem, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftTableAdd creates a table using the netlink handle passed
+func (h *Handle) NftTableAdd(table *NftTable) error {
+	return ErrNotImplemented
+}
+
+// NftTableDel deletes a table along with its chains, rules and sets using the netlink handle passed
+func (h *Handle) NftTableDel(table *NftTable) error {
+	return ErrNotImplemented
+}
+
+// NftTableList returns the tables of a family using the netlink handle passed
+func (h *Handle) NftTableList(family NftFamily) ([]*NftTable, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftChainAdd creates a chain using the netlink handle passed
+func (h *Handle) NftChainAdd(chain *NftChain) error {
+	return ErrNotImplemented
+}
+
+// NftChainDel deletes an empty chain using the netlink handle passed
+func (h *Handle) NftChainDel(chain *NftChain) error {
+	return ErrNotImplemented
+}
+
+// NftChainList returns the chains of a family using the netlink handle passed
+func (h *Handle) NftChainList(family NftFamily, table string) ([]*NftChain, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftRuleAdd appends a rule to its chain using the netlink handle passed
+func (h *Handle) NftRuleAdd(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleInsert inserts a rule at the start of its chain using the netlink handle passed
+func (h *Handle) NftRuleInsert(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleDel deletes a rule using the netlink handle passed
+func (h *Handle) NftRuleDel(rule *NftRule) error {
+	return ErrNotImplemented
+}
+
+// NftRuleList returns the rules of a family using the netlink handle passed
+func (h *Handle) NftRuleList(family NftFamily, table, chain string) ([]*NftRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftSetAdd creates a set or a map along with its elements using the netlink handle passed
+func (h *Handle) NftSetAdd(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetDel deletes a set or a map using the netlink handle passed
+func (h *Handle) NftSetDel(set *NftSet) error {
+	return ErrNotImplemented
+}
+
+// NftSetList returns the sets and maps of a family using the netlink handle passed
+func (h *Handle) NftSetList(family NftFamily, table string) ([]*NftSet, error) {
+	return nil, ErrNotImplemented
+}
+
+// NftSetElemAdd adds elements to a set or a map using the netlink handle passed
+func (h *Handle) NftSetElemAdd(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetElemDel deletes elements of a set or a map using the netlink handle passed
+func (h *Handle) NftSetElemDel(set *NftSet, elems ...NftSetElem) error {
+	return ErrNotImplemented
+}
+
+// NftSetElemList returns the elements of a set or a map using the netlink handle passed
+func (h *Handle) NftSetElemList(set *NftSet) ([]NftSetElem, error) {
+	return nil, ErrNotImplemented
+}
@@ -31,8 +31,25 @@ var L4ProtoMap = map[uint8]string{
 // 	IPCTNL_MSG_MAX
 // };
 const (
-	IPCTNL_MSG_CT_GET    = 1
-	IPCTNL_MSG_CT_DELETE = 2
+	IPCTNL_MSG_CT_NEW           = 0
+	IPCTNL_MSG_CT_GET           = 1
+	IPCTNL_MSG_CT_DELETE        = 2
+	IPCTNL_MSG_CT_GET_STATS_CPU = 4
+	IPCTNL_MSG_CT_GET_STATS     = 5
+)
+
+// enum ctnl_exp_msg_types {
+// 	IPCTNL_MSG_EXP_NEW,
+// 	IPCTNL_MSG_EXP_GET,
+// 	IPCTNL_MSG_EXP_DELETE,
+// 	IPCTNL_MSG_EXP_GET_STATS_CPU,
+//
+// 	IPCTNL_MSG_EXP_MAX
+// };
+const (
+	IPCTNL_MSG_EXP_NEW    = 0
+	IPCTNL_MSG_EXP_GET    = 1
+	IPCTNL_MSG_EXP_DELETE = 2
 )
 
 // #define NFNETLINK_V0	0
@@ -40,9 +57,32 @@ const (
 	NFNETLINK_V0 = 0
 )
 
+// enum nfnetlink_groups {
+// 	NFNLGRP_NONE,
+// 	NFNLGRP_CONNTRACK_NEW,
+// 	NFNLGRP_CONNTRACK_UPDATE,
+// 	NFNLGRP_CONNTRACK_DESTROY,
+// 	NFNLGRP_CONNTRACK_EXP_NEW,
+// 	NFNLGRP_CONNTRACK_EXP_UPDATE,
+// 	NFNLGRP_CONNTRACK_EXP_DESTROY,
+// 	...
+// };
+const (
+	NFNLGRP_CONNTRACK_NEW         = 1
+	NFNLGRP_CONNTRACK_UPDATE      = 2
+	NFNLGRP_CONNTRACK_DESTROY     = 3
+	NFNLGRP_CONNTRACK_EXP_NEW     = 4
+	NFNLGRP_CONNTRACK_EXP_UPDATE  = 5
+	NFNLGRP_CONNTRACK_EXP_DESTROY = 6
+)
+
 // #define NLA_F_NESTED (1 << 15)
+// #define NLA_F_NET_BYTEORDER (1 << 14)
+// #define NLA_TYPE_MASK ~(NLA_F_NESTED | NLA_F_NET_BYTEORDER)
 const (
-	NLA_F_NESTED = (1 << 15)
+	NLA_F_NESTED        = (1 << 15)
+	NLA_F_NET_BYTEORDER = (1 << 14)
+	NLA_TYPE_MASK       = NLA_F_NET_BYTEORDER - 1
 )
 
 // enum ctattr_type {
@@ -73,6 +113,9 @@ const (
 // 	CTA_MARK_MASK,
 // 	CTA_LABELS,
 // 	CTA_LABELS_MASK,
+// 	CTA_SYNPROXY,
+// 	CTA_FILTER,
+// 	CTA_STATUS_MASK,
 // 	__CTA_MAX
 // };
 const (
@@ -84,6 +127,21 @@ const (
 	CTA_COUNTERS_ORIG  = 9
 	CTA_COUNTERS_REPLY = 10
 	CTA_PROTOINFO      = 4
+	CTA_HELP           = 5
+	CTA_NAT_SRC        = 6
+	CTA_USE            = 11
+	CTA_ID             = 12
+	CTA_NAT_DST        = 13
+	CTA_TUPLE_MASTER   = 14
+	CTA_SEQ_ADJ_ORIG   = 15
+	CTA_SEQ_ADJ_REPLY  = 16
+	CTA_ZONE           = 18
+	CTA_TIMESTAMP      = 20
+	CTA_MARK_MASK      = 21
+	CTA_LABELS         = 22
+	CTA_LABELS_MASK    = 23
+	CTA_FILTER         = 25
+	CTA_STATUS_MASK    = 26
 )
 
 // enum ctattr_tuple {
@@ -97,6 +155,7 @@ const (
 const (
 	CTA_TUPLE_IP    = 1
 	CTA_TUPLE_PROTO = 2
+	CTA_TUPLE_ZONE  = 3
 )
 
 // enum ctattr_ip {
@@ -130,9 +189,15 @@ const (
 // };
 // #define CTA_PROTO_MAX (__CTA_PROTO_MAX - 1)
 const (
-	CTA_PROTO_NUM      = 1
-	CTA_PROTO_SRC_PORT = 2
-	CTA_PROTO_DST_PORT = 3
+	CTA_PROTO_NUM         = 1
+	CTA_PROTO_SRC_PORT    = 2
+	CTA_PROTO_DST_PORT    = 3
+	CTA_PROTO_ICMP_ID     = 4
+	CTA_PROTO_ICMP_TYPE   = 5
+	CTA_PROTO_ICMP_CODE   = 6
+	CTA_PROTO_ICMPV6_ID   = 7
+	CTA_PROTO_ICMPV6_TYPE = 8
+	CTA_PROTO_ICMPV6_CODE = 9
 )
 
 // enum ctattr_protoinfo {
@@ -180,6 +245,215 @@ const (
 	CTA_COUNTERS_BYTES   = 2
 )
 
+// enum ctattr_filter {
+// 	CTA_FILTER_UNSPEC,
+// 	CTA_FILTER_ORIG_FLAGS,
+// 	CTA_FILTER_REPLY_FLAGS,
+// 	__CTA_FILTER_MAX
+// };
+// #define CTA_FILTER_MAX (__CTA_FILTER_MAX - 1)
+const (
+	CTA_FILTER_ORIG_FLAGS  = 1
+	CTA_FILTER_REPLY_FLAGS = 2
+)
+
+// Flags of CTA_FILTER_ORIG_FLAGS and CTA_FILTER_REPLY_FLAGS, from
+// net/netfilter/nf_conntrack_netlink.c
+const (
+	CTA_FILTER_F_CTA_IP_SRC         = 1 << 0
+	CTA_FILTER_F_CTA_IP_DST         = 1 << 1
+	CTA_FILTER_F_CTA_TUPLE_ZONE     = 1 << 2
+	CTA_FILTER_F_CTA_PROTO_NUM      = 1 << 3
+	CTA_FILTER_F_CTA_PROTO_SRC_PORT = 1 << 4
+	CTA_FILTER_F_CTA_PROTO_DST_PORT = 1 << 5
+)
+
+// enum ctattr_expect {
+// 	CTA_EXPECT_UNSPEC,
+// 	CTA_EXPECT_MASTER,
+// 	CTA_EXPECT_TUPLE,
+// 	CTA_EXPECT_MASK,
+// 	CTA_EXPECT_TIMEOUT,
+// 	CTA_EXPECT_ID,
+// 	CTA_EXPECT_HELP_NAME,
+// 	CTA_EXPECT_ZONE,
+// 	CTA_EXPECT_FLAGS,
+// 	CTA_EXPECT_CLASS,
+// 	CTA_EXPECT_NAT,
+// 	CTA_EXPECT_FN,
+// 	__CTA_EXPECT_MAX
+// };
+// #define CTA_EXPECT_MAX (__CTA_EXPECT_MAX - 1)
+const (
+	CTA_EXPECT_MASTER    = 1
+	CTA_EXPECT_TUPLE     = 2
+	CTA_EXPECT_MASK      = 3
+	CTA_EXPECT_TIMEOUT   = 4
+	CTA_EXPECT_ID        = 5
+	CTA_EXPECT_HELP_NAME = 6
+	CTA_EXPECT_ZONE      = 7
+	CTA_EXPECT_FLAGS     = 8
+	CTA_EXPECT_CLASS     = 9
+	CTA_EXPECT_NAT       = 10
+	CTA_EXPECT_FN        = 11
+)
+
+// enum ctattr_expect_nat {
+// 	CTA_EXPECT_NAT_UNSPEC,
+// 	CTA_EXPECT_NAT_DIR,
+// 	CTA_EXPECT_NAT_TUPLE,
+// 	__CTA_EXPECT_NAT_MAX
+// };
+// #define CTA_EXPECT_NAT_MAX (__CTA_EXPECT_NAT_MAX - 1)
+const (
+	CTA_EXPECT_NAT_DIR   = 1
+	CTA_EXPECT_NAT_TUPLE = 2
+)
+
+// Expectation flags, from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nf_conntrack_common.h
+const (
+	NF_CT_EXPECT_PERMANENT = 0x1
+	NF_CT_EXPECT_INACTIVE  = 0x2
+	NF_CT_EXPECT_USERSPACE = 0x4
+)
+
+// enum ctattr_stats_cpu {
+// 	CTA_STATS_UNSPEC,
+// 	CTA_STATS_SEARCHED,	/* no longer used */
+// 	CTA_STATS_FOUND,
+// 	CTA_STATS_NEW,		/* no longer used */
+// 	CTA_STATS_INVALID,
+// 	CTA_STATS_IGNORE,	/* no longer used */
+// 	CTA_STATS_DELETE,	/* no longer used */
+// 	CTA_STATS_DELETE_LIST,	/* no longer used */
+// 	CTA_STATS_INSERT,
+// 	CTA_STATS_INSERT_FAILED,
+// 	CTA_STATS_DROP,
+// 	CTA_STATS_EARLY_DROP,
+// 	CTA_STATS_ERROR,
+// 	CTA_STATS_SEARCH_RESTART,
+// 	CTA_STATS_CLASH_RESOLVE,
+// 	CTA_STATS_CHAIN_TOOLONG,
+// 	__CTA_STATS_MAX,
+// };
+// #define CTA_STATS_MAX (__CTA_STATS_MAX - 1)
+const (
+	CTA_STATS_FOUND          = 2
+	CTA_STATS_INVALID        = 4
+	CTA_STATS_INSERT         = 8
+	CTA_STATS_INSERT_FAILED  = 9
+	CTA_STATS_DROP           = 10
+	CTA_STATS_EARLY_DROP     = 11
+	CTA_STATS_ERROR          = 12
+	CTA_STATS_SEARCH_RESTART = 13
+	CTA_STATS_CLASH_RESOLVE  = 14
+	CTA_STATS_CHAIN_TOOLONG  = 15
+)
+
+// enum ctattr_stats_global {
+// 	CTA_STATS_GLOBAL_UNSPEC,
+// 	CTA_STATS_GLOBAL_ENTRIES,
+// 	CTA_STATS_GLOBAL_MAX_ENTRIES,
+// 	__CTA_STATS_GLOBAL_MAX,
+// };
+// #define CTA_STATS_GLOBAL_MAX (__CTA_STATS_GLOBAL_MAX - 1)
+const (
+	CTA_STATS_GLOBAL_ENTRIES     = 1
+	CTA_STATS_GLOBAL_MAX_ENTRIES = 2
+)
+
+// enum ctattr_tstamp {
+// 	CTA_TIMESTAMP_UNSPEC,
+// 	CTA_TIMESTAMP_START,
+// 	CTA_TIMESTAMP_STOP,
+// 	CTA_TIMESTAMP_PAD,
+// 	__CTA_TIMESTAMP_MAX
+// };
+// #define CTA_TIMESTAMP_MAX (__CTA_TIMESTAMP_MAX - 1)
+const (
+	CTA_TIMESTAMP_START = 1
+	CTA_TIMESTAMP_STOP  = 2
+)
+
+// enum ctattr_seqadj {
+// 	CTA_SEQADJ_UNSPEC,
+// 	CTA_SEQADJ_CORRECTION_POS,
+// 	CTA_SEQADJ_OFFSET_BEFORE,
+// 	CTA_SEQADJ_OFFSET_AFTER,
+// 	__CTA_SEQADJ_MAX
+// };
+// #define CTA_SEQADJ_MAX (__CTA_SEQADJ_MAX - 1)
+const (
+	CTA_SEQADJ_CORRECTION_POS = 1
+	CTA_SEQADJ_OFFSET_BEFORE  = 2
+	CTA_SEQADJ_OFFSET_AFTER   = 3
+)
+
+// enum ctattr_help {
+// 	CTA_HELP_UNSPEC,
+// 	CTA_HELP_NAME,
+// 	CTA_HELP_INFO,
+// 	__CTA_HELP_MAX
+// };
+// #define CTA_HELP_MAX (__CTA_HELP_MAX - 1)
+const (
+	CTA_HELP_NAME = 1
+	CTA_HELP_INFO = 2
+)
+
+// Connection status bits, from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nf_conntrack_common.h
+const (
+	IPS_EXPECTED      = 1 << 0
+	IPS_SEEN_REPLY    = 1 << 1
+	IPS_ASSURED       = 1 << 2
+	IPS_CONFIRMED     = 1 << 3
+	IPS_SRC_NAT       = 1 << 4
+	IPS_DST_NAT       = 1 << 5
+	IPS_SEQ_ADJUST    = 1 << 6
+	IPS_SRC_NAT_DONE  = 1 << 7
+	IPS_DST_NAT_DONE  = 1 << 8
+	IPS_DYING         = 1 << 9
+	IPS_FIXED_TIMEOUT = 1 << 10
+	IPS_TEMPLATE      = 1 << 11
+	IPS_UNTRACKED     = 1 << 12
+	IPS_HELPER        = 1 << 13
+	IPS_OFFLOAD       = 1 << 14
+	IPS_HW_OFFLOAD    = 1 << 15
+)
+
+// enum tcp_conntrack {
+// 	TCP_CONNTRACK_NONE,
+// 	TCP_CONNTRACK_SYN_SENT,
+// 	TCP_CONNTRACK_SYN_RECV,
+// 	TCP_CONNTRACK_ESTABLISHED,
+// 	TCP_CONNTRACK_FIN_WAIT,
+// 	TCP_CONNTRACK_CLOSE_WAIT,
+// 	TCP_CONNTRACK_LAST_ACK,
+// 	TCP_CONNTRACK_TIME_WAIT,
+// 	TCP_CONNTRACK_CLOSE,
+// 	TCP_CONNTRACK_LISTEN,	/* obsolete */
+// #define TCP_CONNTRACK_SYN_SENT2	TCP_CONNTRACK_LISTEN
+// 	TCP_CONNTRACK_MAX,
+// 	TCP_CONNTRACK_IGNORE,
+// 	TCP_CONNTRACK_RETRANS,
+// 	TCP_CONNTRACK_UNACK,
+// 	TCP_CONNTRACK_TIMEOUT_MAX
+// };
+const (
+	TCP_CONNTRACK_NONE        = 0
+	TCP_CONNTRACK_SYN_SENT    = 1
+	TCP_CONNTRACK_SYN_RECV    = 2
+	TCP_CONNTRACK_ESTABLISHED = 3
+	TCP_CONNTRACK_FIN_WAIT    = 4
+	TCP_CONNTRACK_CLOSE_WAIT  = 5
+	TCP_CONNTRACK_LAST_ACK    = 6
+	TCP_CONNTRACK_TIME_WAIT   = 7
+	TCP_CONNTRACK_CLOSE       = 8
+	TCP_CONNTRACK_SYN_SENT2   = 9
+)
+
 // /* General form of address family dependent message.
 //  */
 // struct nfgenmsg {
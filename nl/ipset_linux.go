@@ -0,0 +1,184 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/ipset/ip_set.h
+
+const (
+	NFNL_SUBSYS_IPSET = 6
+)
+
+const (
+	/* The protocol version */
+	IPSET_PROTOCOL = 6
+	/* The max length of strings including NUL: set and type identifiers */
+	IPSET_MAXNAMELEN = 32
+	/* The maximum permissible comment length we will accept over netlink */
+	IPSET_MAX_COMMENT_SIZE = 255
+)
+
+// enum ipset_cmd {
+// 	IPSET_CMD_NONE,
+// 	IPSET_CMD_PROTOCOL,	/* 1: Return protocol version */
+// 	IPSET_CMD_CREATE,	/* 2: Create a new (empty) set */
+// 	IPSET_CMD_DESTROY,	/* 3: Destroy a (empty) set */
+// 	IPSET_CMD_FLUSH,	/* 4: Remove all elements from a set */
+// 	IPSET_CMD_RENAME,	/* 5: Rename a set */
+// 	IPSET_CMD_SWAP,		/* 6: Swap two sets */
+// 	IPSET_CMD_LIST,		/* 7: List sets */
+// 	IPSET_CMD_SAVE,		/* 8: Save sets */
+// 	IPSET_CMD_ADD,		/* 9: Add an element to a set */
+// 	IPSET_CMD_DEL,		/* 10: Delete an element from a set */
+// 	IPSET_CMD_TEST,		/* 11: Test an element in a set */
+// 	IPSET_CMD_HEADER,	/* 12: Get set header data only */
+// 	IPSET_CMD_TYPE,		/* 13: Get set type */
+// 	IPSET_CMD_GET_BYNAME,	/* 14: Get set index by name */
+// 	IPSET_CMD_GET_BYINDEX,	/* 15: Get set name by index */
+// 	IPSET_MSG_MAX,		/* Netlink message commands */
+// };
+const (
+	IPSET_CMD_PROTOCOL = 1
+	IPSET_CMD_CREATE   = 2
+	IPSET_CMD_DESTROY  = 3
+	IPSET_CMD_FLUSH    = 4
+	IPSET_CMD_RENAME   = 5
+	IPSET_CMD_SWAP     = 6
+	IPSET_CMD_LIST     = 7
+	IPSET_CMD_SAVE     = 8
+	IPSET_CMD_ADD      = 9
+	IPSET_CMD_DEL      = 10
+	IPSET_CMD_TEST     = 11
+	IPSET_CMD_HEADER   = 12
+	IPSET_CMD_TYPE     = 13
+)
+
+// Attributes at command level
+// enum {
+// 	IPSET_ATTR_UNSPEC,
+// 	IPSET_ATTR_PROTOCOL,	/* 1: Protocol version */
+// 	IPSET_ATTR_SETNAME,	/* 2: Name of the set */
+// 	IPSET_ATTR_TYPENAME,	/* 3: Typename */
+// 	IPSET_ATTR_SETNAME2 = IPSET_ATTR_TYPENAME, /* Setname at rename/swap */
+// 	IPSET_ATTR_REVISION,	/* 4: Settype revision */
+// 	IPSET_ATTR_FAMILY,	/* 5: Settype family */
+// 	IPSET_ATTR_FLAGS,	/* 6: Flags at command level */
+// 	IPSET_ATTR_DATA,	/* 7: Nested attributes */
+// 	IPSET_ATTR_ADT,		/* 8: Multiple data containers */
+// 	IPSET_ATTR_LINENO,	/* 9: Restore lineno */
+// 	IPSET_ATTR_PROTOCOL_MIN, /* 10: Minimal supported version number */
+// 	IPSET_ATTR_REVISION_MIN	= IPSET_ATTR_PROTOCOL_MIN, /* type rev min */
+// 	IPSET_ATTR_INDEX,	/* 11: Kernel index of set */
+// 	__IPSET_ATTR_CMD_MAX,
+// };
+const (
+	IPSET_ATTR_PROTOCOL     = 1
+	IPSET_ATTR_SETNAME      = 2
+	IPSET_ATTR_TYPENAME     = 3
+	IPSET_ATTR_SETNAME2     = IPSET_ATTR_TYPENAME
+	IPSET_ATTR_REVISION     = 4
+	IPSET_ATTR_FAMILY       = 5
+	IPSET_ATTR_FLAGS        = 6
+	IPSET_ATTR_DATA         = 7
+	IPSET_ATTR_ADT          = 8
+	IPSET_ATTR_LINENO       = 9
+	IPSET_ATTR_PROTOCOL_MIN = 10
+	IPSET_ATTR_REVISION_MIN = IPSET_ATTR_PROTOCOL_MIN
+)
+
+// CADT specific attributes
+const (
+	IPSET_ATTR_IP          = 1
+	IPSET_ATTR_IP_FROM     = IPSET_ATTR_IP
+	IPSET_ATTR_IP_TO       = 2
+	IPSET_ATTR_CIDR        = 3
+	IPSET_ATTR_PORT        = 4
+	IPSET_ATTR_PORT_FROM   = IPSET_ATTR_PORT
+	IPSET_ATTR_PORT_TO     = 5
+	IPSET_ATTR_TIMEOUT     = 6
+	IPSET_ATTR_PROTO       = 7
+	IPSET_ATTR_CADT_FLAGS  = 8
+	IPSET_ATTR_CADT_LINENO = IPSET_ATTR_LINENO
+	IPSET_ATTR_MARK        = 10
+	IPSET_ATTR_MARKMASK    = 11
+	IPSET_ATTR_CADT_MAX    = 16
+)
+
+// Create-only specific attributes
+const (
+	IPSET_ATTR_GC         = 17
+	IPSET_ATTR_HASHSIZE   = 18
+	IPSET_ATTR_MAXELEM    = 19
+	IPSET_ATTR_NETMASK    = 20
+	IPSET_ATTR_BUCKETSIZE = 21
+	IPSET_ATTR_RESIZE     = 22
+	IPSET_ATTR_SIZE       = 23
+	// Kernel-only
+	IPSET_ATTR_ELEMENTS   = 24
+	IPSET_ATTR_REFERENCES = 25
+	IPSET_ATTR_MEMSIZE    = 26
+)
+
+// ADT specific attributes
+const (
+	IPSET_ATTR_ETHER    = IPSET_ATTR_CADT_MAX + 1
+	IPSET_ATTR_NAME     = 18
+	IPSET_ATTR_NAMEREF  = 19
+	IPSET_ATTR_IP2      = 20
+	IPSET_ATTR_CIDR2    = 21
+	IPSET_ATTR_IP2_TO   = 22
+	IPSET_ATTR_IFACE    = 23
+	IPSET_ATTR_BYTES    = 24
+	IPSET_ATTR_PACKETS  = 25
+	IPSET_ATTR_COMMENT  = 26
+	IPSET_ATTR_SKBMARK  = 27
+	IPSET_ATTR_SKBPRIO  = 28
+	IPSET_ATTR_SKBQUEUE = 29
+)
+
+// IP specific attributes
+const (
+	IPSET_ATTR_IPADDR_IPV4 = 1
+	IPSET_ATTR_IPADDR_IPV6 = 2
+)
+
+// Error codes, above the errno values
+const (
+	IPSET_ERR_PRIVATE          = 4096
+	IPSET_ERR_PROTOCOL         = 4097
+	IPSET_ERR_FIND_TYPE        = 4098
+	IPSET_ERR_MAX_SETS         = 4099
+	IPSET_ERR_BUSY             = 4100
+	IPSET_ERR_EXIST_SETNAME2   = 4101
+	IPSET_ERR_TYPE_MISMATCH    = 4102
+	IPSET_ERR_EXIST            = 4103
+	IPSET_ERR_INVALID_CIDR     = 4104
+	IPSET_ERR_INVALID_NETMASK  = 4105
+	IPSET_ERR_INVALID_FAMILY   = 4106
+	IPSET_ERR_TIMEOUT          = 4107
+	IPSET_ERR_REFERENCED       = 4108
+	IPSET_ERR_IPADDR_IPV4      = 4109
+	IPSET_ERR_IPADDR_IPV6      = 4110
+	IPSET_ERR_COUNTER          = 4111
+	IPSET_ERR_COMMENT          = 4112
+	IPSET_ERR_INVALID_MARKMASK = 4113
+	IPSET_ERR_SKBINFO          = 4114
+	IPSET_ERR_TYPE_SPECIFIC    = 4352
+)
+
+// Flags at command level
+const (
+	IPSET_FLAG_EXIST        = 1 << 0
+	IPSET_FLAG_LIST_SETNAME = 1 << 1
+	IPSET_FLAG_LIST_HEADER  = 1 << 2
+)
+
+// Flags at CADT attribute level
+const (
+	IPSET_FLAG_BEFORE         = 1 << 0
+	IPSET_FLAG_PHYSDEV        = 1 << 1
+	IPSET_FLAG_NOMATCH        = 1 << 2
+	IPSET_FLAG_WITH_COUNTERS  = 1 << 3
+	IPSET_FLAG_WITH_COMMENT   = 1 << 4
+	IPSET_FLAG_WITH_FORCEADD  = 1 << 5
+	IPSET_FLAG_WITH_SKBINFO   = 1 << 6
+	IPSET_FLAG_IFACE_WILDCARD = 1 << 7
+)
@@ -186,7 +186,18 @@ const (
 	IFLA_GRE_ENCAP_SPORT
 	IFLA_GRE_ENCAP_DPORT
 	IFLA_GRE_COLLECT_METADATA
-	IFLA_GRE_MAX = IFLA_GRE_COLLECT_METADATA
+	IFLA_GRE_IGNORE_DF
+	IFLA_GRE_FWMARK
+	IFLA_GRE_ERSPAN_INDEX
+	IFLA_GRE_ERSPAN_VER
+	IFLA_GRE_ERSPAN_DIR
+	IFLA_GRE_ERSPAN_HWID
+	IFLA_GRE_MAX = IFLA_GRE_ERSPAN_HWID
+)
+
+const (
+	ERSPAN_DIRECTION_INGRESS = iota
+	ERSPAN_DIRECTION_EGRESS
 )
 
 const (
@@ -494,7 +505,53 @@ const (
 	IFLA_IPTUN_ENCAP_SPORT
 	IFLA_IPTUN_ENCAP_DPORT
 	IFLA_IPTUN_COLLECT_METADATA
-	IFLA_IPTUN_MAX = IFLA_IPTUN_COLLECT_METADATA
+	IFLA_IPTUN_FWMARK
+	IFLA_IPTUN_MAX = IFLA_IPTUN_FWMARK
+)
+
+const (
+	IP6_TNL_F_IGN_ENCAP_LIMIT    = 0x1
+	IP6_TNL_F_USE_ORIG_TCLASS    = 0x2
+	IP6_TNL_F_USE_ORIG_FLOWLABEL = 0x4
+	IP6_TNL_F_MIP6_DEV           = 0x8
+	IP6_TNL_F_RCV_DSCP_COPY      = 0x10
+	IP6_TNL_F_USE_ORIG_FWMARK    = 0x20
+	IP6_TNL_F_ALLOW_LOCAL_REMOTE = 0x40
+	IP6_TNL_F_CAP_XMIT           = 0x10000
+	IP6_TNL_F_CAP_RCV            = 0x20000
+	IP6_TNL_F_CAP_PER_PACKET     = 0x40000
+)
+
+const (
+	IPV6_FLOWINFO_FLOWLABEL = 0x000fffff
+	IPV6_FLOWINFO_PRIORITY  = 0x0ff00000
+)
+
+const (
+	IFLA_GENEVE_UNSPEC = iota
+	IFLA_GENEVE_ID
+	IFLA_GENEVE_REMOTE
+	IFLA_GENEVE_TTL
+	IFLA_GENEVE_TOS
+	IFLA_GENEVE_PORT
+	IFLA_GENEVE_COLLECT_METADATA
+	IFLA_GENEVE_REMOTE6
+	IFLA_GENEVE_UDP_CSUM
+	IFLA_GENEVE_UDP_ZERO_CSUM6_TX
+	IFLA_GENEVE_UDP_ZERO_CSUM6_RX
+	IFLA_GENEVE_LABEL
+	IFLA_GENEVE_TTL_INHERIT
+	IFLA_GENEVE_DF
+	IFLA_GENEVE_MAX = IFLA_GENEVE_DF
+)
+
+const (
+	IFLA_BAREUDP_UNSPEC = iota
+	IFLA_BAREUDP_PORT
+	IFLA_BAREUDP_ETHERTYPE
+	IFLA_BAREUDP_SRCPORT_MIN
+	IFLA_BAREUDP_MULTIPROTO_MODE
+	IFLA_BAREUDP_MAX = IFLA_BAREUDP_MULTIPROTO_MODE
 )
 
 const (
@@ -573,3 +630,35 @@ const (
 	GTP_ROLE_GGSN = iota
 	GTP_ROLE_SGSN
 )
+
+const (
+	IFLA_MACSEC_UNSPEC = iota
+	IFLA_MACSEC_SCI
+	IFLA_MACSEC_PORT
+	IFLA_MACSEC_ICV_LEN
+	IFLA_MACSEC_CIPHER_SUITE
+	IFLA_MACSEC_WINDOW
+	IFLA_MACSEC_ENCODING_SA
+	IFLA_MACSEC_ENCRYPT
+	IFLA_MACSEC_PROTECT
+	IFLA_MACSEC_INC_SCI
+	IFLA_MACSEC_ES
+	IFLA_MACSEC_SCB
+	IFLA_MACSEC_REPLAY_PROTECT
+	IFLA_MACSEC_VALIDATION
+	IFLA_MACSEC_PAD
+	IFLA_MACSEC_OFFLOAD
+	IFLA_MACSEC_MAX = IFLA_MACSEC_OFFLOAD
+)
+
+const (
+	MACSEC_VALIDATE_DISABLED = iota
+	MACSEC_VALIDATE_CHECK
+	MACSEC_VALIDATE_STRICT
+)
+
+const (
+	MACSEC_OFFLOAD_OFF = iota
+	MACSEC_OFFLOAD_PHY
+	MACSEC_OFFLOAD_MAC
+)
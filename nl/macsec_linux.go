@@ -0,0 +1,218 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/if_macsec.h
+
+const (
+	MACSEC_GENL_NAME    = "macsec"
+	MACSEC_GENL_VERSION = 1
+)
+
+const (
+	MACSEC_KEYID_LEN      = 16
+	MACSEC_SALT_LEN       = 12
+	MACSEC_MIN_ICV_LEN    = 8
+	MACSEC_MAX_ICV_LEN    = 32
+	MACSEC_STD_ICV_LEN    = 16
+	MACSEC_DEFAULT_PN_LEN = 4
+	MACSEC_XPN_PN_LEN     = 8
+)
+
+// enum macsec_attrs {
+// 	MACSEC_ATTR_UNSPEC,
+// 	MACSEC_ATTR_IFINDEX,     /* u32, ifindex of the MACsec netdevice */
+// 	MACSEC_ATTR_RXSC_CONFIG, /* config, nested macsec_rxsc_attrs */
+// 	MACSEC_ATTR_SA_CONFIG,   /* config, nested macsec_sa_attrs */
+// 	MACSEC_ATTR_SECY,        /* dump, nested macsec_secy_attrs */
+// 	MACSEC_ATTR_TXSA_LIST,   /* dump, nested, macsec_sa_attrs for each TXSA */
+// 	MACSEC_ATTR_RXSC_LIST,   /* dump, nested, macsec_rxsc_attrs for each RXSC */
+// 	MACSEC_ATTR_TXSC_STATS,  /* dump, nested, macsec_txsc_stats_attr */
+// 	MACSEC_ATTR_SECY_STATS,  /* dump, nested, macsec_secy_stats_attr */
+// 	MACSEC_ATTR_OFFLOAD,     /* config, nested, macsec_offload_attrs */
+// 	__MACSEC_ATTR_END,
+// 	NUM_MACSEC_ATTR = __MACSEC_ATTR_END,
+// 	MACSEC_ATTR_MAX = __MACSEC_ATTR_END - 1,
+// };
+const (
+	MACSEC_ATTR_UNSPEC      = 0
+	MACSEC_ATTR_IFINDEX     = 1
+	MACSEC_ATTR_RXSC_CONFIG = 2
+	MACSEC_ATTR_SA_CONFIG   = 3
+	MACSEC_ATTR_SECY        = 4
+	MACSEC_ATTR_TXSA_LIST   = 5
+	MACSEC_ATTR_RXSC_LIST   = 6
+	MACSEC_ATTR_TXSC_STATS  = 7
+	MACSEC_ATTR_SECY_STATS  = 8
+	MACSEC_ATTR_OFFLOAD     = 9
+)
+
+// enum macsec_secy_attrs {
+// 	MACSEC_SECY_ATTR_UNSPEC,
+// 	MACSEC_SECY_ATTR_SCI,
+// 	MACSEC_SECY_ATTR_ENCODING_SA,
+// 	MACSEC_SECY_ATTR_WINDOW,
+// 	MACSEC_SECY_ATTR_CIPHER_SUITE,
+// 	MACSEC_SECY_ATTR_ICV_LEN,
+// 	MACSEC_SECY_ATTR_PROTECT,
+// 	MACSEC_SECY_ATTR_REPLAY,
+// 	MACSEC_SECY_ATTR_OPER,
+// 	MACSEC_SECY_ATTR_VALIDATE,
+// 	MACSEC_SECY_ATTR_ENCRYPT,
+// 	MACSEC_SECY_ATTR_INC_SCI,
+// 	MACSEC_SECY_ATTR_ES,
+// 	MACSEC_SECY_ATTR_SCB,
+// 	MACSEC_SECY_ATTR_PAD,
+// 	__MACSEC_SECY_ATTR_END,
+// };
+const (
+	MACSEC_SECY_ATTR_UNSPEC       = 0
+	MACSEC_SECY_ATTR_SCI          = 1
+	MACSEC_SECY_ATTR_ENCODING_SA  = 2
+	MACSEC_SECY_ATTR_WINDOW       = 3
+	MACSEC_SECY_ATTR_CIPHER_SUITE = 4
+	MACSEC_SECY_ATTR_ICV_LEN      = 5
+	MACSEC_SECY_ATTR_PROTECT      = 6
+	MACSEC_SECY_ATTR_REPLAY       = 7
+	MACSEC_SECY_ATTR_OPER         = 8
+	MACSEC_SECY_ATTR_VALIDATE     = 9
+	MACSEC_SECY_ATTR_ENCRYPT      = 10
+	MACSEC_SECY_ATTR_INC_SCI      = 11
+	MACSEC_SECY_ATTR_ES           = 12
+	MACSEC_SECY_ATTR_SCB          = 13
+	MACSEC_SECY_ATTR_PAD          = 14
+)
+
+// enum macsec_rxsc_attrs {
+// 	MACSEC_RXSC_ATTR_UNSPEC,
+// 	MACSEC_RXSC_ATTR_SCI,     /* config/dump, u64 */
+// 	MACSEC_RXSC_ATTR_ACTIVE,  /* config/dump, u8 0..1 */
+// 	MACSEC_RXSC_ATTR_SA_LIST, /* dump, nested */
+// 	MACSEC_RXSC_ATTR_STATS,   /* dump, nested, macsec_rxsc_stats_attr */
+// 	MACSEC_RXSC_ATTR_PAD,
+// 	__MACSEC_RXSC_ATTR_END,
+// };
+const (
+	MACSEC_RXSC_ATTR_UNSPEC  = 0
+	MACSEC_RXSC_ATTR_SCI     = 1
+	MACSEC_RXSC_ATTR_ACTIVE  = 2
+	MACSEC_RXSC_ATTR_SA_LIST = 3
+	MACSEC_RXSC_ATTR_STATS   = 4
+	MACSEC_RXSC_ATTR_PAD     = 5
+)
+
+// enum macsec_sa_attrs {
+// 	MACSEC_SA_ATTR_UNSPEC,
+// 	MACSEC_SA_ATTR_AN,     /* config/dump, u8 0..3 */
+// 	MACSEC_SA_ATTR_ACTIVE, /* config/dump, u8 0..1 */
+// 	MACSEC_SA_ATTR_PN,     /* config/dump, u32/u64 (u64 if XPN) */
+// 	MACSEC_SA_ATTR_KEY,    /* config, data */
+// 	MACSEC_SA_ATTR_KEYID,  /* config/dump, 128-bit */
+// 	MACSEC_SA_ATTR_STATS,  /* dump, nested, macsec_sa_stats_attr */
+// 	MACSEC_SA_ATTR_PAD,
+// 	MACSEC_SA_ATTR_SSCI,   /* config/dump, u32 - XPN only */
+// 	MACSEC_SA_ATTR_SALT,   /* config, 96-bit - XPN only */
+// 	__MACSEC_SA_ATTR_END,
+// };
+const (
+	MACSEC_SA_ATTR_UNSPEC = 0
+	MACSEC_SA_ATTR_AN     = 1
+	MACSEC_SA_ATTR_ACTIVE = 2
+	MACSEC_SA_ATTR_PN     = 3
+	MACSEC_SA_ATTR_KEY    = 4
+	MACSEC_SA_ATTR_KEYID  = 5
+	MACSEC_SA_ATTR_STATS  = 6
+	MACSEC_SA_ATTR_PAD    = 7
+	MACSEC_SA_ATTR_SSCI   = 8
+	MACSEC_SA_ATTR_SALT   = 9
+)
+
+// enum macsec_offload_attrs {
+// 	MACSEC_OFFLOAD_ATTR_UNSPEC,
+// 	MACSEC_OFFLOAD_ATTR_TYPE, /* config/dump, u8 0..2 */
+// 	MACSEC_OFFLOAD_ATTR_PAD,
+// 	__MACSEC_OFFLOAD_ATTR_END,
+// };
+const (
+	MACSEC_OFFLOAD_ATTR_UNSPEC = 0
+	MACSEC_OFFLOAD_ATTR_TYPE   = 1
+	MACSEC_OFFLOAD_ATTR_PAD    = 2
+)
+
+// enum macsec_nl_commands {
+// 	MACSEC_CMD_GET_TXSC,
+// 	MACSEC_CMD_ADD_RXSC,
+// 	MACSEC_CMD_DEL_RXSC,
+// 	MACSEC_CMD_UPD_RXSC,
+// 	MACSEC_CMD_ADD_TXSA,
+// 	MACSEC_CMD_DEL_TXSA,
+// 	MACSEC_CMD_UPD_TXSA,
+// 	MACSEC_CMD_ADD_RXSA,
+// 	MACSEC_CMD_DEL_RXSA,
+// 	MACSEC_CMD_UPD_RXSA,
+// 	MACSEC_CMD_UPD_OFFLOAD,
+// };
+const (
+	MACSEC_CMD_GET_TXSC    = 0
+	MACSEC_CMD_ADD_RXSC    = 1
+	MACSEC_CMD_DEL_RXSC    = 2
+	MACSEC_CMD_UPD_RXSC    = 3
+	MACSEC_CMD_ADD_TXSA    = 4
+	MACSEC_CMD_DEL_TXSA    = 5
+	MACSEC_CMD_UPD_TXSA    = 6
+	MACSEC_CMD_ADD_RXSA    = 7
+	MACSEC_CMD_DEL_RXSA    = 8
+	MACSEC_CMD_UPD_RXSA    = 9
+	MACSEC_CMD_UPD_OFFLOAD = 10
+)
+
+// u64 per-RXSC stats
+const (
+	MACSEC_RXSC_STATS_ATTR_UNSPEC               = 0
+	MACSEC_RXSC_STATS_ATTR_IN_OCTETS_VALIDATED  = 1
+	MACSEC_RXSC_STATS_ATTR_IN_OCTETS_DECRYPTED  = 2
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNCHECKED    = 3
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_DELAYED      = 4
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_OK           = 5
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_INVALID      = 6
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_LATE         = 7
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_VALID    = 8
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_USING_SA = 9
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNUSED_SA    = 10
+	MACSEC_RXSC_STATS_ATTR_PAD                  = 11
+)
+
+// u32 per-{RX,TX}SA stats
+const (
+	MACSEC_SA_STATS_ATTR_UNSPEC               = 0
+	MACSEC_SA_STATS_ATTR_IN_PKTS_OK           = 1
+	MACSEC_SA_STATS_ATTR_IN_PKTS_INVALID      = 2
+	MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_VALID    = 3
+	MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_USING_SA = 4
+	MACSEC_SA_STATS_ATTR_IN_PKTS_UNUSED_SA    = 5
+	MACSEC_SA_STATS_ATTR_OUT_PKTS_PROTECTED   = 6
+	MACSEC_SA_STATS_ATTR_OUT_PKTS_ENCRYPTED   = 7
+)
+
+// u64 per-TXSC stats
+const (
+	MACSEC_TXSC_STATS_ATTR_UNSPEC               = 0
+	MACSEC_TXSC_STATS_ATTR_OUT_PKTS_PROTECTED   = 1
+	MACSEC_TXSC_STATS_ATTR_OUT_PKTS_ENCRYPTED   = 2
+	MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_PROTECTED = 3
+	MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_ENCRYPTED = 4
+	MACSEC_TXSC_STATS_ATTR_PAD                  = 5
+)
+
+// u64 per-SecY stats
+const (
+	MACSEC_SECY_STATS_ATTR_UNSPEC              = 0
+	MACSEC_SECY_STATS_ATTR_OUT_PKTS_UNTAGGED   = 1
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_UNTAGGED    = 2
+	MACSEC_SECY_STATS_ATTR_OUT_PKTS_TOO_LONG   = 3
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_TAG      = 4
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_BAD_TAG     = 5
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_UNKNOWN_SCI = 6
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_SCI      = 7
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_OVERRUN     = 8
+	MACSEC_SECY_STATS_ATTR_PAD                 = 9
+)
@@ -0,0 +1,117 @@
+package nl
+
+import (
+	"unsafe"
+)
+
+// Nexthop object messages, linux/rtnetlink.h
+const (
+	RTM_NEWNEXTHOP = 0x68
+	RTM_DELNEXTHOP = 0x69
+	RTM_GETNEXTHOP = 0x6a
+)
+
+// Multicast group for nexthop object notifications
+const RTNLGRP_NEXTHOP = 0x20
+
+// Route attribute referencing a nexthop object
+const RTA_NH_ID = 0x1e
+
+// Nexthop object attributes, linux/nexthop.h
+const (
+	NHA_UNSPEC = iota
+	NHA_ID
+	NHA_GROUP
+	NHA_GROUP_TYPE
+	NHA_BLACKHOLE
+	NHA_OIF
+	NHA_GATEWAY
+	NHA_ENCAP_TYPE
+	NHA_ENCAP
+	NHA_GROUPS
+	NHA_MASTER
+	NHA_FDB
+	NHA_RES_GROUP
+	NHA_RES_BUCKET
+	NHA_MAX = NHA_RES_BUCKET
+)
+
+// Nexthop group types
+const (
+	NEXTHOP_GRP_TYPE_MPATH = iota /* hash-threshold nexthop group */
+	NEXTHOP_GRP_TYPE_RES          /* resilient nexthop group */
+)
+
+// Resilient nexthop group attributes, nested in NHA_RES_GROUP
+const (
+	NHA_RES_GROUP_PAD = iota
+	NHA_RES_GROUP_BUCKETS
+	NHA_RES_GROUP_IDLE_TIMER
+	NHA_RES_GROUP_UNBALANCED_TIMER
+	NHA_RES_GROUP_UNBALANCED_TIME
+)
+
+const (
+	SizeofNhMsg      = 0x08
+	SizeofNexthopGrp = 0x08
+)
+
+// struct nhmsg {
+//   unsigned char nh_family;
+//   unsigned char nh_scope;     /* return only */
+//   unsigned char nh_protocol;  /* Routing protocol that installed nh */
+//   unsigned char resvd;
+//   unsigned int  nh_flags;     /* RTNH_F flags */
+// };
+
+type NhMsg struct {
+	Family   uint8
+	Scope    uint8
+	Protocol uint8
+	Resvd    uint8
+	Flags    uint32
+}
+
+func NewNhMsg(family int) *NhMsg {
+	return &NhMsg{
+		Family: uint8(family),
+	}
+}
+
+func (msg *NhMsg) Len() int {
+	return SizeofNhMsg
+}
+
+func DeserializeNhMsg(b []byte) *NhMsg {
+	return (*NhMsg)(unsafe.Pointer(&b[0:SizeofNhMsg][0]))
+}
+
+func (msg *NhMsg) Serialize() []byte {
+	return (*(*[SizeofNhMsg]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct nexthop_grp {
+//   __u32 id;     /* nexthop id - must exist */
+//   __u8  weight; /* weight of this nexthop */
+//   __u8  resvd1;
+//   __u16 resvd2;
+// };
+
+type NexthopGrp struct {
+	Id     uint32
+	Weight uint8
+	Resvd1 uint8
+	Resvd2 uint16
+}
+
+func (msg *NexthopGrp) Len() int {
+	return SizeofNexthopGrp
+}
+
+func DeserializeNexthopGrp(b []byte) *NexthopGrp {
+	return (*NexthopGrp)(unsafe.Pointer(&b[0:SizeofNexthopGrp][0]))
+}
+
+func (msg *NexthopGrp) Serialize() []byte {
+	return (*(*[SizeofNexthopGrp]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -0,0 +1,122 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nfnetlink_log.h
+
+const (
+	NFNL_SUBSYS_ULOG = 4
+)
+
+// Track the message sizes for the correct serialization/deserialization
+const (
+	SizeofNfulnlMsgPacketHdr  = 4
+	SizeofNfulnlMsgConfigCmd  = 1
+	SizeofNfulnlMsgConfigMode = 6
+)
+
+// enum nfulnl_msg_types {
+// 	NFULNL_MSG_PACKET,		/* packet from kernel to userspace */
+// 	NFULNL_MSG_CONFIG,		/* connect to a particular queue */
+//
+// 	NFULNL_MSG_MAX
+// };
+const (
+	NFULNL_MSG_PACKET = 0
+	NFULNL_MSG_CONFIG = 1
+)
+
+// enum nfulnl_attr_type {
+// 	NFULA_UNSPEC,
+// 	NFULA_PACKET_HDR,
+// 	NFULA_MARK,			/* __u32 nfmark */
+// 	NFULA_TIMESTAMP,		/* nfulnl_msg_packet_timestamp */
+// 	NFULA_IFINDEX_INDEV,		/* __u32 ifindex */
+// 	NFULA_IFINDEX_OUTDEV,		/* __u32 ifindex */
+// 	NFULA_IFINDEX_PHYSINDEV,	/* __u32 ifindex */
+// 	NFULA_IFINDEX_PHYSOUTDEV,	/* __u32 ifindex */
+// 	NFULA_HWADDR,			/* nfulnl_msg_packet_hw */
+// 	NFULA_PAYLOAD,			/* opaque data payload */
+// 	NFULA_PREFIX,			/* string prefix */
+// 	NFULA_UID,			/* user id of socket */
+// 	NFULA_SEQ,			/* instance-local sequence number */
+// 	NFULA_SEQ_GLOBAL,		/* global sequence number */
+// 	NFULA_GID,			/* group id of socket */
+// 	NFULA_HWTYPE,			/* hardware type */
+// 	NFULA_HWHEADER,			/* hardware header */
+// 	NFULA_HWLEN,			/* hardware header length */
+// 	NFULA_CT,                       /* nfnetlink_conntrack.h */
+// 	NFULA_CT_INFO,                  /* enum ip_conntrack_info */
+// 	NFULA_VLAN,			/* nested attribute: packet vlan info */
+// 	NFULA_L2HDR,			/* full L2 header */
+//
+// 	__NFULA_MAX
+// };
+const (
+	NFULA_PACKET_HDR         = 1
+	NFULA_MARK               = 2
+	NFULA_TIMESTAMP          = 3
+	NFULA_IFINDEX_INDEV      = 4
+	NFULA_IFINDEX_OUTDEV     = 5
+	NFULA_IFINDEX_PHYSINDEV  = 6
+	NFULA_IFINDEX_PHYSOUTDEV = 7
+	NFULA_HWADDR             = 8
+	NFULA_PAYLOAD            = 9
+	NFULA_PREFIX             = 10
+	NFULA_UID                = 11
+	NFULA_SEQ                = 12
+	NFULA_SEQ_GLOBAL         = 13
+	NFULA_GID                = 14
+	NFULA_HWTYPE             = 15
+	NFULA_HWHEADER           = 16
+	NFULA_HWLEN              = 17
+	NFULA_CT                 = 18
+	NFULA_CT_INFO            = 19
+	NFULA_VLAN               = 20
+	NFULA_L2HDR              = 21
+)
+
+// enum nfulnl_msg_config_cmds {
+// 	NFULNL_CFG_CMD_NONE,
+// 	NFULNL_CFG_CMD_BIND,
+// 	NFULNL_CFG_CMD_UNBIND,
+// 	NFULNL_CFG_CMD_PF_BIND,
+// 	NFULNL_CFG_CMD_PF_UNBIND,
+// };
+const (
+	NFULNL_CFG_CMD_NONE      = 0
+	NFULNL_CFG_CMD_BIND      = 1
+	NFULNL_CFG_CMD_UNBIND    = 2
+	NFULNL_CFG_CMD_PF_BIND   = 3
+	NFULNL_CFG_CMD_PF_UNBIND = 4
+)
+
+// enum nfulnl_attr_config {
+// 	NFULA_CFG_UNSPEC,
+// 	NFULA_CFG_CMD,			/* nfulnl_msg_config_cmd */
+// 	NFULA_CFG_MODE,			/* nfulnl_msg_config_mode */
+// 	NFULA_CFG_NLBUFSIZ,		/* __u32 buffer size */
+// 	NFULA_CFG_TIMEOUT,		/* __u32 in 1/100 s */
+// 	NFULA_CFG_QTHRESH,		/* __u32 */
+// 	NFULA_CFG_FLAGS,		/* __u16 */
+// 	__NFULA_CFG_MAX
+// };
+const (
+	NFULA_CFG_CMD      = 1
+	NFULA_CFG_MODE     = 2
+	NFULA_CFG_NLBUFSIZ = 3
+	NFULA_CFG_TIMEOUT  = 4
+	NFULA_CFG_QTHRESH  = 5
+	NFULA_CFG_FLAGS    = 6
+)
+
+const (
+	NFULNL_COPY_NONE   = 0x00
+	NFULNL_COPY_META   = 0x01
+	NFULNL_COPY_PACKET = 0x02
+)
+
+const (
+	NFULNL_CFG_F_SEQ        = 0x0001
+	NFULNL_CFG_F_SEQ_GLOBAL = 0x0002
+	NFULNL_CFG_F_CONNTRACK  = 0x0004
+)
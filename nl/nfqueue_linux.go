@@ -0,0 +1,144 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nfnetlink_queue.h
+
+const (
+	NFNL_SUBSYS_QUEUE = 3
+)
+
+// Track the message sizes for the correct serialization/deserialization
+const (
+	SizeofNfqnlMsgPacketHdr       = 7
+	SizeofNfqnlMsgPacketHw        = 12
+	SizeofNfqnlMsgPacketTimestamp = 16
+	SizeofNfqnlMsgVerdictHdr      = 8
+	SizeofNfqnlMsgConfigCmd       = 4
+	SizeofNfqnlMsgConfigParams    = 5
+)
+
+// enum nfqnl_msg_types {
+// 	NFQNL_MSG_PACKET,		/* packet from kernel to userspace */
+// 	NFQNL_MSG_VERDICT,		/* verdict from userspace to kernel */
+// 	NFQNL_MSG_CONFIG,		/* connect to a particular queue */
+// 	NFQNL_MSG_VERDICT_BATCH,	/* batchv from userspace to kernel */
+//
+// 	NFQNL_MSG_MAX
+// };
+const (
+	NFQNL_MSG_PACKET        = 0
+	NFQNL_MSG_VERDICT       = 1
+	NFQNL_MSG_CONFIG        = 2
+	NFQNL_MSG_VERDICT_BATCH = 3
+)
+
+// enum nfqnl_attr_type {
+// 	NFQA_UNSPEC,
+// 	NFQA_PACKET_HDR,
+// 	NFQA_VERDICT_HDR,		/* nfqnl_msg_verdict_hrd */
+// 	NFQA_MARK,			/* __u32 nfmark */
+// 	NFQA_TIMESTAMP,			/* nfqnl_msg_packet_timestamp */
+// 	NFQA_IFINDEX_INDEV,		/* __u32 ifindex */
+// 	NFQA_IFINDEX_OUTDEV,		/* __u32 ifindex */
+// 	NFQA_IFINDEX_PHYSINDEV,		/* __u32 ifindex */
+// 	NFQA_IFINDEX_PHYSOUTDEV,	/* __u32 ifindex */
+// 	NFQA_HWADDR,			/* nfqnl_msg_packet_hw */
+// 	NFQA_PAYLOAD,			/* opaque data payload */
+// 	NFQA_CT,			/* nfnetlink_conntrack.h */
+// 	NFQA_CT_INFO,			/* enum ip_conntrack_info */
+// 	NFQA_CAP_LEN,			/* __u32 length of captured packet */
+// 	NFQA_SKB_INFO,			/* __u32 skb meta information */
+// 	NFQA_EXP,			/* nfnetlink_conntrack.h */
+// 	NFQA_UID,			/* __u32 sk uid */
+// 	NFQA_GID,			/* __u32 sk gid */
+// 	NFQA_SECCTX,			/* security context string */
+// 	NFQA_VLAN,			/* nested attribute: packet vlan info */
+// 	NFQA_L2HDR,			/* full L2 header */
+// 	NFQA_PRIORITY,			/* skb->priority */
+// 	NFQA_CGROUP_CLASSID,		/* __u32 cgroup classid */
+//
+// 	__NFQA_MAX
+// };
+const (
+	NFQA_PACKET_HDR         = 1
+	NFQA_VERDICT_HDR        = 2
+	NFQA_MARK               = 3
+	NFQA_TIMESTAMP          = 4
+	NFQA_IFINDEX_INDEV      = 5
+	NFQA_IFINDEX_OUTDEV     = 6
+	NFQA_IFINDEX_PHYSINDEV  = 7
+	NFQA_IFINDEX_PHYSOUTDEV = 8
+	NFQA_HWADDR             = 9
+	NFQA_PAYLOAD            = 10
+	NFQA_CT                 = 11
+	NFQA_CT_INFO            = 12
+	NFQA_CAP_LEN            = 13
+	NFQA_SKB_INFO           = 14
+	NFQA_EXP                = 15
+	NFQA_UID                = 16
+	NFQA_GID                = 17
+	NFQA_SECCTX             = 18
+	NFQA_VLAN               = 19
+	NFQA_L2HDR              = 20
+	NFQA_PRIORITY           = 21
+	NFQA_CGROUP_CLASSID     = 22
+)
+
+// enum nfqnl_msg_config_cmds {
+// 	NFQNL_CFG_CMD_NONE,
+// 	NFQNL_CFG_CMD_BIND,
+// 	NFQNL_CFG_CMD_UNBIND,
+// 	NFQNL_CFG_CMD_PF_BIND,
+// 	NFQNL_CFG_CMD_PF_UNBIND,
+// };
+const (
+	NFQNL_CFG_CMD_NONE      = 0
+	NFQNL_CFG_CMD_BIND      = 1
+	NFQNL_CFG_CMD_UNBIND    = 2
+	NFQNL_CFG_CMD_PF_BIND   = 3
+	NFQNL_CFG_CMD_PF_UNBIND = 4
+)
+
+// enum nfqnl_config_mode {
+// 	NFQNL_COPY_NONE,
+// 	NFQNL_COPY_META,
+// 	NFQNL_COPY_PACKET,
+// };
+const (
+	NFQNL_COPY_NONE   = 0
+	NFQNL_COPY_META   = 1
+	NFQNL_COPY_PACKET = 2
+)
+
+// enum nfqnl_attr_config {
+// 	NFQA_CFG_UNSPEC,
+// 	NFQA_CFG_CMD,			/* nfqnl_msg_config_cmd */
+// 	NFQA_CFG_PARAMS,		/* nfqnl_msg_config_params */
+// 	NFQA_CFG_QUEUE_MAXLEN,		/* __u32 */
+// 	NFQA_CFG_MASK,			/* identify which flags to change */
+// 	NFQA_CFG_FLAGS,			/* value of these flags (__u32) */
+// 	__NFQA_CFG_MAX
+// };
+const (
+	NFQA_CFG_CMD          = 1
+	NFQA_CFG_PARAMS       = 2
+	NFQA_CFG_QUEUE_MAXLEN = 3
+	NFQA_CFG_MASK         = 4
+	NFQA_CFG_FLAGS        = 5
+)
+
+// Flags for NFQA_CFG_FLAGS
+const (
+	NFQA_CFG_F_FAIL_OPEN = 1 << 0
+	NFQA_CFG_F_CONNTRACK = 1 << 1
+	NFQA_CFG_F_GSO       = 1 << 2
+	NFQA_CFG_F_UID_GID   = 1 << 3
+	NFQA_CFG_F_SECCTX    = 1 << 4
+)
+
+// Flags for NFQA_SKB_INFO
+const (
+	NFQA_SKB_CSUMNOTREADY     = 1 << 0
+	NFQA_SKB_GSO              = 1 << 1
+	NFQA_SKB_CSUM_NOTVERIFIED = 1 << 2
+)
@@ -0,0 +1,415 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nfnetlink.h
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nf_tables.h
+
+const (
+	NFNL_SUBSYS_NFTABLES = 10
+)
+
+// #define NFNL_MSG_BATCH_BEGIN		NLMSG_MIN_TYPE
+// #define NFNL_MSG_BATCH_END		NLMSG_MIN_TYPE+1
+const (
+	NFNL_MSG_BATCH_BEGIN = 0x10
+	NFNL_MSG_BATCH_END   = 0x11
+)
+
+const (
+	NFNLGRP_NFTABLES = 7
+)
+
+// enum {
+// 	NFPROTO_UNSPEC =  0,
+// 	NFPROTO_INET   =  1,
+// 	NFPROTO_IPV4   =  2,
+// 	NFPROTO_ARP    =  3,
+// 	NFPROTO_NETDEV =  5,
+// 	NFPROTO_BRIDGE =  7,
+// 	NFPROTO_IPV6   = 10,
+// };
+const (
+	NFPROTO_UNSPEC = 0
+	NFPROTO_INET   = 1
+	NFPROTO_IPV4   = 2
+	NFPROTO_ARP    = 3
+	NFPROTO_NETDEV = 5
+	NFPROTO_BRIDGE = 7
+	NFPROTO_IPV6   = 10
+)
+
+// enum nf_tables_msg_types {
+// 	NFT_MSG_NEWTABLE,
+// 	NFT_MSG_GETTABLE,
+// 	NFT_MSG_DELTABLE,
+// 	NFT_MSG_NEWCHAIN,
+// 	NFT_MSG_GETCHAIN,
+// 	NFT_MSG_DELCHAIN,
+// 	NFT_MSG_NEWRULE,
+// 	NFT_MSG_GETRULE,
+// 	NFT_MSG_DELRULE,
+// 	NFT_MSG_NEWSET,
+// 	NFT_MSG_GETSET,
+// 	NFT_MSG_DELSET,
+// 	NFT_MSG_NEWSETELEM,
+// 	NFT_MSG_GETSETELEM,
+// 	NFT_MSG_DELSETELEM,
+// 	NFT_MSG_NEWGEN,
+// 	NFT_MSG_GETGEN,
+// 	...
+// };
+const (
+	NFT_MSG_NEWTABLE   = 0
+	NFT_MSG_GETTABLE   = 1
+	NFT_MSG_DELTABLE   = 2
+	NFT_MSG_NEWCHAIN   = 3
+	NFT_MSG_GETCHAIN   = 4
+	NFT_MSG_DELCHAIN   = 5
+	NFT_MSG_NEWRULE    = 6
+	NFT_MSG_GETRULE    = 7
+	NFT_MSG_DELRULE    = 8
+	NFT_MSG_NEWSET     = 9
+	NFT_MSG_GETSET     = 10
+	NFT_MSG_DELSET     = 11
+	NFT_MSG_NEWSETELEM = 12
+	NFT_MSG_GETSETELEM = 13
+	NFT_MSG_DELSETELEM = 14
+	NFT_MSG_NEWGEN     = 15
+	NFT_MSG_GETGEN     = 16
+)
+
+const (
+	NFTA_LIST_ELEM = 1
+)
+
+// enum nft_table_attributes {
+// 	NFTA_TABLE_UNSPEC,
+// 	NFTA_TABLE_NAME,
+// 	NFTA_TABLE_FLAGS,
+// 	NFTA_TABLE_USE,
+// 	NFTA_TABLE_HANDLE,
+// 	NFTA_TABLE_PAD,
+// 	NFTA_TABLE_USERDATA,
+// 	NFTA_TABLE_OWNER,
+// 	__NFTA_TABLE_MAX
+// };
+const (
+	NFTA_TABLE_NAME     = 1
+	NFTA_TABLE_FLAGS    = 2
+	NFTA_TABLE_USE      = 3
+	NFTA_TABLE_HANDLE   = 4
+	NFTA_TABLE_USERDATA = 6
+)
+
+const (
+	NFT_TABLE_F_DORMANT = 0x1
+)
+
+// enum nft_chain_attributes {
+// 	NFTA_CHAIN_UNSPEC,
+// 	NFTA_CHAIN_TABLE,
+// 	NFTA_CHAIN_HANDLE,
+// 	NFTA_CHAIN_NAME,
+// 	NFTA_CHAIN_HOOK,
+// 	NFTA_CHAIN_POLICY,
+// 	NFTA_CHAIN_USE,
+// 	NFTA_CHAIN_TYPE,
+// 	NFTA_CHAIN_COUNTERS,
+// 	NFTA_CHAIN_PAD,
+// 	NFTA_CHAIN_FLAGS,
+// 	NFTA_CHAIN_ID,
+// 	NFTA_CHAIN_USERDATA,
+// 	__NFTA_CHAIN_MAX
+// };
+const (
+	NFTA_CHAIN_TABLE  = 1
+	NFTA_CHAIN_HANDLE = 2
+	NFTA_CHAIN_NAME   = 3
+	NFTA_CHAIN_HOOK   = 4
+	NFTA_CHAIN_POLICY = 5
+	NFTA_CHAIN_USE    = 6
+	NFTA_CHAIN_TYPE   = 7
+	NFTA_CHAIN_FLAGS  = 10
+)
+
+// enum nft_hook_attributes {
+// 	NFTA_HOOK_UNSPEC,
+// 	NFTA_HOOK_HOOKNUM,
+// 	NFTA_HOOK_PRIORITY,
+// 	NFTA_HOOK_DEV,
+// 	NFTA_HOOK_DEVS,
+// 	__NFTA_HOOK_MAX
+// };
+const (
+	NFTA_HOOK_HOOKNUM  = 1
+	NFTA_HOOK_PRIORITY = 2
+	NFTA_HOOK_DEV      = 3
+)
+
+// enum nft_rule_attributes {
+// 	NFTA_RULE_UNSPEC,
+// 	NFTA_RULE_TABLE,
+// 	NFTA_RULE_CHAIN,
+// 	NFTA_RULE_HANDLE,
+// 	NFTA_RULE_EXPRESSIONS,
+// 	NFTA_RULE_COMPAT,
+// 	NFTA_RULE_POSITION,
+// 	NFTA_RULE_USERDATA,
+// 	NFTA_RULE_PAD,
+// 	NFTA_RULE_ID,
+// 	NFTA_RULE_POSITION_ID,
+// 	NFTA_RULE_CHAIN_ID,
+// 	__NFTA_RULE_MAX
+// };
+const (
+	NFTA_RULE_TABLE       = 1
+	NFTA_RULE_CHAIN       = 2
+	NFTA_RULE_HANDLE      = 3
+	NFTA_RULE_EXPRESSIONS = 4
+	NFTA_RULE_POSITION    = 6
+	NFTA_RULE_USERDATA    = 7
+)
+
+// enum nft_expr_attributes {
+// 	NFTA_EXPR_UNSPEC,
+// 	NFTA_EXPR_NAME,
+// 	NFTA_EXPR_DATA,
+// 	__NFTA_EXPR_MAX
+// };
+const (
+	NFTA_EXPR_NAME = 1
+	NFTA_EXPR_DATA = 2
+)
+
+// enum nft_data_attributes {
+// 	NFTA_DATA_UNSPEC,
+// 	NFTA_DATA_VALUE,
+// 	NFTA_DATA_VERDICT,
+// 	__NFTA_DATA_MAX
+// };
+const (
+	NFTA_DATA_VALUE   = 1
+	NFTA_DATA_VERDICT = 2
+)
+
+// enum nft_verdict_attributes {
+// 	NFTA_VERDICT_UNSPEC,
+// 	NFTA_VERDICT_CODE,
+// 	NFTA_VERDICT_CHAIN,
+// 	NFTA_VERDICT_CHAIN_ID,
+// 	__NFTA_VERDICT_MAX
+// };
+const (
+	NFTA_VERDICT_CODE  = 1
+	NFTA_VERDICT_CHAIN = 2
+)
+
+// enum nft_payload_attributes {
+// 	NFTA_PAYLOAD_UNSPEC,
+// 	NFTA_PAYLOAD_DREG,
+// 	NFTA_PAYLOAD_BASE,
+// 	NFTA_PAYLOAD_OFFSET,
+// 	NFTA_PAYLOAD_LEN,
+// 	NFTA_PAYLOAD_SREG,
+// 	NFTA_PAYLOAD_CSUM_TYPE,
+// 	NFTA_PAYLOAD_CSUM_OFFSET,
+// 	NFTA_PAYLOAD_CSUM_FLAGS,
+// 	__NFTA_PAYLOAD_MAX
+// };
+const (
+	NFTA_PAYLOAD_DREG   = 1
+	NFTA_PAYLOAD_BASE   = 2
+	NFTA_PAYLOAD_OFFSET = 3
+	NFTA_PAYLOAD_LEN    = 4
+)
+
+// enum nft_cmp_attributes {
+// 	NFTA_CMP_UNSPEC,
+// 	NFTA_CMP_SREG,
+// 	NFTA_CMP_OP,
+// 	NFTA_CMP_DATA,
+// 	__NFTA_CMP_MAX
+// };
+const (
+	NFTA_CMP_SREG = 1
+	NFTA_CMP_OP   = 2
+	NFTA_CMP_DATA = 3
+)
+
+// enum nft_meta_attributes {
+// 	NFTA_META_UNSPEC,
+// 	NFTA_META_DREG,
+// 	NFTA_META_KEY,
+// 	NFTA_META_SREG,
+// 	__NFTA_META_MAX
+// };
+const (
+	NFTA_META_DREG = 1
+	NFTA_META_KEY  = 2
+	NFTA_META_SREG = 3
+)
+
+// enum nft_immediate_attributes {
+// 	NFTA_IMMEDIATE_UNSPEC,
+// 	NFTA_IMMEDIATE_DREG,
+// 	NFTA_IMMEDIATE_DATA,
+// 	__NFTA_IMMEDIATE_MAX
+// };
+const (
+	NFTA_IMMEDIATE_DREG = 1
+	NFTA_IMMEDIATE_DATA = 2
+)
+
+// enum nft_counter_attributes {
+// 	NFTA_COUNTER_UNSPEC,
+// 	NFTA_COUNTER_BYTES,
+// 	NFTA_COUNTER_PACKETS,
+// 	NFTA_COUNTER_PAD,
+// 	__NFTA_COUNTER_MAX
+// };
+const (
+	NFTA_COUNTER_BYTES   = 1
+	NFTA_COUNTER_PACKETS = 2
+)
+
+// enum nft_ct_attributes {
+// 	NFTA_CT_UNSPEC,
+// 	NFTA_CT_DREG,
+// 	NFTA_CT_KEY,
+// 	NFTA_CT_DIRECTION,
+// 	NFTA_CT_SREG,
+// 	__NFTA_CT_MAX
+// };
+const (
+	NFTA_CT_DREG      = 1
+	NFTA_CT_KEY       = 2
+	NFTA_CT_DIRECTION = 3
+	NFTA_CT_SREG      = 4
+)
+
+// enum nft_nat_attributes {
+// 	NFTA_NAT_UNSPEC,
+// 	NFTA_NAT_TYPE,
+// 	NFTA_NAT_FAMILY,
+// 	NFTA_NAT_REG_ADDR_MIN,
+// 	NFTA_NAT_REG_ADDR_MAX,
+// 	NFTA_NAT_REG_PROTO_MIN,
+// 	NFTA_NAT_REG_PROTO_MAX,
+// 	NFTA_NAT_FLAGS,
+// 	__NFTA_NAT_MAX
+// };
+const (
+	NFTA_NAT_TYPE          = 1
+	NFTA_NAT_FAMILY        = 2
+	NFTA_NAT_REG_ADDR_MIN  = 3
+	NFTA_NAT_REG_ADDR_MAX  = 4
+	NFTA_NAT_REG_PROTO_MIN = 5
+	NFTA_NAT_REG_PROTO_MAX = 6
+	NFTA_NAT_FLAGS         = 7
+)
+
+// enum nft_lookup_attributes {
+// 	NFTA_LOOKUP_UNSPEC,
+// 	NFTA_LOOKUP_SET,
+// 	NFTA_LOOKUP_SREG,
+// 	NFTA_LOOKUP_DREG,
+// 	NFTA_LOOKUP_SET_ID,
+// 	NFTA_LOOKUP_FLAGS,
+// 	__NFTA_LOOKUP_MAX
+// };
+const (
+	NFTA_LOOKUP_SET    = 1
+	NFTA_LOOKUP_SREG   = 2
+	NFTA_LOOKUP_DREG   = 3
+	NFTA_LOOKUP_SET_ID = 4
+	NFTA_LOOKUP_FLAGS  = 5
+)
+
+const (
+	NFT_LOOKUP_F_INV = 0x1
+)
+
+// enum nft_set_attributes {
+// 	NFTA_SET_UNSPEC,
+// 	NFTA_SET_TABLE,
+// 	NFTA_SET_NAME,
+// 	NFTA_SET_FLAGS,
+// 	NFTA_SET_KEY_TYPE,
+// 	NFTA_SET_KEY_LEN,
+// 	NFTA_SET_DATA_TYPE,
+// 	NFTA_SET_DATA_LEN,
+// 	NFTA_SET_POLICY,
+// 	NFTA_SET_DESC,
+// 	NFTA_SET_ID,
+// 	NFTA_SET_TIMEOUT,
+// 	NFTA_SET_GC_INTERVAL,
+// 	NFTA_SET_USERDATA,
+// 	NFTA_SET_PAD,
+// 	NFTA_SET_OBJ_TYPE,
+// 	NFTA_SET_HANDLE,
+// 	NFTA_SET_EXPR,
+// 	NFTA_SET_EXPRESSIONS,
+// 	__NFTA_SET_MAX
+// };
+const (
+	NFTA_SET_TABLE     = 1
+	NFTA_SET_NAME      = 2
+	NFTA_SET_FLAGS     = 3
+	NFTA_SET_KEY_TYPE  = 4
+	NFTA_SET_KEY_LEN   = 5
+	NFTA_SET_DATA_TYPE = 6
+	NFTA_SET_DATA_LEN  = 7
+	NFTA_SET_ID        = 10
+	NFTA_SET_TIMEOUT   = 11
+	NFTA_SET_HANDLE    = 16
+)
+
+// enum nft_set_elem_list_attributes {
+// 	NFTA_SET_ELEM_LIST_UNSPEC,
+// 	NFTA_SET_ELEM_LIST_TABLE,
+// 	NFTA_SET_ELEM_LIST_SET,
+// 	NFTA_SET_ELEM_LIST_ELEMENTS,
+// 	NFTA_SET_ELEM_LIST_SET_ID,
+// 	__NFTA_SET_ELEM_LIST_MAX
+// };
+const (
+	NFTA_SET_ELEM_LIST_TABLE    = 1
+	NFTA_SET_ELEM_LIST_SET      = 2
+	NFTA_SET_ELEM_LIST_ELEMENTS = 3
+	NFTA_SET_ELEM_LIST_SET_ID   = 4
+)
+
+// enum nft_set_elem_attributes {
+// 	NFTA_SET_ELEM_UNSPEC,
+// 	NFTA_SET_ELEM_KEY,
+// 	NFTA_SET_ELEM_DATA,
+// 	NFTA_SET_ELEM_FLAGS,
+// 	NFTA_SET_ELEM_TIMEOUT,
+// 	NFTA_SET_ELEM_EXPIRATION,
+// 	NFTA_SET_ELEM_USERDATA,
+// 	NFTA_SET_ELEM_EXPR,
+// 	NFTA_SET_ELEM_PAD,
+// 	NFTA_SET_ELEM_OBJREF,
+// 	NFTA_SET_ELEM_KEY_END,
+// 	NFTA_SET_ELEM_EXPRESSIONS,
+// 	__NFTA_SET_ELEM_MAX
+// };
+const (
+	NFTA_SET_ELEM_KEY        = 1
+	NFTA_SET_ELEM_DATA       = 2
+	NFTA_SET_ELEM_FLAGS      = 3
+	NFTA_SET_ELEM_TIMEOUT    = 4
+	NFTA_SET_ELEM_EXPIRATION = 5
+	NFTA_SET_ELEM_KEY_END    = 10
+)
+
+// enum nft_gen_attributes {
+// 	NFTA_GEN_UNSPEC,
+// 	NFTA_GEN_ID,
+// 	NFTA_GEN_PROC_PID,
+// 	NFTA_GEN_PROC_NAME,
+// 	__NFTA_GEN_MAX
+// };
+const (
+	NFTA_GEN_ID = 1
+)
@@ -3,13 +3,16 @@ package nl
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/vishvananda/netns"
@@ -26,6 +29,9 @@ const (
 	// from kernel more verbose messages e.g. for statistics,
 	// tc rules or filters, or other more memory requiring data.
 	RECEIVE_BUFFER_SIZE = 65536
+	// Upper bound on how long a context aware receive waits on the socket
+	// before checking again whether its context has been cancelled.
+	CONTEXT_POLL_INTERVAL = 100 * time.Millisecond
 )
 
 // SupportedNlFamilies contains the list of netlink families this netlink package supports
@@ -33,6 +39,11 @@ var SupportedNlFamilies = []int{unix.NETLINK_ROUTE, unix.NETLINK_XFRM, unix.NETL
 
 var nextSeqNr uint32
 
+// ErrDumpInterrupted is returned by Execute when the kernel signalled with
+// NLM_F_DUMP_INTR that the dumped table changed while it was being read, so
+// the results may be incomplete or inconsistent.
+var ErrDumpInterrupted = errors.New("dump interrupted: results may be incomplete or inconsistent")
+
 // GetIPFamily returns the family type of a net.IP.
 func GetIPFamily(ip net.IP) int {
 	if len(ip) <= net.IPv4len {
@@ -251,6 +262,10 @@ func rtaAlignOf(attrlen int) int {
 	return (attrlen + unix.RTA_ALIGNTO - 1) & ^(unix.RTA_ALIGNTO - 1)
 }
 
+func nlmAlignOf(msglen int) int {
+	return (msglen + unix.NLMSG_ALIGNTO - 1) & ^(unix.NLMSG_ALIGNTO - 1)
+}
+
 func NewIfInfomsgChild(parent *RtAttr, family int) *IfInfomsg {
 	msg := NewIfInfomsg(family)
 	parent.children = append(parent.children, msg)
@@ -333,6 +348,32 @@ type NetlinkRequest struct {
 	Data    []NetlinkRequestData
 	RawData []byte
 	Sockets map[int]*SocketHandle
+	// DumpRetries is the number of times a dump request is transparently
+	// repeated when the kernel reports it as interrupted. Zero disables it.
+	DumpRetries int
+	ctx         context.Context
+}
+
+// Context returns the request's context. The returned context is always
+// non-nil; it defaults to the background context.
+func (req *NetlinkRequest) Context() context.Context {
+	if req.ctx != nil {
+		return req.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of req with its context changed to ctx.
+// Execute on the returned request gives up waiting for the kernel reply and
+// returns ctx.Err() as soon as ctx is done. The provided ctx must be non-nil.
+func (req *NetlinkRequest) WithContext(ctx context.Context) *NetlinkRequest {
+	if ctx == nil {
+		panic("nil context")
+	}
+	r := new(NetlinkRequest)
+	*r = *req
+	r.ctx = ctx
+	return r
 }
 
 // Serialize the Netlink Request into a byte array
@@ -378,13 +419,21 @@ func (req *NetlinkRequest) AddRawData(data []byte) {
 
 // Execute the request against a the given sockType.
 // Returns a list of netlink messages in serialized format, optionally filtered
-// by resType.
+// by resType. If the request carries a context, see WithContext, the wait for
+// the reply is aborted and the context error returned once it is done.
+// An interrupted dump is retried up to DumpRetries times; if it still fails
+// the collected messages are returned along with ErrDumpInterrupted.
 func (req *NetlinkRequest) Execute(sockType int, resType uint16) ([][]byte, error) {
 	var (
 		s   *NetlinkSocket
 		err error
 	)
 
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if req.Sockets != nil {
 		if sh, ok := req.Sockets[sockType]; ok {
 			s = sh.Socket
@@ -404,20 +453,43 @@ func (req *NetlinkRequest) Execute(sockType int, resType uint16) ([][]byte, erro
 		defer s.Unlock()
 	}
 
-	if err := s.Send(req); err != nil {
+	pid, err := s.GetPid()
+	if err != nil {
 		return nil, err
 	}
 
-	pid, err := s.GetPid()
-	if err != nil {
+	for attempt := 0; ; attempt++ {
+		res, err := req.exchange(ctx, s, pid, sharedSocket, resType)
+		if err != ErrDumpInterrupted || attempt >= req.DumpRetries ||
+			req.Flags&unix.NLM_F_DUMP != unix.NLM_F_DUMP {
+			return res, err
+		}
+		// The previous reply was consumed up to NLMSG_DONE, so the dump
+		// can simply be requested again under a fresh sequence number.
+		if sharedSocket {
+			req.Seq = atomic.AddUint32(&req.Sockets[sockType].Seq, 1)
+		} else {
+			req.Seq = atomic.AddUint32(&nextSeqNr, 1)
+		}
+	}
+}
+
+// exchange sends the request on s and collects the reply. If the kernel
+// flagged any part of a dump reply as interrupted, the whole reply is still
+// read and returned together with ErrDumpInterrupted.
+func (req *NetlinkRequest) exchange(ctx context.Context, s *NetlinkSocket, pid uint32, sharedSocket bool, resType uint16) ([][]byte, error) {
+	if err := s.Send(req); err != nil {
 		return nil, err
 	}
 
-	var res [][]byte
+	var (
+		res         [][]byte
+		interrupted bool
+	)
 
 done:
 	for {
-		msgs, err := s.Receive()
+		msgs, err := s.ReceiveContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -431,16 +503,17 @@ done:
 			if m.Header.Pid != pid {
 				return nil, fmt.Errorf("Wrong pid %d, expected %d", m.Header.Pid, pid)
 			}
+			if m.Header.Flags&NLM_F_DUMP_INTR != 0 {
+				interrupted = true
+			}
 			if m.Header.Type == unix.NLMSG_DONE {
 				break done
 			}
 			if m.Header.Type == unix.NLMSG_ERROR {
-				native := NativeEndian()
-				error := int32(native.Uint32(m.Data[0:4]))
-				if error == 0 {
-					break done
+				if err := parseErrorMessage(m.Header.Flags, m.Data); err != nil {
+					return nil, err
 				}
-				return nil, syscall.Errno(-error)
+				break done
 			}
 			if resType != 0 && m.Header.Type != resType {
 				continue
@@ -451,9 +524,199 @@ done:
 			}
 		}
 	}
+	if interrupted {
+		return res, ErrDumpInterrupted
+	}
 	return res, nil
 }
 
+// ExecuteBatch sends reqs to the NETLINK_NETFILTER socket in a single
+// datagram, enclosed by a NFNL_MSG_BATCH_BEGIN and NFNL_MSG_BATCH_END pair
+// for the nfnetlink subsystem subsys, so that the kernel applies them as
+// one transaction. Every request is acknowledged and the first error
+// reported by the kernel is returned. The sockets and the context of the
+// first request are used for the whole batch.
+func ExecuteBatch(subsys int, reqs []*NetlinkRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+	var (
+		s   *NetlinkSocket
+		sh  *SocketHandle
+		err error
+	)
+
+	ctx := reqs[0].Context()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if reqs[0].Sockets != nil {
+		if sh = reqs[0].Sockets[unix.NETLINK_NETFILTER]; sh != nil {
+			s = sh.Socket
+		}
+	}
+	sharedSocket := s != nil
+
+	if s == nil {
+		s, err = getNetlinkSocket(unix.NETLINK_NETFILTER)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+	} else {
+		s.Lock()
+		defer s.Unlock()
+	}
+
+	pid, err := s.GetPid()
+	if err != nil {
+		return err
+	}
+
+	nextSeq := func() uint32 {
+		if sharedSocket {
+			return atomic.AddUint32(&sh.Seq, 1)
+		}
+		return atomic.AddUint32(&nextSeqNr, 1)
+	}
+	// The batch messages carry the subsystem in the res_id of their
+	// nfgenmsg header, in network byte order.
+	batchMsg := func(msgType int) *NetlinkRequest {
+		msg := NewNetlinkRequest(msgType, 0)
+		msg.Seq = nextSeq()
+		msg.AddRawData([]byte{unix.AF_UNSPEC, NFNETLINK_V0, byte(subsys >> 8), byte(subsys)})
+		return msg
+	}
+
+	begin := batchMsg(NFNL_MSG_BATCH_BEGIN)
+	buf := begin.Serialize()
+	pending := make(map[uint32]bool, len(reqs))
+	for _, req := range reqs {
+		req.Seq = nextSeq()
+		req.Flags |= unix.NLM_F_ACK
+		pending[req.Seq] = true
+		buf = append(buf, req.Serialize()...)
+	}
+	buf = append(buf, batchMsg(NFNL_MSG_BATCH_END).Serialize()...)
+
+	fd := s.GetFd()
+	if fd < 0 {
+		return fmt.Errorf("Send called on a closed socket")
+	}
+	if err := unix.Sendto(fd, buf, 0, &s.lsa); err != nil {
+		return err
+	}
+
+	var firstErr error
+	for len(pending) > 0 {
+		msgs, err := s.ReceiveContext(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			// The kernel answers on the sequence number of the batch
+			// begin message when it rejects the batch as a whole.
+			if m.Header.Seq == begin.Seq && m.Header.Type == unix.NLMSG_ERROR {
+				if err := parseErrorMessage(m.Header.Flags, m.Data); err != nil {
+					return err
+				}
+				continue
+			}
+			if !pending[m.Header.Seq] {
+				if sharedSocket {
+					continue
+				}
+				return fmt.Errorf("Wrong Seq nr %d in batch reply", m.Header.Seq)
+			}
+			if m.Header.Pid != pid {
+				return fmt.Errorf("Wrong pid %d, expected %d", m.Header.Pid, pid)
+			}
+			if m.Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			if err := parseErrorMessage(m.Header.Flags, m.Data); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			delete(pending, m.Header.Seq)
+		}
+	}
+	return firstErr
+}
+
+// NetlinkError is returned by Execute when the kernel rejects a request and
+// attaches extended acknowledgement attributes to the error reply.
+// Requests which fail without them still return a bare syscall.Errno.
+type NetlinkError struct {
+	Errno syscall.Errno
+	// Msg is the human readable reason reported by the kernel.
+	Msg string
+	// Offset is the offset in bytes of the offending attribute within
+	// the request message, or zero if the kernel did not report one.
+	Offset uint32
+	// Cookie is an opaque subsystem specific value.
+	Cookie []byte
+}
+
+func (e *NetlinkError) Error() string {
+	if e.Msg == "" {
+		return e.Errno.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Errno.Error(), e.Msg)
+}
+
+// Unwrap returns the underlying errno, so that errors.Is can be used to
+// match it against the syscall error values.
+func (e *NetlinkError) Unwrap() error {
+	return e.Errno
+}
+
+// parseErrorMessage decodes the payload of a NLMSG_ERROR message whose
+// header carries flags. It returns nil for a positive acknowledgement.
+func parseErrorMessage(flags uint16, b []byte) error {
+	native := NativeEndian()
+	if len(b) < 4 {
+		return fmt.Errorf("Got short error message from netlink")
+	}
+	errno := int32(native.Uint32(b[0:4]))
+	if errno == 0 {
+		return nil
+	}
+	err := syscall.Errno(-errno)
+	if flags&NLM_F_ACK_TLVS == 0 {
+		return err
+	}
+
+	// The error code is followed by the header of the offending request
+	// and, unless the kernel capped the reply, by its payload as well.
+	offset := 4 + unix.SizeofNlMsghdr
+	if flags&NLM_F_CAPPED == 0 && len(b) >= offset {
+		orig := (*unix.NlMsghdr)(unsafe.Pointer(&b[4]))
+		offset = 4 + nlmAlignOf(int(orig.Len))
+	}
+	if len(b) <= offset {
+		return err
+	}
+	attrs, perr := ParseRouteAttr(b[offset:])
+	if perr != nil {
+		return err
+	}
+	nerr := &NetlinkError{Errno: err}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case NLMSGERR_ATTR_MSG:
+			nerr.Msg = string(bytes.TrimRight(attr.Value, "\x00"))
+		case NLMSGERR_ATTR_OFFS:
+			if len(attr.Value) >= 4 {
+				nerr.Offset = native.Uint32(attr.Value)
+			}
+		case NLMSGERR_ATTR_COOKIE:
+			nerr.Cookie = append([]byte(nil), attr.Value...)
+		}
+	}
+	return nerr
+}
+
 // Create a new netlink request from proto and flags
 // Note the Len value will be inaccurate once data is added until
 // the message is serialized
@@ -487,6 +750,10 @@ func getNetlinkSocket(protocol int) (*NetlinkSocket, error) {
 		unix.Close(fd)
 		return nil, err
 	}
+	// Ask for the extended acknowledgement, so that the kernel explains
+	// why a request was rejected. Kernels older than 4.12 do not support
+	// it, in which case errors are reported as plain errno values.
+	unix.SetsockoptInt(fd, SOL_NETLINK, NETLINK_EXT_ACK, 1)
 
 	return s, nil
 }
@@ -627,10 +894,62 @@ func (s *NetlinkSocket) Receive() ([]syscall.NetlinkMessage, error) {
 	if nr < unix.NLMSG_HDRLEN {
 		return nil, fmt.Errorf("Got short response from netlink")
 	}
-	rb = rb[:nr]
+	// The packets of nfnetlink_queue are not padded to the alignment
+	// ParseNetlinkMessage expects, the buffer is zeroed past nr
+	rb = rb[:nlmAlignOf(nr)]
 	return syscall.ParseNetlinkMessage(rb)
 }
 
+// ReceiveContext works like Receive but stops waiting for data and
+// returns ctx.Err() once ctx is done.
+func (s *NetlinkSocket) ReceiveContext(ctx context.Context) ([]syscall.NetlinkMessage, error) {
+	if ctx.Done() == nil {
+		return s.Receive()
+	}
+	if err := s.waitReadable(ctx); err != nil {
+		return nil, err
+	}
+	return s.Receive()
+}
+
+// waitReadable blocks until the socket has data to read or ctx is done.
+// The socket is polled in slices no longer than CONTEXT_POLL_INTERVAL so
+// that a cancellation is noticed even without a deadline.
+func (s *NetlinkSocket) waitReadable(ctx context.Context) error {
+	fd := atomic.LoadInt32(&s.fd)
+	if fd < 0 {
+		return fmt.Errorf("Receive called on a closed socket")
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		timeout := CONTEXT_POLL_INTERVAL
+		if deadline, ok := ctx.Deadline(); ok {
+			if left := time.Until(deadline); left < timeout {
+				timeout = left
+			}
+		}
+		// Round up so that a sub-millisecond remainder does not turn
+		// into a busy loop of zero timeout polls.
+		ms := int((timeout + time.Millisecond - 1) / time.Millisecond)
+		if ms < 0 {
+			ms = 0
+		}
+		fds := []unix.PollFd{{Fd: fd, Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, ms)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+}
+
 // SetSendTimeout allows to set a send timeout on the socket
 func (s *NetlinkSocket) SetSendTimeout(timeout *unix.Timeval) error {
 	// Set a send timeout of SOCKET_SEND_TIMEOUT, this will allow the Send to periodically unblock and avoid that a routine
@@ -714,6 +1033,10 @@ func ParseRouteAttr(b []byte) ([]syscall.NetlinkRouteAttr, error) {
 		}
 		ra := syscall.NetlinkRouteAttr{Attr: syscall.RtAttr(*a), Value: vbuf[:int(a.Len)-unix.SizeofRtAttr]}
 		attrs = append(attrs, ra)
+		// The last attribute may not be padded
+		if alen > len(b) {
+			break
+		}
 		b = b[alen:]
 	}
 	return attrs, nil
@@ -2,8 +2,10 @@ package nl
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -98,3 +100,194 @@ func TestIfSocketCloses(t *testing.T) {
 		t.Fatalf("Expected error instead received nil")
 	}
 }
+
+func TestExecuteContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP).WithContext(ctx)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	if _, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req = NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP).WithContext(ctx)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) == 0 {
+		t.Fatal("Expected at least the loopback link")
+	}
+}
+
+func TestReceiveContextDeadline(t *testing.T) {
+	s, err := Subscribe(unix.NETLINK_ROUTE, unix.RTNLGRP_NEIGH)
+	if err != nil {
+		t.Fatalf("Error on creating the socket: %v", err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.ReceiveContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d > CONTEXT_POLL_INTERVAL+time.Second {
+		t.Fatalf("ReceiveContext returned too late: %v", d)
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	native := NativeEndian()
+	orig := NewNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
+	orig.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	origBytes := orig.Serialize()
+
+	errHdr := make([]byte, 4)
+	errno := -int32(unix.EINVAL)
+	native.PutUint32(errHdr, uint32(errno))
+
+	// Without extended acknowledgement a bare errno is returned
+	b := append(append([]byte{}, errHdr...), origBytes...)
+	if err := parseErrorMessage(0, b); err != unix.EINVAL {
+		t.Fatalf("Expected %v, got %v", unix.EINVAL, err)
+	}
+
+	msg := NewRtAttr(NLMSGERR_ATTR_MSG, ZeroTerminated("Unknown device type"))
+	offs := NewRtAttr(NLMSGERR_ATTR_OFFS, Uint32Attr(32))
+	tlvs := append(msg.Serialize(), offs.Serialize()...)
+
+	// Full reply echoing the original payload
+	b = append(append(append([]byte{}, errHdr...), origBytes...), tlvs...)
+	err := parseErrorMessage(NLM_F_ACK_TLVS, b)
+	nerr, ok := err.(*NetlinkError)
+	if !ok {
+		t.Fatalf("Expected *NetlinkError, got %T", err)
+	}
+	if nerr.Errno != unix.EINVAL || nerr.Msg != "Unknown device type" || nerr.Offset != 32 {
+		t.Fatalf("Unexpected error fields: %+v", nerr)
+	}
+	if !errors.Is(err, unix.EINVAL) {
+		t.Fatal("Expected the error to wrap EINVAL")
+	}
+	if err.Error() != "invalid argument: Unknown device type" {
+		t.Fatalf("Unexpected error string %q", err.Error())
+	}
+
+	// Capped reply carrying only the original header
+	b = append(append(append([]byte{}, errHdr...), origBytes[:unix.SizeofNlMsghdr]...), tlvs...)
+	err = parseErrorMessage(NLM_F_ACK_TLVS|NLM_F_CAPPED, b)
+	if nerr, ok := err.(*NetlinkError); !ok || nerr.Msg != "Unknown device type" {
+		t.Fatalf("Unexpected error for capped reply: %v", err)
+	}
+
+	// Positive acknowledgement
+	if err := parseErrorMessage(NLM_F_CAPPED, make([]byte, 4+unix.SizeofNlMsghdr)); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+}
+
+func TestParseRouteAttrUnpadded(t *testing.T) {
+	b := append(NewRtAttr(1, []byte{1, 2}).Serialize(), NewRtAttr(2, []byte("hello")).Serialize()...)
+	// Drop the padding of the last attribute as nfnetlink_queue does
+	b = b[:len(b)-3]
+	attrs, err := ParseRouteAttr(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attrs) != 2 || attrs[0].Attr.Type != 1 || attrs[1].Attr.Type != 2 || string(attrs[1].Value) != "hello" {
+		t.Fatalf("Unexpected attributes %+v", attrs)
+	}
+}
+
+// replyDumps answers the dump requests received on peer with a one message
+// reply, flagged as interrupted when interrupted returns true for the attempt.
+// The number of requests answered is sent on the returned channel.
+func replyDumps(t *testing.T, peer *NetlinkSocket, pid uint32, interrupted func(attempt int) bool) <-chan int {
+	answered := make(chan int, 1)
+	go func() {
+		attempt := 0
+		defer func() { answered <- attempt }()
+		for {
+			msgs, err := peer.Receive()
+			if err != nil {
+				return
+			}
+			for _, m := range msgs {
+				flags := uint16(unix.NLM_F_MULTI)
+				if interrupted(attempt) {
+					flags |= NLM_F_DUMP_INTR
+				}
+				attempt++
+				var b []byte
+				for _, typ := range []uint16{unix.RTM_NEWLINK, unix.NLMSG_DONE} {
+					reply := &NetlinkRequest{NlMsghdr: unix.NlMsghdr{Type: typ, Flags: flags, Seq: m.Header.Seq, Pid: pid}}
+					reply.AddRawData(make([]byte, 4))
+					b = append(b, reply.Serialize()...)
+				}
+				if err := unix.Sendto(int(peer.fd), b, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Pid: pid}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}
+	}()
+	return answered
+}
+
+func TestExecuteDumpInterrupted(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		retries     int
+		interrupted func(attempt int) bool
+		attempts    int
+		err         error
+	}{
+		{"no retry", 0, func(int) bool { return true }, 1, ErrDumpInterrupted},
+		{"retried", 2, func(attempt int) bool { return attempt == 0 }, 2, nil},
+		{"given up", 2, func(int) bool { return true }, 3, ErrDumpInterrupted},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The fake kernel is a second socket that the requests are sent to
+			s, err := getNetlinkSocket(unix.NETLINK_USERSOCK)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			peer, err := getNetlinkSocket(unix.NETLINK_USERSOCK)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := peer.SetReceiveTimeout(&unix.Timeval{Usec: 200000}); err != nil {
+				t.Fatal(err)
+			}
+			defer peer.Close()
+			pid, err := s.GetPid()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.lsa.Pid, err = peer.GetPid(); err != nil {
+				t.Fatal(err)
+			}
+			answered := replyDumps(t, peer, pid, tc.interrupted)
+
+			req := NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+			req.Sockets = map[int]*SocketHandle{unix.NETLINK_USERSOCK: {Socket: s}}
+			req.DumpRetries = tc.retries
+			msgs, err := req.Execute(unix.NETLINK_USERSOCK, unix.RTM_NEWLINK)
+			if err != tc.err {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("Expected the reply of the last attempt, got %d messages", len(msgs))
+			}
+			if attempts := <-answered; attempts != tc.attempts {
+				t.Fatalf("Expected %d attempts, got %d", tc.attempts, attempts)
+			}
+		})
+	}
+}
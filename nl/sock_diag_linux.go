@@ -0,0 +1,199 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/net/tcp_states.h
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/inet_diag.h
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/sock_diag.h
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/tcp.h
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/unix_diag.h
+
+// Track the message sizes for the correct serialization/deserialization
+const (
+	SizeofUnixDiagReq      = 0x18
+	SizeofUnixDiagMsg      = 0x10
+	SizeofInetDiagBcOp     = 0x4
+	SizeofInetDiagHostcond = 0x8
+	SizeofInetDiagMarkcond = 0x8
+	SizeofInetDiagMeminfo  = 0x10
+	SizeofTCPInfo          = 0xf8
+	SK_MEMINFO_VARS        = 9
+)
+
+// enum {
+// 	TCP_ESTABLISHED = 1,
+// 	TCP_SYN_SENT,
+// 	TCP_SYN_RECV,
+// 	TCP_FIN_WAIT1,
+// 	TCP_FIN_WAIT2,
+// 	TCP_TIME_WAIT,
+// 	TCP_CLOSE,
+// 	TCP_CLOSE_WAIT,
+// 	TCP_LAST_ACK,
+// 	TCP_LISTEN,
+// 	TCP_CLOSING,	/* Now a valid state */
+// 	TCP_NEW_SYN_RECV,
+// 	TCP_BOUND_INACTIVE, /* Pseudo-state for inet_diag */
+//
+// 	TCP_MAX_STATES	/* Leave at the end! */
+// };
+const (
+	TCP_ESTABLISHED    = 1
+	TCP_SYN_SENT       = 2
+	TCP_SYN_RECV       = 3
+	TCP_FIN_WAIT1      = 4
+	TCP_FIN_WAIT2      = 5
+	TCP_TIME_WAIT      = 6
+	TCP_CLOSE          = 7
+	TCP_CLOSE_WAIT     = 8
+	TCP_LAST_ACK       = 9
+	TCP_LISTEN         = 10
+	TCP_CLOSING        = 11
+	TCP_NEW_SYN_RECV   = 12
+	TCP_BOUND_INACTIVE = 13
+	TCP_MAX_STATES     = 14
+)
+
+// TCP_ALL is the mask of all the states but TCP_BOUND_INACTIVE, which is
+// only dumped on request
+const TCP_ALL = (1<<TCP_MAX_STATES - 1) &^ (1 << TCP_BOUND_INACTIVE)
+
+// enum {
+// 	INET_DIAG_NONE,
+// 	INET_DIAG_MEMINFO,
+// 	INET_DIAG_INFO,
+// 	INET_DIAG_VEGASINFO,
+// 	INET_DIAG_CONG,
+// 	INET_DIAG_TOS,
+// 	INET_DIAG_TCLASS,
+// 	INET_DIAG_SKMEMINFO,
+// 	INET_DIAG_SHUTDOWN,
+// 	INET_DIAG_DCTCPINFO,
+// 	INET_DIAG_PROTOCOL,  /* response attribute only */
+// 	INET_DIAG_SKV6ONLY,
+// 	INET_DIAG_LOCALS,
+// 	INET_DIAG_PEERS,
+// 	INET_DIAG_PAD,
+// 	INET_DIAG_MARK,		/* only with CAP_NET_ADMIN */
+// 	INET_DIAG_BBRINFO,	/* request as INET_DIAG_VEGASINFO */
+// 	INET_DIAG_CLASS_ID,	/* request as INET_DIAG_TCLASS */
+// 	INET_DIAG_MD5SIG,
+// 	INET_DIAG_ULP_INFO,
+// 	INET_DIAG_SK_BPF_STORAGES,
+// 	INET_DIAG_CGROUP_ID,
+// 	INET_DIAG_SOCKOPT,
+// 	__INET_DIAG_MAX,
+// };
+const (
+	INET_DIAG_NONE            = 0
+	INET_DIAG_MEMINFO         = 1
+	INET_DIAG_INFO            = 2
+	INET_DIAG_VEGASINFO       = 3
+	INET_DIAG_CONG            = 4
+	INET_DIAG_TOS             = 5
+	INET_DIAG_TCLASS          = 6
+	INET_DIAG_SKMEMINFO       = 7
+	INET_DIAG_SHUTDOWN        = 8
+	INET_DIAG_DCTCPINFO       = 9
+	INET_DIAG_PROTOCOL        = 10
+	INET_DIAG_SKV6ONLY        = 11
+	INET_DIAG_LOCALS          = 12
+	INET_DIAG_PEERS           = 13
+	INET_DIAG_PAD             = 14
+	INET_DIAG_MARK            = 15
+	INET_DIAG_BBRINFO         = 16
+	INET_DIAG_CLASS_ID        = 17
+	INET_DIAG_MD5SIG          = 18
+	INET_DIAG_ULP_INFO        = 19
+	INET_DIAG_SK_BPF_STORAGES = 20
+	INET_DIAG_CGROUP_ID       = 21
+	INET_DIAG_SOCKOPT         = 22
+)
+
+// enum {
+// 	INET_DIAG_REQ_NONE,
+// 	INET_DIAG_REQ_BYTECODE,
+// 	INET_DIAG_REQ_SK_BPF_STORAGES,
+// 	INET_DIAG_REQ_PROTOCOL,
+// 	__INET_DIAG_REQ_MAX,
+// };
+const (
+	INET_DIAG_REQ_NONE            = 0
+	INET_DIAG_REQ_BYTECODE        = 1
+	INET_DIAG_REQ_SK_BPF_STORAGES = 2
+	INET_DIAG_REQ_PROTOCOL        = 3
+)
+
+// enum {
+// 	INET_DIAG_BC_NOP,
+// 	INET_DIAG_BC_JMP,
+// 	INET_DIAG_BC_S_GE,
+// 	INET_DIAG_BC_S_LE,
+// 	INET_DIAG_BC_D_GE,
+// 	INET_DIAG_BC_D_LE,
+// 	INET_DIAG_BC_AUTO,
+// 	INET_DIAG_BC_S_COND,
+// 	INET_DIAG_BC_D_COND,
+// 	INET_DIAG_BC_DEV_COND,   /* u32 ifindex */
+// 	INET_DIAG_BC_MARK_COND,
+// 	INET_DIAG_BC_S_EQ,
+// 	INET_DIAG_BC_D_EQ,
+// 	INET_DIAG_BC_CGROUP_COND,   /* u64 cgroup v2 ID */
+// };
+const (
+	INET_DIAG_BC_NOP         = 0
+	INET_DIAG_BC_JMP         = 1
+	INET_DIAG_BC_S_GE        = 2
+	INET_DIAG_BC_S_LE        = 3
+	INET_DIAG_BC_D_GE        = 4
+	INET_DIAG_BC_D_LE        = 5
+	INET_DIAG_BC_AUTO        = 6
+	INET_DIAG_BC_S_COND      = 7
+	INET_DIAG_BC_D_COND      = 8
+	INET_DIAG_BC_DEV_COND    = 9
+	INET_DIAG_BC_MARK_COND   = 10
+	INET_DIAG_BC_S_EQ        = 11
+	INET_DIAG_BC_D_EQ        = 12
+	INET_DIAG_BC_CGROUP_COND = 13
+)
+
+// /* Show mask bits */
+// #define UDIAG_SHOW_NAME		0x00000001	/* show name (not path) */
+// #define UDIAG_SHOW_VFS		0x00000002	/* show VFS inode info */
+// #define UDIAG_SHOW_PEER		0x00000004	/* show peer socket info */
+// #define UDIAG_SHOW_ICONS	0x00000008	/* show pending connections */
+// #define UDIAG_SHOW_RQLEN	0x00000010	/* show skb receive queue len */
+// #define UDIAG_SHOW_MEMINFO	0x00000020	/* show memory info of a socket */
+// #define UDIAG_SHOW_UID		0x00000040	/* show socket's UID */
+const (
+	UDIAG_SHOW_NAME    = 0x00000001
+	UDIAG_SHOW_VFS     = 0x00000002
+	UDIAG_SHOW_PEER    = 0x00000004
+	UDIAG_SHOW_ICONS   = 0x00000008
+	UDIAG_SHOW_RQLEN   = 0x00000010
+	UDIAG_SHOW_MEMINFO = 0x00000020
+	UDIAG_SHOW_UID     = 0x00000040
+)
+
+// enum {
+// 	/* UNIX_DIAG_NONE, standard nl API requires this attribute!  */
+// 	UNIX_DIAG_NAME,
+// 	UNIX_DIAG_VFS,
+// 	UNIX_DIAG_PEER,
+// 	UNIX_DIAG_ICONS,
+// 	UNIX_DIAG_RQLEN,
+// 	UNIX_DIAG_MEMINFO,
+// 	UNIX_DIAG_SHUTDOWN,
+// 	UNIX_DIAG_UID,
+//
+// 	__UNIX_DIAG_MAX,
+// };
+const (
+	UNIX_DIAG_NAME     = 0
+	UNIX_DIAG_VFS      = 1
+	UNIX_DIAG_PEER     = 2
+	UNIX_DIAG_ICONS    = 3
+	UNIX_DIAG_RQLEN    = 4
+	UNIX_DIAG_MEMINFO  = 5
+	UNIX_DIAG_SHUTDOWN = 6
+	UNIX_DIAG_UID      = 7
+)
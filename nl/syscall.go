@@ -39,6 +39,7 @@ const (
 // socket diags related
 const (
 	SOCK_DIAG_BY_FAMILY = 20         /* linux.sock_diag.h */
+	SOCK_DESTROY        = 21         /* linux.sock_diag.h */
 	TCPDIAG_NOCOOKIE    = 0xFFFFFFFF /* TCPDIAG_NOCOOKIE in net/ipv4/tcp_diag.h*/
 )
 
@@ -77,3 +78,20 @@ const (
 	IPV6_SRCRT_TYPE_2 = 2    // IPv6 type 2 Routing Header
 	IPV6_SRCRT_TYPE_4 = 4    // Segment Routing with IPv6
 )
+
+// netlink message flags and extended acknowledgement, linux/netlink.h
+const (
+	SOL_NETLINK     = 0x10e
+	NETLINK_EXT_ACK = 0xb
+	NLM_F_DUMP_INTR = 0x10  /* dump was inconsistent due to sequence change */
+	NLM_F_CAPPED    = 0x100 /* request was capped */
+	NLM_F_ACK_TLVS  = 0x200 /* extended ACK TVLs were included */
+)
+
+// extended acknowledgement attributes, linux/netlink.h
+const (
+	NLMSGERR_ATTR_UNUSED = iota
+	NLMSGERR_ATTR_MSG    /* error message string */
+	NLMSGERR_ATTR_OFFS   /* offset of the invalid attribute in the original message */
+	NLMSGERR_ATTR_COOKIE /* arbitrary subsystem specific cookie */
+)
@@ -28,7 +28,13 @@ const (
 	TCA_POLICE_PEAKRATE
 	TCA_POLICE_AVRATE
 	TCA_POLICE_RESULT
-	TCA_POLICE_MAX = TCA_POLICE_RESULT
+	TCA_POLICE_TM
+	TCA_POLICE_PAD
+	TCA_POLICE_RATE64
+	TCA_POLICE_PEAKRATE64
+	TCA_POLICE_PKTRATE64
+	TCA_POLICE_PKTBURST64
+	TCA_POLICE_MAX = TCA_POLICE_PKTBURST64
 )
 
 // Message types
@@ -42,7 +48,24 @@ const (
 	TCA_FCNT
 	TCA_STATS2
 	TCA_STAB
-	TCA_MAX = TCA_STAB
+	TCA_PAD
+	TCA_DUMP_INVISIBLE
+	TCA_CHAIN
+	TCA_HW_OFFLOAD
+	TCA_INGRESS_BLOCK
+	TCA_EGRESS_BLOCK
+	TCA_MAX = TCA_EGRESS_BLOCK
+)
+
+// TCM_IFINDEX_MAGIC_BLOCK in the ifindex of a tcmsg means that the parent
+// field holds a shared block index instead of a qdisc handle.
+const TCM_IFINDEX_MAGIC_BLOCK = 0xFFFFFFFF
+
+// Chain message types
+const (
+	RTM_NEWCHAIN = 0x64
+	RTM_DELCHAIN = 0x65
+	RTM_GETCHAIN = 0x66
 )
 
 const (
@@ -50,6 +73,16 @@ const (
 	TCAA_MAX    = 1
 )
 
+// Attributes of RTM_*ACTION messages
+const (
+	TCA_ROOT_UNSPEC = iota
+	TCA_ROOT_TAB
+	TCA_ROOT_FLAGS
+	TCA_ROOT_COUNT
+	TCA_ROOT_TIME_DELTA /* in msecs */
+	TCA_ROOT_MAX        = TCA_ROOT_TIME_DELTA
+)
+
 const (
 	TCA_ACT_UNSPEC = iota
 	TCA_ACT_KIND
@@ -91,6 +124,20 @@ const (
 	SizeofTcGen          = 0x14
 	SizeofTcMirred       = SizeofTcGen + 0x08
 	SizeofTcPolice       = 2*SizeofTcRateSpec + 0x20
+	SizeofTcGactP        = 0x08
+	SizeofTcTunnelKey    = SizeofTcGen + 0x04
+	SizeofTcSkbEdit      = SizeofTcGen
+	SizeofTcVlan         = SizeofTcGen + 0x04
+	SizeofTcPeditKey     = 0x18
+	SizeofTcPeditSel     = SizeofTcGen + 0x04 // without keys
+	SizeofTcCsum         = SizeofTcGen + 0x04
+	SizeofTcConnmark     = SizeofTcGen + 0x04
+	SizeofTcf            = 0x20
+	SizeofTcSfqQopt      = 0x14
+	SizeofTcSfqQoptV1    = SizeofTcSfqQopt + 0x34
+	SizeofTcRedQopt      = 0x10
+	SizeofTcMqprioQopt   = 0x52
+	SizeofTcMultiqQopt   = 0x04
 )
 
 // struct tcmsg {
@@ -618,6 +665,63 @@ const (
 
 type TcGact TcGen
 
+// Random gact types
+const (
+	PGACT_NONE = iota
+	PGACT_NETRAND
+	PGACT_DETERM
+)
+
+// struct tc_gact_p {
+// 	__u16                 ptype;
+// 	__u16                 pval;
+// 	int                   paction;
+// };
+
+type TcGactP struct {
+	Ptype   uint16
+	Pval    uint16
+	Paction int32
+}
+
+func (msg *TcGactP) Len() int {
+	return SizeofTcGactP
+}
+
+func DeserializeTcGactP(b []byte) *TcGactP {
+	return (*TcGactP)(unsafe.Pointer(&b[0:SizeofTcGactP][0]))
+}
+
+func (x *TcGactP) Serialize() []byte {
+	return (*(*[SizeofTcGactP]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tcf_t {
+// 	__u64   install;
+// 	__u64   lastuse;
+// 	__u64   expires;
+// 	__u64   firstuse;
+// };
+
+type Tcf struct {
+	Install  uint64
+	LastUse  uint64
+	Expires  uint64
+	FirstUse uint64
+}
+
+func (msg *Tcf) Len() int {
+	return SizeofTcf
+}
+
+func DeserializeTcf(b []byte) *Tcf {
+	return (*Tcf)(unsafe.Pointer(&b[0:SizeofTcf][0]))
+}
+
+func (x *Tcf) Serialize() []byte {
+	return (*(*[SizeofTcf]byte)(unsafe.Pointer(x)))[:]
+}
+
 const (
 	TCA_ACT_BPF = 13
 )
@@ -687,6 +791,333 @@ func (x *TcMirred) Serialize() []byte {
 	return (*(*[SizeofTcMirred]byte)(unsafe.Pointer(x)))[:]
 }
 
+const (
+	TCA_ACT_TUNNEL_KEY = 17
+)
+
+const (
+	TCA_TUNNEL_KEY_UNSPEC = iota
+	TCA_TUNNEL_KEY_TM
+	TCA_TUNNEL_KEY_PARMS
+	TCA_TUNNEL_KEY_ENC_IPV4_SRC
+	TCA_TUNNEL_KEY_ENC_IPV4_DST
+	TCA_TUNNEL_KEY_ENC_IPV6_SRC
+	TCA_TUNNEL_KEY_ENC_IPV6_DST
+	TCA_TUNNEL_KEY_ENC_KEY_ID
+	TCA_TUNNEL_KEY_PAD
+	TCA_TUNNEL_KEY_ENC_DST_PORT
+	TCA_TUNNEL_KEY_NO_CSUM
+	TCA_TUNNEL_KEY_ENC_OPTS
+	TCA_TUNNEL_KEY_ENC_TOS
+	TCA_TUNNEL_KEY_ENC_TTL
+	TCA_TUNNEL_KEY_NO_FRAG
+	TCA_TUNNEL_KEY_MAX = TCA_TUNNEL_KEY_NO_FRAG
+)
+
+const (
+	TCA_TUNNEL_KEY_ACT_SET     = 1
+	TCA_TUNNEL_KEY_ACT_RELEASE = 2
+)
+
+// struct tc_tunnel_key {
+// 	tc_gen;
+// 	int t_action;
+// };
+
+type TcTunnelKey struct {
+	TcGen
+	Action int32
+}
+
+func (msg *TcTunnelKey) Len() int {
+	return SizeofTcTunnelKey
+}
+
+func DeserializeTcTunnelKey(b []byte) *TcTunnelKey {
+	return (*TcTunnelKey)(unsafe.Pointer(&b[0:SizeofTcTunnelKey][0]))
+}
+
+func (x *TcTunnelKey) Serialize() []byte {
+	return (*(*[SizeofTcTunnelKey]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_ACT_SKBEDIT = 11
+)
+
+const (
+	TCA_SKBEDIT_UNSPEC = iota
+	TCA_SKBEDIT_TM
+	TCA_SKBEDIT_PARMS
+	TCA_SKBEDIT_PRIORITY
+	TCA_SKBEDIT_QUEUE_MAPPING
+	TCA_SKBEDIT_MARK
+	TCA_SKBEDIT_PAD
+	TCA_SKBEDIT_PTYPE
+	TCA_SKBEDIT_MASK
+	TCA_SKBEDIT_FLAGS
+	TCA_SKBEDIT_QUEUE_MAPPING_MAX
+	TCA_SKBEDIT_MAX = TCA_SKBEDIT_QUEUE_MAPPING_MAX
+)
+
+// struct tc_skbedit {
+// 	tc_gen;
+// };
+
+type TcSkbEdit struct {
+	TcGen
+}
+
+func (msg *TcSkbEdit) Len() int {
+	return SizeofTcSkbEdit
+}
+
+func DeserializeTcSkbEdit(b []byte) *TcSkbEdit {
+	return (*TcSkbEdit)(unsafe.Pointer(&b[0:SizeofTcSkbEdit][0]))
+}
+
+func (x *TcSkbEdit) Serialize() []byte {
+	return (*(*[SizeofTcSkbEdit]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_ACT_VLAN = 12
+)
+
+const (
+	TCA_VLAN_UNSPEC = iota
+	TCA_VLAN_TM
+	TCA_VLAN_PARMS
+	TCA_VLAN_PUSH_VLAN_ID
+	TCA_VLAN_PUSH_VLAN_PROTOCOL
+	TCA_VLAN_PAD
+	TCA_VLAN_PUSH_VLAN_PRIORITY
+	TCA_VLAN_PUSH_ETH_DST
+	TCA_VLAN_PUSH_ETH_SRC
+	TCA_VLAN_MAX = TCA_VLAN_PUSH_ETH_SRC
+)
+
+const (
+	TCA_VLAN_ACT_POP      = 1
+	TCA_VLAN_ACT_PUSH     = 2
+	TCA_VLAN_ACT_MODIFY   = 3
+	TCA_VLAN_ACT_POP_ETH  = 4
+	TCA_VLAN_ACT_PUSH_ETH = 5
+)
+
+// struct tc_vlan {
+// 	tc_gen;
+// 	int v_action;
+// };
+
+type TcVlan struct {
+	TcGen
+	Action int32
+}
+
+func (msg *TcVlan) Len() int {
+	return SizeofTcVlan
+}
+
+func DeserializeTcVlan(b []byte) *TcVlan {
+	return (*TcVlan)(unsafe.Pointer(&b[0:SizeofTcVlan][0]))
+}
+
+func (x *TcVlan) Serialize() []byte {
+	return (*(*[SizeofTcVlan]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_ACT_PEDIT = 7
+)
+
+const (
+	TCA_PEDIT_UNSPEC = iota
+	TCA_PEDIT_TM
+	TCA_PEDIT_PARMS
+	TCA_PEDIT_PAD
+	TCA_PEDIT_PARMS_EX
+	TCA_PEDIT_KEYS_EX
+	TCA_PEDIT_KEY_EX
+	TCA_PEDIT_MAX = TCA_PEDIT_KEY_EX
+)
+
+const (
+	TCA_PEDIT_KEY_EX_HTYPE = 1
+	TCA_PEDIT_KEY_EX_CMD   = 2
+)
+
+const (
+	TCA_PEDIT_KEY_EX_HDR_TYPE_NETWORK = iota
+	TCA_PEDIT_KEY_EX_HDR_TYPE_ETH
+	TCA_PEDIT_KEY_EX_HDR_TYPE_IP4
+	TCA_PEDIT_KEY_EX_HDR_TYPE_IP6
+	TCA_PEDIT_KEY_EX_HDR_TYPE_TCP
+	TCA_PEDIT_KEY_EX_HDR_TYPE_UDP
+)
+
+const (
+	TCA_PEDIT_KEY_EX_CMD_SET = iota
+	TCA_PEDIT_KEY_EX_CMD_ADD
+)
+
+// struct tc_pedit_key {
+// 	__u32           mask;  /* AND */
+// 	__u32           val;   /*XOR */
+// 	__u32           off;  /*offset */
+// 	__u32           at;
+// 	__u32           offmask;
+// 	__u32           shift;
+// };
+
+type TcPeditKey struct {
+	Mask    uint32 // big endian
+	Val     uint32 // big endian
+	Off     uint32
+	At      uint32
+	OffMask uint32
+	Shift   uint32
+}
+
+func (msg *TcPeditKey) Len() int {
+	return SizeofTcPeditKey
+}
+
+func DeserializeTcPeditKey(b []byte) *TcPeditKey {
+	return (*TcPeditKey)(unsafe.Pointer(&b[0:SizeofTcPeditKey][0]))
+}
+
+func (x *TcPeditKey) Serialize() []byte {
+	return (*(*[SizeofTcPeditKey]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tc_pedit_sel {
+// 	tc_gen;
+// 	unsigned char           nkeys;
+// 	unsigned char           flags;
+// 	struct tc_pedit_key     keys[0];
+// };
+
+type TcPeditSel struct {
+	TcGen
+	Nkeys uint8
+	Flags uint8
+	Pad   [2]byte
+	Keys  []TcPeditKey
+}
+
+func (msg *TcPeditSel) Len() int {
+	return SizeofTcPeditSel + int(msg.Nkeys)*SizeofTcPeditKey
+}
+
+func DeserializeTcPeditSel(b []byte) *TcPeditSel {
+	x := &TcPeditSel{}
+	copy((*(*[SizeofTcPeditSel]byte)(unsafe.Pointer(x)))[:], b)
+	next := SizeofTcPeditSel
+	var i uint8
+	for i = 0; i < x.Nkeys; i++ {
+		x.Keys = append(x.Keys, *DeserializeTcPeditKey(b[next:]))
+		next += SizeofTcPeditKey
+	}
+	return x
+}
+
+func (x *TcPeditSel) Serialize() []byte {
+	// This can't just unsafe.cast because it must iterate through keys.
+	buf := make([]byte, x.Len())
+	copy(buf, (*(*[SizeofTcPeditSel]byte)(unsafe.Pointer(x)))[:])
+	next := SizeofTcPeditSel
+	for _, key := range x.Keys {
+		keyBuf := key.Serialize()
+		copy(buf[next:], keyBuf)
+		next += SizeofTcPeditKey
+	}
+	return buf
+}
+
+const (
+	TCA_ACT_CSUM = 16
+)
+
+const (
+	TCA_CSUM_UNSPEC = iota
+	TCA_CSUM_PARMS
+	TCA_CSUM_TM
+	TCA_CSUM_PAD
+	TCA_CSUM_MAX = TCA_CSUM_PAD
+)
+
+const (
+	TCA_CSUM_UPDATE_FLAG_IPV4HDR = 1 << iota
+	TCA_CSUM_UPDATE_FLAG_ICMP
+	TCA_CSUM_UPDATE_FLAG_IGMP
+	TCA_CSUM_UPDATE_FLAG_TCP
+	TCA_CSUM_UPDATE_FLAG_UDP
+	TCA_CSUM_UPDATE_FLAG_UDPLITE
+	TCA_CSUM_UPDATE_FLAG_SCTP
+)
+
+// struct tc_csum {
+// 	tc_gen;
+// 	__u32 update_flags;
+// };
+
+type TcCsum struct {
+	TcGen
+	UpdateFlags uint32
+}
+
+func (msg *TcCsum) Len() int {
+	return SizeofTcCsum
+}
+
+func DeserializeTcCsum(b []byte) *TcCsum {
+	return (*TcCsum)(unsafe.Pointer(&b[0:SizeofTcCsum][0]))
+}
+
+func (x *TcCsum) Serialize() []byte {
+	return (*(*[SizeofTcCsum]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_ACT_CONNMARK = 14
+)
+
+const (
+	TCA_CONNMARK_UNSPEC = iota
+	TCA_CONNMARK_PARMS
+	TCA_CONNMARK_TM
+	TCA_CONNMARK_PAD
+	TCA_CONNMARK_MAX = TCA_CONNMARK_PAD
+)
+
+// struct tc_connmark {
+// 	tc_gen;
+// 	__u16 zone;
+// };
+
+type TcConnmark struct {
+	TcGen
+	Zone uint16
+	Pad  [2]byte
+}
+
+func (msg *TcConnmark) Len() int {
+	return SizeofTcConnmark
+}
+
+func DeserializeTcConnmark(b []byte) *TcConnmark {
+	return (*TcConnmark)(unsafe.Pointer(&b[0:SizeofTcConnmark][0]))
+}
+
+func (x *TcConnmark) Serialize() []byte {
+	return (*(*[SizeofTcConnmark]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_ACT_POLICE = 1
+)
+
 // struct tc_police {
 // 	__u32			index;
 // 	int			action;
@@ -742,6 +1173,128 @@ const (
 	TCA_MATCHALL_FLAGS
 )
 
+// Classifier offload flags, shared by flower, matchall, u32 and bpf
+const (
+	TCA_CLS_FLAGS_SKIP_HW   = 1 << 0 /* don't offload filter to HW */
+	TCA_CLS_FLAGS_SKIP_SW   = 1 << 1 /* don't use filter in SW */
+	TCA_CLS_FLAGS_IN_HW     = 1 << 2 /* filter is offloaded to HW */
+	TCA_CLS_FLAGS_NOT_IN_HW = 1 << 3 /* filter isn't offloaded to HW */
+	TCA_CLS_FLAGS_VERBOSE   = 1 << 4 /* verbose logging */
+)
+
+const (
+	TCA_FLOWER_UNSPEC = iota
+	TCA_FLOWER_CLASSID
+	TCA_FLOWER_INDEV
+	TCA_FLOWER_ACT
+	TCA_FLOWER_KEY_ETH_DST       /* ETH_ALEN */
+	TCA_FLOWER_KEY_ETH_DST_MASK  /* ETH_ALEN */
+	TCA_FLOWER_KEY_ETH_SRC       /* ETH_ALEN */
+	TCA_FLOWER_KEY_ETH_SRC_MASK  /* ETH_ALEN */
+	TCA_FLOWER_KEY_ETH_TYPE      /* be16 */
+	TCA_FLOWER_KEY_IP_PROTO      /* u8 */
+	TCA_FLOWER_KEY_IPV4_SRC      /* be32 */
+	TCA_FLOWER_KEY_IPV4_SRC_MASK /* be32 */
+	TCA_FLOWER_KEY_IPV4_DST      /* be32 */
+	TCA_FLOWER_KEY_IPV4_DST_MASK /* be32 */
+	TCA_FLOWER_KEY_IPV6_SRC      /* struct in6_addr */
+	TCA_FLOWER_KEY_IPV6_SRC_MASK /* struct in6_addr */
+	TCA_FLOWER_KEY_IPV6_DST      /* struct in6_addr */
+	TCA_FLOWER_KEY_IPV6_DST_MASK /* struct in6_addr */
+	TCA_FLOWER_KEY_TCP_SRC       /* be16 */
+	TCA_FLOWER_KEY_TCP_DST       /* be16 */
+	TCA_FLOWER_KEY_UDP_SRC       /* be16 */
+	TCA_FLOWER_KEY_UDP_DST       /* be16 */
+	TCA_FLOWER_FLAGS
+	TCA_FLOWER_KEY_VLAN_ID               /* be16 */
+	TCA_FLOWER_KEY_VLAN_PRIO             /* u8   */
+	TCA_FLOWER_KEY_VLAN_ETH_TYPE         /* be16 */
+	TCA_FLOWER_KEY_ENC_KEY_ID            /* be32 */
+	TCA_FLOWER_KEY_ENC_IPV4_SRC          /* be32 */
+	TCA_FLOWER_KEY_ENC_IPV4_SRC_MASK     /* be32 */
+	TCA_FLOWER_KEY_ENC_IPV4_DST          /* be32 */
+	TCA_FLOWER_KEY_ENC_IPV4_DST_MASK     /* be32 */
+	TCA_FLOWER_KEY_ENC_IPV6_SRC          /* struct in6_addr */
+	TCA_FLOWER_KEY_ENC_IPV6_SRC_MASK     /* struct in6_addr */
+	TCA_FLOWER_KEY_ENC_IPV6_DST          /* struct in6_addr */
+	TCA_FLOWER_KEY_ENC_IPV6_DST_MASK     /* struct in6_addr */
+	TCA_FLOWER_KEY_TCP_SRC_MASK          /* be16 */
+	TCA_FLOWER_KEY_TCP_DST_MASK          /* be16 */
+	TCA_FLOWER_KEY_UDP_SRC_MASK          /* be16 */
+	TCA_FLOWER_KEY_UDP_DST_MASK          /* be16 */
+	TCA_FLOWER_KEY_SCTP_SRC_MASK         /* be16 */
+	TCA_FLOWER_KEY_SCTP_DST_MASK         /* be16 */
+	TCA_FLOWER_KEY_SCTP_SRC              /* be16 */
+	TCA_FLOWER_KEY_SCTP_DST              /* be16 */
+	TCA_FLOWER_KEY_ENC_UDP_SRC_PORT      /* be16 */
+	TCA_FLOWER_KEY_ENC_UDP_SRC_PORT_MASK /* be16 */
+	TCA_FLOWER_KEY_ENC_UDP_DST_PORT      /* be16 */
+	TCA_FLOWER_KEY_ENC_UDP_DST_PORT_MASK /* be16 */
+	TCA_FLOWER_KEY_FLAGS                 /* be32 */
+	TCA_FLOWER_KEY_FLAGS_MASK            /* be32 */
+	TCA_FLOWER_KEY_ICMPV4_CODE           /* u8 */
+	TCA_FLOWER_KEY_ICMPV4_CODE_MASK      /* u8 */
+	TCA_FLOWER_KEY_ICMPV4_TYPE           /* u8 */
+	TCA_FLOWER_KEY_ICMPV4_TYPE_MASK      /* u8 */
+	TCA_FLOWER_KEY_ICMPV6_CODE           /* u8 */
+	TCA_FLOWER_KEY_ICMPV6_CODE_MASK      /* u8 */
+	TCA_FLOWER_KEY_ICMPV6_TYPE           /* u8 */
+	TCA_FLOWER_KEY_ICMPV6_TYPE_MASK      /* u8 */
+	TCA_FLOWER_KEY_ARP_SIP               /* be32 */
+	TCA_FLOWER_KEY_ARP_SIP_MASK          /* be32 */
+	TCA_FLOWER_KEY_ARP_TIP               /* be32 */
+	TCA_FLOWER_KEY_ARP_TIP_MASK          /* be32 */
+	TCA_FLOWER_KEY_ARP_OP                /* u8 */
+	TCA_FLOWER_KEY_ARP_OP_MASK           /* u8 */
+	TCA_FLOWER_KEY_ARP_SHA               /* ETH_ALEN */
+	TCA_FLOWER_KEY_ARP_SHA_MASK          /* ETH_ALEN */
+	TCA_FLOWER_KEY_ARP_THA               /* ETH_ALEN */
+	TCA_FLOWER_KEY_ARP_THA_MASK          /* ETH_ALEN */
+	TCA_FLOWER_KEY_MPLS_TTL              /* u8 - 8 bits */
+	TCA_FLOWER_KEY_MPLS_BOS              /* u8 - 1 bit */
+	TCA_FLOWER_KEY_MPLS_TC               /* u8 - 3 bits */
+	TCA_FLOWER_KEY_MPLS_LABEL            /* be32 - 20 bits */
+	TCA_FLOWER_KEY_TCP_FLAGS             /* be16 */
+	TCA_FLOWER_KEY_TCP_FLAGS_MASK        /* be16 */
+	TCA_FLOWER_KEY_IP_TOS                /* u8 */
+	TCA_FLOWER_KEY_IP_TOS_MASK           /* u8 */
+	TCA_FLOWER_KEY_IP_TTL                /* u8 */
+	TCA_FLOWER_KEY_IP_TTL_MASK           /* u8 */
+	TCA_FLOWER_KEY_CVLAN_ID              /* be16 */
+	TCA_FLOWER_KEY_CVLAN_PRIO            /* u8   */
+	TCA_FLOWER_KEY_CVLAN_ETH_TYPE        /* be16 */
+	TCA_FLOWER_KEY_ENC_IP_TOS            /* u8 */
+	TCA_FLOWER_KEY_ENC_IP_TOS_MASK       /* u8 */
+	TCA_FLOWER_KEY_ENC_IP_TTL            /* u8 */
+	TCA_FLOWER_KEY_ENC_IP_TTL_MASK       /* u8 */
+	TCA_FLOWER_KEY_ENC_OPTS
+	TCA_FLOWER_KEY_ENC_OPTS_MASK
+	TCA_FLOWER_IN_HW_COUNT
+	TCA_FLOWER_KEY_PORT_SRC_MIN   /* be16 */
+	TCA_FLOWER_KEY_PORT_SRC_MAX   /* be16 */
+	TCA_FLOWER_KEY_PORT_DST_MIN   /* be16 */
+	TCA_FLOWER_KEY_PORT_DST_MAX   /* be16 */
+	TCA_FLOWER_KEY_CT_STATE       /* u16 */
+	TCA_FLOWER_KEY_CT_STATE_MASK  /* u16 */
+	TCA_FLOWER_KEY_CT_ZONE        /* u16 */
+	TCA_FLOWER_KEY_CT_ZONE_MASK   /* u16 */
+	TCA_FLOWER_KEY_CT_MARK        /* u32 */
+	TCA_FLOWER_KEY_CT_MARK_MASK   /* u32 */
+	TCA_FLOWER_KEY_CT_LABELS      /* u128 */
+	TCA_FLOWER_KEY_CT_LABELS_MASK /* u128 */
+	TCA_FLOWER_MAX                = TCA_FLOWER_KEY_CT_LABELS_MASK
+)
+
+// Conntrack state bits matched by TCA_FLOWER_KEY_CT_STATE
+const (
+	TCA_FLOWER_KEY_CT_FLAGS_NEW         = 1 << 0 /* Beginning of a new connection. */
+	TCA_FLOWER_KEY_CT_FLAGS_ESTABLISHED = 1 << 1 /* Part of an existing connection. */
+	TCA_FLOWER_KEY_CT_FLAGS_RELATED     = 1 << 2 /* Related to an established connection. */
+	TCA_FLOWER_KEY_CT_FLAGS_TRACKED     = 1 << 3 /* Conntrack has occurred. */
+	TCA_FLOWER_KEY_CT_FLAGS_INVALID     = 1 << 4 /* Conntrack is invalid. */
+	TCA_FLOWER_KEY_CT_FLAGS_REPLY       = 1 << 5 /* Packet is in the reply direction. */
+)
+
 const (
 	TCA_FQ_UNSPEC             = iota
 	TCA_FQ_PLIMIT             // limit of total number of packets in queue
@@ -770,9 +1323,328 @@ const (
 	TCA_FQ_CODEL_MEMORY_LIMIT
 )
 
+const (
+	TCA_FQ_CODEL_XSTATS_QDISC = iota
+	TCA_FQ_CODEL_XSTATS_CLASS
+)
+
 const (
 	TCA_HFSC_UNSPEC = iota
 	TCA_HFSC_RSC
 	TCA_HFSC_FSC
 	TCA_HFSC_USC
 )
+
+const (
+	TCA_CODEL_UNSPEC = iota
+	TCA_CODEL_TARGET
+	TCA_CODEL_LIMIT
+	TCA_CODEL_INTERVAL
+	TCA_CODEL_ECN
+	TCA_CODEL_CE_THRESHOLD
+)
+
+const (
+	TCA_CAKE_UNSPEC = iota
+	TCA_CAKE_PAD
+	TCA_CAKE_BASE_RATE64
+	TCA_CAKE_DIFFSERV_MODE
+	TCA_CAKE_ATM
+	TCA_CAKE_FLOW_MODE
+	TCA_CAKE_OVERHEAD
+	TCA_CAKE_RTT
+	TCA_CAKE_TARGET
+	TCA_CAKE_AUTORATE
+	TCA_CAKE_MEMORY
+	TCA_CAKE_NAT
+	TCA_CAKE_RAW
+	TCA_CAKE_WASH
+	TCA_CAKE_MPU
+	TCA_CAKE_INGRESS
+	TCA_CAKE_ACK_FILTER
+	TCA_CAKE_SPLIT_GSO
+	TCA_CAKE_FWMARK
+)
+
+const (
+	CAKE_FLOW_NONE = iota
+	CAKE_FLOW_SRC_IP
+	CAKE_FLOW_DST_IP
+	CAKE_FLOW_HOSTS
+	CAKE_FLOW_FLOWS
+	CAKE_FLOW_DUAL_SRC
+	CAKE_FLOW_DUAL_DST
+	CAKE_FLOW_TRIPLE
+)
+
+const (
+	CAKE_DIFFSERV_DIFFSERV3 = iota
+	CAKE_DIFFSERV_DIFFSERV4
+	CAKE_DIFFSERV_DIFFSERV8
+	CAKE_DIFFSERV_BESTEFFORT
+	CAKE_DIFFSERV_PRECEDENCE
+)
+
+const (
+	CAKE_ACK_NONE = iota
+	CAKE_ACK_FILTER
+	CAKE_ACK_AGGRESSIVE
+)
+
+const (
+	CAKE_ATM_NONE = iota
+	CAKE_ATM_ATM
+	CAKE_ATM_PTM
+)
+
+// struct tc_sfq_qopt {
+// 	unsigned	quantum;	/* Bytes per round allocated to flow */
+// 	int		perturb_period;	/* Period of hash perturbation */
+// 	__u32		limit;		/* Maximal packets in queue */
+// 	unsigned	divisor;	/* Hash divisor  */
+// 	unsigned	flows;		/* Maximal number of flows  */
+// };
+
+type TcSfqQopt struct {
+	Quantum       uint32
+	PerturbPeriod int32
+	Limit         uint32
+	Divisor       uint32
+	Flows         uint32
+}
+
+// struct tc_sfqred_stats {
+// 	__u32           prob_drop;      /* Early drops, below max threshold */
+// 	__u32           forced_drop;	/* Early drops, after max threshold */
+// 	__u32           prob_mark;      /* Marked packets, below max threshold */
+// 	__u32           forced_mark;    /* Marked packets, after max threshold */
+// 	__u32           prob_mark_head; /* Marked packets, below max threshold */
+// 	__u32           forced_mark_head;/* Marked packets, after max threshold */
+// };
+//
+// struct tc_sfq_qopt_v1 {
+// 	struct tc_sfq_qopt v0;
+// 	unsigned int	depth;		/* max number of packets per flow */
+// 	unsigned int	headdrop;
+// /* SFQRED parameters */
+// 	__u32		limit;		/* HARD maximal flow queue length (bytes) */
+// 	__u32		qth_min;	/* Min average length threshold (bytes) */
+// 	__u32		qth_max;	/* Max average length threshold (bytes) */
+// 	unsigned char   Wlog;		/* log(W)		*/
+// 	unsigned char   Plog;		/* log(P_max/(qth_max-qth_min))	*/
+// 	unsigned char   Scell_log;	/* cell size for idle damping */
+// 	unsigned char	flags;
+// 	__u32		max_P;		/* probability, high resolution */
+// /* SFQRED stats */
+// 	struct tc_sfqred_stats stats;
+// };
+
+type TcSfqRedStats struct {
+	ProbDrop       uint32
+	ForcedDrop     uint32
+	ProbMark       uint32
+	ForcedMark     uint32
+	ProbMarkHead   uint32
+	ForcedMarkHead uint32
+}
+
+type TcSfqQoptV1 struct {
+	TcSfqQopt
+	Depth    uint32
+	Headdrop uint32
+	RedLimit uint32
+	QthMin   uint32
+	QthMax   uint32
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	Flags    uint8
+	MaxP     uint32
+	Stats    TcSfqRedStats
+}
+
+func (msg *TcSfqQoptV1) Len() int {
+	return SizeofTcSfqQoptV1
+}
+
+func DeserializeTcSfqQoptV1(b []byte) *TcSfqQoptV1 {
+	return (*TcSfqQoptV1)(unsafe.Pointer(&b[0:SizeofTcSfqQoptV1][0]))
+}
+
+func (x *TcSfqQoptV1) Serialize() []byte {
+	return (*(*[SizeofTcSfqQoptV1]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_RED_UNSPEC = iota
+	TCA_RED_PARMS
+	TCA_RED_STAB
+	TCA_RED_MAX_P
+	TCA_RED_FLAGS
+	TCA_RED_EARLY_DROP_BLOCK
+	TCA_RED_MARK_BLOCK
+)
+
+const (
+	TC_RED_ECN        = 1
+	TC_RED_HARDDROP   = 2
+	TC_RED_ADAPTATIVE = 4
+	TC_RED_NODROP     = 8
+)
+
+// struct tc_red_qopt {
+// 	__u32		limit;		/* HARD maximal queue length (bytes)	*/
+// 	__u32		qth_min;	/* Min average length threshold (bytes) */
+// 	__u32		qth_max;	/* Max average length threshold (bytes) */
+// 	unsigned char   Wlog;		/* log(W)		*/
+// 	unsigned char   Plog;		/* log(P_max/(qth_max-qth_min))	*/
+// 	unsigned char   Scell_log;	/* cell size for idle damping */
+// 	unsigned char	flags;
+// };
+
+type TcRedQopt struct {
+	Limit    uint32
+	QthMin   uint32
+	QthMax   uint32
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	Flags    uint8
+}
+
+func (msg *TcRedQopt) Len() int {
+	return SizeofTcRedQopt
+}
+
+func DeserializeTcRedQopt(b []byte) *TcRedQopt {
+	return (*TcRedQopt)(unsafe.Pointer(&b[0:SizeofTcRedQopt][0]))
+}
+
+func (x *TcRedQopt) Serialize() []byte {
+	return (*(*[SizeofTcRedQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TC_QOPT_BITMASK   = 15
+	TC_QOPT_MAX_QUEUE = 16
+)
+
+// struct tc_mqprio_qopt {
+// 	__u8	num_tc;
+// 	__u8	prio_tc_map[TC_QOPT_BITMASK + 1];
+// 	__u8	hw;
+// 	__u16	count[TC_QOPT_MAX_QUEUE];
+// 	__u16	offset[TC_QOPT_MAX_QUEUE];
+// };
+
+type TcMqprioQopt struct {
+	NumTc     uint8
+	PrioTcMap [TC_QOPT_BITMASK + 1]uint8
+	Hw        uint8
+	Count     [TC_QOPT_MAX_QUEUE]uint16
+	Offset    [TC_QOPT_MAX_QUEUE]uint16
+}
+
+func (msg *TcMqprioQopt) Len() int {
+	return SizeofTcMqprioQopt
+}
+
+func DeserializeTcMqprioQopt(b []byte) *TcMqprioQopt {
+	return (*TcMqprioQopt)(unsafe.Pointer(&b[0:SizeofTcMqprioQopt][0]))
+}
+
+func (x *TcMqprioQopt) Serialize() []byte {
+	return (*(*[SizeofTcMqprioQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TC_MQPRIO_MODE_DCB = iota
+	TC_MQPRIO_MODE_CHANNEL
+)
+
+const (
+	TC_MQPRIO_SHAPER_DCB = iota
+	TC_MQPRIO_SHAPER_BW_RATE
+)
+
+const (
+	TCA_MQPRIO_UNSPEC = iota
+	TCA_MQPRIO_MODE
+	TCA_MQPRIO_SHAPER
+	TCA_MQPRIO_MIN_RATE64
+	TCA_MQPRIO_MAX_RATE64
+)
+
+const (
+	TCA_TAPRIO_ATTR_UNSPEC = iota
+	TCA_TAPRIO_ATTR_PRIOMAP
+	TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST
+	TCA_TAPRIO_ATTR_SCHED_BASE_TIME
+	TCA_TAPRIO_ATTR_SCHED_SINGLE_ENTRY
+	TCA_TAPRIO_ATTR_SCHED_CLOCKID
+	TCA_TAPRIO_PAD
+	TCA_TAPRIO_ATTR_ADMIN_SCHED
+	TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME
+	TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION
+	TCA_TAPRIO_ATTR_FLAGS
+	TCA_TAPRIO_ATTR_TXTIME_DELAY
+	TCA_TAPRIO_ATTR_TC_ENTRY
+)
+
+const (
+	TCA_TAPRIO_SCHED_UNSPEC = iota
+	TCA_TAPRIO_SCHED_ENTRY
+)
+
+const (
+	TCA_TAPRIO_SCHED_ENTRY_UNSPEC = iota
+	TCA_TAPRIO_SCHED_ENTRY_INDEX
+	TCA_TAPRIO_SCHED_ENTRY_CMD
+	TCA_TAPRIO_SCHED_ENTRY_GATE_MASK
+	TCA_TAPRIO_SCHED_ENTRY_INTERVAL
+)
+
+const (
+	TC_TAPRIO_CMD_SET_GATES = iota
+	TC_TAPRIO_CMD_SET_AND_HOLD
+	TC_TAPRIO_CMD_SET_AND_RELEASE
+)
+
+const (
+	TCA_TAPRIO_ATTR_FLAG_TXTIME_ASSIST = 1 << 0
+	TCA_TAPRIO_ATTR_FLAG_FULL_OFFLOAD  = 1 << 1
+)
+
+const (
+	TCA_ETS_UNSPEC = iota
+	TCA_ETS_NBANDS
+	TCA_ETS_NSTRICT
+	TCA_ETS_QUANTA
+	TCA_ETS_QUANTA_BAND
+	TCA_ETS_PRIOMAP
+	TCA_ETS_PRIOMAP_BAND
+)
+
+const TCQ_ETS_MAX_BANDS = 16
+
+// struct tc_multiq_qopt {
+// 	__u16	bands;			/* Number of bands */
+// 	__u16	max_bands;		/* Maximum number of queues */
+// };
+
+type TcMultiqQopt struct {
+	Bands    uint16
+	MaxBands uint16
+}
+
+func (msg *TcMultiqQopt) Len() int {
+	return SizeofTcMultiqQopt
+}
+
+func DeserializeTcMultiqQopt(b []byte) *TcMultiqQopt {
+	return (*TcMultiqQopt)(unsafe.Pointer(&b[0:SizeofTcMultiqQopt][0]))
+}
+
+func (x *TcMultiqQopt) Serialize() []byte {
+	return (*(*[SizeofTcMultiqQopt]byte)(unsafe.Pointer(x)))[:]
+}
@@ -171,3 +171,243 @@ func TestTcHtbCoptDeserializeSerialize(t *testing.T) {
 	msg := DeserializeTcHtbCopt(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+/* TcGactP */
+func (msg *TcGactP) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint16(b[0:2], msg.Ptype)
+	native.PutUint16(b[2:4], msg.Pval)
+	native.PutUint32(b[4:8], uint32(msg.Paction))
+}
+
+func (msg *TcGactP) serializeSafe() []byte {
+	length := SizeofTcGactP
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcGactPSafe(b []byte) *TcGactP {
+	var msg = TcGactP{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcGactP]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcGactPDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcGactP)
+	rand.Read(orig)
+	safemsg := deserializeTcGactPSafe(orig)
+	msg := DeserializeTcGactP(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcTunnelKey */
+func (msg *TcGen) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Index)
+	native.PutUint32(b[4:8], msg.Capab)
+	native.PutUint32(b[8:12], uint32(msg.Action))
+	native.PutUint32(b[12:16], uint32(msg.Refcnt))
+	native.PutUint32(b[16:20], uint32(msg.Bindcnt))
+}
+
+func (msg *TcTunnelKey) write(b []byte) {
+	native := NativeEndian()
+	msg.TcGen.write(b[0:SizeofTcGen])
+	native.PutUint32(b[SizeofTcGen:SizeofTcTunnelKey], uint32(msg.Action))
+}
+
+func (msg *TcTunnelKey) serializeSafe() []byte {
+	length := SizeofTcTunnelKey
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcTunnelKeySafe(b []byte) *TcTunnelKey {
+	var msg = TcTunnelKey{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcTunnelKey]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcTunnelKeyDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcTunnelKey)
+	rand.Read(orig)
+	safemsg := deserializeTcTunnelKeySafe(orig)
+	msg := DeserializeTcTunnelKey(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcConnmark */
+func (msg *TcConnmark) write(b []byte) {
+	native := NativeEndian()
+	msg.TcGen.write(b[0:SizeofTcGen])
+	native.PutUint16(b[SizeofTcGen:SizeofTcGen+2], msg.Zone)
+	copy(b[SizeofTcGen+2:SizeofTcConnmark], msg.Pad[:])
+}
+
+func (msg *TcConnmark) serializeSafe() []byte {
+	length := SizeofTcConnmark
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcConnmarkSafe(b []byte) *TcConnmark {
+	var msg = TcConnmark{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcConnmark]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcConnmarkDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcConnmark)
+	rand.Read(orig)
+	safemsg := deserializeTcConnmarkSafe(orig)
+	msg := DeserializeTcConnmark(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcPeditKey */
+func (msg *TcPeditKey) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Mask)
+	native.PutUint32(b[4:8], msg.Val)
+	native.PutUint32(b[8:12], msg.Off)
+	native.PutUint32(b[12:16], msg.At)
+	native.PutUint32(b[16:20], msg.OffMask)
+	native.PutUint32(b[20:24], msg.Shift)
+}
+
+func (msg *TcPeditKey) serializeSafe() []byte {
+	length := SizeofTcPeditKey
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcPeditKeySafe(b []byte) *TcPeditKey {
+	var msg = TcPeditKey{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcPeditKey]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcPeditKeyDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcPeditKey)
+	rand.Read(orig)
+	safemsg := deserializeTcPeditKeySafe(orig)
+	msg := DeserializeTcPeditKey(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcSfqQoptV1 */
+func (msg *TcSfqQoptV1) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Quantum)
+	native.PutUint32(b[4:8], uint32(msg.PerturbPeriod))
+	native.PutUint32(b[8:12], msg.Limit)
+	native.PutUint32(b[12:16], msg.Divisor)
+	native.PutUint32(b[16:20], msg.Flows)
+	native.PutUint32(b[20:24], msg.Depth)
+	native.PutUint32(b[24:28], msg.Headdrop)
+	native.PutUint32(b[28:32], msg.RedLimit)
+	native.PutUint32(b[32:36], msg.QthMin)
+	native.PutUint32(b[36:40], msg.QthMax)
+	b[40] = msg.Wlog
+	b[41] = msg.Plog
+	b[42] = msg.ScellLog
+	b[43] = msg.Flags
+	native.PutUint32(b[44:48], msg.MaxP)
+	native.PutUint32(b[48:52], msg.Stats.ProbDrop)
+	native.PutUint32(b[52:56], msg.Stats.ForcedDrop)
+	native.PutUint32(b[56:60], msg.Stats.ProbMark)
+	native.PutUint32(b[60:64], msg.Stats.ForcedMark)
+	native.PutUint32(b[64:68], msg.Stats.ProbMarkHead)
+	native.PutUint32(b[68:72], msg.Stats.ForcedMarkHead)
+}
+
+func (msg *TcSfqQoptV1) serializeSafe() []byte {
+	length := SizeofTcSfqQoptV1
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcSfqQoptV1Safe(b []byte) *TcSfqQoptV1 {
+	var msg = TcSfqQoptV1{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcSfqQoptV1]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcSfqQoptV1DeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcSfqQoptV1)
+	rand.Read(orig)
+	safemsg := deserializeTcSfqQoptV1Safe(orig)
+	msg := DeserializeTcSfqQoptV1(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcRedQopt */
+func (msg *TcRedQopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Limit)
+	native.PutUint32(b[4:8], msg.QthMin)
+	native.PutUint32(b[8:12], msg.QthMax)
+	b[12] = msg.Wlog
+	b[13] = msg.Plog
+	b[14] = msg.ScellLog
+	b[15] = msg.Flags
+}
+
+func (msg *TcRedQopt) serializeSafe() []byte {
+	length := SizeofTcRedQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcRedQoptSafe(b []byte) *TcRedQopt {
+	var msg = TcRedQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcRedQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcRedQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcRedQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcRedQoptSafe(orig)
+	msg := DeserializeTcRedQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcMqprioQopt */
+func (msg *TcMqprioQopt) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.NumTc
+	copy(b[1:17], msg.PrioTcMap[:])
+	b[17] = msg.Hw
+	for i := range msg.Count {
+		native.PutUint16(b[18+2*i:20+2*i], msg.Count[i])
+		native.PutUint16(b[50+2*i:52+2*i], msg.Offset[i])
+	}
+}
+
+func (msg *TcMqprioQopt) serializeSafe() []byte {
+	length := SizeofTcMqprioQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcMqprioQoptSafe(b []byte) *TcMqprioQopt {
+	var msg = TcMqprioQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcMqprioQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcMqprioQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcMqprioQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcMqprioQoptSafe(orig)
+	msg := DeserializeTcMqprioQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
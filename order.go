@@ -30,3 +30,13 @@ func ntohl(buf []byte) uint32 {
 func ntohs(buf []byte) uint16 {
 	return binary.BigEndian.Uint16(buf)
 }
+
+func htonll(val uint64) []byte {
+	bytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bytes, val)
+	return bytes
+}
+
+func ntohll(buf []byte) uint64 {
+	return binary.BigEndian.Uint64(buf)
+}
@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -49,6 +50,12 @@ func (h *Handle) LinkGetProtinfo(link Link) (Protinfo, error) {
 	return pi, fmt.Errorf("Device with index %d not found", base.Index)
 }
 
+// LinkGetProtinfoContext is like LinkGetProtinfo but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) LinkGetProtinfoContext(ctx context.Context, link Link) (Protinfo, error) {
+	return h.withContext(ctx).LinkGetProtinfo(link)
+}
+
 func parseProtinfo(infos []syscall.NetlinkRouteAttr) *Protinfo {
 	var pi Protinfo
 	for _, info := range infos {
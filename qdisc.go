@@ -26,16 +26,21 @@ type Qdisc interface {
 // has a handle, a parent and a refcnt. The root qdisc of a device should
 // have parent == HANDLE_ROOT.
 type QdiscAttrs struct {
-	LinkIndex int
-	Handle    uint32
-	Parent    uint32
-	Refcnt    uint32 // read only
+	LinkIndex  int
+	Handle     uint32
+	Parent     uint32
+	Refcnt     uint32 // read only
+	Statistics *QdiscStatistics
 }
 
 func (q QdiscAttrs) String() string {
 	return fmt.Sprintf("{LinkIndex: %d, Handle: %s, Parent: %s, Refcnt: %d}", q.LinkIndex, HandleStr(q.Handle), HandleStr(q.Parent), q.Refcnt)
 }
 
+// QdiscStatistics representation based on generic networking statistics for netlink.
+// See Documentation/networking/gen_stats.txt in Linux source code for more details.
+type QdiscStatistics ClassStatistics
+
 func MakeHandle(major, minor uint16) uint32 {
 	return (uint32(major) << 16) | uint32(minor)
 }
@@ -213,6 +218,9 @@ func (qdisc *Tbf) Type() string {
 // Ingress is a qdisc for adding ingress filters
 type Ingress struct {
 	QdiscAttrs
+	// IngressBlock shares the filters of the given block index with
+	// every other qdisc using the same block.
+	IngressBlock uint32
 }
 
 func (qdisc *Ingress) Attrs() *QdiscAttrs {
@@ -223,6 +231,25 @@ func (qdisc *Ingress) Type() string {
 	return "ingress"
 }
 
+// Clsact is a qdisc for adding filters on both the ingress and the
+// egress hook of a link. Its Parent must be HANDLE_CLSACT and filters
+// are attached with HANDLE_MIN_INGRESS or HANDLE_MIN_EGRESS as parent.
+type Clsact struct {
+	QdiscAttrs
+	// IngressBlock and EgressBlock share the filters of the given block
+	// index with every other qdisc using the same block.
+	IngressBlock uint32
+	EgressBlock  uint32
+}
+
+func (qdisc *Clsact) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Clsact) Type() string {
+	return "clsact"
+}
+
 // GenericQdisc qdiscs represent types that are not currently understood
 // by this netlink library.
 type GenericQdisc struct {
@@ -281,6 +308,27 @@ type Fq struct {
 	Buckets          uint32
 	FlowRefillDelay  uint32
 	LowRateThreshold uint32
+	XStats           *FqXStats // read only
+}
+
+// FqXStats are the fq specific statistics of the qdisc.
+// Ref: struct tc_fq_qd_stats { ... }
+type FqXStats struct {
+	GcFlows             uint64
+	HighprioPackets     uint64
+	TcpRetrans          uint64
+	Throttled           uint64
+	FlowsPlimit         uint64
+	PktsTooLong         uint64
+	AllocationErrors    uint64
+	TimeNextDelayedFlow int64
+	Flows               uint32
+	InactiveFlows       uint32
+	ThrottledFlows      uint32
+	UnthrottleLatencyNs uint32
+	CeMark              uint64 // packets above ce_threshold
+	HorizonDrops        uint64
+	HorizonCaps         uint64
 }
 
 func (fq *Fq) String() string {
@@ -315,6 +363,21 @@ type FqCodel struct {
 	Flows    uint32
 	Quantum  uint32
 	// There are some more attributes here, but support for them seems not ubiquitous
+	XStats *FqCodelXStats // read only
+}
+
+// FqCodelXStats are the fq_codel specific statistics of the qdisc.
+// Ref: struct tc_fq_codel_qd_stats { ... }
+type FqCodelXStats struct {
+	Maxpacket      uint32 // largest packet we've seen so far
+	DropOverlimit  uint32 // number of time max qdisc packet limit was hit
+	EcnMark        uint32 // number of packets we ECN marked instead of being dropped
+	NewFlowCount   uint32 // number of time packets created a 'new flow'
+	NewFlowsLen    uint32 // count of flows in new list
+	OldFlowsLen    uint32 // count of flows in old list
+	CeMark         uint32 // packets above ce_threshold
+	MemoryUsage    uint32 // in bytes
+	DropOvermemory uint32
 }
 
 func (fqcodel *FqCodel) String() string {
@@ -338,3 +401,380 @@ func (qdisc *FqCodel) Attrs() *QdiscAttrs {
 func (qdisc *FqCodel) Type() string {
 	return "fq_codel"
 }
+
+// Codel (Controlled Delay) is an AQM qdisc that drops packets based on
+// their sojourn time in the queue.
+type Codel struct {
+	QdiscAttrs
+	Target      uint32
+	Limit       uint32
+	Interval    uint32
+	ECN         uint32
+	CEThreshold uint32
+	XStats      *CodelXStats // read only
+}
+
+// CodelXStats are the codel specific statistics of the qdisc.
+// Ref: struct tc_codel_xstats { ... }
+type CodelXStats struct {
+	Maxpacket     uint32 // largest packet we've seen so far
+	Count         uint32 // how many drops we've done since the last time we entered dropping state
+	Lastcount     uint32 // count at entry to dropping state
+	Ldelay        uint32 // in-queue delay seen by most recently dequeued packet
+	DropNext      int32  // time to drop next packet
+	DropOverlimit uint32 // number of time max qdisc packet limit was hit
+	EcnMark       uint32 // number of packets we ECN marked instead of dropping
+	Dropping      uint32 // are we in dropping state ?
+	CeMark        uint32 // number of CE marked packets because of ce_threshold
+}
+
+func (codel *Codel) String() string {
+	return fmt.Sprintf(
+		"{%v -- Target: %v, Limit: %v, Interval: %v, ECN: %v, CEThreshold: %v}",
+		codel.Attrs(), codel.Target, codel.Limit, codel.Interval, codel.ECN, codel.CEThreshold,
+	)
+}
+
+func (qdisc *Codel) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Codel) Type() string {
+	return "codel"
+}
+
+type CakeDiffservMode uint32
+
+const (
+	CAKE_DIFFSERV_DIFFSERV3 CakeDiffservMode = iota
+	CAKE_DIFFSERV_DIFFSERV4
+	CAKE_DIFFSERV_DIFFSERV8
+	CAKE_DIFFSERV_BESTEFFORT
+	CAKE_DIFFSERV_PRECEDENCE
+)
+
+type CakeFlowMode uint32
+
+const (
+	CAKE_FLOW_NONE CakeFlowMode = iota
+	CAKE_FLOW_SRC_IP
+	CAKE_FLOW_DST_IP
+	CAKE_FLOW_HOSTS
+	CAKE_FLOW_FLOWS
+	CAKE_FLOW_DUAL_SRC
+	CAKE_FLOW_DUAL_DST
+	CAKE_FLOW_TRIPLE
+)
+
+type CakeAckFilter uint32
+
+const (
+	CAKE_ACK_NONE CakeAckFilter = iota
+	CAKE_ACK_FILTER
+	CAKE_ACK_AGGRESSIVE
+)
+
+type CakeAtmMode uint32
+
+const (
+	CAKE_ATM_NONE CakeAtmMode = iota
+	CAKE_ATM_ATM
+	CAKE_ATM_PTM
+)
+
+// Cake (Common Applications Kept Enhanced) is a shaping AQM qdisc with
+// per host and per flow fairness, meant for home gateways.
+// Use NewCake to start from the kernel defaults.
+type Cake struct {
+	QdiscAttrs
+	// In bytes per second, 0 is unlimited
+	Bandwidth    uint64
+	DiffservMode CakeDiffservMode
+	FlowMode     CakeFlowMode
+	AckFilter    CakeAckFilter
+	Atm          CakeAtmMode
+	// Overhead in bytes added to every packet when shaping
+	Overhead int32
+	Mpu      uint32
+	// In usec
+	RTT    uint32
+	Target uint32
+	// In bytes, 0 picks a limit from the bandwidth
+	Memory   uint32
+	Fwmark   uint32
+	Nat      bool
+	Wash     bool
+	Ingress  bool
+	SplitGSO bool
+	Autorate bool
+}
+
+func NewCake(attrs QdiscAttrs) *Cake {
+	return &Cake{
+		QdiscAttrs: attrs,
+		FlowMode:   CAKE_FLOW_TRIPLE,
+		SplitGSO:   true,
+	}
+}
+
+func (cake *Cake) String() string {
+	return fmt.Sprintf(
+		"{%v -- Bandwidth: %v, Diffserv: %v, FlowMode: %v, AckFilter: %v, Atm: %v, Overhead: %v, Mpu: %v, RTT: %v, Target: %v, Memory: %v, Fwmark: %v, Nat: %v, Wash: %v, Ingress: %v, SplitGSO: %v, Autorate: %v}",
+		cake.Attrs(), cake.Bandwidth, cake.DiffservMode, cake.FlowMode, cake.AckFilter, cake.Atm, cake.Overhead, cake.Mpu, cake.RTT, cake.Target, cake.Memory, cake.Fwmark, cake.Nat, cake.Wash, cake.Ingress, cake.SplitGSO, cake.Autorate,
+	)
+}
+
+func (qdisc *Cake) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Cake) Type() string {
+	return "cake"
+}
+
+// Sfq (Stochastic Fairness Queueing) is a classless qdisc that round
+// robins over hashed flows.
+type Sfq struct {
+	QdiscAttrs
+	// In bytes
+	Quantum uint32
+	// In seconds
+	Perturb int32
+	// In packets
+	Limit    uint32
+	Divisor  uint32
+	Flows    uint32
+	Depth    uint32
+	Headdrop bool
+}
+
+func (sfq *Sfq) String() string {
+	return fmt.Sprintf(
+		"{%v -- Quantum: %v, Perturb: %v, Limit: %v, Divisor: %v, Flows: %v, Depth: %v, Headdrop: %v}",
+		sfq.Attrs(), sfq.Quantum, sfq.Perturb, sfq.Limit, sfq.Divisor, sfq.Flows, sfq.Depth, sfq.Headdrop,
+	)
+}
+
+func (qdisc *Sfq) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Sfq) Type() string {
+	return "sfq"
+}
+
+// Red (Random Early Detection) is a classless qdisc that drops or marks
+// packets with a probability growing with the average queue length.
+// Avpkt, Burst and Bandwidth are only used to compute the kernel
+// parameters when adding the qdisc and are not reported back.
+type Red struct {
+	QdiscAttrs
+	// In bytes
+	Limit uint32
+	Min   uint32
+	Max   uint32
+	Avpkt uint32
+	// In packets
+	Burst       uint32
+	Probability float64
+	// In bytes per second
+	Bandwidth uint64
+	ECN       bool
+	Harddrop  bool
+	Adaptive  bool
+	XStats    *RedXStats // read only
+}
+
+// RedXStats are the red specific statistics of the qdisc.
+// Ref: struct tc_red_xstats { ... }
+type RedXStats struct {
+	Early  uint32 // Early drops
+	Pdrop  uint32 // Drops due to queue limits
+	Other  uint32 // Drops due to drop() calls
+	Marked uint32 // Marked packets
+}
+
+// NewRed returns a Red with the defaults of `tc qdisc add red`, deriving
+// Min and Burst from max.
+func NewRed(attrs QdiscAttrs, limit, max uint32) *Red {
+	red := &Red{
+		QdiscAttrs:  attrs,
+		Limit:       limit,
+		Max:         max,
+		Min:         max / 3,
+		Avpkt:       1000,
+		Probability: 0.02,
+		Bandwidth:   10000000 / 8,
+	}
+	red.Burst = (2*red.Min + red.Max) / (3 * red.Avpkt)
+	return red
+}
+
+func (red *Red) String() string {
+	return fmt.Sprintf(
+		"{%v -- Limit: %v, Min: %v, Max: %v, Probability: %v, ECN: %v, Harddrop: %v, Adaptive: %v}",
+		red.Attrs(), red.Limit, red.Min, red.Max, red.Probability, red.ECN, red.Harddrop, red.Adaptive,
+	)
+}
+
+func (qdisc *Red) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Red) Type() string {
+	return "red"
+}
+
+const TC_QOPT_MAX_QUEUE = 16
+
+const (
+	MQPRIO_MODE_DCB     = 0
+	MQPRIO_MODE_CHANNEL = 1
+)
+
+const (
+	MQPRIO_SHAPER_DCB     = 0
+	MQPRIO_SHAPER_BW_RATE = 1
+)
+
+// Mqprio maps priorities to traffic classes and traffic classes to
+// ranges of transmit queues of a multiqueue device.
+type Mqprio struct {
+	QdiscAttrs
+	NumTc     uint8
+	PrioTcMap [PRIORITY_MAP_LEN]uint8
+	// Hw offloads the mapping to the device
+	Hw uint8
+	// Count and Offset give the queue range of each traffic class
+	Count  [TC_QOPT_MAX_QUEUE]uint16
+	Offset [TC_QOPT_MAX_QUEUE]uint16
+	// Mode, Shaper and the rates are only used with Hw
+	Mode   uint16
+	Shaper uint16
+	// In bytes per second, one per traffic class
+	MinRate []uint64
+	MaxRate []uint64
+}
+
+func (mqprio *Mqprio) String() string {
+	return fmt.Sprintf(
+		"{%v -- NumTc: %v, PrioTcMap: %v, Hw: %v, Count: %v, Offset: %v}",
+		mqprio.Attrs(), mqprio.NumTc, mqprio.PrioTcMap, mqprio.Hw, mqprio.Count, mqprio.Offset,
+	)
+}
+
+func (qdisc *Mqprio) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Mqprio) Type() string {
+	return "mqprio"
+}
+
+type TaprioCmd uint8
+
+const (
+	TAPRIO_CMD_SET_GATES TaprioCmd = iota
+	TAPRIO_CMD_SET_AND_HOLD
+	TAPRIO_CMD_SET_AND_RELEASE
+)
+
+const (
+	TAPRIO_FLAG_TXTIME_ASSIST = 1 << 0
+	TAPRIO_FLAG_FULL_OFFLOAD  = 1 << 1
+)
+
+// TaprioSchedEntry opens the gates in GateMask for Interval nanoseconds.
+type TaprioSchedEntry struct {
+	// Index is only reported by the kernel
+	Index    uint32
+	Command  TaprioCmd
+	GateMask uint32
+	Interval uint32
+}
+
+// TaprioSchedule is a gate control list repeated every cycle from
+// BaseTime. Times are in nanoseconds, a zero CycleTime is the sum of the
+// entry intervals.
+type TaprioSchedule struct {
+	BaseTime           int64
+	CycleTime          int64
+	CycleTimeExtension int64
+	Entries            []TaprioSchedEntry
+}
+
+// Taprio is the time aware priority shaper of IEEE 802.1Qbv. Traffic
+// classes are mapped to queues like with Mqprio.
+type Taprio struct {
+	QdiscAttrs
+	NumTc     uint8
+	PrioTcMap [PRIORITY_MAP_LEN]uint8
+	Count     [TC_QOPT_MAX_QUEUE]uint16
+	Offset    [TC_QOPT_MAX_QUEUE]uint16
+	// ClockID is ignored with TAPRIO_FLAG_FULL_OFFLOAD
+	ClockID int32
+	Flags   uint32
+	// In nanoseconds, used with TAPRIO_FLAG_TXTIME_ASSIST
+	TxTimeDelay uint32
+	Schedule    TaprioSchedule
+	// AdminSchedule is the schedule waiting for its base time to replace
+	// Schedule. It is only reported by the kernel.
+	AdminSchedule *TaprioSchedule
+}
+
+func (taprio *Taprio) String() string {
+	return fmt.Sprintf(
+		"{%v -- NumTc: %v, PrioTcMap: %v, Count: %v, Offset: %v, ClockID: %v, Flags: %v, Schedule: %+v}",
+		taprio.Attrs(), taprio.NumTc, taprio.PrioTcMap, taprio.Count, taprio.Offset, taprio.ClockID, taprio.Flags, taprio.Schedule,
+	)
+}
+
+func (qdisc *Taprio) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Taprio) Type() string {
+	return "taprio"
+}
+
+// Ets (Enhanced Transmission Selection) is a classful qdisc with Strict
+// strict priority bands followed by deficit round robin bands.
+type Ets struct {
+	QdiscAttrs
+	Bands  uint8
+	Strict uint8
+	// Quanta in bytes of the bands after the strict ones
+	Quanta  []uint32
+	PrioMap []uint8
+}
+
+func (ets *Ets) String() string {
+	return fmt.Sprintf(
+		"{%v -- Bands: %v, Strict: %v, Quanta: %v, PrioMap: %v}",
+		ets.Attrs(), ets.Bands, ets.Strict, ets.Quanta, ets.PrioMap,
+	)
+}
+
+func (qdisc *Ets) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Ets) Type() string {
+	return "ets"
+}
+
+// Multiq is a classful qdisc with one band per transmit queue of a
+// multiqueue device. The kernel sets Bands to the number of queues.
+type Multiq struct {
+	QdiscAttrs
+	Bands    uint16
+	MaxBands uint16
+}
+
+func (qdisc *Multiq) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Multiq) Type() string {
+	return "multiq"
+}
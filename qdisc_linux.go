@@ -1,8 +1,11 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"strconv"
 	"strings"
 	"syscall"
@@ -88,6 +91,12 @@ func (h *Handle) QdiscDel(qdisc Qdisc) error {
 	return h.qdiscModify(unix.RTM_DELQDISC, 0, qdisc)
 }
 
+// QdiscDelContext is like QdiscDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) QdiscDelContext(ctx context.Context, qdisc Qdisc) error {
+	return h.withContext(ctx).QdiscDel(qdisc)
+}
+
 // QdiscChange will change a qdisc in place
 // Equivalent to: `tc qdisc change $qdisc`
 // The parent and handle MUST NOT be changed.
@@ -102,6 +111,12 @@ func (h *Handle) QdiscChange(qdisc Qdisc) error {
 	return h.qdiscModify(unix.RTM_NEWQDISC, 0, qdisc)
 }
 
+// QdiscChangeContext is like QdiscChange but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) QdiscChangeContext(ctx context.Context, qdisc Qdisc) error {
+	return h.withContext(ctx).QdiscChange(qdisc)
+}
+
 // QdiscReplace will replace a qdisc to the system.
 // Equivalent to: `tc qdisc replace $qdisc`
 // The handle MUST change.
@@ -119,6 +134,12 @@ func (h *Handle) QdiscReplace(qdisc Qdisc) error {
 		qdisc)
 }
 
+// QdiscReplaceContext is like QdiscReplace but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) QdiscReplaceContext(ctx context.Context, qdisc Qdisc) error {
+	return h.withContext(ctx).QdiscReplace(qdisc)
+}
+
 // QdiscAdd will add a qdisc to the system.
 // Equivalent to: `tc qdisc add $qdisc`
 func QdiscAdd(qdisc Qdisc) error {
@@ -134,6 +155,12 @@ func (h *Handle) QdiscAdd(qdisc Qdisc) error {
 		qdisc)
 }
 
+// QdiscAddContext is like QdiscAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) QdiscAddContext(ctx context.Context, qdisc Qdisc) error {
+	return h.withContext(ctx).QdiscAdd(qdisc)
+}
+
 func (h *Handle) qdiscModify(cmd, flags int, qdisc Qdisc) error {
 	req := h.newNetlinkRequest(cmd, flags|unix.NLM_F_ACK)
 	base := qdisc.Attrs()
@@ -236,6 +263,19 @@ func qdiscPayload(req *nl.NetlinkRequest, qdisc Qdisc) error {
 		if qdisc.Attrs().Parent != HANDLE_INGRESS {
 			return fmt.Errorf("Ingress filters must set Parent to HANDLE_INGRESS")
 		}
+		if qdisc.IngressBlock != 0 {
+			req.AddData(nl.NewRtAttr(nl.TCA_INGRESS_BLOCK, nl.Uint32Attr(qdisc.IngressBlock)))
+		}
+	case *Clsact:
+		if qdisc.Attrs().Parent != HANDLE_CLSACT {
+			return fmt.Errorf("Clsact qdiscs must set Parent to HANDLE_CLSACT")
+		}
+		if qdisc.IngressBlock != 0 {
+			req.AddData(nl.NewRtAttr(nl.TCA_INGRESS_BLOCK, nl.Uint32Attr(qdisc.IngressBlock)))
+		}
+		if qdisc.EgressBlock != 0 {
+			req.AddData(nl.NewRtAttr(nl.TCA_EGRESS_BLOCK, nl.Uint32Attr(qdisc.EgressBlock)))
+		}
 	case *FqCodel:
 		nl.NewRtAttrChild(options, nl.TCA_FQ_CODEL_ECN, nl.Uint32Attr((uint32(qdisc.ECN))))
 		if qdisc.Limit > 0 {
@@ -278,12 +318,242 @@ func qdiscPayload(req *nl.NetlinkRequest, qdisc Qdisc) error {
 		if qdisc.FlowDefaultRate > 0 {
 			nl.NewRtAttrChild(options, nl.TCA_FQ_FLOW_DEFAULT_RATE, nl.Uint32Attr((uint32(qdisc.FlowDefaultRate))))
 		}
+	case *Codel:
+		nl.NewRtAttrChild(options, nl.TCA_CODEL_ECN, nl.Uint32Attr(qdisc.ECN))
+		if qdisc.Target > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CODEL_TARGET, nl.Uint32Attr(qdisc.Target))
+		}
+		if qdisc.Limit > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CODEL_LIMIT, nl.Uint32Attr(qdisc.Limit))
+		}
+		if qdisc.Interval > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CODEL_INTERVAL, nl.Uint32Attr(qdisc.Interval))
+		}
+		if qdisc.CEThreshold > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CODEL_CE_THRESHOLD, nl.Uint32Attr(qdisc.CEThreshold))
+		}
+	case *Cake:
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_BASE_RATE64, nl.Uint64Attr(qdisc.Bandwidth))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_DIFFSERV_MODE, nl.Uint32Attr(uint32(qdisc.DiffservMode)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_FLOW_MODE, nl.Uint32Attr(uint32(qdisc.FlowMode)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_ACK_FILTER, nl.Uint32Attr(uint32(qdisc.AckFilter)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_ATM, nl.Uint32Attr(uint32(qdisc.Atm)))
+		if qdisc.Overhead != 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_OVERHEAD, nl.Uint32Attr(uint32(qdisc.Overhead)))
+		}
+		if qdisc.Mpu > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_MPU, nl.Uint32Attr(qdisc.Mpu))
+		}
+		if qdisc.RTT > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_RTT, nl.Uint32Attr(qdisc.RTT))
+		}
+		if qdisc.Target > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_TARGET, nl.Uint32Attr(qdisc.Target))
+		}
+		if qdisc.Memory > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_MEMORY, nl.Uint32Attr(qdisc.Memory))
+		}
+		if qdisc.Fwmark > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_FWMARK, nl.Uint32Attr(qdisc.Fwmark))
+		}
+		// nat fails on kernels without conntrack, so only send it when set
+		if qdisc.Nat {
+			nl.NewRtAttrChild(options, nl.TCA_CAKE_NAT, nl.Uint32Attr(1))
+		}
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_WASH, nl.Uint32Attr(boolToUint32(qdisc.Wash)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_INGRESS, nl.Uint32Attr(boolToUint32(qdisc.Ingress)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_SPLIT_GSO, nl.Uint32Attr(boolToUint32(qdisc.SplitGSO)))
+		nl.NewRtAttrChild(options, nl.TCA_CAKE_AUTORATE, nl.Uint32Attr(boolToUint32(qdisc.Autorate)))
+	case *Sfq:
+		opt := nl.TcSfqQoptV1{}
+		opt.Quantum = qdisc.Quantum
+		opt.PerturbPeriod = qdisc.Perturb
+		opt.Limit = qdisc.Limit
+		opt.Divisor = qdisc.Divisor
+		opt.Flows = qdisc.Flows
+		opt.Depth = qdisc.Depth
+		opt.Headdrop = boolToUint32(qdisc.Headdrop)
+		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+	case *Red:
+		opt, stab, err := redParms(qdisc)
+		if err != nil {
+			return err
+		}
+		nl.NewRtAttrChild(options, nl.TCA_RED_PARMS, opt.Serialize())
+		nl.NewRtAttrChild(options, nl.TCA_RED_STAB, stab)
+		if qdisc.Probability < 1 {
+			nl.NewRtAttrChild(options, nl.TCA_RED_MAX_P, nl.Uint32Attr(uint32(qdisc.Probability*(1<<32))))
+		}
+	case *Mqprio:
+		opt := nl.TcMqprioQopt{
+			NumTc:     qdisc.NumTc,
+			PrioTcMap: qdisc.PrioTcMap,
+			Hw:        qdisc.Hw,
+			Count:     qdisc.Count,
+			Offset:    qdisc.Offset,
+		}
+		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+		// the kernel rejects these attributes without offload
+		if qdisc.Hw != 0 {
+			nl.NewRtAttrChild(options, nl.TCA_MQPRIO_MODE, nl.Uint16Attr(qdisc.Mode))
+			nl.NewRtAttrChild(options, nl.TCA_MQPRIO_SHAPER, nl.Uint16Attr(qdisc.Shaper))
+			if len(qdisc.MinRate) > 0 {
+				rates := nl.NewRtAttrChild(options, nl.TCA_MQPRIO_MIN_RATE64, nil)
+				for _, rate := range qdisc.MinRate {
+					nl.NewRtAttrChild(rates, nl.TCA_MQPRIO_MIN_RATE64, nl.Uint64Attr(rate))
+				}
+			}
+			if len(qdisc.MaxRate) > 0 {
+				rates := nl.NewRtAttrChild(options, nl.TCA_MQPRIO_MAX_RATE64, nil)
+				for _, rate := range qdisc.MaxRate {
+					nl.NewRtAttrChild(rates, nl.TCA_MQPRIO_MAX_RATE64, nl.Uint64Attr(rate))
+				}
+			}
+		}
+	case *Taprio:
+		opt := nl.TcMqprioQopt{
+			NumTc:     qdisc.NumTc,
+			PrioTcMap: qdisc.PrioTcMap,
+			Count:     qdisc.Count,
+			Offset:    qdisc.Offset,
+		}
+		nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_PRIOMAP, opt.Serialize())
+		if qdisc.Flags&TAPRIO_FLAG_FULL_OFFLOAD == 0 {
+			nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_SCHED_CLOCKID, nl.Uint32Attr(uint32(qdisc.ClockID)))
+		}
+		if qdisc.Flags != 0 {
+			nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_FLAGS, nl.Uint32Attr(qdisc.Flags))
+		}
+		if qdisc.TxTimeDelay > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_TXTIME_DELAY, nl.Uint32Attr(qdisc.TxTimeDelay))
+		}
+		encodeTaprioSchedule(options, &qdisc.Schedule)
+	case *Ets:
+		// sch_ets parses its options strictly, the nests must be flagged
+		options = nl.NewRtAttr(nl.TCA_OPTIONS|unix.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(options, nl.TCA_ETS_NBANDS, nl.Uint8Attr(qdisc.Bands))
+		if qdisc.Strict > 0 {
+			nl.NewRtAttrChild(options, nl.TCA_ETS_NSTRICT, nl.Uint8Attr(qdisc.Strict))
+		}
+		if len(qdisc.Quanta) > 0 {
+			quanta := nl.NewRtAttrChild(options, nl.TCA_ETS_QUANTA|unix.NLA_F_NESTED, nil)
+			for _, quantum := range qdisc.Quanta {
+				nl.NewRtAttrChild(quanta, nl.TCA_ETS_QUANTA_BAND, nl.Uint32Attr(quantum))
+			}
+		}
+		if len(qdisc.PrioMap) > 0 {
+			priomap := nl.NewRtAttrChild(options, nl.TCA_ETS_PRIOMAP|unix.NLA_F_NESTED, nil)
+			for _, band := range qdisc.PrioMap {
+				nl.NewRtAttrChild(priomap, nl.TCA_ETS_PRIOMAP_BAND, nl.Uint8Attr(band))
+			}
+		}
+	case *Multiq:
+		opt := nl.TcMultiqQopt{
+			Bands:    qdisc.Bands,
+			MaxBands: qdisc.MaxBands,
+		}
+		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
 	}
 
 	req.AddData(options)
 	return nil
 }
 
+func encodeTaprioSchedule(options *nl.RtAttr, sched *TaprioSchedule) {
+	nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_SCHED_BASE_TIME, nl.Uint64Attr(uint64(sched.BaseTime)))
+	if sched.CycleTime > 0 {
+		nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME, nl.Uint64Attr(uint64(sched.CycleTime)))
+	}
+	if sched.CycleTimeExtension > 0 {
+		nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION, nl.Uint64Attr(uint64(sched.CycleTimeExtension)))
+	}
+	list := nl.NewRtAttrChild(options, nl.TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST, nil)
+	for _, entry := range sched.Entries {
+		e := nl.NewRtAttrChild(list, nl.TCA_TAPRIO_SCHED_ENTRY, nil)
+		nl.NewRtAttrChild(e, nl.TCA_TAPRIO_SCHED_ENTRY_CMD, nl.Uint8Attr(uint8(entry.Command)))
+		nl.NewRtAttrChild(e, nl.TCA_TAPRIO_SCHED_ENTRY_GATE_MASK, nl.Uint32Attr(entry.GateMask))
+		nl.NewRtAttrChild(e, nl.TCA_TAPRIO_SCHED_ENTRY_INTERVAL, nl.Uint32Attr(entry.Interval))
+	}
+}
+
+// redParms computes the kernel RED parameters and idle damping table the
+// same way as `tc qdisc add red` does.
+func redParms(red *Red) (*nl.TcRedQopt, []byte, error) {
+	if red.Avpkt == 0 || red.Max == 0 {
+		return nil, nil, fmt.Errorf("red requires Avpkt and Max")
+	}
+	if red.Min >= red.Max {
+		return nil, nil, fmt.Errorf("red Min must be lower than Max")
+	}
+	if red.Bandwidth == 0 {
+		return nil, nil, fmt.Errorf("red requires Bandwidth")
+	}
+	opt := &nl.TcRedQopt{
+		Limit:  red.Limit,
+		QthMin: red.Min,
+		QthMax: red.Max,
+	}
+	if red.ECN {
+		opt.Flags |= nl.TC_RED_ECN
+	}
+	if red.Harddrop {
+		opt.Flags |= nl.TC_RED_HARDDROP
+	}
+	if red.Adaptive {
+		opt.Flags |= nl.TC_RED_ADAPTATIVE
+	}
+
+	// W is the weight of the average queue length
+	a := float64(red.Burst) + 1 - float64(red.Min)/float64(red.Avpkt)
+	if a < 1 {
+		return nil, nil, fmt.Errorf("red Burst is too small for Min and Avpkt")
+	}
+	w := 0.5
+	for opt.Wlog = 1; opt.Wlog < 32; opt.Wlog++ {
+		if a <= (1-math.Pow(1-w, float64(red.Burst)))/w {
+			break
+		}
+		w /= 2
+	}
+	if opt.Wlog >= 32 {
+		return nil, nil, fmt.Errorf("red Burst is too large")
+	}
+
+	// P is the drop probability per byte above Min
+	prob := red.Probability / float64(red.Max-red.Min)
+	for opt.Plog = 0; opt.Plog < 32; opt.Plog++ {
+		if prob > 1 {
+			break
+		}
+		prob *= 2
+	}
+	if opt.Plog >= 32 {
+		return nil, nil, fmt.Errorf("red Probability is too small")
+	}
+
+	// the table decays the average while the queue is idle
+	lw := -math.Log(1-1/float64(uint32(1)<<opt.Wlog)) / Xmittime(red.Bandwidth, red.Avpkt)
+	maxtime := 31 / lw
+	for opt.ScellLog = 0; opt.ScellLog < 32; opt.ScellLog++ {
+		if maxtime/float64(uint64(1)<<opt.ScellLog) < 512 {
+			break
+		}
+	}
+	if opt.ScellLog >= 32 {
+		return nil, nil, fmt.Errorf("red Bandwidth is too small")
+	}
+	stab := make([]byte, 256)
+	for i := 1; i < 255; i++ {
+		v := float64(i<<opt.ScellLog) * lw
+		if v > 31 {
+			v = 31
+		}
+		stab[i] = uint8(v)
+	}
+	stab[255] = 31
+	return opt, stab, nil
+}
+
 // QdiscList gets a list of qdiscs in the system.
 // Equivalent to: `tc qdisc show`.
 // The list can be filtered by link.
@@ -308,9 +578,9 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 	}
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWQDISC)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWQDISC)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Qdisc
@@ -348,6 +618,8 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 					qdisc = &Tbf{}
 				case "ingress":
 					qdisc = &Ingress{}
+				case "clsact":
+					qdisc = &Clsact{}
 				case "htb":
 					qdisc = &Htb{}
 				case "fq":
@@ -358,6 +630,22 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 					qdisc = &FqCodel{}
 				case "netem":
 					qdisc = &Netem{}
+				case "codel":
+					qdisc = &Codel{}
+				case "cake":
+					qdisc = &Cake{}
+				case "sfq":
+					qdisc = &Sfq{}
+				case "red":
+					qdisc = &Red{}
+				case "mqprio":
+					qdisc = &Mqprio{}
+				case "taprio":
+					qdisc = &Taprio{}
+				case "ets":
+					qdisc = &Ets{}
+				case "multiq":
+					qdisc = &Multiq{}
 				default:
 					qdisc = &GenericQdisc{QdiscType: qdiscType}
 				}
@@ -413,16 +701,105 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 					if err := parseNetemData(qdisc, attr.Value); err != nil {
 						return nil, err
 					}
+				case "codel":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseCodelData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "cake":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseCakeData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "sfq":
+					if err := parseSfqData(qdisc, attr.Value); err != nil {
+						return nil, err
+					}
+				case "red":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseRedData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "mqprio":
+					if err := parseMqprioData(qdisc, attr.Value); err != nil {
+						return nil, err
+					}
+				case "taprio":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseTaprioData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "ets":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseEtsData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "multiq":
+					if err := parseMultiqData(qdisc, attr.Value); err != nil {
+						return nil, err
+					}
 
 					// no options for ingress
 				}
+			case nl.TCA_INGRESS_BLOCK:
+				switch qdisc := qdisc.(type) {
+				case *Ingress:
+					qdisc.IngressBlock = native.Uint32(attr.Value)
+				case *Clsact:
+					qdisc.IngressBlock = native.Uint32(attr.Value)
+				}
+			case nl.TCA_EGRESS_BLOCK:
+				if clsact, ok := qdisc.(*Clsact); ok {
+					clsact.EgressBlock = native.Uint32(attr.Value)
+				}
+			case nl.TCA_STATS2:
+				stats, err := parseTcStats2(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				base.Statistics = (*QdiscStatistics)(stats)
+			// For backward compatibility, tc_stats lacks requeues.
+			case nl.TCA_STATS:
+				if base.Statistics != nil {
+					continue
+				}
+				stats, err := parseTcStats(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				base.Statistics = (*QdiscStatistics)(stats)
+			case nl.TCA_XSTATS:
+				if err := parseQdiscXStats(qdisc, attr.Value); err != nil {
+					return nil, err
+				}
 			}
 		}
 		*qdisc.Attrs() = base
 		res = append(res, qdisc)
 	}
 
-	return res, nil
+	return res, executeErr
+}
+
+// QdiscListContext is like QdiscList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) QdiscListContext(ctx context.Context, link Link) ([]Qdisc, error) {
+	return h.withContext(ctx).QdiscList(link)
 }
 
 func parsePfifoFastData(qdisc Qdisc, value []byte) error {
@@ -578,6 +955,284 @@ func parseTbfData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
 	return nil
 }
 
+func parseCodelData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	codel := qdisc.(*Codel)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CODEL_TARGET:
+			codel.Target = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_LIMIT:
+			codel.Limit = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_INTERVAL:
+			codel.Interval = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_ECN:
+			codel.ECN = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_CE_THRESHOLD:
+			codel.CEThreshold = native.Uint32(datum.Value)
+		}
+	}
+	return nil
+}
+
+func parseCakeData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	cake := qdisc.(*Cake)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CAKE_BASE_RATE64:
+			cake.Bandwidth = native.Uint64(datum.Value)
+		case nl.TCA_CAKE_DIFFSERV_MODE:
+			cake.DiffservMode = CakeDiffservMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_FLOW_MODE:
+			cake.FlowMode = CakeFlowMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_ACK_FILTER:
+			cake.AckFilter = CakeAckFilter(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_ATM:
+			cake.Atm = CakeAtmMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_OVERHEAD:
+			cake.Overhead = int32(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_MPU:
+			cake.Mpu = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_RTT:
+			cake.RTT = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_TARGET:
+			cake.Target = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_MEMORY:
+			cake.Memory = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_FWMARK:
+			cake.Fwmark = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_NAT:
+			cake.Nat = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_WASH:
+			cake.Wash = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_INGRESS:
+			cake.Ingress = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_SPLIT_GSO:
+			cake.SplitGSO = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_AUTORATE:
+			cake.Autorate = native.Uint32(datum.Value) != 0
+		}
+	}
+	return nil
+}
+
+func parseSfqData(qdisc Qdisc, value []byte) error {
+	sfq := qdisc.(*Sfq)
+	if len(value) < nl.SizeofTcSfqQoptV1 {
+		return fmt.Errorf("sfq options too short: %d", len(value))
+	}
+	opt := nl.DeserializeTcSfqQoptV1(value)
+	sfq.Quantum = opt.Quantum
+	sfq.Perturb = opt.PerturbPeriod
+	sfq.Limit = opt.Limit
+	sfq.Divisor = opt.Divisor
+	sfq.Flows = opt.Flows
+	sfq.Depth = opt.Depth
+	sfq.Headdrop = opt.Headdrop != 0
+	return nil
+}
+
+func parseRedData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	red := qdisc.(*Red)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_RED_PARMS:
+			opt := nl.DeserializeTcRedQopt(datum.Value)
+			red.Limit = opt.Limit
+			red.Min = opt.QthMin
+			red.Max = opt.QthMax
+			red.ECN = opt.Flags&nl.TC_RED_ECN != 0
+			red.Harddrop = opt.Flags&nl.TC_RED_HARDDROP != 0
+			red.Adaptive = opt.Flags&nl.TC_RED_ADAPTATIVE != 0
+		case nl.TCA_RED_MAX_P:
+			red.Probability = float64(native.Uint32(datum.Value)) / (1 << 32)
+		}
+	}
+	return nil
+}
+
+func parseMqprioData(qdisc Qdisc, value []byte) error {
+	mqprio := qdisc.(*Mqprio)
+	if len(value) < nl.SizeofTcMqprioQopt {
+		return fmt.Errorf("mqprio options too short: %d", len(value))
+	}
+	opt := nl.DeserializeTcMqprioQopt(value)
+	mqprio.NumTc = opt.NumTc
+	mqprio.PrioTcMap = opt.PrioTcMap
+	mqprio.Hw = opt.Hw
+	mqprio.Count = opt.Count
+	mqprio.Offset = opt.Offset
+
+	// attributes follow the aligned struct
+	start := (nl.SizeofTcMqprioQopt + unix.RTA_ALIGNTO - 1) &^ (unix.RTA_ALIGNTO - 1)
+	if len(value) <= start {
+		return nil
+	}
+	data, err := nl.ParseRouteAttr(value[start:])
+	if err != nil {
+		return err
+	}
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_MQPRIO_MODE:
+			mqprio.Mode = native.Uint16(datum.Value)
+		case nl.TCA_MQPRIO_SHAPER:
+			mqprio.Shaper = native.Uint16(datum.Value)
+		case nl.TCA_MQPRIO_MIN_RATE64, nl.TCA_MQPRIO_MAX_RATE64:
+			rates, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			var res []uint64
+			for _, rate := range rates {
+				res = append(res, native.Uint64(rate.Value))
+			}
+			if datum.Attr.Type == nl.TCA_MQPRIO_MIN_RATE64 {
+				mqprio.MinRate = res
+			} else {
+				mqprio.MaxRate = res
+			}
+		}
+	}
+	return nil
+}
+
+func parseTaprioData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	taprio := qdisc.(*Taprio)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_TAPRIO_ATTR_PRIOMAP:
+			opt := nl.DeserializeTcMqprioQopt(datum.Value)
+			taprio.NumTc = opt.NumTc
+			taprio.PrioTcMap = opt.PrioTcMap
+			taprio.Count = opt.Count
+			taprio.Offset = opt.Offset
+		case nl.TCA_TAPRIO_ATTR_SCHED_CLOCKID:
+			taprio.ClockID = int32(native.Uint32(datum.Value))
+		case nl.TCA_TAPRIO_ATTR_FLAGS:
+			taprio.Flags = native.Uint32(datum.Value)
+		case nl.TCA_TAPRIO_ATTR_TXTIME_DELAY:
+			taprio.TxTimeDelay = native.Uint32(datum.Value)
+		case nl.TCA_TAPRIO_ATTR_ADMIN_SCHED:
+			admin, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			taprio.AdminSchedule = &TaprioSchedule{}
+			if err := parseTaprioSchedule(taprio.AdminSchedule, admin); err != nil {
+				return err
+			}
+		}
+	}
+	return parseTaprioSchedule(&taprio.Schedule, data)
+}
+
+func parseTaprioSchedule(sched *TaprioSchedule, data []syscall.NetlinkRouteAttr) error {
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_TAPRIO_ATTR_SCHED_BASE_TIME:
+			sched.BaseTime = int64(native.Uint64(datum.Value))
+		case nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME:
+			sched.CycleTime = int64(native.Uint64(datum.Value))
+		case nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION:
+			sched.CycleTimeExtension = int64(native.Uint64(datum.Value))
+		case nl.TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST:
+			entries, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				if e.Attr.Type != nl.TCA_TAPRIO_SCHED_ENTRY {
+					continue
+				}
+				attrs, err := nl.ParseRouteAttr(e.Value)
+				if err != nil {
+					return err
+				}
+				entry := TaprioSchedEntry{}
+				for _, attr := range attrs {
+					switch attr.Attr.Type {
+					case nl.TCA_TAPRIO_SCHED_ENTRY_INDEX:
+						entry.Index = native.Uint32(attr.Value)
+					case nl.TCA_TAPRIO_SCHED_ENTRY_CMD:
+						entry.Command = TaprioCmd(attr.Value[0])
+					case nl.TCA_TAPRIO_SCHED_ENTRY_GATE_MASK:
+						entry.GateMask = native.Uint32(attr.Value)
+					case nl.TCA_TAPRIO_SCHED_ENTRY_INTERVAL:
+						entry.Interval = native.Uint32(attr.Value)
+					}
+				}
+				sched.Entries = append(sched.Entries, entry)
+			}
+		}
+	}
+	return nil
+}
+
+func parseEtsData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	ets := qdisc.(*Ets)
+	for _, datum := range data {
+		switch datum.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.TCA_ETS_NBANDS:
+			ets.Bands = datum.Value[0]
+		case nl.TCA_ETS_NSTRICT:
+			ets.Strict = datum.Value[0]
+		case nl.TCA_ETS_QUANTA:
+			quanta, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			for _, quantum := range quanta {
+				ets.Quanta = append(ets.Quanta, native.Uint32(quantum.Value))
+			}
+		case nl.TCA_ETS_PRIOMAP:
+			priomap, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			for _, band := range priomap {
+				ets.PrioMap = append(ets.PrioMap, band.Value[0])
+			}
+		}
+	}
+	return nil
+}
+
+func parseMultiqData(qdisc Qdisc, value []byte) error {
+	multiq := qdisc.(*Multiq)
+	opt := nl.DeserializeTcMultiqQopt(value)
+	multiq.Bands = opt.Bands
+	multiq.MaxBands = opt.MaxBands
+	return nil
+}
+
+func parseQdiscXStats(qdisc Qdisc, data []byte) error {
+	switch qdisc := qdisc.(type) {
+	case *FqCodel:
+		if len(data) < 4 || native.Uint32(data) != nl.TCA_FQ_CODEL_XSTATS_QDISC {
+			return nil
+		}
+		qdisc.XStats = &FqCodelXStats{}
+		return parseXStats(data[4:], qdisc.XStats)
+	case *Fq:
+		qdisc.XStats = &FqXStats{}
+		return parseXStats(data, qdisc.XStats)
+	case *Codel:
+		qdisc.XStats = &CodelXStats{}
+		return parseXStats(data, qdisc.XStats)
+	case *Red:
+		qdisc.XStats = &RedXStats{}
+		return parseXStats(data, qdisc.XStats)
+	}
+	return nil
+}
+
+func boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 const (
 	TIME_UNITS_PER_SEC = 1000000
 )
@@ -3,7 +3,12 @@
 package netlink
 
 import (
+	"reflect"
+	"syscall"
 	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
 )
 
 func TestTbfAddDel(t *testing.T) {
@@ -46,6 +51,9 @@ func TestTbfAddDel(t *testing.T) {
 	if tbf.Rate != qdisc.Rate {
 		t.Fatal("Rate doesn't match")
 	}
+	if tbf.Statistics == nil || tbf.Statistics.Basic == nil || tbf.Statistics.Queue == nil {
+		t.Fatal("Statistics not parsed")
+	}
 	if tbf.Limit != qdisc.Limit {
 		t.Fatal("Limit doesn't match")
 	}
@@ -495,3 +503,404 @@ func TestIngressAddDel(t *testing.T) {
 		t.Fatal("Failed to remove qdisc")
 	}
 }
+
+func TestClsactAddDel(t *testing.T) {
+	minKernelRequired(t, 4, 16)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Clsact{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    HANDLE_CLSACT,
+		},
+		IngressBlock: 21,
+		EgressBlock:  22,
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	clsact, ok := qdiscs[0].(*Clsact)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if clsact.IngressBlock != 21 || clsact.EgressBlock != 22 {
+		t.Fatalf("Blocks not set properly: %d %d", clsact.IngressBlock, clsact.EgressBlock)
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 0 {
+		t.Fatal("Failed to remove qdisc")
+	}
+
+	if err := QdiscAdd(&Clsact{QdiscAttrs: QdiscAttrs{LinkIndex: link.Attrs().Index}}); err == nil {
+		t.Fatal("Clsact without HANDLE_CLSACT parent should fail")
+	}
+}
+
+func TestCakeAddDel(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := NewCake(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	})
+	qdisc.Bandwidth = 1000000
+	qdisc.DiffservMode = CAKE_DIFFSERV_DIFFSERV4
+	qdisc.AckFilter = CAKE_ACK_FILTER
+	qdisc.RTT = 50000
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	cake, ok := qdiscs[0].(*Cake)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if cake.Bandwidth != qdisc.Bandwidth || cake.DiffservMode != qdisc.DiffservMode ||
+		cake.FlowMode != CAKE_FLOW_TRIPLE || cake.AckFilter != qdisc.AckFilter || cake.RTT != qdisc.RTT {
+		t.Fatalf("Cake not added properly: %s", cake)
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 0 {
+		t.Fatal("Failed to remove qdisc")
+	}
+}
+
+func TestTaprioAddDel(t *testing.T) {
+	minKernelRequired(t, 5, 2)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo", NumTxQueues: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Taprio{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		NumTc:     2,
+		PrioTcMap: [PRIORITY_MAP_LEN]uint8{0, 1},
+		Count:     [TC_QOPT_MAX_QUEUE]uint16{2, 2},
+		Offset:    [TC_QOPT_MAX_QUEUE]uint16{0, 2},
+		ClockID:   unix.CLOCK_TAI,
+		Schedule: TaprioSchedule{
+			BaseTime: 1000000000,
+			Entries: []TaprioSchedEntry{
+				{Command: TAPRIO_CMD_SET_GATES, GateMask: 0x1, Interval: 300000},
+				{Command: TAPRIO_CMD_SET_GATES, GateMask: 0x2, Interval: 700000},
+			},
+		},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := QdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var taprio *Taprio
+	for _, q := range qdiscs {
+		if q, ok := q.(*Taprio); ok {
+			taprio = q
+		}
+	}
+	if taprio == nil {
+		t.Fatal("Failed to add qdisc")
+	}
+	if taprio.NumTc != 2 || taprio.Count != qdisc.Count || taprio.Offset != qdisc.Offset ||
+		taprio.ClockID != unix.CLOCK_TAI || len(taprio.Schedule.Entries) != 2 ||
+		taprio.Schedule.CycleTime != 1000000 || taprio.Schedule.Entries[1].GateMask != 0x2 {
+		t.Fatalf("Taprio not added properly: %s", taprio)
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEtsAddDel(t *testing.T) {
+	minKernelRequired(t, 5, 6)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Ets{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		Bands:   4,
+		Strict:  1,
+		Quanta:  []uint32{1500, 3000, 4500},
+		PrioMap: []uint8{3, 2, 1, 0},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := QdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ets *Ets
+	for _, q := range qdiscs {
+		if q, ok := q.(*Ets); ok {
+			ets = q
+		}
+	}
+	if ets == nil {
+		t.Fatal("Failed to add qdisc")
+	}
+	if ets.Bands != 4 || ets.Strict != 1 || len(ets.Quanta) != 3 || ets.Quanta[2] != 4500 ||
+		len(ets.PrioMap) < 4 || ets.PrioMap[0] != 3 || ets.PrioMap[3] != 0 {
+		t.Fatalf("Ets not added properly: %+v", ets)
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = QdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range qdiscs {
+		if _, ok := q.(*Ets); ok {
+			t.Fatal("Failed to remove qdisc")
+		}
+	}
+}
+
+func encodeQdiscOptions(t *testing.T, qdisc Qdisc) []byte {
+	req := pkgHandle.newNetlinkRequest(unix.RTM_NEWQDISC, 0)
+	if err := qdiscPayload(req, qdisc); err != nil {
+		t.Fatal(err)
+	}
+	options := req.Data[len(req.Data)-1].(*nl.RtAttr)
+	return options.Serialize()[unix.SizeofRtAttr:]
+}
+
+func TestQdiscEncodeDecode(t *testing.T) {
+	attrs := QdiscAttrs{Parent: HANDLE_ROOT}
+	nested := func(parse func(Qdisc, []syscall.NetlinkRouteAttr) error) func(Qdisc, []byte) error {
+		return func(qdisc Qdisc, value []byte) error {
+			data, err := nl.ParseRouteAttr(value)
+			if err != nil {
+				return err
+			}
+			return parse(qdisc, data)
+		}
+	}
+
+	cake := NewCake(attrs)
+	cake.Bandwidth = 1 << 33
+	cake.DiffservMode = CAKE_DIFFSERV_DIFFSERV8
+	cake.Atm = CAKE_ATM_PTM
+	cake.Overhead = -4
+	cake.RTT = 20000
+	cake.Nat = true
+	cake.Wash = true
+
+	tests := []struct {
+		qdisc  Qdisc
+		parsed Qdisc
+		parse  func(Qdisc, []byte) error
+	}{
+		{
+			qdisc:  &Codel{QdiscAttrs: attrs, Target: 5000, Limit: 1000, Interval: 100000, ECN: 1, CEThreshold: 2000},
+			parsed: &Codel{},
+			parse:  nested(parseCodelData),
+		},
+		{
+			qdisc:  cake,
+			parsed: &Cake{},
+			parse:  nested(parseCakeData),
+		},
+		{
+			qdisc:  &Sfq{QdiscAttrs: attrs, Quantum: 1514, Perturb: 10, Limit: 127, Divisor: 1024, Flows: 128, Depth: 64, Headdrop: true},
+			parsed: &Sfq{},
+			parse:  parseSfqData,
+		},
+		{
+			qdisc: &Mqprio{
+				QdiscAttrs: attrs,
+				NumTc:      3,
+				PrioTcMap:  [PRIORITY_MAP_LEN]uint8{2, 2, 1, 0},
+				Hw:         1,
+				Count:      [TC_QOPT_MAX_QUEUE]uint16{1, 1, 2},
+				Offset:     [TC_QOPT_MAX_QUEUE]uint16{0, 1, 2},
+				Mode:       MQPRIO_MODE_CHANNEL,
+				Shaper:     MQPRIO_SHAPER_BW_RATE,
+				MinRate:    []uint64{0, 1000, 2000},
+				MaxRate:    []uint64{5000, 6000, 7000},
+			},
+			parsed: &Mqprio{},
+			parse:  parseMqprioData,
+		},
+		{
+			qdisc: &Taprio{
+				QdiscAttrs:  attrs,
+				NumTc:       2,
+				PrioTcMap:   [PRIORITY_MAP_LEN]uint8{1, 0, 1},
+				Count:       [TC_QOPT_MAX_QUEUE]uint16{1, 1},
+				Offset:      [TC_QOPT_MAX_QUEUE]uint16{0, 1},
+				ClockID:     unix.CLOCK_TAI,
+				Flags:       TAPRIO_FLAG_TXTIME_ASSIST,
+				TxTimeDelay: 200000,
+				Schedule: TaprioSchedule{
+					BaseTime:           -1,
+					CycleTime:          1000000,
+					CycleTimeExtension: 1000,
+					Entries: []TaprioSchedEntry{
+						{Command: TAPRIO_CMD_SET_GATES, GateMask: 0x3, Interval: 500000},
+						{Command: TAPRIO_CMD_SET_AND_HOLD, GateMask: 0x1, Interval: 500000},
+					},
+				},
+			},
+			parsed: &Taprio{},
+			parse:  nested(parseTaprioData),
+		},
+		{
+			qdisc:  &Ets{QdiscAttrs: attrs, Bands: 4, Strict: 1, Quanta: []uint32{1500, 3000, 4500}, PrioMap: []uint8{3, 2, 1, 0}},
+			parsed: &Ets{},
+			parse:  nested(parseEtsData),
+		},
+		{
+			qdisc:  &Multiq{QdiscAttrs: attrs, Bands: 4, MaxBands: 8},
+			parsed: &Multiq{},
+			parse:  parseMultiqData,
+		},
+	}
+	for _, test := range tests {
+		if err := test.parse(test.parsed, encodeQdiscOptions(t, test.qdisc)); err != nil {
+			t.Fatalf("%s: %v", test.qdisc.Type(), err)
+		}
+		*test.parsed.Attrs() = *test.qdisc.Attrs()
+		if !reflect.DeepEqual(test.qdisc, test.parsed) {
+			t.Fatalf("%s: expected %+v, got %+v", test.qdisc.Type(), test.qdisc, test.parsed)
+		}
+	}
+}
+
+func TestRedEncodeDecode(t *testing.T) {
+	red := NewRed(QdiscAttrs{Parent: HANDLE_ROOT}, 400000, 90000)
+	red.ECN = true
+	data, err := nl.ParseRouteAttr(encodeQdiscOptions(t, red))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_RED_PARMS:
+			// as computed by the tc_red_eval_* helpers of iproute2
+			opt := nl.DeserializeTcRedQopt(datum.Value)
+			if opt.Wlog != 5 || opt.Plog != 22 || opt.Flags != nl.TC_RED_ECN {
+				t.Fatalf("Wrong red parameters: %+v", opt)
+			}
+		case nl.TCA_RED_STAB:
+			if len(datum.Value) != 256 || datum.Value[0] != 0 || datum.Value[255] != 31 {
+				t.Fatalf("Wrong red stab: %v", datum.Value)
+			}
+		}
+	}
+	parsed := &Red{}
+	if err := parseRedData(parsed, data); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Limit != red.Limit || parsed.Min != red.Min || parsed.Max != red.Max || !parsed.ECN ||
+		parsed.Probability < 0.0199 || parsed.Probability > 0.0201 {
+		t.Fatalf("Red not decoded properly: %s", parsed)
+	}
+
+	if _, _, err := redParms(&Red{Max: 100, Min: 200, Avpkt: 1000, Bandwidth: 1000}); err == nil {
+		t.Fatal("Expected an error for Min above Max")
+	}
+}
+
+func TestQdiscXStats(t *testing.T) {
+	// fq_codel xstats of a kernel without the memory counters
+	data := make([]byte, 4+7*4)
+	native.PutUint32(data[0:], nl.TCA_FQ_CODEL_XSTATS_QDISC)
+	native.PutUint32(data[4:], 1514)
+	native.PutUint32(data[8:], 3)
+	native.PutUint32(data[28:], 9)
+	fqCodel := &FqCodel{}
+	if err := parseQdiscXStats(fqCodel, data); err != nil {
+		t.Fatal(err)
+	}
+	expected := FqCodelXStats{Maxpacket: 1514, DropOverlimit: 3, CeMark: 9}
+	if fqCodel.XStats == nil || *fqCodel.XStats != expected {
+		t.Fatalf("Wrong fq_codel xstats: %+v", fqCodel.XStats)
+	}
+
+	// class stats are not reported on the qdisc
+	native.PutUint32(data[0:], nl.TCA_FQ_CODEL_XSTATS_CLASS)
+	fqCodel = &FqCodel{}
+	if err := parseQdiscXStats(fqCodel, data); err != nil || fqCodel.XStats != nil {
+		t.Fatalf("Unexpected fq_codel xstats: %+v %v", fqCodel.XStats, err)
+	}
+
+	// red xstats with trailing fields of a newer kernel
+	data = make([]byte, 6*4)
+	native.PutUint32(data[0:], 1)
+	native.PutUint32(data[12:], 4)
+	native.PutUint32(data[16:], 5)
+	red := &Red{}
+	if err := parseQdiscXStats(red, data); err != nil {
+		t.Fatal(err)
+	}
+	if red.XStats == nil || *red.XStats != (RedXStats{Early: 1, Marked: 4}) {
+		t.Fatalf("Wrong red xstats: %+v", red.XStats)
+	}
+}
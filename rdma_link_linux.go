@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"net"
@@ -110,3 +111,9 @@ func (h *Handle) RdmaLinkByName(name string) (*RdmaLink, error) {
 
 	return execRdmaGetLink(req, name)
 }
+
+// RdmaLinkByNameContext is like RdmaLinkByName but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RdmaLinkByNameContext(ctx context.Context, name string) (*RdmaLink, error) {
+	return h.withContext(ctx).RdmaLinkByName(name)
+}
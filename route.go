@@ -36,6 +36,7 @@ type Route struct {
 	Src        net.IP
 	Gw         net.IP
 	MultiPath  []*NexthopInfo
+	NhID       uint32
 	Protocol   int
 	Priority   int
 	Table      int
@@ -66,7 +67,9 @@ func (r Route) String() string {
 		elems = append(elems, fmt.Sprintf("Encap: %s", r.Encap))
 	}
 	elems = append(elems, fmt.Sprintf("Src: %s", r.Src))
-	if len(r.MultiPath) > 0 {
+	if r.NhID > 0 {
+		elems = append(elems, fmt.Sprintf("NhID: %d", r.NhID))
+	} else if len(r.MultiPath) > 0 {
 		elems = append(elems, fmt.Sprintf("Gw: %s", r.MultiPath))
 	} else {
 		elems = append(elems, fmt.Sprintf("Gw: %s", r.Gw))
@@ -84,6 +87,7 @@ func (r Route) Equal(x Route) bool {
 		r.Src.Equal(x.Src) &&
 		r.Gw.Equal(x.Gw) &&
 		nexthopInfoSlice(r.MultiPath).Equal(x.MultiPath) &&
+		r.NhID == x.NhID &&
 		r.Protocol == x.Protocol &&
 		r.Priority == x.Priority &&
 		r.Table == x.Table &&
@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -456,6 +458,12 @@ func (h *Handle) RouteAdd(route *Route) error {
 	return h.routeHandle(route, req, nl.NewRtMsg())
 }
 
+// RouteAddContext is like RouteAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteAddContext(ctx context.Context, route *Route) error {
+	return h.withContext(ctx).RouteAdd(route)
+}
+
 // RouteReplace will add a route to the system.
 // Equivalent to: `ip route replace $route`
 func RouteReplace(route *Route) error {
@@ -470,6 +478,12 @@ func (h *Handle) RouteReplace(route *Route) error {
 	return h.routeHandle(route, req, nl.NewRtMsg())
 }
 
+// RouteReplaceContext is like RouteReplace but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteReplaceContext(ctx context.Context, route *Route) error {
+	return h.withContext(ctx).RouteReplace(route)
+}
+
 // RouteDel will delete a route from the system.
 // Equivalent to: `ip route del $route`
 func RouteDel(route *Route) error {
@@ -483,6 +497,12 @@ func (h *Handle) RouteDel(route *Route) error {
 	return h.routeHandle(route, req, nl.NewRtDelMsg())
 }
 
+// RouteDelContext is like RouteDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteDelContext(ctx context.Context, route *Route) error {
+	return h.withContext(ctx).RouteDel(route)
+}
+
 func (h *Handle) routeHandle(route *Route, req *nl.NetlinkRequest, msg *nl.RtMsg) error {
 	if (route.Dst == nil || route.Dst.IP == nil) && route.Src == nil && route.Gw == nil && route.MPLSDst == nil {
 		return fmt.Errorf("one of Dst.IP, Src, or Gw must not be nil")
@@ -611,6 +631,10 @@ func (h *Handle) routeHandle(route *Route, req *nl.NetlinkRequest, msg *nl.RtMsg
 		rtAttrs = append(rtAttrs, nl.NewRtAttr(unix.RTA_MULTIPATH, buf))
 	}
 
+	if route.NhID > 0 {
+		rtAttrs = append(rtAttrs, nl.NewRtAttr(nl.RTA_NH_ID, nl.Uint32Attr(route.NhID)))
+	}
+
 	if route.Table > 0 {
 		if route.Table >= 256 {
 			msg.Table = unix.RT_TABLE_UNSPEC
@@ -696,6 +720,12 @@ func (h *Handle) RouteList(link Link, family int) ([]Route, error) {
 	return h.RouteListFiltered(family, routeFilter, RT_FILTER_OIF)
 }
 
+// RouteListContext is like RouteList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteListContext(ctx context.Context, link Link, family int) ([]Route, error) {
+	return h.withContext(ctx).RouteList(link, family)
+}
+
 // RouteListFiltered gets a list of routes in the system filtered with specified rules.
 // All rules must be defined in RouteFilter struct
 func RouteListFiltered(family int, filter *Route, filterMask uint64) ([]Route, error) {
@@ -709,9 +739,9 @@ func (h *Handle) RouteListFiltered(family int, filter *Route, filterMask uint64)
 	infmsg := nl.NewIfInfomsg(family)
 	req.AddData(infmsg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWROUTE)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWROUTE)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []Route
@@ -761,7 +791,13 @@ func (h *Handle) RouteListFiltered(family int, filter *Route, filterMask uint64)
 		}
 		res = append(res, route)
 	}
-	return res, nil
+	return res, executeErr
+}
+
+// RouteListFilteredContext is like RouteListFiltered but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteListFilteredContext(ctx context.Context, family int, filter *Route, filterMask uint64) ([]Route, error) {
+	return h.withContext(ctx).RouteListFiltered(family, filter, filterMask)
 }
 
 // deserializeRoute decodes a binary netlink message into a Route struct
@@ -809,6 +845,8 @@ func deserializeRoute(m []byte) (Route, error) {
 			route.Priority = int(native.Uint32(attr.Value[0:4]))
 		case unix.RTA_TABLE:
 			route.Table = int(native.Uint32(attr.Value[0:4]))
+		case nl.RTA_NH_ID:
+			route.NhID = native.Uint32(attr.Value[0:4])
 		case unix.RTA_MULTIPATH:
 			parseRtNexthop := func(value []byte) (*NexthopInfo, []byte, error) {
 				if len(value) < unix.SizeofRtNexthop {
@@ -904,24 +942,9 @@ func deserializeRoute(m []byte) (Route, error) {
 	}
 
 	if len(encap.Value) != 0 && len(encapType.Value) != 0 {
-		typ := int(native.Uint16(encapType.Value[0:2]))
-		var e Encap
-		switch typ {
-		case nl.LWTUNNEL_ENCAP_MPLS:
-			e = &MPLSEncap{}
-			if err := e.Decode(encap.Value); err != nil {
-				return route, err
-			}
-		case nl.LWTUNNEL_ENCAP_SEG6:
-			e = &SEG6Encap{}
-			if err := e.Decode(encap.Value); err != nil {
-				return route, err
-			}
-		case nl.LWTUNNEL_ENCAP_SEG6_LOCAL:
-			e = &SEG6LocalEncap{}
-			if err := e.Decode(encap.Value); err != nil {
-				return route, err
-			}
+		e, err := decodeEncap(encapType, encap)
+		if err != nil {
+			return route, err
 		}
 		route.Encap = e
 	}
@@ -929,6 +952,27 @@ func deserializeRoute(m []byte) (Route, error) {
 	return route, nil
 }
 
+// decodeEncap decodes a light weight tunnel encapsulation from the pair of
+// encap type and encap attributes of a route or nexthop.
+func decodeEncap(encapType, encap syscall.NetlinkRouteAttr) (Encap, error) {
+	typ := int(native.Uint16(encapType.Value[0:2]))
+	var e Encap
+	switch typ {
+	case nl.LWTUNNEL_ENCAP_MPLS:
+		e = &MPLSEncap{}
+	case nl.LWTUNNEL_ENCAP_SEG6:
+		e = &SEG6Encap{}
+	case nl.LWTUNNEL_ENCAP_SEG6_LOCAL:
+		e = &SEG6LocalEncap{}
+	default:
+		return nil, nil
+	}
+	if err := e.Decode(encap.Value); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 // RouteGet gets a route to a specific destination from the host system.
 // Equivalent to: 'ip route get'.
 func RouteGet(destination net.IP) ([]Route, error) {
@@ -974,6 +1018,12 @@ func (h *Handle) RouteGet(destination net.IP) ([]Route, error) {
 
 }
 
+// RouteGetContext is like RouteGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RouteGetContext(ctx context.Context, destination net.IP) ([]Route, error) {
+	return h.withContext(ctx).RouteGet(destination)
+}
+
 // RouteSubscribe takes a chan down which notifications will be sent
 // when routes are added or deleted. Close the 'done' chan to stop subscription.
 func RouteSubscribe(ch chan<- RouteUpdate, done <-chan struct{}) error {
@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 
@@ -23,6 +25,12 @@ func (h *Handle) RuleAdd(rule *Rule) error {
 	return ruleHandle(rule, req)
 }
 
+// RuleAddContext is like RuleAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RuleAddContext(ctx context.Context, rule *Rule) error {
+	return h.withContext(ctx).RuleAdd(rule)
+}
+
 // RuleDel deletes a rule from the system.
 // Equivalent to: ip rule del
 func RuleDel(rule *Rule) error {
@@ -36,6 +44,12 @@ func (h *Handle) RuleDel(rule *Rule) error {
 	return ruleHandle(rule, req)
 }
 
+// RuleDelContext is like RuleDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RuleDelContext(ctx context.Context, rule *Rule) error {
+	return h.withContext(ctx).RuleDel(rule)
+}
+
 func ruleHandle(rule *Rule, req *nl.NetlinkRequest) error {
 	msg := nl.NewRtMsg()
 	msg.Family = unix.AF_INET
@@ -167,9 +181,9 @@ func (h *Handle) RuleList(family int) ([]Rule, error) {
 	msg := nl.NewIfInfomsg(family)
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWRULE)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWRULE)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	native := nl.NativeEndian()
@@ -230,5 +244,11 @@ func (h *Handle) RuleList(family int) ([]Rule, error) {
 		res = append(res, *rule)
 	}
 
-	return res, nil
+	return res, executeErr
+}
+
+// RuleListContext is like RuleList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) RuleListContext(ctx context.Context, family int) ([]Rule, error) {
+	return h.withContext(ctx).RuleList(family)
 }
@@ -12,7 +12,8 @@ type SocketID struct {
 	Cookie          [2]uint32
 }
 
-// Socket represents a netlink socket.
+// Socket represents a socket as reported by the sock_diag netlink interface,
+// the ID of a unix socket only holds its cookie.
 type Socket struct {
 	Family  uint8
 	State   uint8
@@ -24,4 +25,52 @@ type Socket struct {
 	WQueue  uint32
 	UID     uint32
 	INode   uint32
+	// Protocol is the IPPROTO_* protocol of an inet socket
+	Protocol uint8
+	// Type is the SOCK_* type of a unix socket
+	Type uint8
+	// Path is the address a unix socket is bound to, abstract addresses
+	// start with '@'
+	Path string
+	// Peer is the inode of the peer of a connected unix socket
+	Peer uint32
+	// The extensions of an inet socket, only set when requested by
+	// SocketDiagFilter.Extensions and supported by the protocol. ClassID
+	// is requested with the INET_DIAG_TCLASS extension.
+	TCPInfo    *TCPInfo
+	Congestion string
+	MemInfo    *InetDiagMemInfo
+	SkMemInfo  *SkMemInfo
+	TOS        *uint8
+	TClass     *uint8
+	ClassID    *uint32
+	// Reported whatever the extensions requested, Mark only to
+	// CAP_NET_ADMIN and CgroupID, the cgroup v2 id, when the kernel
+	// tracks the cgroups of the sockets
+	Shutdown *uint8
+	Mark     *uint32
+	CgroupID *uint64
+}
+
+// InetDiagMemInfo is the memory usage of an inet socket, reported with the
+// INET_DIAG_MEMINFO extension
+type InetDiagMemInfo struct {
+	RMem uint32
+	WMem uint32
+	FMem uint32
+	TMem uint32
+}
+
+// SkMemInfo is the memory usage of a socket, reported with the
+// INET_DIAG_SKMEMINFO extension
+type SkMemInfo struct {
+	RMemAlloc  uint32
+	RcvBuf     uint32
+	WMemAlloc  uint32
+	SndBuf     uint32
+	FwdAlloc   uint32
+	WMemQueued uint32
+	OptMem     uint32
+	Backlog    uint32
+	Drops      uint32
 }
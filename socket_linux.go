@@ -1,9 +1,12 @@
 package netlink
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net"
+	"strings"
 
 	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
@@ -15,6 +18,26 @@ const (
 	sizeofSocket        = sizeofSocketID + 0x18
 )
 
+// SocketDiagFilter selects the sockets dumped by SocketDiagDump
+type SocketDiagFilter struct {
+	// Family is unix.AF_INET, unix.AF_INET6 or unix.AF_UNIX, both inet
+	// families are dumped when it is unix.AF_UNSPEC
+	Family uint8
+	// Protocol is the unix.IPPROTO_TCP, IPPROTO_UDP, IPPROTO_UDPLITE,
+	// IPPROTO_RAW or IPPROTO_SCTP protocol of the inet sockets, it is
+	// ignored for the unix sockets
+	Protocol uint8
+	// States is a mask of 1 << state, nl.TCP_ALL when zero. The unix
+	// sockets use the TCP states as well.
+	States uint32
+	// Extensions is a mask of 1 << (ext - 1) for the extensions from
+	// nl.INET_DIAG_MEMINFO to nl.INET_DIAG_SHUTDOWN reported with the inet
+	// sockets
+	Extensions uint8
+	// Filter is run by the kernel to select the inet sockets
+	Filter *SocketFilter
+}
+
 type socketRequest struct {
 	Family   uint8
 	Protocol uint8
@@ -49,10 +72,15 @@ func (r *socketRequest) Serialize() []byte {
 	native.PutUint32(b.Next(4), r.States)
 	networkOrder.PutUint16(b.Next(2), r.ID.SourcePort)
 	networkOrder.PutUint16(b.Next(2), r.ID.DestinationPort)
-	copy(b.Next(4), r.ID.Source.To4())
-	b.Next(12)
-	copy(b.Next(4), r.ID.Destination.To4())
-	b.Next(12)
+	if r.Family == unix.AF_INET6 {
+		copy(b.Next(16), r.ID.Source.To16())
+		copy(b.Next(16), r.ID.Destination.To16())
+	} else {
+		copy(b.Next(4), r.ID.Source.To4())
+		b.Next(12)
+		copy(b.Next(4), r.ID.Destination.To4())
+		b.Next(12)
+	}
 	native.PutUint32(b.Next(4), r.ID.Interface)
 	native.PutUint32(b.Next(4), r.ID.Cookie[0])
 	native.PutUint32(b.Next(4), r.ID.Cookie[1])
@@ -61,6 +89,31 @@ func (r *socketRequest) Serialize() []byte {
 
 func (r *socketRequest) Len() int { return sizeofSocketRequest }
 
+type unixSocketRequest struct {
+	Family   uint8
+	Protocol uint8
+	pad      uint16
+	States   uint32
+	INode    uint32
+	Show     uint32
+	Cookie   [2]uint32
+}
+
+func (r *unixSocketRequest) Serialize() []byte {
+	b := writeBuffer{Bytes: make([]byte, nl.SizeofUnixDiagReq)}
+	b.Write(r.Family)
+	b.Write(r.Protocol)
+	native.PutUint16(b.Next(2), r.pad)
+	native.PutUint32(b.Next(4), r.States)
+	native.PutUint32(b.Next(4), r.INode)
+	native.PutUint32(b.Next(4), r.Show)
+	native.PutUint32(b.Next(4), r.Cookie[0])
+	native.PutUint32(b.Next(4), r.Cookie[1])
+	return b.Bytes
+}
+
+func (r *unixSocketRequest) Len() int { return nl.SizeofUnixDiagReq }
+
 type readBuffer struct {
 	Bytes []byte
 	pos   int
@@ -89,10 +142,15 @@ func (s *Socket) deserialize(b []byte) error {
 	s.Retrans = rb.Read()
 	s.ID.SourcePort = networkOrder.Uint16(rb.Next(2))
 	s.ID.DestinationPort = networkOrder.Uint16(rb.Next(2))
-	s.ID.Source = net.IPv4(rb.Read(), rb.Read(), rb.Read(), rb.Read())
-	rb.Next(12)
-	s.ID.Destination = net.IPv4(rb.Read(), rb.Read(), rb.Read(), rb.Read())
-	rb.Next(12)
+	if s.Family == unix.AF_INET6 {
+		s.ID.Source = net.IP(append([]byte(nil), rb.Next(16)...))
+		s.ID.Destination = net.IP(append([]byte(nil), rb.Next(16)...))
+	} else {
+		s.ID.Source = net.IPv4(rb.Read(), rb.Read(), rb.Read(), rb.Read())
+		rb.Next(12)
+		s.ID.Destination = net.IPv4(rb.Read(), rb.Read(), rb.Read(), rb.Read())
+		rb.Next(12)
+	}
 	s.ID.Interface = native.Uint32(rb.Next(4))
 	s.ID.Cookie[0] = native.Uint32(rb.Next(4))
 	s.ID.Cookie[1] = native.Uint32(rb.Next(4))
@@ -101,59 +159,615 @@ func (s *Socket) deserialize(b []byte) error {
 	s.WQueue = native.Uint32(rb.Next(4))
 	s.UID = native.Uint32(rb.Next(4))
 	s.INode = native.Uint32(rb.Next(4))
+	attrs, err := nl.ParseRouteAttr(b[sizeofSocket:])
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.INET_DIAG_PROTOCOL:
+			// Reported for the raw sockets, which are requested as
+			// IPPROTO_RAW whatever their protocol
+			s.Protocol = attr.Value[0]
+		case nl.INET_DIAG_INFO:
+			// The other protocols report their own info struct
+			if s.Protocol == unix.IPPROTO_TCP {
+				s.TCPInfo = &TCPInfo{}
+				s.TCPInfo.deserialize(attr.Value)
+			}
+		case nl.INET_DIAG_CONG:
+			s.Congestion = nl.BytesToString(attr.Value)
+		case nl.INET_DIAG_MEMINFO:
+			if len(attr.Value) < nl.SizeofInetDiagMeminfo {
+				return fmt.Errorf("socket meminfo short read (%d); want %d", len(attr.Value), nl.SizeofInetDiagMeminfo)
+			}
+			s.MemInfo = &InetDiagMemInfo{
+				RMem: native.Uint32(attr.Value[0:4]),
+				WMem: native.Uint32(attr.Value[4:8]),
+				FMem: native.Uint32(attr.Value[8:12]),
+				TMem: native.Uint32(attr.Value[12:16]),
+			}
+		case nl.INET_DIAG_SKMEMINFO:
+			if len(attr.Value) < nl.SK_MEMINFO_VARS*4 {
+				return fmt.Errorf("socket skmeminfo short read (%d); want %d", len(attr.Value), nl.SK_MEMINFO_VARS*4)
+			}
+			s.SkMemInfo = &SkMemInfo{
+				RMemAlloc:  native.Uint32(attr.Value[0:4]),
+				RcvBuf:     native.Uint32(attr.Value[4:8]),
+				WMemAlloc:  native.Uint32(attr.Value[8:12]),
+				SndBuf:     native.Uint32(attr.Value[12:16]),
+				FwdAlloc:   native.Uint32(attr.Value[16:20]),
+				WMemQueued: native.Uint32(attr.Value[20:24]),
+				OptMem:     native.Uint32(attr.Value[24:28]),
+				Backlog:    native.Uint32(attr.Value[28:32]),
+				Drops:      native.Uint32(attr.Value[32:36]),
+			}
+		case nl.INET_DIAG_TOS:
+			tos := attr.Value[0]
+			s.TOS = &tos
+		case nl.INET_DIAG_TCLASS:
+			tclass := attr.Value[0]
+			s.TClass = &tclass
+		case nl.INET_DIAG_SHUTDOWN:
+			shutdown := attr.Value[0]
+			s.Shutdown = &shutdown
+		case nl.INET_DIAG_MARK:
+			mark := native.Uint32(attr.Value)
+			s.Mark = &mark
+		case nl.INET_DIAG_CLASS_ID:
+			classID := native.Uint32(attr.Value)
+			s.ClassID = &classID
+		case nl.INET_DIAG_CGROUP_ID:
+			cgroupID := native.Uint64(attr.Value)
+			s.CgroupID = &cgroupID
+		}
+	}
 	return nil
 }
 
-// SocketGet returns the Socket identified by its local and remote addresses.
-func SocketGet(local, remote net.Addr) (*Socket, error) {
-	localTCP, ok := local.(*net.TCPAddr)
-	if !ok {
-		return nil, ErrNotImplemented
+func (s *Socket) deserializeUnix(b []byte) error {
+	if len(b) < nl.SizeofUnixDiagMsg {
+		return fmt.Errorf("unix socket data short read (%d); want %d", len(b), nl.SizeofUnixDiagMsg)
 	}
-	remoteTCP, ok := remote.(*net.TCPAddr)
-	if !ok {
-		return nil, ErrNotImplemented
+	rb := readBuffer{Bytes: b}
+	s.Family = rb.Read()
+	s.Type = rb.Read()
+	s.State = rb.Read()
+	rb.Read()
+	s.INode = native.Uint32(rb.Next(4))
+	s.ID.Cookie[0] = native.Uint32(rb.Next(4))
+	s.ID.Cookie[1] = native.Uint32(rb.Next(4))
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofUnixDiagMsg:])
+	if err != nil {
+		return err
 	}
-	localIP := localTCP.IP.To4()
-	if localIP == nil {
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.UNIX_DIAG_NAME:
+			// Abstract addresses start with a NUL byte
+			if len(attr.Value) > 0 && attr.Value[0] == 0 {
+				s.Path = "@" + string(attr.Value[1:])
+			} else {
+				s.Path = strings.TrimRight(string(attr.Value), "\x00")
+			}
+		case nl.UNIX_DIAG_PEER:
+			s.Peer = native.Uint32(attr.Value)
+		case nl.UNIX_DIAG_RQLEN:
+			s.RQueue = native.Uint32(attr.Value)
+			s.WQueue = native.Uint32(attr.Value[4:])
+		case nl.UNIX_DIAG_UID:
+			s.UID = native.Uint32(attr.Value)
+		}
+	}
+	return nil
+}
+
+// SocketGet returns the Socket identified by its local and remote addresses.
+// The addresses are either both *net.TCPAddr or both *net.UDPAddr.
+func SocketGet(local, remote net.Addr) (*Socket, error) {
+	return pkgHandle.SocketGet(local, remote)
+}
+
+// SocketGet returns the Socket identified by its local and remote addresses
+// using the netlink handle passed. The addresses are either both
+// *net.TCPAddr or both *net.UDPAddr.
+func (h *Handle) SocketGet(local, remote net.Addr) (*Socket, error) {
+	var (
+		protocol              uint8
+		localIP, remoteIP     net.IP
+		localPort, remotePort int
+	)
+	switch l := local.(type) {
+	case *net.TCPAddr:
+		r, ok := remote.(*net.TCPAddr)
+		if !ok {
+			return nil, ErrNotImplemented
+		}
+		protocol = unix.IPPROTO_TCP
+		localIP, localPort, remoteIP, remotePort = l.IP, l.Port, r.IP, r.Port
+	case *net.UDPAddr:
+		r, ok := remote.(*net.UDPAddr)
+		if !ok {
+			return nil, ErrNotImplemented
+		}
+		protocol = unix.IPPROTO_UDP
+		// The kernel looks up the UDP sockets with the local and remote
+		// addresses swapped
+		localIP, localPort, remoteIP, remotePort = r.IP, r.Port, l.IP, l.Port
+	default:
 		return nil, ErrNotImplemented
 	}
-	remoteIP := remoteTCP.IP.To4()
-	if remoteIP == nil {
+	family := uint8(unix.AF_INET6)
+	if localIP.To4() != nil && remoteIP.To4() != nil {
+		family = unix.AF_INET
+	} else if localIP.To16() == nil || remoteIP.To16() == nil {
 		return nil, ErrNotImplemented
 	}
 
-	s, err := nl.Subscribe(unix.NETLINK_INET_DIAG)
-	if err != nil {
-		return nil, err
-	}
-	defer s.Close()
-	req := nl.NewNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, 0)
+	req := h.newNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, 0)
 	req.AddData(&socketRequest{
-		Family:   unix.AF_INET,
-		Protocol: unix.IPPROTO_TCP,
+		Family:   family,
+		Protocol: protocol,
 		ID: SocketID{
-			SourcePort:      uint16(localTCP.Port),
-			DestinationPort: uint16(remoteTCP.Port),
+			SourcePort:      uint16(localPort),
+			DestinationPort: uint16(remotePort),
 			Source:          localIP,
 			Destination:     remoteIP,
 			Cookie:          [2]uint32{nl.TCPDIAG_NOCOOKIE, nl.TCPDIAG_NOCOOKIE},
 		},
 	})
-	s.Send(req)
-	msgs, err := s.Receive()
+	msgs, err := req.Execute(unix.NETLINK_INET_DIAG, nl.SOCK_DIAG_BY_FAMILY)
 	if err != nil {
 		return nil, err
 	}
 	if len(msgs) == 0 {
 		return nil, errors.New("no message nor error from netlink")
 	}
-	if len(msgs) > 2 {
+	if len(msgs) > 1 {
 		return nil, fmt.Errorf("multiple (%d) matching sockets", len(msgs))
 	}
-	sock := &Socket{}
-	if err := sock.deserialize(msgs[0].Data); err != nil {
+	sock := &Socket{Protocol: protocol}
+	if err := sock.deserialize(msgs[0]); err != nil {
 		return nil, err
 	}
 	return sock, nil
 }
+
+// SocketDiagDump returns the sockets selected by filter.
+// ss -tuxan
+func SocketDiagDump(filter SocketDiagFilter) ([]*Socket, error) {
+	return pkgHandle.SocketDiagDump(filter)
+}
+
+// SocketDiagDump returns the sockets selected by filter using the netlink
+// handle passed.
+// ss -tuxan
+func (h *Handle) SocketDiagDump(filter SocketDiagFilter) ([]*Socket, error) {
+	states := filter.States
+	if states == 0 {
+		states = nl.TCP_ALL
+	}
+	switch filter.Family {
+	case unix.AF_UNIX:
+		return h.unixSocketDiagDump(states)
+	case unix.AF_UNSPEC:
+		sockets, err := h.inetSocketDiagDump(unix.AF_INET, states, filter)
+		if err != nil {
+			return nil, err
+		}
+		sockets6, err := h.inetSocketDiagDump(unix.AF_INET6, states, filter)
+		if err != nil {
+			return nil, err
+		}
+		return append(sockets, sockets6...), nil
+	case unix.AF_INET, unix.AF_INET6:
+		return h.inetSocketDiagDump(filter.Family, states, filter)
+	}
+	return nil, fmt.Errorf("unsupported socket family %d", filter.Family)
+}
+
+// SocketDiagDumpContext is like SocketDiagDump but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) SocketDiagDumpContext(ctx context.Context, filter SocketDiagFilter) ([]*Socket, error) {
+	return h.withContext(ctx).SocketDiagDump(filter)
+}
+
+// ErrSocketDestroyNotSupported is returned when the kernel can't destroy the
+// sockets of a protocol, it needs CONFIG_INET_DIAG_DESTROY. The error returned
+// also matches unix.EOPNOTSUPP with errors.Is.
+var ErrSocketDestroyNotSupported = errors.New("socket destroy not supported")
+
+// socketDestroyError wraps the EOPNOTSUPP of the kernel so that it also
+// matches ErrSocketDestroyNotSupported
+type socketDestroyError struct {
+	err error
+}
+
+func (e socketDestroyError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrSocketDestroyNotSupported, e.err)
+}
+
+func (e socketDestroyError) Unwrap() error {
+	return e.err
+}
+
+func (e socketDestroyError) Is(target error) bool {
+	return target == ErrSocketDestroyNotSupported
+}
+
+// SocketDestroy closes the socket of the unix.IPPROTO_TCP or IPPROTO_UDP
+// protocol identified by id with ECONNABORTED. The protocol is given apart
+// since SocketID doesn't hold it. A zero cookie matches any socket.
+// ss -K
+func SocketDestroy(protocol uint8, id SocketID) error {
+	return pkgHandle.SocketDestroy(protocol, id)
+}
+
+// SocketDestroy closes the socket of the unix.IPPROTO_TCP or IPPROTO_UDP
+// protocol identified by id with ECONNABORTED using the netlink handle
+// passed. The protocol is given apart since SocketID doesn't hold it. A zero
+// cookie matches any socket.
+// ss -K
+func (h *Handle) SocketDestroy(protocol uint8, id SocketID) error {
+	family := uint8(unix.AF_INET)
+	for _, ip := range []net.IP{id.Source, id.Destination} {
+		if ip != nil && ip.To4() == nil {
+			family = unix.AF_INET6
+		}
+	}
+	return h.socketDestroy(family, protocol, protocol, id)
+}
+
+// SocketDiagDestroy closes the inet sockets selected by filter with
+// ECONNABORTED and returns them, the sockets closed in the meantime are
+// skipped.
+// ss -K
+func SocketDiagDestroy(filter SocketDiagFilter) ([]*Socket, error) {
+	return pkgHandle.SocketDiagDestroy(filter)
+}
+
+// SocketDiagDestroy closes the inet sockets selected by filter with
+// ECONNABORTED using the netlink handle passed and returns them, the sockets
+// closed in the meantime are skipped.
+// ss -K
+func (h *Handle) SocketDiagDestroy(filter SocketDiagFilter) ([]*Socket, error) {
+	if filter.Family == unix.AF_UNIX {
+		return nil, fmt.Errorf("unix sockets can't be destroyed")
+	}
+	sockets, err := h.SocketDiagDump(filter)
+	if err != nil {
+		return nil, err
+	}
+	destroyed := make([]*Socket, 0, len(sockets))
+	for _, s := range sockets {
+		// The raw sockets report their own protocol
+		err := h.socketDestroy(s.Family, filter.Protocol, s.Protocol, s.ID)
+		if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ESTALE) {
+			continue
+		}
+		if err != nil {
+			return destroyed, err
+		}
+		destroyed = append(destroyed, s)
+	}
+	return destroyed, nil
+}
+
+// SocketDiagDestroyContext is like SocketDiagDestroy but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) SocketDiagDestroyContext(ctx context.Context, filter SocketDiagFilter) ([]*Socket, error) {
+	return h.withContext(ctx).SocketDiagDestroy(filter)
+}
+
+func (h *Handle) socketDestroy(family, protocol, rawProtocol uint8, id SocketID) error {
+	if id.Cookie == [2]uint32{} {
+		id.Cookie = [2]uint32{nl.TCPDIAG_NOCOOKIE, nl.TCPDIAG_NOCOOKIE}
+	}
+	req := h.newNetlinkRequest(nl.SOCK_DESTROY, unix.NLM_F_ACK)
+	sreq := &socketRequest{
+		Family:   family,
+		Protocol: protocol,
+		ID:       id,
+	}
+	if protocol == unix.IPPROTO_RAW {
+		sreq.pad = rawProtocol
+	}
+	req.AddData(sreq)
+	_, err := req.Execute(unix.NETLINK_INET_DIAG, 0)
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		return socketDestroyError{err}
+	}
+	return err
+}
+
+func (h *Handle) inetSocketDiagDump(family uint8, states uint32, filter SocketDiagFilter) ([]*Socket, error) {
+	protocol := filter.Protocol
+	switch protocol {
+	case unix.IPPROTO_TCP, unix.IPPROTO_UDP, unix.IPPROTO_UDPLITE, unix.IPPROTO_RAW, unix.IPPROTO_SCTP:
+	default:
+		return nil, fmt.Errorf("unsupported socket protocol %d", protocol)
+	}
+	req := h.newNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, unix.NLM_F_DUMP)
+	sreq := &socketRequest{
+		Family:   family,
+		Protocol: protocol,
+		Ext:      filter.Extensions,
+		States:   states,
+	}
+	// The raw protocol, held by the pad field, only matters to the
+	// lookup of a raw socket, IPPROTO_RAW is what ss sends
+	if protocol == unix.IPPROTO_RAW {
+		sreq.pad = unix.IPPROTO_RAW
+	}
+	req.AddData(sreq)
+	if filter.Filter != nil {
+		bytecode, err := filter.Filter.Bytecode()
+		if err != nil {
+			return nil, err
+		}
+		req.AddData(nl.NewRtAttr(nl.INET_DIAG_REQ_BYTECODE, bytecode))
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_INET_DIAG, nl.SOCK_DIAG_BY_FAMILY)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	sockets := make([]*Socket, 0, len(msgs))
+	for _, m := range msgs {
+		sock := &Socket{Protocol: protocol}
+		if err := sock.deserialize(m); err != nil {
+			return nil, err
+		}
+		sockets = append(sockets, sock)
+	}
+	return sockets, executeErr
+}
+
+func (h *Handle) unixSocketDiagDump(states uint32) ([]*Socket, error) {
+	req := h.newNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, unix.NLM_F_DUMP)
+	req.AddData(&unixSocketRequest{
+		Family: unix.AF_UNIX,
+		States: states,
+		Show:   nl.UDIAG_SHOW_NAME | nl.UDIAG_SHOW_PEER | nl.UDIAG_SHOW_RQLEN | nl.UDIAG_SHOW_UID,
+	})
+	msgs, executeErr := req.Execute(unix.NETLINK_INET_DIAG, nl.SOCK_DIAG_BY_FAMILY)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	sockets := make([]*Socket, 0, len(msgs))
+	for _, m := range msgs {
+		sock := &Socket{}
+		if err := sock.deserializeUnix(m); err != nil {
+			return nil, err
+		}
+		sockets = append(sockets, sock)
+	}
+	return sockets, executeErr
+}
+
+type socketFilterOp int
+
+const (
+	socketFilterCond socketFilterOp = iota
+	socketFilterAnd
+	socketFilterOr
+	socketFilterNot
+)
+
+// SocketFilterCmp compares the port of a socket in a SocketFilter
+type SocketFilterCmp int
+
+const (
+	SOCKET_FILTER_EQ SocketFilterCmp = iota
+	SOCKET_FILTER_GE
+	SOCKET_FILTER_LE
+)
+
+// SocketFilter is a condition on the inet sockets, compiled to the bytecode
+// the kernel runs to select the sockets dumped. The conditions are combined
+// with SocketFilterAnd, SocketFilterOr and SocketFilterNot.
+type SocketFilter struct {
+	op      socketFilterOp
+	code    uint8
+	data    []byte
+	filters []*SocketFilter
+	err     error
+}
+
+// SocketFilterSport matches the sockets whose source port compares to port,
+// the ranges of ports are matched with SocketFilterAnd
+func SocketFilterSport(cmp SocketFilterCmp, port uint16) *SocketFilter {
+	return socketFilterPort(cmp, nl.INET_DIAG_BC_S_EQ, nl.INET_DIAG_BC_S_GE, nl.INET_DIAG_BC_S_LE, port)
+}
+
+// SocketFilterDport matches the sockets whose destination port compares to
+// port
+func SocketFilterDport(cmp SocketFilterCmp, port uint16) *SocketFilter {
+	return socketFilterPort(cmp, nl.INET_DIAG_BC_D_EQ, nl.INET_DIAG_BC_D_GE, nl.INET_DIAG_BC_D_LE, port)
+}
+
+func socketFilterPort(cmp SocketFilterCmp, eq, ge, le uint8, port uint16) *SocketFilter {
+	// The port is held by the no field of a second op
+	data := make([]byte, nl.SizeofInetDiagBcOp)
+	native.PutUint16(data[2:], port)
+	f := &SocketFilter{data: data}
+	switch cmp {
+	case SOCKET_FILTER_EQ:
+		f.code = eq
+	case SOCKET_FILTER_GE:
+		f.code = ge
+	case SOCKET_FILTER_LE:
+		f.code = le
+	default:
+		f.err = fmt.Errorf("invalid socket filter comparison %d", cmp)
+	}
+	return f
+}
+
+// SocketFilterSrc matches the sockets whose source address is in prefix and
+// source port is port. A nil prefix matches any address and a negative port
+// any port.
+func SocketFilterSrc(prefix *net.IPNet, port int) *SocketFilter {
+	return socketFilterHost(nl.INET_DIAG_BC_S_COND, prefix, port)
+}
+
+// SocketFilterDst matches the sockets whose destination address is in prefix
+// and destination port is port, as SocketFilterSrc
+func SocketFilterDst(prefix *net.IPNet, port int) *SocketFilter {
+	return socketFilterHost(nl.INET_DIAG_BC_D_COND, prefix, port)
+}
+
+func socketFilterHost(code uint8, prefix *net.IPNet, port int) *SocketFilter {
+	if port < 0 {
+		port = -1
+	}
+	f := &SocketFilter{code: code}
+	var addr []byte
+	data := make([]byte, nl.SizeofInetDiagHostcond)
+	if prefix != nil {
+		ones, bits := prefix.Mask.Size()
+		switch {
+		case bits == 32 && prefix.IP.To4() != nil:
+			data[0] = unix.AF_INET
+			addr = prefix.IP.To4()
+		case bits == 128 && prefix.IP.To16() != nil:
+			data[0] = unix.AF_INET6
+			addr = prefix.IP.To16()
+		default:
+			f.err = fmt.Errorf("invalid socket filter prefix %v", prefix)
+		}
+		data[1] = uint8(ones)
+	}
+	native.PutUint32(data[4:], uint32(int32(port)))
+	f.data = append(data, addr...)
+	return f
+}
+
+// SocketFilterDev matches the sockets bound to the link ifindex
+func SocketFilterDev(ifindex int) *SocketFilter {
+	data := make([]byte, 4)
+	native.PutUint32(data, uint32(ifindex))
+	return &SocketFilter{code: nl.INET_DIAG_BC_DEV_COND, data: data}
+}
+
+// SocketFilterMark matches the sockets whose mark masked by mask is mark. The
+// kernel fails the whole dump with EPERM when the caller lacks CAP_NET_ADMIN.
+func SocketFilterMark(mark, mask uint32) *SocketFilter {
+	data := make([]byte, nl.SizeofInetDiagMarkcond)
+	native.PutUint32(data, mark)
+	native.PutUint32(data[4:], mask)
+	return &SocketFilter{code: nl.INET_DIAG_BC_MARK_COND, data: data}
+}
+
+// SocketFilterCgroup matches the sockets of the cgroup v2 id
+func SocketFilterCgroup(id uint64) *SocketFilter {
+	data := make([]byte, 8)
+	native.PutUint64(data, id)
+	return &SocketFilter{code: nl.INET_DIAG_BC_CGROUP_COND, data: data}
+}
+
+// SocketFilterAutoBound matches the sockets whose source port was picked by
+// the kernel
+func SocketFilterAutoBound() *SocketFilter {
+	return &SocketFilter{code: nl.INET_DIAG_BC_AUTO}
+}
+
+// SocketFilterAnd matches the sockets matched by all the filters, any socket
+// when there are none
+func SocketFilterAnd(filters ...*SocketFilter) *SocketFilter {
+	return &SocketFilter{op: socketFilterAnd, filters: filters}
+}
+
+// SocketFilterOr matches the sockets matched by one of the filters, no
+// socket when there are none
+func SocketFilterOr(filters ...*SocketFilter) *SocketFilter {
+	return &SocketFilter{op: socketFilterOr, filters: filters}
+}
+
+// SocketFilterNot matches the sockets not matched by filter
+func SocketFilterNot(filter *SocketFilter) *SocketFilter {
+	return &SocketFilter{op: socketFilterNot, filters: []*SocketFilter{filter}}
+}
+
+// Bytecode compiles the filter to the inet_diag bytecode sent as the
+// INET_DIAG_REQ_BYTECODE attribute
+func (f *SocketFilter) Bytecode() ([]byte, error) {
+	b, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+	// The jumps are 16 bits offsets up to the end of the bytecode plus
+	// one op
+	if len(b)+nl.SizeofInetDiagBcOp > math.MaxUint16 {
+		return nil, fmt.Errorf("socket filter too long (%d)", len(b))
+	}
+	return b, nil
+}
+
+// compile returns the bytecode of the filter, which is left by jumping to
+// its end when the socket matches and one op past its end otherwise, as ss
+// does
+func (f *SocketFilter) compile() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	switch f.op {
+	case socketFilterCond:
+		n := nl.SizeofInetDiagBcOp + len(f.data)
+		return append(socketFilterBcOp(f.code, n, n+nl.SizeofInetDiagBcOp), f.data...), nil
+	case socketFilterAnd:
+		var b []byte
+		for _, filter := range f.filters {
+			c, err := filter.compile()
+			if err != nil {
+				return nil, err
+			}
+			socketFilterRelocate(b, len(c))
+			b = append(b, c...)
+		}
+		return b, nil
+	case socketFilterOr:
+		if len(f.filters) == 0 {
+			return socketFilterBcOp(nl.INET_DIAG_BC_JMP, nl.SizeofInetDiagBcOp, 2*nl.SizeofInetDiagBcOp), nil
+		}
+		var b []byte
+		for i, filter := range f.filters {
+			c, err := filter.compile()
+			if err != nil {
+				return nil, err
+			}
+			// The filters compiled so far jump over the next one when the
+			// socket matches, and fall into it otherwise
+			if i > 0 {
+				b = append(b, socketFilterBcOp(nl.INET_DIAG_BC_JMP, nl.SizeofInetDiagBcOp, len(c)+nl.SizeofInetDiagBcOp)...)
+			}
+			b = append(b, c...)
+		}
+		return b, nil
+	case socketFilterNot:
+		b, err := f.filters[0].compile()
+		if err != nil {
+			return nil, err
+		}
+		return append(b, socketFilterBcOp(nl.INET_DIAG_BC_JMP, nl.SizeofInetDiagBcOp, 2*nl.SizeofInetDiagBcOp)...), nil
+	}
+	return nil, fmt.Errorf("invalid socket filter")
+}
+
+func socketFilterBcOp(code uint8, yes, no int) []byte {
+	b := make([]byte, nl.SizeofInetDiagBcOp)
+	b[0] = code
+	b[1] = uint8(yes)
+	native.PutUint16(b[2:], uint16(no))
+	return b
+}
+
+// socketFilterRelocate moves the jumps one op past the end of the bytecode b
+// to one op past the end of the reloc bytes appended to it
+func socketFilterRelocate(b []byte, reloc int) {
+	for pos := 0; pos < len(b); pos += int(b[pos+1]) {
+		no := b[pos+2 : pos+4]
+		if int(native.Uint16(no)) == len(b)-pos+nl.SizeofInetDiagBcOp {
+			native.PutUint16(no, uint16(len(b)-pos+nl.SizeofInetDiagBcOp+reloc))
+		}
+	}
+}
@@ -3,11 +3,19 @@
 package netlink
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
 	"log"
 	"net"
+	"os"
 	"os/user"
+	"path/filepath"
 	"strconv"
 	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
 )
 
 func TestSocketGet(t *testing.T) {
@@ -56,3 +64,447 @@ func TestSocketGet(t *testing.T) {
 		t.Fatalf("UID = %s, want %s", got, want)
 	}
 }
+
+func TestSocketGetIPv6UDP(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	// ::1 is not assigned before lo is up
+	l, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	conn, err := net.DialUDP("udp6", nil, l.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	remoteAddr := conn.RemoteAddr().(*net.UDPAddr)
+	socket, err := SocketGet(localAddr, remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if socket.Family != unix.AF_INET6 || socket.Protocol != unix.IPPROTO_UDP {
+		t.Fatalf("Unexpected socket %+v", socket)
+	}
+	if !socket.ID.Source.Equal(localAddr.IP) || int(socket.ID.SourcePort) != localAddr.Port ||
+		!socket.ID.Destination.Equal(remoteAddr.IP) || int(socket.ID.DestinationPort) != remoteAddr.Port {
+		t.Fatalf("Unexpected socket ID %+v", socket.ID)
+	}
+}
+
+// findSocket returns the inet socket bound to addr
+func findSocket(sockets []*Socket, addr net.Addr) *Socket {
+	var ip net.IP
+	var port int
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip, port = a.IP, a.Port
+	case *net.UDPAddr:
+		ip, port = a.IP, a.Port
+	}
+	for _, s := range sockets {
+		if s.ID.Source.Equal(ip) && int(s.ID.SourcePort) == port {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestSocketDiagDumpInet(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	l4, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l4.Close()
+	l6, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l6.Close()
+	conn, err := net.Dial("tcp4", l4.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	u, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+
+	sockets, err := SocketDiagDump(SocketDiagFilter{Protocol: unix.IPPROTO_TCP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []net.Addr{l4.Addr(), l6.Addr()} {
+		s := findSocket(sockets, addr)
+		if s == nil || s.State != nl.TCP_LISTEN || s.Protocol != unix.IPPROTO_TCP || s.INode == 0 {
+			t.Fatalf("Unexpected listening socket %+v for %v", s, addr)
+		}
+	}
+	if s := findSocket(sockets, conn.LocalAddr()); s == nil || s.State != nl.TCP_ESTABLISHED {
+		t.Fatalf("Unexpected connected socket %+v", s)
+	}
+
+	sockets, err = SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_TCP, States: 1 << nl.TCP_LISTEN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 1 || findSocket(sockets, l4.Addr()) == nil {
+		t.Fatalf("Unexpected listening sockets %+v", sockets)
+	}
+
+	sockets, err = SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_UDP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := findSocket(sockets, u.LocalAddr()); s == nil || s.Family != unix.AF_INET || s.Protocol != unix.IPPROTO_UDP {
+		t.Fatalf("Unexpected UDP sockets %+v", sockets)
+	}
+	sockets, err = SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET6, Protocol: unix.IPPROTO_UDP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 0 {
+		t.Fatalf("Unexpected UDP6 sockets %+v", sockets)
+	}
+
+	if _, err := SocketDiagDump(SocketDiagFilter{Protocol: unix.IPPROTO_ICMP}); err == nil {
+		t.Fatal("Dumping an unsupported protocol should fail")
+	}
+}
+
+func TestSocketDiagDumpUDPLite(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, unix.IPPROTO_UDPLITE)
+	if err != nil {
+		t.Skipf("UDP-Lite is not supported: %v", err)
+	}
+	defer unix.Close(fd)
+	if err := unix.Bind(fd, &unix.SockaddrInet4{Port: 5555, Addr: [4]byte{127, 0, 0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	sockets, err := SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_UDPLITE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 1 || sockets[0].ID.SourcePort != 5555 || sockets[0].Protocol != unix.IPPROTO_UDPLITE {
+		t.Fatalf("Unexpected UDP-Lite sockets %+v", sockets)
+	}
+}
+
+func TestSocketDiagDumpUnix(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	dir, err := ioutil.TempDir("", "netlink-socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	abstract, err := net.Listen("unix", "@netlink-socket-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer abstract.Close()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	server, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	sockets, err := SocketDiagDump(SocketDiagFilter{Family: unix.AF_UNIX, States: 1 << nl.TCP_LISTEN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]*Socket{}
+	for _, s := range sockets {
+		if s.Family != unix.AF_UNIX || s.Type != unix.SOCK_STREAM || s.State != nl.TCP_LISTEN || s.INode == 0 {
+			t.Fatalf("Unexpected listening socket %+v", s)
+		}
+		paths[s.Path] = s
+	}
+	if paths[path] == nil || paths["@netlink-socket-test"] == nil {
+		t.Fatalf("Unexpected listening sockets %+v", sockets)
+	}
+
+	sockets, err = SocketDiagDump(SocketDiagFilter{Family: unix.AF_UNIX, States: 1 << nl.TCP_ESTABLISHED})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inodes := map[uint32]*Socket{}
+	for _, s := range sockets {
+		inodes[s.INode] = s
+	}
+	var connected int
+	for _, s := range sockets {
+		if peer := inodes[s.Peer]; peer != nil && peer.Peer == s.INode {
+			connected++
+		}
+	}
+	if connected != 2 {
+		t.Fatalf("Connected pair not found in %+v", sockets)
+	}
+}
+
+func TestSocketDiagDumpExtensions(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	conn, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	raw, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_MARK, 0x42)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	sockets, err := SocketDiagDump(SocketDiagFilter{
+		Family:   unix.AF_INET,
+		Protocol: unix.IPPROTO_TCP,
+		Extensions: 1<<(nl.INET_DIAG_INFO-1) | 1<<(nl.INET_DIAG_CONG-1) | 1<<(nl.INET_DIAG_MEMINFO-1) |
+			1<<(nl.INET_DIAG_SKMEMINFO-1) | 1<<(nl.INET_DIAG_TOS-1),
+		Filter: SocketFilterMark(0x42, 0xff),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 1 || findSocket(sockets, conn.LocalAddr()) == nil {
+		t.Fatalf("Unexpected marked sockets %+v", sockets)
+	}
+	s := sockets[0]
+	if s.TCPInfo == nil || s.TCPInfo.State != nl.TCP_ESTABLISHED || s.TCPInfo.SndCwnd == 0 || s.TCPInfo.SndMss == 0 {
+		t.Fatalf("Unexpected TCP info %+v", s.TCPInfo)
+	}
+	if s.Congestion == "" || s.MemInfo == nil || s.SkMemInfo == nil || s.SkMemInfo.SndBuf == 0 || s.TOS == nil {
+		t.Fatalf("Unexpected extensions %+v", s)
+	}
+	if s.Mark == nil || *s.Mark != 0x42 || s.Shutdown == nil {
+		t.Fatalf("Unexpected socket %+v", s)
+	}
+	if s.TClass != nil || s.ClassID != nil {
+		t.Fatalf("Unrequested extensions reported %+v", s)
+	}
+}
+
+func TestSocketDiagDumpFilter(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	var listeners []net.Listener
+	var ports []uint16
+	for _, addr := range []string{"127.0.0.1:0", "127.0.0.1:0", "127.0.0.2:0"} {
+		l, err := net.Listen("tcp4", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		listeners = append(listeners, l)
+		ports = append(ports, uint16(l.Addr().(*net.TCPAddr).Port))
+	}
+	conn, err := net.Dial("tcp4", listeners[0].Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	dump := func(filter *SocketFilter, want ...net.Addr) {
+		t.Helper()
+		sockets, err := SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_TCP, Filter: filter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sockets) != len(want) {
+			t.Fatalf("Unexpected sockets %+v, want %v", sockets, want)
+		}
+		for _, addr := range want {
+			if findSocket(sockets, addr) == nil {
+				t.Fatalf("Socket %v not found in %+v", addr, sockets)
+			}
+		}
+	}
+
+	_, lo2, _ := net.ParseCIDR("127.0.0.2/32")
+	dump(SocketFilterSport(SOCKET_FILTER_EQ, ports[1]), listeners[1].Addr())
+	dump(SocketFilterOr(SocketFilterSport(SOCKET_FILTER_EQ, ports[1]), SocketFilterSrc(lo2, -1)), listeners[1].Addr(), listeners[2].Addr())
+	dump(SocketFilterAnd(SocketFilterSport(SOCKET_FILTER_GE, ports[2]), SocketFilterSport(SOCKET_FILTER_LE, ports[2])), listeners[2].Addr())
+	dump(SocketFilterAnd(SocketFilterSrc(nil, int(ports[0])), SocketFilterNot(SocketFilterDst(nil, 0))), listeners[0].Addr())
+	dump(SocketFilterDst(&net.IPNet{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}, int(ports[0])), conn.LocalAddr())
+	dump(SocketFilterAnd(SocketFilterDport(SOCKET_FILTER_EQ, ports[0]), SocketFilterAutoBound()), conn.LocalAddr())
+	dump(SocketFilterOr())
+	// The socket accepted by the first listener has its address
+	dump(SocketFilterNot(SocketFilterOr()), conn.LocalAddr(), listeners[0].Addr(), listeners[0].Addr(), listeners[1].Addr(), listeners[2].Addr())
+}
+
+func TestSocketFilterBytecode(t *testing.T) {
+	native := nl.NativeEndian()
+	op := func(code, yes uint8, no uint16, data ...byte) []byte {
+		b := []byte{code, yes, 0, 0}
+		native.PutUint16(b[2:], no)
+		return append(b, data...)
+	}
+	port := func(port uint16) []byte {
+		return op(0, 0, port)
+	}
+
+	// sport >= :1000 and sport <= :2000, the no jump of the first
+	// condition is moved past the second one
+	b, err := SocketFilterAnd(SocketFilterSport(SOCKET_FILTER_GE, 1000), SocketFilterSport(SOCKET_FILTER_LE, 2000)).Bytecode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(op(nl.INET_DIAG_BC_S_GE, 8, 20, port(1000)...), op(nl.INET_DIAG_BC_S_LE, 8, 12, port(2000)...)...)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("Unexpected bytecode %v, want %v", b, want)
+	}
+
+	// dport = :80 or not dev 2
+	b, err = SocketFilterOr(SocketFilterDport(SOCKET_FILTER_EQ, 80), SocketFilterNot(SocketFilterDev(2))).Bytecode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := make([]byte, 4)
+	native.PutUint32(dev, 2)
+	want = op(nl.INET_DIAG_BC_D_EQ, 8, 12, port(80)...)
+	want = append(want, op(nl.INET_DIAG_BC_JMP, 4, 16)...)
+	want = append(want, op(nl.INET_DIAG_BC_DEV_COND, 8, 12, dev...)...)
+	want = append(want, op(nl.INET_DIAG_BC_JMP, 4, 8)...)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("Unexpected bytecode %v, want %v", b, want)
+	}
+
+	// src 10.0.0.0/8:any
+	_, prefix, _ := net.ParseCIDR("10.0.0.0/8")
+	b, err = SocketFilterSrc(prefix, -1).Bytecode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = op(nl.INET_DIAG_BC_S_COND, 16, 20, unix.AF_INET, 8, 0, 0, 0xff, 0xff, 0xff, 0xff, 10, 0, 0, 0)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("Unexpected bytecode %v, want %v", b, want)
+	}
+
+	if _, err := SocketFilterAnd(SocketFilterSrc(&net.IPNet{IP: net.ParseIP("::1"), Mask: net.CIDRMask(8, 32)}, -1)).Bytecode(); err == nil {
+		t.Fatal("Compiling an invalid prefix should fail")
+	}
+}
+
+func TestSocketDestroyNotSupported(t *testing.T) {
+	var err error = socketDestroyError{unix.EOPNOTSUPP}
+	if !errors.Is(err, ErrSocketDestroyNotSupported) || !errors.Is(err, unix.EOPNOTSUPP) {
+		t.Fatalf("%v should match both ErrSocketDestroyNotSupported and EOPNOTSUPP", err)
+	}
+}
+
+func TestSocketDestroy(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	conn, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	s, err := SocketGet(conn.LocalAddr(), conn.RemoteAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SocketDestroy(unix.IPPROTO_TCP, s.ID)
+	if errors.Is(err, ErrSocketDestroyNotSupported) {
+		t.Skip("Socket destroy needs CONFIG_INET_DIAG_DESTROY")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, unix.ECONNABORTED) {
+		t.Fatalf("Expected ECONNABORTED reading a destroyed socket, got %v", err)
+	}
+	if err := SocketDestroy(unix.IPPROTO_TCP, s.ID); !errors.Is(err, unix.ENOENT) {
+		t.Fatalf("Expected ENOENT destroying a closed socket, got %v", err)
+	}
+
+	u, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	addr := u.LocalAddr().(*net.UDPAddr)
+	if err := SocketDestroy(unix.IPPROTO_UDP, SocketID{Source: addr.IP, SourcePort: uint16(addr.Port)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Read(make([]byte, 1)); !errors.Is(err, unix.ECONNABORTED) {
+		t.Fatalf("Expected ECONNABORTED reading a destroyed socket, got %v", err)
+	}
+}
+
+func TestSocketDiagDestroy(t *testing.T) {
+	defer setUpNetlinkTestWithLoopback(t)()
+
+	var listeners []net.Listener
+	for _, addr := range []string{"127.0.0.1:0", "127.0.0.2:0", "127.0.0.2:0"} {
+		l, err := net.Listen("tcp4", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		listeners = append(listeners, l)
+	}
+
+	_, prefix, _ := net.ParseCIDR("127.0.0.2/32")
+	filter := SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_TCP, Filter: SocketFilterSrc(prefix, -1)}
+	sockets, err := SocketDiagDestroy(filter)
+	if errors.Is(err, ErrSocketDestroyNotSupported) {
+		t.Skip("Socket destroy needs CONFIG_INET_DIAG_DESTROY")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 2 || findSocket(sockets, listeners[1].Addr()) == nil || findSocket(sockets, listeners[2].Addr()) == nil {
+		t.Fatalf("Unexpected destroyed sockets %+v", sockets)
+	}
+	sockets, err = SocketDiagDump(SocketDiagFilter{Family: unix.AF_INET, Protocol: unix.IPPROTO_TCP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 1 || findSocket(sockets, listeners[0].Addr()) == nil {
+		t.Fatalf("Unexpected sockets left %+v", sockets)
+	}
+
+	if _, err := SocketDiagDestroy(SocketDiagFilter{Family: unix.AF_UNIX}); err == nil {
+		t.Fatal("Destroying unix sockets should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package netlink
+
+// TCPInfo is the struct tcp_info reported for the TCP sockets with the
+// INET_DIAG_INFO extension. The fields added by kernels newer than the
+// running one are left zero.
+type TCPInfo struct {
+	State                  uint8
+	CaState                uint8
+	Retransmits            uint8
+	Probes                 uint8
+	Backoff                uint8
+	Options                uint8
+	SndWscale              uint8
+	RcvWscale              uint8
+	DeliveryRateAppLimited bool
+	FastopenClientFail     uint8
+	// Rto and Ato are in usec
+	Rto     uint32
+	Ato     uint32
+	SndMss  uint32
+	RcvMss  uint32
+	Unacked uint32
+	Sacked  uint32
+	Lost    uint32
+	Retrans uint32
+	Fackets uint32
+	// Times, in msec
+	LastDataSent uint32
+	LastAckSent  uint32
+	LastDataRecv uint32
+	LastAckRecv  uint32
+	// Metrics, Rtt, Rttvar and RcvRtt are in usec
+	Pmtu         uint32
+	RcvSsthresh  uint32
+	Rtt          uint32
+	Rttvar       uint32
+	SndSsthresh  uint32
+	SndCwnd      uint32
+	Advmss       uint32
+	Reordering   uint32
+	RcvRtt       uint32
+	RcvSpace     uint32
+	TotalRetrans uint32
+	// PacingRate and MaxPacingRate are in bytes per second
+	PacingRate    uint64
+	MaxPacingRate uint64
+	BytesAcked    uint64
+	BytesReceived uint64
+	SegsOut       uint32
+	SegsIn        uint32
+	NotsentBytes  uint32
+	MinRtt        uint32 // In usec
+	DataSegsIn    uint32
+	DataSegsOut   uint32
+	// In bytes per second
+	DeliveryRate uint64
+	// BusyTime, RwndLimited and SndbufLimited are in usec
+	BusyTime      uint64
+	RwndLimited   uint64
+	SndbufLimited uint64
+	Delivered     uint32
+	DeliveredCe   uint32
+	BytesSent     uint64
+	BytesRetrans  uint64
+	DsackDups     uint32
+	ReordSeen     uint32
+	RcvOoopack    uint32
+	SndWnd        uint32
+	RcvWnd        uint32
+	Rehash        uint32
+	TotalRto      uint16
+	// TotalRtoRecoveries counts the RTO recoveries and TotalRtoTime their
+	// duration in msec
+	TotalRtoRecoveries uint16
+	TotalRtoTime       uint32
+}
@@ -0,0 +1,83 @@
+package netlink
+
+import (
+	"github.com/vishvananda/netlink/nl"
+)
+
+func (t *TCPInfo) deserialize(b []byte) {
+	// Older kernels report a shorter tcp_info, its missing fields are
+	// read as zero
+	if len(b) < nl.SizeofTCPInfo {
+		b = append(append([]byte(nil), b...), make([]byte, nl.SizeofTCPInfo-len(b))...)
+	}
+	rb := readBuffer{Bytes: b}
+	t.State = rb.Read()
+	t.CaState = rb.Read()
+	t.Retransmits = rb.Read()
+	t.Probes = rb.Read()
+	t.Backoff = rb.Read()
+	t.Options = rb.Read()
+	// The bit fields are allocated from the low bits on the little endian
+	// architectures and from the high bits on the big endian ones
+	wscale, flags := rb.Read(), rb.Read()
+	if native == networkOrder {
+		t.SndWscale, t.RcvWscale = wscale>>4, wscale&0xf
+		t.DeliveryRateAppLimited = flags&0x80 != 0
+		t.FastopenClientFail = flags >> 5 & 0x3
+	} else {
+		t.SndWscale, t.RcvWscale = wscale&0xf, wscale>>4
+		t.DeliveryRateAppLimited = flags&0x1 != 0
+		t.FastopenClientFail = flags >> 1 & 0x3
+	}
+	t.Rto = native.Uint32(rb.Next(4))
+	t.Ato = native.Uint32(rb.Next(4))
+	t.SndMss = native.Uint32(rb.Next(4))
+	t.RcvMss = native.Uint32(rb.Next(4))
+	t.Unacked = native.Uint32(rb.Next(4))
+	t.Sacked = native.Uint32(rb.Next(4))
+	t.Lost = native.Uint32(rb.Next(4))
+	t.Retrans = native.Uint32(rb.Next(4))
+	t.Fackets = native.Uint32(rb.Next(4))
+	t.LastDataSent = native.Uint32(rb.Next(4))
+	t.LastAckSent = native.Uint32(rb.Next(4))
+	t.LastDataRecv = native.Uint32(rb.Next(4))
+	t.LastAckRecv = native.Uint32(rb.Next(4))
+	t.Pmtu = native.Uint32(rb.Next(4))
+	t.RcvSsthresh = native.Uint32(rb.Next(4))
+	t.Rtt = native.Uint32(rb.Next(4))
+	t.Rttvar = native.Uint32(rb.Next(4))
+	t.SndSsthresh = native.Uint32(rb.Next(4))
+	t.SndCwnd = native.Uint32(rb.Next(4))
+	t.Advmss = native.Uint32(rb.Next(4))
+	t.Reordering = native.Uint32(rb.Next(4))
+	t.RcvRtt = native.Uint32(rb.Next(4))
+	t.RcvSpace = native.Uint32(rb.Next(4))
+	t.TotalRetrans = native.Uint32(rb.Next(4))
+	t.PacingRate = native.Uint64(rb.Next(8))
+	t.MaxPacingRate = native.Uint64(rb.Next(8))
+	t.BytesAcked = native.Uint64(rb.Next(8))
+	t.BytesReceived = native.Uint64(rb.Next(8))
+	t.SegsOut = native.Uint32(rb.Next(4))
+	t.SegsIn = native.Uint32(rb.Next(4))
+	t.NotsentBytes = native.Uint32(rb.Next(4))
+	t.MinRtt = native.Uint32(rb.Next(4))
+	t.DataSegsIn = native.Uint32(rb.Next(4))
+	t.DataSegsOut = native.Uint32(rb.Next(4))
+	t.DeliveryRate = native.Uint64(rb.Next(8))
+	t.BusyTime = native.Uint64(rb.Next(8))
+	t.RwndLimited = native.Uint64(rb.Next(8))
+	t.SndbufLimited = native.Uint64(rb.Next(8))
+	t.Delivered = native.Uint32(rb.Next(4))
+	t.DeliveredCe = native.Uint32(rb.Next(4))
+	t.BytesSent = native.Uint64(rb.Next(8))
+	t.BytesRetrans = native.Uint64(rb.Next(8))
+	t.DsackDups = native.Uint32(rb.Next(4))
+	t.ReordSeen = native.Uint32(rb.Next(4))
+	t.RcvOoopack = native.Uint32(rb.Next(4))
+	t.SndWnd = native.Uint32(rb.Next(4))
+	t.RcvWnd = native.Uint32(rb.Next(4))
+	t.Rehash = native.Uint32(rb.Next(4))
+	t.TotalRto = native.Uint16(rb.Next(2))
+	t.TotalRtoRecoveries = native.Uint16(rb.Next(2))
+	t.TotalRtoTime = native.Uint32(rb.Next(4))
+}
@@ -0,0 +1,84 @@
+package netlink
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"time"
+)
+
+// WireguardKey is a Curve25519 key, or a preshared symmetric key, of
+// WireGuard
+type WireguardKey [32]byte
+
+// ParseWireguardKey parses the base64 encoding of a key, as used by `wg`
+func ParseWireguardKey(s string) (WireguardKey, error) {
+	var key WireguardKey
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return key, err
+	}
+	if len(b) != len(key) {
+		return key, fmt.Errorf("invalid WireGuard key length %d, should be %d", len(b), len(key))
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// String returns the base64 encoding of the key
+func (k WireguardKey) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// WireguardDevice is the configuration and the state of a WireGuard link,
+// as reported by the kernel. The private key is only reported to
+// CAP_NET_ADMIN.
+type WireguardDevice struct {
+	LinkIndex    int
+	Name         string
+	PrivateKey   WireguardKey
+	PublicKey    WireguardKey
+	ListenPort   uint16
+	FirewallMark uint32
+	Peers        []*WireguardPeer
+}
+
+// WireguardPeer is a peer of a WireGuard link, as reported by the kernel.
+// LastHandshakeTime is zero when no handshake was completed.
+type WireguardPeer struct {
+	PublicKey                   WireguardKey
+	PresharedKey                WireguardKey
+	Endpoint                    *net.UDPAddr
+	PersistentKeepaliveInterval time.Duration
+	LastHandshakeTime           time.Time
+	ReceiveBytes                uint64
+	TransmitBytes               uint64
+	AllowedIPs                  []net.IPNet
+	ProtocolVersion             int
+}
+
+// WireguardConfig is a change of the configuration of a WireGuard link, the
+// nil fields are left unchanged. ReplacePeers removes the peers not listed
+// in Peers.
+type WireguardConfig struct {
+	PrivateKey   *WireguardKey
+	ListenPort   *uint16
+	FirewallMark *uint32
+	ReplacePeers bool
+	Peers        []WireguardPeerConfig
+}
+
+// WireguardPeerConfig is a change of the configuration of the peer
+// identified by PublicKey, the nil fields are left unchanged. Remove removes
+// the peer and UpdateOnly only changes an existing one. ReplaceAllowedIPs
+// removes the allowed IPs of the peer not listed in AllowedIPs.
+type WireguardPeerConfig struct {
+	PublicKey                   WireguardKey
+	Remove                      bool
+	UpdateOnly                  bool
+	PresharedKey                *WireguardKey
+	Endpoint                    *net.UDPAddr
+	PersistentKeepaliveInterval *time.Duration
+	ReplaceAllowedIPs           bool
+	AllowedIPs                  []net.IPNet
+}
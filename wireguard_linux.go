@@ -0,0 +1,335 @@
+package netlink
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// WireguardGet returns the configuration, the peers and their state of the
+// WireGuard link.
+// Equivalent to: `wg show $link`
+func WireguardGet(link Link) (*WireguardDevice, error) {
+	return pkgHandle.WireguardGet(link)
+}
+
+// WireguardGet returns the configuration, the peers and their state of the
+// WireGuard link using the netlink handle passed.
+// Equivalent to: `wg show $link`
+func (h *Handle) WireguardGet(link Link) (*WireguardDevice, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req, err := h.newWireguardRequest(nl.WG_CMD_GET_DEVICE, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseWireguardDevice(msgs)
+}
+
+// WireguardGetContext is like WireguardGet but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) WireguardGetContext(ctx context.Context, link Link) (*WireguardDevice, error) {
+	return h.withContext(ctx).WireguardGet(link)
+}
+
+// WireguardSet changes the configuration and the peers of the WireGuard
+// link.
+// Equivalent to: `wg set $link ...`
+func WireguardSet(link Link, config *WireguardConfig) error {
+	return pkgHandle.WireguardSet(link, config)
+}
+
+// WireguardSet changes the configuration and the peers of the WireGuard link
+// using the netlink handle passed.
+// Equivalent to: `wg set $link ...`
+func (h *Handle) WireguardSet(link Link, config *WireguardConfig) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req, err := h.newWireguardRequest(nl.WG_CMD_SET_DEVICE, unix.NLM_F_ACK)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	attrs, err := wireguardConfigAttrs(config)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		req.AddData(attr)
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// WireguardSetContext is like WireguardSet but gives up and returns
+// ctx.Err() once ctx is done.
+func (h *Handle) WireguardSetContext(ctx context.Context, link Link, config *WireguardConfig) error {
+	return h.withContext(ctx).WireguardSet(link, config)
+}
+
+func (h *Handle) newWireguardRequest(cmd uint8, flags int) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.WG_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	req := h.newNetlinkRequest(int(f.ID), flags)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: nl.WG_GENL_VERSION,
+	})
+	return req, nil
+}
+
+func wireguardConfigAttrs(config *WireguardConfig) ([]*nl.RtAttr, error) {
+	var attrs []*nl.RtAttr
+	if config.PrivateKey != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.WGDEVICE_A_PRIVATE_KEY, config.PrivateKey[:]))
+	}
+	if config.ListenPort != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.WGDEVICE_A_LISTEN_PORT, nl.Uint16Attr(*config.ListenPort)))
+	}
+	if config.FirewallMark != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.WGDEVICE_A_FWMARK, nl.Uint32Attr(*config.FirewallMark)))
+	}
+	if config.ReplacePeers {
+		attrs = append(attrs, nl.NewRtAttr(nl.WGDEVICE_A_FLAGS, nl.Uint32Attr(nl.WGDEVICE_F_REPLACE_PEERS)))
+	}
+	if len(config.Peers) == 0 {
+		return attrs, nil
+	}
+	// The kernel ignores the type of the elements of the nested lists
+	peers := nl.NewRtAttr(nl.WGDEVICE_A_PEERS|nl.NLA_F_NESTED, nil)
+	for _, p := range config.Peers {
+		peer := nl.NewRtAttrChild(peers, nl.NLA_F_NESTED, nil)
+		nl.NewRtAttrChild(peer, nl.WGPEER_A_PUBLIC_KEY, p.PublicKey[:])
+		var flags uint32
+		if p.Remove {
+			flags |= nl.WGPEER_F_REMOVE_ME
+		}
+		if p.UpdateOnly {
+			flags |= nl.WGPEER_F_UPDATE_ONLY
+		}
+		if p.ReplaceAllowedIPs {
+			flags |= nl.WGPEER_F_REPLACE_ALLOWEDIPS
+		}
+		if flags != 0 {
+			nl.NewRtAttrChild(peer, nl.WGPEER_A_FLAGS, nl.Uint32Attr(flags))
+		}
+		if p.Remove {
+			continue
+		}
+		if p.PresharedKey != nil {
+			nl.NewRtAttrChild(peer, nl.WGPEER_A_PRESHARED_KEY, p.PresharedKey[:])
+		}
+		if p.Endpoint != nil {
+			endpoint, err := wireguardEndpointAttr(p.Endpoint)
+			if err != nil {
+				return nil, err
+			}
+			nl.NewRtAttrChild(peer, nl.WGPEER_A_ENDPOINT, endpoint)
+		}
+		if p.PersistentKeepaliveInterval != nil {
+			interval := *p.PersistentKeepaliveInterval / time.Second
+			if *p.PersistentKeepaliveInterval < 0 || interval > math.MaxUint16 {
+				return nil, fmt.Errorf("invalid persistent keepalive interval %v, should be 0 to %d seconds", *p.PersistentKeepaliveInterval, math.MaxUint16)
+			}
+			nl.NewRtAttrChild(peer, nl.WGPEER_A_PERSISTENT_KEEPALIVE_INTERVAL, nl.Uint16Attr(uint16(interval)))
+		}
+		if len(p.AllowedIPs) == 0 {
+			continue
+		}
+		allowedIPs := nl.NewRtAttrChild(peer, nl.WGPEER_A_ALLOWEDIPS|nl.NLA_F_NESTED, nil)
+		for _, ipNet := range p.AllowedIPs {
+			family, ip := uint16(unix.AF_INET6), ipNet.IP.To16()
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				family, ip = unix.AF_INET, ip4
+			}
+			if ip == nil {
+				return nil, fmt.Errorf("invalid allowed IP %v", ipNet.IP)
+			}
+			ones, bits := ipNet.Mask.Size()
+			if bits != 8*len(ip) {
+				return nil, fmt.Errorf("invalid mask %v of allowed IP %v", ipNet.Mask, ipNet.IP)
+			}
+			allowedIP := nl.NewRtAttrChild(allowedIPs, nl.NLA_F_NESTED, nil)
+			nl.NewRtAttrChild(allowedIP, nl.WGALLOWEDIP_A_FAMILY, nl.Uint16Attr(family))
+			nl.NewRtAttrChild(allowedIP, nl.WGALLOWEDIP_A_IPADDR, ip)
+			nl.NewRtAttrChild(allowedIP, nl.WGALLOWEDIP_A_CIDR_MASK, nl.Uint8Attr(uint8(ones)))
+		}
+	}
+	return append(attrs, peers), nil
+}
+
+// wireguardEndpointAttr returns the sockaddr_in or sockaddr_in6 of addr, the
+// zone of an IPv6 address is either an interface name or index
+func wireguardEndpointAttr(addr *net.UDPAddr) ([]byte, error) {
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		b := make([]byte, unix.SizeofSockaddrInet4)
+		native.PutUint16(b[0:2], unix.AF_INET)
+		networkOrder.PutUint16(b[2:4], uint16(addr.Port))
+		copy(b[4:8], ip4)
+		return b, nil
+	}
+	ip := addr.IP.To16()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid endpoint %v", addr)
+	}
+	b := make([]byte, unix.SizeofSockaddrInet6)
+	native.PutUint16(b[0:2], unix.AF_INET6)
+	networkOrder.PutUint16(b[2:4], uint16(addr.Port))
+	copy(b[8:24], ip)
+	if addr.Zone != "" {
+		index, err := strconv.Atoi(addr.Zone)
+		if err != nil {
+			iface, err := net.InterfaceByName(addr.Zone)
+			if err != nil {
+				return nil, err
+			}
+			index = iface.Index
+		}
+		native.PutUint32(b[24:28], uint32(index))
+	}
+	return b, nil
+}
+
+func parseWireguardEndpoint(b []byte) *net.UDPAddr {
+	if len(b) < unix.SizeofSockaddrInet4 {
+		return nil
+	}
+	port := int(networkOrder.Uint16(b[2:4]))
+	switch native.Uint16(b[0:2]) {
+	case unix.AF_INET:
+		return &net.UDPAddr{IP: net.IP(append([]byte{}, b[4:8]...)), Port: port}
+	case unix.AF_INET6:
+		if len(b) < unix.SizeofSockaddrInet6 {
+			return nil
+		}
+		addr := &net.UDPAddr{IP: net.IP(append([]byte{}, b[8:24]...)), Port: port}
+		if index := int(native.Uint32(b[24:28])); index != 0 {
+			addr.Zone = strconv.Itoa(index)
+			if iface, err := net.InterfaceByIndex(index); err == nil {
+				addr.Zone = iface.Name
+			}
+		}
+		return addr
+	}
+	return nil
+}
+
+// parseWireguardDevice parses the messages of a WG_CMD_GET_DEVICE dump. The
+// kernel splits the peers over several messages, a peer whose allowed IPs
+// do not fit in a message continues in the next one.
+func parseWireguardDevice(msgs [][]byte) (*WireguardDevice, error) {
+	dev := &WireguardDevice{}
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.WGDEVICE_A_IFINDEX:
+				dev.LinkIndex = int(native.Uint32(attr.Value))
+			case nl.WGDEVICE_A_IFNAME:
+				dev.Name = strings.TrimRight(string(attr.Value), "\x00")
+			case nl.WGDEVICE_A_PRIVATE_KEY:
+				copy(dev.PrivateKey[:], attr.Value)
+			case nl.WGDEVICE_A_PUBLIC_KEY:
+				copy(dev.PublicKey[:], attr.Value)
+			case nl.WGDEVICE_A_LISTEN_PORT:
+				dev.ListenPort = native.Uint16(attr.Value)
+			case nl.WGDEVICE_A_FWMARK:
+				dev.FirewallMark = native.Uint32(attr.Value)
+			case nl.WGDEVICE_A_PEERS:
+				peers, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range peers {
+					peer, err := parseWireguardPeer(p.Value)
+					if err != nil {
+						return nil, err
+					}
+					if n := len(dev.Peers); n > 0 && dev.Peers[n-1].PublicKey == peer.PublicKey {
+						dev.Peers[n-1].AllowedIPs = append(dev.Peers[n-1].AllowedIPs, peer.AllowedIPs...)
+						continue
+					}
+					dev.Peers = append(dev.Peers, peer)
+				}
+			}
+		}
+	}
+	return dev, nil
+}
+
+func parseWireguardPeer(b []byte) (*WireguardPeer, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	peer := &WireguardPeer{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.WGPEER_A_PUBLIC_KEY:
+			copy(peer.PublicKey[:], attr.Value)
+		case nl.WGPEER_A_PRESHARED_KEY:
+			copy(peer.PresharedKey[:], attr.Value)
+		case nl.WGPEER_A_ENDPOINT:
+			peer.Endpoint = parseWireguardEndpoint(attr.Value)
+		case nl.WGPEER_A_PERSISTENT_KEEPALIVE_INTERVAL:
+			peer.PersistentKeepaliveInterval = time.Duration(native.Uint16(attr.Value)) * time.Second
+		case nl.WGPEER_A_LAST_HANDSHAKE_TIME:
+			// struct __kernel_timespec
+			if len(attr.Value) < 16 {
+				return nil, fmt.Errorf("invalid last handshake time of %d bytes", len(attr.Value))
+			}
+			sec := int64(native.Uint64(attr.Value[0:8]))
+			nsec := int64(native.Uint64(attr.Value[8:16]))
+			if sec != 0 || nsec != 0 {
+				peer.LastHandshakeTime = time.Unix(sec, nsec)
+			}
+		case nl.WGPEER_A_RX_BYTES:
+			peer.ReceiveBytes = native.Uint64(attr.Value)
+		case nl.WGPEER_A_TX_BYTES:
+			peer.TransmitBytes = native.Uint64(attr.Value)
+		case nl.WGPEER_A_PROTOCOL_VERSION:
+			peer.ProtocolVersion = int(native.Uint32(attr.Value))
+		case nl.WGPEER_A_ALLOWEDIPS:
+			allowedIPs, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range allowedIPs {
+				data, err := nl.ParseRouteAttr(a.Value)
+				if err != nil {
+					return nil, err
+				}
+				var ipNet net.IPNet
+				var ones int
+				for _, datum := range data {
+					switch datum.Attr.Type & nl.NLA_TYPE_MASK {
+					case nl.WGALLOWEDIP_A_IPADDR:
+						ipNet.IP = net.IP(datum.Value)
+					case nl.WGALLOWEDIP_A_CIDR_MASK:
+						ones = int(datum.Value[0])
+					}
+				}
+				ipNet.Mask = net.CIDRMask(ones, 8*len(ipNet.IP))
+				peer.AllowedIPs = append(peer.AllowedIPs, ipNet)
+			}
+		}
+	}
+	return peer, nil
+}
@@ -0,0 +1,186 @@
+// +build linux
+
+package netlink
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+)
+
+func TestWireguardSetGet(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "wireguard")
+	defer tearDown()
+
+	link := &GenericLink{LinkAttrs: LinkAttrs{Name: "wg0"}, LinkType: "wireguard"}
+	if err := LinkAdd(link); err != nil {
+		t.Fatal(err)
+	}
+
+	// The kernel clamps the private keys, this one is already clamped
+	privateKey := WireguardKey{8, 2, 3}
+	privateKey[31] = 0x40
+	port := uint16(51820)
+	mark := uint32(7)
+	keepalive := 25 * time.Second
+	peerKey := WireguardKey{4, 5, 6}
+	_, allowed, _ := net.ParseCIDR("10.1.0.0/16")
+	config := &WireguardConfig{
+		PrivateKey:   &privateKey,
+		ListenPort:   &port,
+		FirewallMark: &mark,
+		Peers: []WireguardPeerConfig{{
+			PublicKey:                   peerKey,
+			Endpoint:                    &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 51821},
+			PersistentKeepaliveInterval: &keepalive,
+			AllowedIPs:                  []net.IPNet{*allowed},
+		}},
+	}
+	if err := WireguardSet(link, config); err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := WireguardGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Name != "wg0" || dev.PrivateKey != privateKey || dev.ListenPort != port || dev.FirewallMark != mark {
+		t.Fatalf("Unexpected device %+v", dev)
+	}
+	if len(dev.Peers) != 1 {
+		t.Fatalf("Device has %d peers, should have 1", len(dev.Peers))
+	}
+	peer := dev.Peers[0]
+	if peer.PublicKey != peerKey || peer.PersistentKeepaliveInterval != keepalive {
+		t.Fatalf("Unexpected peer %+v", peer)
+	}
+	if peer.Endpoint == nil || !peer.Endpoint.IP.Equal(net.ParseIP("192.168.1.1")) || peer.Endpoint.Port != 51821 {
+		t.Fatalf("Peer endpoint is %v, should be 192.168.1.1:51821", peer.Endpoint)
+	}
+	if len(peer.AllowedIPs) != 1 || peer.AllowedIPs[0].String() != allowed.String() {
+		t.Fatalf("Peer allowed IPs are %v, should be [%v]", peer.AllowedIPs, allowed)
+	}
+	if !peer.LastHandshakeTime.IsZero() {
+		t.Fatalf("Peer handshake at %v without traffic", peer.LastHandshakeTime)
+	}
+
+	config = &WireguardConfig{Peers: []WireguardPeerConfig{{PublicKey: peerKey, Remove: true}}}
+	if err := WireguardSet(link, config); err != nil {
+		t.Fatal(err)
+	}
+	if dev, err = WireguardGet(link); err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Peers) != 0 {
+		t.Fatalf("Peer not removed %+v", dev.Peers)
+	}
+}
+
+func TestWireguardParseDevice(t *testing.T) {
+	key := WireguardKey{1}
+	_, allowed4, _ := net.ParseCIDR("10.0.0.0/8")
+	_, allowed6, _ := net.ParseCIDR("fd00::/64")
+	keepalive := 10 * time.Second
+	config := &WireguardConfig{
+		Peers: []WireguardPeerConfig{{
+			PublicKey:                   key,
+			Endpoint:                    &net.UDPAddr{IP: net.ParseIP("fd00::1"), Port: 1234, Zone: "5"},
+			PersistentKeepaliveInterval: &keepalive,
+			AllowedIPs:                  []net.IPNet{*allowed4},
+		}},
+	}
+	attrs, err := wireguardConfigAttrs(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := make([]byte, nl.SizeofGenlmsg)
+	first = append(first, nl.NewRtAttr(nl.WGDEVICE_A_IFNAME, nl.ZeroTerminated("wg0")).Serialize()...)
+	for _, attr := range attrs {
+		first = append(first, attr.Serialize()...)
+	}
+
+	// The allowed IPs of the peer continue in the next message
+	config.Peers[0] = WireguardPeerConfig{PublicKey: key, AllowedIPs: []net.IPNet{*allowed6}}
+	if attrs, err = wireguardConfigAttrs(config); err != nil {
+		t.Fatal(err)
+	}
+	second := make([]byte, nl.SizeofGenlmsg)
+	for _, attr := range attrs {
+		second = append(second, attr.Serialize()...)
+	}
+
+	dev, err := parseWireguardDevice([][]byte{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Name != "wg0" || len(dev.Peers) != 1 {
+		t.Fatalf("Unexpected device %+v", dev)
+	}
+	peer := dev.Peers[0]
+	if peer.PublicKey != key || peer.PersistentKeepaliveInterval != keepalive {
+		t.Fatalf("Unexpected peer %+v", peer)
+	}
+	if peer.Endpoint == nil || !peer.Endpoint.IP.Equal(net.ParseIP("fd00::1")) || peer.Endpoint.Port != 1234 || peer.Endpoint.Zone == "" {
+		t.Fatalf("Peer endpoint is %v, should be [fd00::1%%5]:1234", peer.Endpoint)
+	}
+	if len(peer.AllowedIPs) != 2 || peer.AllowedIPs[0].String() != allowed4.String() || peer.AllowedIPs[1].String() != allowed6.String() {
+		t.Fatalf("Peer allowed IPs are %v, should be [%v %v]", peer.AllowedIPs, allowed4, allowed6)
+	}
+}
+
+func TestWireguardConfigAttrsMask(t *testing.T) {
+	for _, allowed := range []net.IPNet{
+		{IP: net.ParseIP("10.0.0.0")},
+		{IP: net.ParseIP("10.0.0.0"), Mask: net.CIDRMask(64, 128)},
+		{IP: net.ParseIP("fd00::"), Mask: net.CIDRMask(8, 32)},
+		{IP: net.ParseIP("fd00::"), Mask: net.IPMask{0xff, 0, 0xff, 0}},
+	} {
+		config := &WireguardConfig{Peers: []WireguardPeerConfig{{AllowedIPs: []net.IPNet{allowed}}}}
+		if _, err := wireguardConfigAttrs(config); err == nil {
+			t.Fatalf("Allowed IP %v with mask %v should be rejected", allowed.IP, allowed.Mask)
+		}
+	}
+
+	allowed := net.IPNet{IP: net.ParseIP("10.0.0.0"), Mask: net.CIDRMask(0, 32)}
+	config := &WireguardConfig{Peers: []WireguardPeerConfig{{AllowedIPs: []net.IPNet{allowed}}}}
+	if _, err := wireguardConfigAttrs(config); err != nil {
+		t.Fatalf("Default route should be allowed: %v", err)
+	}
+}
+
+func TestWireguardConfigAttrsKeepalive(t *testing.T) {
+	for _, keepalive := range []time.Duration{-time.Second, -time.Millisecond, 65536 * time.Second} {
+		config := &WireguardConfig{Peers: []WireguardPeerConfig{{PersistentKeepaliveInterval: &keepalive}}}
+		if _, err := wireguardConfigAttrs(config); err == nil {
+			t.Fatalf("Keepalive interval %v should be rejected", keepalive)
+		}
+	}
+
+	keepalive := 65535 * time.Second
+	config := &WireguardConfig{Peers: []WireguardPeerConfig{{PersistentKeepaliveInterval: &keepalive}}}
+	if _, err := wireguardConfigAttrs(config); err != nil {
+		t.Fatalf("Keepalive interval %v should be allowed: %v", keepalive, err)
+	}
+}
+
+func TestWireguardParseShortHandshake(t *testing.T) {
+	attr := nl.NewRtAttr(nl.WGPEER_A_LAST_HANDSHAKE_TIME, make([]byte, 8))
+	if _, err := parseWireguardPeer(attr.Serialize()); err == nil {
+		t.Fatal("Last handshake time of 8 bytes should be rejected")
+	}
+}
+
+func TestWireguardKey(t *testing.T) {
+	key, err := ParseWireguardKey("yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key[0] != 0xc8 || key.String() != "yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk=" {
+		t.Fatalf("Key %x does not round trip", key)
+	}
+	if _, err := ParseWireguardKey("AAAA"); err == nil {
+		t.Fatal("Short key should be rejected")
+	}
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package netlink
+
+func WireguardGet(link Link) (*WireguardDevice, error) {
+	return nil, ErrNotImplemented
+}
+
+func WireguardSet(link Link, config *WireguardConfig) error {
+	return ErrNotImplemented
+}
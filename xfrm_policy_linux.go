@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
 )
@@ -42,6 +44,12 @@ func (h *Handle) XfrmPolicyAdd(policy *XfrmPolicy) error {
 	return h.xfrmPolicyAddOrUpdate(policy, nl.XFRM_MSG_NEWPOLICY)
 }
 
+// XfrmPolicyAddContext is like XfrmPolicyAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyAddContext(ctx context.Context, policy *XfrmPolicy) error {
+	return h.withContext(ctx).XfrmPolicyAdd(policy)
+}
+
 // XfrmPolicyUpdate will update an xfrm policy to the system.
 // Equivalent to: `ip xfrm policy update $policy`
 func XfrmPolicyUpdate(policy *XfrmPolicy) error {
@@ -54,6 +62,12 @@ func (h *Handle) XfrmPolicyUpdate(policy *XfrmPolicy) error {
 	return h.xfrmPolicyAddOrUpdate(policy, nl.XFRM_MSG_UPDPOLICY)
 }
 
+// XfrmPolicyUpdateContext is like XfrmPolicyUpdate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyUpdateContext(ctx context.Context, policy *XfrmPolicy) error {
+	return h.withContext(ctx).XfrmPolicyUpdate(policy)
+}
+
 func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 	req := h.newNetlinkRequest(nlProto, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
 
@@ -111,6 +125,12 @@ func (h *Handle) XfrmPolicyDel(policy *XfrmPolicy) error {
 	return err
 }
 
+// XfrmPolicyDelContext is like XfrmPolicyDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyDelContext(ctx context.Context, policy *XfrmPolicy) error {
+	return h.withContext(ctx).XfrmPolicyDel(policy)
+}
+
 // XfrmPolicyList gets a list of xfrm policies in the system.
 // Equivalent to: `ip xfrm policy show`.
 // The list can be filtered by ip family.
@@ -127,9 +147,9 @@ func (h *Handle) XfrmPolicyList(family int) ([]XfrmPolicy, error) {
 	msg := nl.NewIfInfomsg(family)
 	req.AddData(msg)
 
-	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWPOLICY)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWPOLICY)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []XfrmPolicy
@@ -142,7 +162,13 @@ func (h *Handle) XfrmPolicyList(family int) ([]XfrmPolicy, error) {
 			return nil, err
 		}
 	}
-	return res, nil
+	return res, executeErr
+}
+
+// XfrmPolicyListContext is like XfrmPolicyList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyListContext(ctx context.Context, family int) ([]XfrmPolicy, error) {
+	return h.withContext(ctx).XfrmPolicyList(family)
 }
 
 // XfrmPolicyGet gets a the policy described by the index or selector, if found.
@@ -157,6 +183,12 @@ func (h *Handle) XfrmPolicyGet(policy *XfrmPolicy) (*XfrmPolicy, error) {
 	return h.xfrmPolicyGetOrDelete(policy, nl.XFRM_MSG_GETPOLICY)
 }
 
+// XfrmPolicyGetContext is like XfrmPolicyGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyGetContext(ctx context.Context, policy *XfrmPolicy) (*XfrmPolicy, error) {
+	return h.withContext(ctx).XfrmPolicyGet(policy)
+}
+
 // XfrmPolicyFlush will flush the policies on the system.
 // Equivalent to: `ip xfrm policy flush`
 func XfrmPolicyFlush() error {
@@ -171,6 +203,12 @@ func (h *Handle) XfrmPolicyFlush() error {
 	return err
 }
 
+// XfrmPolicyFlushContext is like XfrmPolicyFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmPolicyFlushContext(ctx context.Context) error {
+	return h.withContext(ctx).XfrmPolicyFlush()
+}
+
 func (h *Handle) xfrmPolicyGetOrDelete(policy *XfrmPolicy, nlProto int) (*XfrmPolicy, error) {
 	req := h.newNetlinkRequest(nlProto, unix.NLM_F_ACK)
 
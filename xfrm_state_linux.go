@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"unsafe"
 
@@ -89,6 +91,12 @@ func (h *Handle) XfrmStateAdd(state *XfrmState) error {
 	return h.xfrmStateAddOrUpdate(state, nl.XFRM_MSG_NEWSA)
 }
 
+// XfrmStateAddContext is like XfrmStateAdd but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateAddContext(ctx context.Context, state *XfrmState) error {
+	return h.withContext(ctx).XfrmStateAdd(state)
+}
+
 // XfrmStateAllocSpi will allocate an xfrm state in the system.
 // Equivalent to: `ip xfrm state allocspi`
 func XfrmStateAllocSpi(state *XfrmState) (*XfrmState, error) {
@@ -107,6 +115,12 @@ func (h *Handle) XfrmStateUpdate(state *XfrmState) error {
 	return h.xfrmStateAddOrUpdate(state, nl.XFRM_MSG_UPDSA)
 }
 
+// XfrmStateUpdateContext is like XfrmStateUpdate but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateUpdateContext(ctx context.Context, state *XfrmState) error {
+	return h.withContext(ctx).XfrmStateUpdate(state)
+}
+
 func (h *Handle) xfrmStateAddOrUpdate(state *XfrmState, nlProto int) error {
 
 	// A state with spi 0 can't be deleted so don't allow it to be set
@@ -207,6 +221,12 @@ func (h *Handle) XfrmStateDel(state *XfrmState) error {
 	return err
 }
 
+// XfrmStateDelContext is like XfrmStateDel but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateDelContext(ctx context.Context, state *XfrmState) error {
+	return h.withContext(ctx).XfrmStateDel(state)
+}
+
 // XfrmStateList gets a list of xfrm states in the system.
 // Equivalent to: `ip [-4|-6] xfrm state show`.
 // The list can be filtered by ip family.
@@ -220,9 +240,9 @@ func XfrmStateList(family int) ([]XfrmState, error) {
 func (h *Handle) XfrmStateList(family int) ([]XfrmState, error) {
 	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSA, unix.NLM_F_DUMP)
 
-	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSA)
-	if err != nil {
-		return nil, err
+	msgs, executeErr := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSA)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
 	}
 
 	var res []XfrmState
@@ -235,7 +255,13 @@ func (h *Handle) XfrmStateList(family int) ([]XfrmState, error) {
 			return nil, err
 		}
 	}
-	return res, nil
+	return res, executeErr
+}
+
+// XfrmStateListContext is like XfrmStateList but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateListContext(ctx context.Context, family int) ([]XfrmState, error) {
+	return h.withContext(ctx).XfrmStateList(family)
 }
 
 // XfrmStateGet gets the xfrm state described by the ID, if found.
@@ -256,6 +282,12 @@ func (h *Handle) XfrmStateGet(state *XfrmState) (*XfrmState, error) {
 	return h.xfrmStateGetOrDelete(state, nl.XFRM_MSG_GETSA)
 }
 
+// XfrmStateGetContext is like XfrmStateGet but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateGetContext(ctx context.Context, state *XfrmState) (*XfrmState, error) {
+	return h.withContext(ctx).XfrmStateGet(state)
+}
+
 func (h *Handle) xfrmStateGetOrDelete(state *XfrmState, nlProto int) (*XfrmState, error) {
 	req := h.newNetlinkRequest(nlProto, unix.NLM_F_ACK)
 
@@ -401,6 +433,12 @@ func (h *Handle) XfrmStateFlush(proto Proto) error {
 	return nil
 }
 
+// XfrmStateFlushContext is like XfrmStateFlush but gives up and returns ctx.Err()
+// once ctx is done.
+func (h *Handle) XfrmStateFlushContext(ctx context.Context, proto Proto) error {
+	return h.withContext(ctx).XfrmStateFlush(proto)
+}
+
 func limitsToLft(lmts XfrmStateLimits, lft *nl.XfrmLifetimeCfg) {
 	if lmts.ByteSoft != 0 {
 		lft.SoftByteLimit = lmts.ByteSoft